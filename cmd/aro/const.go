@@ -8,4 +8,7 @@ const (
 	DatabaseAccountName = "DATABASE_ACCOUNT_NAME"
 	KeyVaultPrefix      = "KEYVAULT_PREFIX"
 	DBTokenUrl          = "DBTOKEN_URL"
+
+	PrometheusMetricsAddress = "PROMETHEUS_METRICS_ADDRESS"
+	MonitorCollectors        = "MONITOR_COLLECTORS"
 )
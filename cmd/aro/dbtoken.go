@@ -20,7 +20,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 )
 
-func dbtoken(ctx context.Context, log *logrus.Entry) error {
+func dbtoken(ctx context.Context, log, audit *logrus.Entry) error {
 	_env, err := env.NewCore(ctx, log)
 	if err != nil {
 		return err
@@ -78,7 +78,12 @@ func dbtoken(ctx context.Context, log *logrus.Entry) error {
 
 	userc := cosmosdb.NewUserClient(dbc, dbName)
 
-	err = pkgdbtoken.ConfigurePermissions(ctx, dbName, userc)
+	policy, err := pkgdbtoken.NewPolicy()
+	if err != nil {
+		return err
+	}
+
+	err = pkgdbtoken.ConfigurePermissions(ctx, dbName, userc, policy)
 	if err != nil {
 		return err
 	}
@@ -116,7 +121,7 @@ func dbtoken(ctx context.Context, log *logrus.Entry) error {
 
 	log.Print("listening")
 
-	server, err := pkgdbtoken.NewServer(ctx, _env, log.WithField("component", "dbtoken"), log.WithField("component", "dbtoken-access"), l, servingKey, servingCerts, verifier, userc, m)
+	server, err := pkgdbtoken.NewServer(ctx, _env, log.WithField("component", "dbtoken"), audit, log.WithField("component", "dbtoken-access"), l, servingKey, servingCerts, verifier, userc, policy, m)
 	if err != nil {
 		return err
 	}
@@ -5,14 +5,17 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database"
 	pkgdbtoken "github.com/Azure/ARO-RP/pkg/dbtoken"
 	"github.com/Azure/ARO-RP/pkg/env"
@@ -109,9 +112,14 @@ func gateway(ctx context.Context, log *logrus.Entry) error {
 		return err
 	}
 
+	defaultLimits, err := gatewayDefaultLimits()
+	if err != nil {
+		return err
+	}
+
 	log.Print("listening")
 
-	p, err := pkggateway.NewGateway(ctx, _env, log.WithField("component", "gateway"), log.WithField("component", "gateway-access"), dbGateway, httpsl, httpl, healthListener, os.Getenv("ACR_RESOURCE_ID"), os.Getenv("GATEWAY_DOMAINS"), m)
+	p, err := pkggateway.NewGateway(ctx, _env, log.WithField("component", "gateway"), log.WithField("component", "gateway-access"), dbGateway, httpsl, httpl, healthListener, os.Getenv("ACR_RESOURCE_ID"), os.Getenv("GATEWAY_DOMAINS"), defaultLimits, m)
 	if err != nil {
 		return err
 	}
@@ -131,6 +139,32 @@ func gateway(ctx context.Context, log *logrus.Entry) error {
 	return nil
 }
 
+// gatewayDefaultLimits returns the per-cluster limits which apply unless a
+// cluster's gateway record overrides them.  GATEWAY_MAX_CLUSTER_CONNECTIONS and
+// GATEWAY_CLUSTER_BANDWIDTH (bytes per second) override the built-in defaults;
+// 0 means no limit.
+func gatewayDefaultLimits() (api.GatewayLimits, error) {
+	limits := pkggateway.DefaultLimits
+
+	if v := os.Getenv("GATEWAY_MAX_CLUSTER_CONNECTIONS"); v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			return api.GatewayLimits{}, fmt.Errorf("invalid GATEWAY_MAX_CLUSTER_CONNECTIONS %q", v)
+		}
+		limits.MaxConnections = i
+	}
+
+	if v := os.Getenv("GATEWAY_CLUSTER_BANDWIDTH"); v != "" {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || i < 0 {
+			return api.GatewayLimits{}, fmt.Errorf("invalid GATEWAY_CLUSTER_BANDWIDTH %q", v)
+		}
+		limits.BandwidthBytesPerSecond = i
+	}
+
+	return limits, nil
+}
+
 func getURL(isLocalDevelopmentMode bool) (string, error) {
 	if isLocalDevelopmentMode {
 		return "https://localhost:8445", nil
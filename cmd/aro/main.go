@@ -28,9 +28,11 @@ func usage() {
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s mirror [release_image...]\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s monitor\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s portal\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s rotate-keys\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s rp\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s operator {master,worker}\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s update-versions\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s verify-keys key_version\n", os.Args[0])
 	flag.PrintDefaults()
 }
 
@@ -54,7 +56,7 @@ func main() {
 	switch strings.ToLower(flag.Arg(0)) {
 	case "dbtoken":
 		checkArgs(1)
-		err = dbtoken(ctx, log)
+		err = dbtoken(ctx, log, audit)
 	case "deploy":
 		checkArgs(3)
 		err = deploy(ctx, log)
@@ -67,6 +69,9 @@ func main() {
 	case "monitor":
 		checkArgs(1)
 		err = monitor(ctx, log)
+	case "rotate-keys":
+		checkArgs(1)
+		err = rotateKeys(ctx, log, "")
 	case "rp":
 		checkArgs(1)
 		err = rp(ctx, log, audit)
@@ -79,6 +84,9 @@ func main() {
 	case "update-versions":
 		checkArgs(1)
 		err = updateOCPVersions(ctx, log)
+	case "verify-keys":
+		checkArgs(2)
+		err = rotateKeys(ctx, log, flag.Arg(1))
 	default:
 		usage()
 		os.Exit(2)
@@ -0,0 +1,71 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/metrics/fanout"
+	"github.com/Azure/ARO-RP/pkg/metrics/prometheus"
+	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+// newMetricsEmitters returns the emitters for the RP's own metrics and for
+// cluster metrics.  Metrics are always emitted to statsd.  If
+// PROMETHEUS_METRICS_ADDRESS is set, they are also served for scraping by
+// Prometheus on /metrics at that address.
+func newMetricsEmitters(ctx context.Context, _log *logrus.Entry, _env env.Core) (metrics.Emitter, metrics.Emitter, error) {
+	m := statsd.New(ctx, _log.WithField("component", "metrics"), _env, os.Getenv("MDM_ACCOUNT"), os.Getenv("MDM_NAMESPACE"), os.Getenv("MDM_STATSD_SOCKET"))
+	clusterm := statsd.New(ctx, _log.WithField("component", "metrics"), _env, os.Getenv("CLUSTER_MDM_ACCOUNT"), os.Getenv("CLUSTER_MDM_NAMESPACE"), os.Getenv("MDM_STATSD_SOCKET"))
+
+	address := os.Getenv(PrometheusMetricsAddress)
+	if address == "" {
+		return m, clusterm, nil
+	}
+
+	promLog := _log.WithField("component", "prometheus")
+
+	p, err := prometheus.New(promLog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p)
+
+	s := &http.Server{
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+		IdleTimeout: 2 * time.Minute,
+		ErrorLog:    log.New(promLog.Writer(), "", 0),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		defer recover.Panic(promLog)
+
+		promLog.Printf("serving metrics on %s", l.Addr())
+		err := s.Serve(l)
+		if err != nil {
+			promLog.Error(err)
+		}
+	}()
+
+	return fanout.New(m, p), fanout.New(clusterm, p), nil
+}
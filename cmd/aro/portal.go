@@ -6,10 +6,12 @@ package main
 import (
 	"context"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"os"
 	"strings"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/ARO-RP/pkg/database"
@@ -18,9 +20,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd/golang"
 	pkgportal "github.com/Azure/ARO-RP/pkg/portal"
 	"github.com/Azure/ARO-RP/pkg/proxy"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
+	"github.com/Azure/ARO-RP/pkg/util/storage"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
 
@@ -87,7 +91,7 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 	serviceKeyvaultURI := keyvault.URI(_env, env.ServiceKeyvaultSuffix, keyVaultPrefix)
 	serviceKeyvault := keyvault.NewManager(msiKVAuthorizer, serviceKeyvaultURI)
 
-	aead, err := encryption.NewMulti(ctx, serviceKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName)
+	aead, err := encryption.NewMulti(ctx, serviceKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName, m)
 	if err != nil {
 		return err
 	}
@@ -111,6 +115,16 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
+	dbAdminActions, err := database.NewAdminActions(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbClusterHealth, err := database.NewClusterHealth(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
 	dbOpenShiftClusters, err := database.NewOpenShiftClusters(ctx, dbc, dbName)
 	if err != nil {
 		return err
@@ -189,11 +203,33 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 
 	log.Printf("listening %s", address)
 
-	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, dialer, m)
+	recordings, err := sshRecordings(log, _env, msiAuthorizer)
+	if err != nil {
+		return err
+	}
+
+	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbAdminActions, dbClusterHealth, dbOpenShiftClusters, dbPortal, dialer, aead, recordings, m)
 
 	return p.Run(ctx)
 }
 
+// sshRecordings returns the store for portal SSH session recordings.  In
+// local development mode, recordings are kept in memory unless a storage
+// account is configured.
+func sshRecordings(log *logrus.Entry, _env env.Core, authorizer autorest.Authorizer) (blobstore.Store, error) {
+	account := os.Getenv("PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT")
+	if account == "" {
+		if _env.IsLocalDevelopmentMode() {
+			return blobstore.NewMemory(), nil
+		}
+		return nil, fmt.Errorf("environment variable %q unset", "PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT")
+	}
+
+	log.Printf("recording SSH sessions to storage account %s", account)
+
+	return blobstore.NewAzure(storage.NewManager(_env, _env.SubscriptionID(), authorizer), _env.ResourceGroup(), account, "sshrecordings"), nil
+}
+
 func parseGroupIDs(_groupIDs string) ([]string, error) {
 	groupIDs := strings.Split(_groupIDs, ",")
 	for _, groupID := range groupIDs {
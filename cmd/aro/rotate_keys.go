@@ -0,0 +1,137 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/portal/ssh"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/keyvault"
+)
+
+const keyRotationBatchSize = 100
+
+// rotateKeys re-seals the encrypted fields of the RP database and the SSH
+// session recordings of the portal with the current encryption key.  If
+// keyVersion is set, it instead verifies that nothing still depends on that key
+// version.
+func rotateKeys(ctx context.Context, log *logrus.Entry, keyVersion string) error {
+	_env, err := env.NewCore(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	msiAuthorizer, err := _env.NewMSIAuthorizer(env.MSIContextRP, _env.Environment().ResourceManagerScope)
+	if err != nil {
+		return err
+	}
+
+	msiKVAuthorizer, err := _env.NewMSIAuthorizer(env.MSIContextRP, _env.Environment().KeyVaultScope)
+	if err != nil {
+		return err
+	}
+
+	if err := env.ValidateVars(KeyVaultPrefix); err != nil {
+		return err
+	}
+	keyVaultPrefix := os.Getenv(KeyVaultPrefix)
+	serviceKeyvaultURI := keyvault.URI(_env, env.ServiceKeyvaultSuffix, keyVaultPrefix)
+	serviceKeyvault := keyvault.NewManager(msiKVAuthorizer, serviceKeyvaultURI)
+
+	aead, err := encryption.NewMulti(ctx, serviceKeyvault, env.EncryptionSecretV2Name, env.EncryptionSecretName, &noop.Noop{})
+	if err != nil {
+		return err
+	}
+
+	if err := env.ValidateVars(DatabaseAccountName); err != nil {
+		return err
+	}
+
+	dbAccountName := os.Getenv(DatabaseAccountName)
+	dbAuthorizer, err := database.NewMasterKeyAuthorizer(ctx, _env, msiAuthorizer, dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	// the database client has no AEAD: KeyRotation reads and writes the
+	// encrypted fields as they are stored
+	dbc, err := database.NewDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, &noop.Noop{}, nil, dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	dbName, err := DBName(_env.IsLocalDevelopmentMode())
+	if err != nil {
+		return err
+	}
+
+	dbRotation, err := database.NewKeyRotation(log.WithField("component", "keyrotation"), dbc, dbName, aead, keyRotationBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// SSH session recordings are sealed with the same key as the database
+	recordings, err := sshRecordings(log, _env, msiAuthorizer)
+	if err != nil {
+		return err
+	}
+
+	recordingsRotation, err := ssh.NewRecordingsKeyRotation(log.WithField("component", "keyrotation"), recordings, aead)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, x := range []struct {
+		name string
+		r    database.KeyRotation
+	}{
+		{name: "database", r: dbRotation},
+		{name: "SSH recordings", r: recordingsRotation},
+	} {
+		var p *database.KeyRotationProgress
+		if keyVersion == "" {
+			p, err = x.r.Rotate(ctx)
+		} else {
+			p, err = x.r.Verify(ctx, keyVersion)
+		}
+		if p != nil {
+			logKeyRotationProgress(log.WithField("store", x.name), p)
+		}
+		if err != nil {
+			log.Errorf("%s: %v", x.name, err)
+			failed = append(failed, x.name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("key rotation failed for %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func logKeyRotationProgress(log *logrus.Entry, p *database.KeyRotationProgress) {
+	log.Printf("%d documents scanned, %d re-sealed, %d failed", p.Documents, p.Rotated, p.Failed)
+
+	versions := make([]string, 0, len(p.KeyVersions))
+	for version := range p.KeyVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	for _, version := range versions {
+		log.Printf("key version %s: %d encrypted fields", version, p.KeyVersions[version])
+	}
+}
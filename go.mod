@@ -74,6 +74,7 @@ require (
 	golang.org/x/oauth2 v0.7.0
 	golang.org/x/sync v0.2.0
 	golang.org/x/text v0.12.0
+	golang.org/x/time v0.3.0
 	golang.org/x/tools v0.7.0
 	k8s.io/api v0.26.2
 	k8s.io/apiextensions-apiserver v0.24.17
@@ -258,7 +259,6 @@ require (
 	golang.org/x/mod v0.10.0 // indirect
 	golang.org/x/sys v0.11.0 // indirect
 	golang.org/x/term v0.11.0 // indirect
-	golang.org/x/time v0.3.0 // indirect
 	gomodules.xyz/jsonpatch/v2 v2.2.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/grpc v1.55.0 // indirect
@@ -5,6 +5,7 @@ package main
 
 import (
 	"flag"
+	"strings"
 
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
@@ -15,6 +16,7 @@ func main() {
 	certFile := flag.String("certFile", "secrets/proxy.crt", "file containing server certificate")
 	keyFile := flag.String("keyFile", "secrets/proxy.key", "file containing server key")
 	clientCertFile := flag.String("clientCertFile", "secrets/proxy-client.crt", "file containing client certificate")
+	clientCertFiles := flag.String("clientCertFiles", "", "comma separated list of name=file pairs of client certificates; overrides clientCertFile")
 	subnet := flag.String("subnet", "10.0.0.0/8", "allowed subnet")
 
 	log := utillog.GetLogger()
@@ -23,13 +25,26 @@ func main() {
 
 	flag.Parse()
 
-	s := &proxy.Server{
-		Log: log,
+	clientCerts := map[string]string{}
+	if *clientCertFiles != "" {
+		for _, pair := range strings.Split(*clientCertFiles, ",") {
+			name, file, found := strings.Cut(pair, "=")
+			if !found || name == "" || file == "" {
+				log.Fatalf("invalid client certificate %q", pair)
+			}
+			clientCerts[name] = file
+		}
+	}
 
-		CertFile:       *certFile,
-		KeyFile:        *keyFile,
-		ClientCertFile: *clientCertFile,
-		Subnet:         *subnet,
+	s := &proxy.Server{
+		Log:      log,
+		AuditLog: log.WithField("component", "audit"),
+
+		CertFile:        *certFile,
+		KeyFile:         *keyFile,
+		ClientCertFile:  *clientCertFile,
+		ClientCertFiles: clientCerts,
+		Subnet:          *subnet,
 	}
 
 	if err := s.Run(); err != nil {
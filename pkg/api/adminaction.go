@@ -0,0 +1,46 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// AdminAction records an admin action taken on a cluster through the RP
+type AdminAction struct {
+	MissingFields
+
+	ID string `json:"id,omitempty"`
+
+	// ResourceID is the resource ID of the cluster the action was taken on
+	ResourceID string `json:"resourceId,omitempty"`
+
+	// Action is the admin action, e.g. redeployvm or adminupdate
+	Action string `json:"action,omitempty"`
+	Method string `json:"method,omitempty"`
+
+	// Parameters holds the query parameters of the request and, for
+	// Kubernetes objects, the kind, namespace and name of the object.  Request
+	// bodies are never recorded as they may contain secrets.
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	ClientPrincipalName string `json:"clientPrincipalName,omitempty"`
+	UserAgent           string `json:"userAgent,omitempty"`
+	CorrelationID       string `json:"correlationId,omitempty"`
+	ClientRequestID     string `json:"clientRequestId,omitempty"`
+	RequestID           string `json:"requestId,omitempty"`
+
+	StartTime time.Time `json:"startTime,omitempty"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+
+	Result AdminActionResult `json:"result,omitempty"`
+}
+
+// AdminActionResult is the result of an AdminAction
+type AdminActionResult struct {
+	MissingFields
+
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,41 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// AdminActionDocuments represents admin action documents.
+// pkg/database/cosmosdb requires its definition.
+type AdminActionDocuments struct {
+	Count                int                    `json:"_count,omitempty"`
+	ResourceID           string                 `json:"_rid,omitempty"`
+	AdminActionDocuments []*AdminActionDocument `json:"Documents,omitempty"`
+}
+
+func (c *AdminActionDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// AdminActionDocument represents an admin action document.
+// pkg/database/cosmosdb requires its definition.
+type AdminActionDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	// Key is the lower case resource ID of the cluster, and the partition key
+	Key string `json:"key,omitempty"`
+
+	AdminAction *AdminAction `json:"adminAction,omitempty"`
+}
+
+func (c *AdminActionDocument) String() string {
+	return encodeJSON(c)
+}
@@ -33,6 +33,8 @@ type AsyncOperationDocument struct {
 
 	OpenShiftClusterKey string            `json:"openShiftClusterKey,omitempty"`
 	OpenShiftCluster    *OpenShiftCluster `json:"openShiftCluster,omitempty"`
+
+	StepTimeline []StepTimelineEntry `json:"stepTimeline,omitempty"`
 }
 
 func (c *AsyncOperationDocument) String() string {
@@ -0,0 +1,67 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// HealthState is the overall health verdict of a cluster
+type HealthState string
+
+// HealthState constants, in order of increasing severity
+const (
+	HealthStateHealthy   HealthState = "Healthy"
+	HealthStateDegraded  HealthState = "Degraded"
+	HealthStateUnhealthy HealthState = "Unhealthy"
+)
+
+// Severity returns the relative severity of the health state, so that states
+// can be compared
+func (s HealthState) Severity() int {
+	switch s {
+	case HealthStateDegraded:
+		return 1
+	case HealthStateUnhealthy:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ClusterHealth represents the health of a cluster, as last evaluated by the
+// monitor
+type ClusterHealth struct {
+	MissingFields
+
+	State   HealthState    `json:"state,omitempty"`
+	Reasons []HealthReason `json:"reasons,omitempty"`
+
+	// EvaluatedTime is when the monitor last evaluated the health of the
+	// cluster
+	EvaluatedTime time.Time `json:"evaluatedTime,omitempty"`
+
+	// History records the changes in State, oldest first
+	History []HealthTransition `json:"history,omitempty"`
+}
+
+// HealthReason explains why a cluster is not healthy
+type HealthReason struct {
+	MissingFields
+
+	// Signal is the monitored signal the reason is derived from, e.g.
+	// "nodeConditions"
+	Signal  string      `json:"signal,omitempty"`
+	State   HealthState `json:"state,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// HealthTransition records a change of the health state of a cluster
+type HealthTransition struct {
+	MissingFields
+
+	State   HealthState    `json:"state,omitempty"`
+	Reasons []HealthReason `json:"reasons,omitempty"`
+	Time    time.Time      `json:"time,omitempty"`
+}
@@ -0,0 +1,42 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// ClusterHealthDocuments represents cluster health documents.
+// pkg/database/cosmosdb requires its definition.
+type ClusterHealthDocuments struct {
+	Count                  int                      `json:"_count,omitempty"`
+	ResourceID             string                   `json:"_rid,omitempty"`
+	ClusterHealthDocuments []*ClusterHealthDocument `json:"Documents,omitempty"`
+}
+
+func (c *ClusterHealthDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// ClusterHealthDocument represents a cluster health document.  Its ID is the
+// ID of the OpenShiftClusterDocument of the cluster.
+// pkg/database/cosmosdb requires its definition.
+type ClusterHealthDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	// Key is the lower case resource ID of the cluster
+	Key string `json:"key,omitempty"`
+
+	ClusterHealth *ClusterHealth `json:"clusterHealth,omitempty"`
+}
+
+func (c *ClusterHealthDocument) String() string {
+	return encodeJSON(c)
+}
@@ -0,0 +1,99 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// FleetOperation runs a maintenance task as an admin update on every cluster
+// matching a selector, a limited number of clusters at a time
+type FleetOperation struct {
+	MissingFields
+
+	ID string `json:"id,omitempty"`
+
+	Selector        FleetSelector   `json:"selector,omitempty"`
+	MaintenanceTask MaintenanceTask `json:"maintenanceTask,omitempty"`
+
+	// MaxConcurrency is the maximum number of clusters updated at once
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+
+	// FailureBudget is the number of clusters which may fail to update before
+	// the operation is paused
+	FailureBudget int `json:"failureBudget,omitempty"`
+
+	State   FleetOperationState `json:"state,omitempty"`
+	Message string              `json:"message,omitempty"`
+
+	StartTime time.Time  `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+
+	Targets []*FleetOperationTarget `json:"targets,omitempty"`
+}
+
+// FleetSelector selects the clusters of a FleetOperation.  A cluster must
+// match every criterion which is set.
+type FleetSelector struct {
+	MissingFields
+
+	Locations       []string `json:"locations,omitempty"`
+	SubscriptionIDs []string `json:"subscriptionIds,omitempty"`
+
+	// MinVersion and MaxVersion bound the cluster version, inclusively
+	MinVersion string `json:"minVersion,omitempty"`
+	MaxVersion string `json:"maxVersion,omitempty"`
+
+	// OperatorFlags must all be set to the given values on the cluster
+	OperatorFlags map[string]string `json:"operatorFlags,omitempty"`
+}
+
+// FleetOperationState represents the state of a FleetOperation
+type FleetOperationState string
+
+// FleetOperationState constants
+const (
+	FleetOperationStateRunning   FleetOperationState = "Running"
+	FleetOperationStatePaused    FleetOperationState = "Paused"
+	FleetOperationStateCanceling FleetOperationState = "Canceling"
+	FleetOperationStateSucceeded FleetOperationState = "Succeeded"
+	FleetOperationStateFailed    FleetOperationState = "Failed"
+	FleetOperationStateCanceled  FleetOperationState = "Canceled"
+)
+
+// IsTerminal returns true if state is Terminal
+func (s FleetOperationState) IsTerminal() bool {
+	return s == FleetOperationStateSucceeded ||
+		s == FleetOperationStateFailed ||
+		s == FleetOperationStateCanceled
+}
+
+// FleetOperationTarget records the progress of a FleetOperation on one
+// cluster
+type FleetOperationTarget struct {
+	MissingFields
+
+	ResourceID string                    `json:"resourceId,omitempty"`
+	State      FleetOperationTargetState `json:"state,omitempty"`
+	Error      string                    `json:"error,omitempty"`
+
+	// AsyncOperationID is the ID of the async operation of the admin update
+	// started on the cluster
+	AsyncOperationID string `json:"asyncOperationId,omitempty"`
+
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+// FleetOperationTargetState represents the state of a FleetOperationTarget
+type FleetOperationTargetState string
+
+// FleetOperationTargetState constants
+const (
+	FleetOperationTargetStatePending   FleetOperationTargetState = "Pending"
+	FleetOperationTargetStateRunning   FleetOperationTargetState = "Running"
+	FleetOperationTargetStateSucceeded FleetOperationTargetState = "Succeeded"
+	FleetOperationTargetStateFailed    FleetOperationTargetState = "Failed"
+	FleetOperationTargetStateSkipped   FleetOperationTargetState = "Skipped"
+)
@@ -0,0 +1,42 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// FleetOperationDocuments represents fleet operation documents.
+// pkg/database/cosmosdb requires its definition.
+type FleetOperationDocuments struct {
+	Count                   int                       `json:"_count,omitempty"`
+	ResourceID              string                    `json:"_rid,omitempty"`
+	FleetOperationDocuments []*FleetOperationDocument `json:"Documents,omitempty"`
+}
+
+func (c *FleetOperationDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// FleetOperationDocument represents a fleet operation document.
+// pkg/database/cosmosdb requires its definition.
+type FleetOperationDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	LeaseOwner   string `json:"leaseOwner,omitempty" deep:"-"`
+	LeaseExpires int    `json:"leaseExpires,omitempty" deep:"-"`
+	Dequeues     int    `json:"dequeues,omitempty"`
+
+	FleetOperation *FleetOperation `json:"fleetOperation,omitempty"`
+}
+
+func (c *FleetOperationDocument) String() string {
+	return encodeJSON(c)
+}
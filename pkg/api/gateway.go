@@ -3,6 +3,10 @@ package api
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 // Gateway represents a Gateway entry
 type Gateway struct {
 	MissingFields
@@ -12,4 +16,42 @@ type Gateway struct {
 
 	StorageSuffix                   string `json:"storageSuffix,omitempty"`
 	ImageRegistryStorageAccountName string `json:"imageRegistryStorageAccountName,omitempty"`
+
+	EgressPolicy *GatewayEgressPolicy `json:"egressPolicy,omitempty"`
+	Limits       *GatewayLimits       `json:"limits,omitempty"`
+}
+
+// GatewayLimits limits the connections which a cluster may hold open through
+// each gateway instance and the bandwidth which they may use between them.
+// Zero values mean no limit.  In a gateway record, non-zero values override
+// the gateway's defaults.
+type GatewayLimits struct {
+	MissingFields
+
+	MaxConnections          int   `json:"maxConnections,omitempty"`
+	BandwidthBytesPerSecond int64 `json:"bandwidthBytesPerSecond,omitempty"`
+}
+
+// GatewayEgressPolicy lists the destinations, in addition to the gateway's
+// static allow list and the cluster's storage accounts, which a cluster may
+// reach through the gateway
+type GatewayEgressPolicy struct {
+	MissingFields
+
+	Rules []GatewayEgressRule `json:"rules,omitempty"`
+}
+
+// GatewayEgressRule allows connections to hosts matching FQDNPattern on the
+// given ports.  FQDNPattern is either a fully qualified domain name or a
+// wildcard of the form *.example.com, which matches any subdomain of
+// example.com but not example.com itself.  If Ports is empty, only port 443
+// is allowed.  A rule with ExpiresAt set is a time-limited exception and is
+// ignored after that time.
+type GatewayEgressRule struct {
+	MissingFields
+
+	FQDNPattern string     `json:"fqdnPattern,omitempty"`
+	Ports       []int      `json:"ports,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
 }
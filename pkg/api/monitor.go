@@ -8,4 +8,23 @@ type Monitor struct {
 	MissingFields
 
 	Buckets []string `json:"buckets,omitempty"`
+
+	// BucketWorkTimes is set in the master document.  It holds the last
+	// measured work time of each bucket, in seconds per monitoring interval,
+	// or nil if the bucket has not been measured.  A bucket can be measured
+	// at zero seconds, e.g. if it holds no clusters.
+	BucketWorkTimes []*float64 `json:"bucketWorkTimes,omitempty"`
+
+	// WorkTimes is set in the document of each registered monitor.  It holds
+	// the measured work times of the buckets which the monitor owns.
+	WorkTimes []BucketWorkTime `json:"workTimes,omitempty"`
+}
+
+// BucketWorkTime represents the time a monitor spends monitoring the clusters
+// in a bucket, in seconds per monitoring interval
+type BucketWorkTime struct {
+	MissingFields
+
+	Bucket  int     `json:"bucket"`
+	Seconds float64 `json:"seconds"`
 }
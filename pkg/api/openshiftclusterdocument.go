@@ -42,6 +42,10 @@ type OpenShiftClusterDocument struct {
 
 	AsyncOperationID string `json:"asyncOperationId,omitempty" deep:"-"`
 
+	// StepCheckpoint is non-nil only while a resumable backend operation is
+	// in progress
+	StepCheckpoint *StepCheckpoint `json:"stepCheckpoint,omitempty" deep:"-"`
+
 	OpenShiftCluster *OpenShiftCluster `json:"openShiftCluster,omitempty"`
 
 	CorrelationData *CorrelationData `json:"correlationData,omitempty" deep:"-"`
@@ -3,6 +3,10 @@ package api
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 // Portal represents a portal
 type Portal struct {
 	MissingFields
@@ -12,8 +16,9 @@ type Portal struct {
 	// ID is the resourceID of the cluster being accessed by the SRE
 	ID string `json:"id,omitempty"`
 
-	SSH        *SSH        `json:"ssh,omitempty"`
-	Kubeconfig *Kubeconfig `json:"kubeconfig,omitempty"`
+	SSH            *SSH            `json:"ssh,omitempty"`
+	Kubeconfig     *Kubeconfig     `json:"kubeconfig,omitempty"`
+	ElevatedAccess *ElevatedAccess `json:"elevatedAccess,omitempty"`
 }
 
 type SSH struct {
@@ -21,10 +26,67 @@ type SSH struct {
 
 	Master        int  `json:"master"`
 	Authenticated bool `json:"authenticated,omitempty"`
+
+	// ExpiresAt is set when the session is authorised by a just-in-time
+	// elevated access grant; the session is closed when the grant expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
+// KubeconfigScope restricts the requests which a portal kubeconfig token may
+// make to the cluster
+type KubeconfigScope string
+
+// KubeconfigScope constants
+const (
+	// KubeconfigScopeAdmin allows all requests.  Tokens without a scope have
+	// this scope.
+	KubeconfigScopeAdmin KubeconfigScope = "Admin"
+	// KubeconfigScopeReadOnly allows read requests, except to secrets and to
+	// the exec, attach, portforward and proxy subresources
+	KubeconfigScopeReadOnly KubeconfigScope = "ReadOnly"
+	// KubeconfigScopeNamespace allows all requests to resources in Namespace
+	// and discovery requests
+	KubeconfigScopeNamespace KubeconfigScope = "Namespace"
+)
+
 type Kubeconfig struct {
 	MissingFields
 
 	Elevated bool `json:"elevated,omitempty"`
+
+	// TokenID identifies the token in listings and revocations without
+	// revealing it
+	TokenID   string          `json:"tokenId,omitempty"`
+	Scope     KubeconfigScope `json:"scope,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ElevatedAccessState represents the state of a just-in-time elevated access
+// request
+type ElevatedAccessState string
+
+// ElevatedAccessState constants
+const (
+	ElevatedAccessStatePending  ElevatedAccessState = "Pending"
+	ElevatedAccessStateApproved ElevatedAccessState = "Approved"
+	ElevatedAccessStateDenied   ElevatedAccessState = "Denied"
+)
+
+// ElevatedAccess is a just-in-time request by Portal.Username for time-boxed
+// elevated access to the cluster Portal.ID.  Once approved by a second user,
+// it grants elevated access until ExpiresAt.
+type ElevatedAccess struct {
+	MissingFields
+
+	Justification string              `json:"justification"`
+	Duration      string              `json:"duration"`
+	State         ElevatedAccessState `json:"state"`
+	RequestedAt   time.Time           `json:"requestedAt"`
+
+	ReviewedBy string     `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
 }
@@ -0,0 +1,36 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// StepCheckpoint records the progress of the backend through the steps of an
+// asynchronous operation, so that a backend which re-dequeues the document
+// (e.g. after a lease loss) can resume from the first unfinished step rather
+// than starting from the top.
+type StepCheckpoint struct {
+	// AsyncOperationID is the asynchronous operation the checkpoint belongs
+	// to.  A checkpoint recorded for a different operation is ignored.
+	AsyncOperationID string `json:"asyncOperationId,omitempty"`
+
+	// Topic identifies the list of steps being run, e.g. "adminUpdate" or
+	// "install.InstallPhaseBootstrap".  A checkpoint recorded for a different
+	// topic is ignored.
+	Topic string `json:"topic,omitempty"`
+
+	// CompletedSteps are the steps which have completed, in run order.
+	CompletedSteps []CompletedStep `json:"completedSteps,omitempty"`
+}
+
+// CompletedStep represents a step which has completed successfully.  It does
+// not hold the outputs of the step: steps persist their outputs in the cluster
+// document, and steps which produce in-memory state are marked
+// steps.AlwaysRun so that they re-derive it on resume.
+type CompletedStep struct {
+	Name            string    `json:"name,omitempty"`
+	CompletedAt     time.Time `json:"completedAt,omitempty"`
+	DurationSeconds int64     `json:"durationSeconds,omitempty"`
+}
@@ -0,0 +1,34 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// StepTimelineEntry records the outcome of a single step run by the backend
+// during an asynchronous operation.  Entries are appended to the asynchronous
+// operation document in run order, so that the timeline of a failed operation
+// can be inspected after the fact.
+type StepTimelineEntry struct {
+	// Topic identifies the list of steps the step belongs to, e.g.
+	// "adminUpdate" or "install.InstallPhaseBootstrap".
+	Topic string `json:"topic,omitempty"`
+
+	// Name is the metrics name of the step, e.g. "action.ensureResourceGroup".
+	Name string `json:"name,omitempty"`
+
+	StartTime time.Time `json:"startTime,omitempty"`
+	EndTime   time.Time `json:"endTime,omitempty"`
+
+	// Outcome is one of "Succeeded", "Failed" or "Skipped".
+	Outcome string `json:"outcome,omitempty"`
+
+	// ErrorClass is a short classification of the error a failed step
+	// returned, e.g. "CloudError/DeploymentFailed" or "Timeout".
+	ErrorClass string `json:"errorClass,omitempty"`
+
+	// Retries is the number of times the step retried before completing.
+	Retries int `json:"retries,omitempty"`
+}
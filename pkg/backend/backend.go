@@ -30,6 +30,7 @@ type backend struct {
 
 	dbAsyncOperations   database.AsyncOperations
 	dbBilling           database.Billing
+	dbFleetOperations   database.FleetOperations
 	dbGateway           database.Gateway
 	dbOpenShiftClusters database.OpenShiftClusters
 	dbSubscriptions     database.Subscriptions
@@ -39,13 +40,15 @@ type backend struct {
 	m       metrics.Emitter
 	billing billing.Manager
 
-	mu       sync.Mutex
-	cond     *sync.Cond
-	workers  int32
-	stopping atomic.Value
+	mu        sync.Mutex
+	cond      *sync.Cond
+	workers   int32
+	stopping  atomic.Value
+	scheduler *scheduler
 
 	ocb *openShiftClusterBackend
 	sb  *subscriptionBackend
+	fb  *fleetOperationBackend
 }
 
 // Runnable represents a runnable object
@@ -54,29 +57,41 @@ type Runnable interface {
 }
 
 // NewBackend returns a new runnable backend
-func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (Runnable, error) {
-	b, err := newBackend(ctx, log, env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, aead, m)
+func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbFleetOperations database.FleetOperations, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (Runnable, error) {
+	b, err := newBackend(ctx, log, env, dbAsyncOperations, dbBilling, dbFleetOperations, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, aead, m)
 	if err != nil {
 		return nil, err
 	}
 
 	b.ocb = newOpenShiftClusterBackend(b)
 	b.sb = newSubscriptionBackend(b)
+	b.fb = newFleetOperationBackend(b)
 	return b, nil
 }
 
-func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (*backend, error) {
+func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbFleetOperations database.FleetOperations, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (*backend, error) {
 	billing, err := billing.NewManager(env, dbBilling, dbSubscriptions, log)
 	if err != nil {
 		return nil, err
 	}
 
+	maxWorkersPerSubscriptionPerInstance, err := maxWorkersPerSubscriptionPerInstanceFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	maxWorkersPerSubscriptionPerInstanceOverrides, err := maxWorkersPerSubscriptionPerInstanceOverridesFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	b := &backend{
 		baseLog: log,
 		env:     env,
 
 		dbAsyncOperations:   dbAsyncOperations,
 		dbBilling:           dbBilling,
+		dbFleetOperations:   dbFleetOperations,
 		dbGateway:           dbGateway,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbSubscriptions:     dbSubscriptions,
@@ -85,6 +100,8 @@ func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsy
 		billing: billing,
 		aead:    aead,
 		m:       m,
+
+		scheduler: newScheduler(maxWorkersPerSubscriptionPerInstance, maxWorkersPerSubscriptionPerInstanceOverrides),
 	}
 	b.cond = sync.NewCond(&b.mu)
 	b.stopping.Store(false)
@@ -129,7 +146,12 @@ func (b *backend) Run(ctx context.Context, stop <-chan struct{}, done chan<- str
 			b.baseLog.Error(err)
 		}
 
-		if !(ocbDidWork || sbDidWork) {
+		fbDidWork, err := b.fb.try(ctx)
+		if err != nil {
+			b.baseLog.Error(err)
+		}
+
+		if !(ocbDidWork || sbDidWork || fbDidWork) {
 			<-t.C
 		}
 	}
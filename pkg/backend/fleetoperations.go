@@ -0,0 +1,494 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+var errFleetTargetBusy = errors.New("cluster is busy")
+
+type fleetOperationBackend struct {
+	*backend
+
+	now func() time.Time
+}
+
+func newFleetOperationBackend(b *backend) *fleetOperationBackend {
+	return &fleetOperationBackend{
+		backend: b,
+		now:     time.Now,
+	}
+}
+
+// try tries to dequeue a FleetOperationDocument for work, and works it on a
+// new goroutine.  It returns a boolean to the caller indicating whether it
+// succeeded in dequeuing anything - if this is false, the caller should sleep
+// before calling again
+func (fb *fleetOperationBackend) try(ctx context.Context) (bool, error) {
+	doc, err := fb.dbFleetOperations.Dequeue(ctx)
+	if err != nil || doc == nil {
+		return false, err
+	}
+
+	log := fb.baseLog.WithField("fleet_operation", doc.ID)
+	if doc.Dequeues > maxDequeueCount {
+		log.Errorf("dequeued %d times, failing", doc.Dequeues)
+		return true, fb.fail(ctx, doc, fmt.Sprintf("The fleet operation was dequeued %d times.", doc.Dequeues))
+	}
+
+	log.Print("dequeued")
+	atomic.AddInt32(&fb.workers, 1)
+	fb.m.EmitGauge("backend.fleetoperations.workers.count", int64(atomic.LoadInt32(&fb.workers)), nil)
+
+	go func() {
+		defer recover.Panic(log)
+
+		t := time.Now()
+
+		defer func() {
+			atomic.AddInt32(&fb.workers, -1)
+			fb.m.EmitGauge("backend.fleetoperations.workers.count", int64(atomic.LoadInt32(&fb.workers)), nil)
+			fb.cond.Signal()
+
+			log.WithField("duration", time.Since(t).Seconds()).Print("done")
+		}()
+
+		err := fb.handle(context.Background(), log, doc)
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	return true, nil
+}
+
+// handle is responsible for handling backend operation and lease
+func (fb *fleetOperationBackend) handle(ctx context.Context, log *logrus.Entry, doc *api.FleetOperationDocument) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := fb.heartbeat(ctx, cancel, log, doc)
+	defer stop()
+
+	err := fb.reconcile(ctx, log, doc)
+	if err != nil {
+		log.Error(err)
+	}
+
+	return fb.endLease(ctx, stop, doc)
+}
+
+// reconcile makes one pass over a fleet operation: it records the outcome of
+// the admin updates which have finished, tracks admin updates which an earlier
+// pass started but did not record and, unless the operation is paused,
+// canceling or over its failure budget, starts admin updates on pending
+// clusters up to the operation's concurrency limit
+func (fb *fleetOperationBackend) reconcile(ctx context.Context, log *logrus.Entry, doc *api.FleetOperationDocument) error {
+	op := doc.FleetOperation
+	updates := map[string]*api.FleetOperationTarget{}
+
+	var running, failures int
+	var err error
+	for _, target := range op.Targets {
+		var update *api.FleetOperationTarget
+
+		switch target.State {
+		case api.FleetOperationTargetStateFailed:
+			failures++
+			continue
+		case api.FleetOperationTargetStateRunning:
+			update, err = fb.checkTarget(ctx, target)
+		case api.FleetOperationTargetStatePending:
+			if target.AsyncOperationID == "" {
+				continue
+			}
+			update, err = fb.recoverTarget(ctx, target)
+		default:
+			continue
+		}
+		if err != nil {
+			break
+		}
+
+		switch {
+		case update == nil:
+			// a running target which has not finished yet, or a pending
+			// target which was not started
+			if target.State == api.FleetOperationTargetStateRunning {
+				running++
+			}
+		case update.State == api.FleetOperationTargetStateRunning:
+			running++
+			updates[target.ResourceID] = update
+		case update.State == api.FleetOperationTargetStateFailed:
+			failures++
+			fallthrough
+		default:
+			updates[target.ResourceID] = update
+		}
+	}
+
+	if err == nil && op.State == api.FleetOperationStateRunning && failures <= op.FailureBudget {
+		for _, target := range op.Targets {
+			if running >= op.MaxConcurrency {
+				break
+			}
+			if target.State != api.FleetOperationTargetStatePending ||
+				updates[target.ResourceID] != nil {
+				continue
+			}
+
+			var update *api.FleetOperationTarget
+			update, err = fb.startTarget(ctx, log, doc, target)
+			if err != nil {
+				break
+			}
+
+			if update != nil {
+				updates[target.ResourceID] = update
+				if update.State == api.FleetOperationTargetStateRunning {
+					running++
+				}
+			}
+		}
+	}
+
+	// record whatever progress was made, even if the pass was cut short, so
+	// that clusters on which an admin update was started are tracked
+	_, patchErr := fb.dbFleetOperations.PatchWithLease(ctx, doc.ID, func(doc *api.FleetOperationDocument) error {
+		applyFleetOperationUpdates(doc.FleetOperation, updates, fb.now().UTC())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return patchErr
+}
+
+// checkTarget returns the outcome of a running target, or nil if the admin
+// update on its cluster has not finished yet
+func (fb *fleetOperationBackend) checkTarget(ctx context.Context, target *api.FleetOperationTarget) (*api.FleetOperationTarget, error) {
+	now := fb.now().UTC()
+
+	ocDoc, err := fb.dbOpenShiftClusters.Get(ctx, strings.ToLower(target.ResourceID))
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return &api.FleetOperationTarget{
+			State:   api.FleetOperationTargetStateFailed,
+			Error:   "The cluster was deleted.",
+			EndTime: &now,
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if ocDoc.OpenShiftCluster.Properties.ProvisioningState == api.ProvisioningStateAdminUpdating {
+		return nil, nil
+	}
+
+	if ocDoc.OpenShiftCluster.Properties.LastAdminUpdateError != "" {
+		return &api.FleetOperationTarget{
+			State:   api.FleetOperationTargetStateFailed,
+			Error:   ocDoc.OpenShiftCluster.Properties.LastAdminUpdateError,
+			EndTime: &now,
+		}, nil
+	}
+
+	return &api.FleetOperationTarget{
+		State:   api.FleetOperationTargetStateSucceeded,
+		EndTime: &now,
+	}, nil
+}
+
+// recoverTarget returns a running target if the admin update of a pending
+// target was started on its cluster by an earlier pass which did not get to
+// record it, or nil otherwise
+func (fb *fleetOperationBackend) recoverTarget(ctx context.Context, target *api.FleetOperationTarget) (*api.FleetOperationTarget, error) {
+	now := fb.now().UTC()
+
+	ocDoc, err := fb.dbOpenShiftClusters.Get(ctx, strings.ToLower(target.ResourceID))
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if ocDoc.AsyncOperationID != target.AsyncOperationID {
+		return nil, nil
+	}
+
+	// the earlier pass may also have stopped before creating the async
+	// operation
+	_, err = fb.dbAsyncOperations.Get(ctx, target.AsyncOperationID)
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		err = fb.createAsyncOperation(ctx, ocDoc, target.AsyncOperationID, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.FleetOperationTarget{
+		State:            api.FleetOperationTargetStateRunning,
+		AsyncOperationID: target.AsyncOperationID,
+		StartTime:        &now,
+	}, nil
+}
+
+// startTarget starts an admin update on the cluster of a pending target.  It
+// returns nil if the cluster is busy and the target should be retried later.
+func (fb *fleetOperationBackend) startTarget(ctx context.Context, log *logrus.Entry, doc *api.FleetOperationDocument, target *api.FleetOperationTarget) (*api.FleetOperationTarget, error) {
+	now := fb.now().UTC()
+	op := doc.FleetOperation
+
+	ocDoc, err := fb.dbOpenShiftClusters.Get(ctx, strings.ToLower(target.ResourceID))
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return &api.FleetOperationTarget{
+			State:   api.FleetOperationTargetStateSkipped,
+			Error:   "The cluster was deleted.",
+			EndTime: &now,
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	switch ocDoc.OpenShiftCluster.Properties.ProvisioningState {
+	case api.ProvisioningStateSucceeded:
+	case api.ProvisioningStateFailed, api.ProvisioningStateDeleting:
+		return &api.FleetOperationTarget{
+			State:   api.FleetOperationTargetStateSkipped,
+			Error:   fmt.Sprintf("The cluster is in provisioning state '%s'.", ocDoc.OpenShiftCluster.Properties.ProvisioningState),
+			EndTime: &now,
+		}, nil
+	default:
+		return nil, nil
+	}
+
+	// record the async operation ID on the target before starting the
+	// cluster, so that a later pass can recover the target if this one stops
+	// before recording that the cluster was started
+	id := fb.dbAsyncOperations.NewUUID()
+	_, err = fb.dbFleetOperations.PatchWithLease(ctx, doc.ID, func(doc *api.FleetOperationDocument) error {
+		for _, t := range doc.FleetOperation.Targets {
+			if t.ResourceID == target.ResourceID {
+				t.AsyncOperationID = id
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fb.dbOpenShiftClusters.Patch(ctx, ocDoc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		if doc.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateSucceeded {
+			return errFleetTargetBusy
+		}
+
+		doc.OpenShiftCluster.Properties.LastProvisioningState = doc.OpenShiftCluster.Properties.ProvisioningState
+		doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateAdminUpdating
+		doc.OpenShiftCluster.Properties.MaintenanceTask = op.MaintenanceTask
+		doc.OpenShiftCluster.Properties.LastAdminUpdateError = ""
+		doc.AsyncOperationID = id
+		doc.Dequeues = 0
+
+		return nil
+	})
+	if err == errFleetTargetBusy {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = fb.createAsyncOperation(ctx, ocDoc, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("started %s on %s", op.MaintenanceTask, ocDoc.OpenShiftCluster.ID)
+
+	return &api.FleetOperationTarget{
+		State:            api.FleetOperationTargetStateRunning,
+		AsyncOperationID: id,
+		StartTime:        &now,
+	}, nil
+}
+
+// createAsyncOperation creates the async operation of an admin update started
+// on a cluster
+func (fb *fleetOperationBackend) createAsyncOperation(ctx context.Context, ocDoc *api.OpenShiftClusterDocument, id string, now time.Time) error {
+	r, err := azure.ParseResourceID(ocDoc.OpenShiftCluster.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = fb.dbAsyncOperations.Create(ctx, &api.AsyncOperationDocument{
+		ID:                  id,
+		OpenShiftClusterKey: ocDoc.Key,
+		AsyncOperation: &api.AsyncOperation{
+			ID:                       "/subscriptions/" + r.SubscriptionID + "/providers/" + r.Provider + "/locations/" + strings.ToLower(fb.env.Location()) + "/operationsstatus/" + id,
+			Name:                     id,
+			InitialProvisioningState: api.ProvisioningStateAdminUpdating,
+			ProvisioningState:        api.ProvisioningStateAdminUpdating,
+			StartTime:                now,
+		},
+	})
+	return err
+}
+
+// applyFleetOperationUpdates applies the target updates of a pass to a fleet
+// operation and moves the operation on to its next state
+func applyFleetOperationUpdates(op *api.FleetOperation, updates map[string]*api.FleetOperationTarget, now time.Time) {
+	for _, target := range op.Targets {
+		update := updates[target.ResourceID]
+		if update == nil {
+			continue
+		}
+
+		// a pending target may have been skipped by a concurrent cancel; a
+		// cluster which was started anyway must still be tracked
+		if target.State == api.FleetOperationTargetStateSkipped &&
+			update.State != api.FleetOperationTargetStateRunning {
+			continue
+		}
+
+		target.State = update.State
+		target.Error = update.Error
+		if update.AsyncOperationID != "" {
+			target.AsyncOperationID = update.AsyncOperationID
+		}
+		if update.StartTime != nil {
+			target.StartTime = update.StartTime
+		}
+		if update.EndTime != nil {
+			target.EndTime = update.EndTime
+		}
+	}
+
+	pending := countFleetOperationTargets(op, api.FleetOperationTargetStatePending)
+	running := countFleetOperationTargets(op, api.FleetOperationTargetStateRunning)
+	failures := countFleetOperationTargets(op, api.FleetOperationTargetStateFailed)
+
+	switch op.State {
+	case api.FleetOperationStateRunning:
+		if failures > op.FailureBudget && pending > 0 {
+			op.State = api.FleetOperationStatePaused
+			op.Message = fmt.Sprintf("The fleet operation was paused after %d clusters failed to update.", failures)
+		}
+	case api.FleetOperationStateCanceled:
+		if running > 0 {
+			op.State = api.FleetOperationStateCanceling
+			op.EndTime = nil
+		}
+	}
+
+	switch op.State {
+	case api.FleetOperationStateRunning, api.FleetOperationStatePaused:
+		if pending == 0 && running == 0 {
+			if failures > 0 {
+				op.State = api.FleetOperationStateFailed
+				op.Message = fmt.Sprintf("%d clusters failed to update.", failures)
+			} else {
+				op.State = api.FleetOperationStateSucceeded
+				op.Message = ""
+			}
+			op.EndTime = &now
+		}
+	case api.FleetOperationStateCanceling:
+		if running == 0 {
+			op.State = api.FleetOperationStateCanceled
+			op.EndTime = &now
+		}
+	}
+}
+
+func countFleetOperationTargets(op *api.FleetOperation, state api.FleetOperationTargetState) (n int) {
+	for _, target := range op.Targets {
+		if target.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+// fail marks a fleet operation which cannot be worked as failed
+func (fb *fleetOperationBackend) fail(ctx context.Context, doc *api.FleetOperationDocument, message string) error {
+	_, err := fb.dbFleetOperations.PatchWithLease(ctx, doc.ID, func(doc *api.FleetOperationDocument) error {
+		now := fb.now().UTC()
+
+		doc.FleetOperation.State = api.FleetOperationStateFailed
+		doc.FleetOperation.Message = message
+		doc.FleetOperation.EndTime = &now
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return fb.endLease(ctx, nil, doc)
+}
+
+func (fb *fleetOperationBackend) heartbeat(ctx context.Context, cancel context.CancelFunc, log *logrus.Entry, doc *api.FleetOperationDocument) func() {
+	var stopped bool
+	stop, done := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer recover.Panic(log)
+
+		defer close(done)
+
+		t := time.NewTicker(10 * time.Second)
+		defer t.Stop()
+
+		for {
+			_, err := fb.dbFleetOperations.Lease(ctx, doc.ID)
+			if err != nil {
+				log.Error(err)
+				cancel()
+				return
+			}
+
+			select {
+			case <-t.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		if !stopped {
+			close(stop)
+			<-done
+			stopped = true
+		}
+	}
+}
+
+func (fb *fleetOperationBackend) endLease(ctx context.Context, stop func(), doc *api.FleetOperationDocument) error {
+	if stop != nil {
+		stop()
+	}
+
+	_, err := fb.dbFleetOperations.EndLease(ctx, doc.ID)
+	return err
+}
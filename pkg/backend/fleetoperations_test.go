@@ -0,0 +1,428 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestFleetOperationBackendTry(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	fleetOperationID := "07070707-0707-0707-0707-070707070001"
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	then := now.Add(-time.Hour)
+
+	clusterDoc := func(name string, state api.ProvisioningState, lastAdminUpdateError string) *api.OpenShiftClusterDocument {
+		resourceID := testdatabase.GetResourcePath(mockSubID, name)
+		return &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:       resourceID,
+				Name:     name,
+				Type:     "Microsoft.RedHatOpenShift/OpenShiftClusters",
+				Location: "eastus",
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState:    state,
+					LastAdminUpdateError: lastAdminUpdateError,
+				},
+			},
+		}
+	}
+
+	startedClusterDoc := func(name, asyncOperationID string) *api.OpenShiftClusterDocument {
+		doc := clusterDoc(name, api.ProvisioningStateAdminUpdating, "")
+		doc.AsyncOperationID = asyncOperationID
+		doc.OpenShiftCluster.Properties.LastProvisioningState = api.ProvisioningStateSucceeded
+		doc.OpenShiftCluster.Properties.MaintenanceTask = api.MaintenanceTaskEverything
+		return doc
+	}
+
+	asyncOperationDoc := func(id, name string) *api.AsyncOperationDocument {
+		return &api.AsyncOperationDocument{
+			ID:                  id,
+			OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, name)),
+			AsyncOperation: &api.AsyncOperation{
+				ID:                       "/subscriptions/" + mockSubID + "/providers/Microsoft.RedHatOpenShift/locations/eastus/operationsstatus/" + id,
+				Name:                     id,
+				InitialProvisioningState: api.ProvisioningStateAdminUpdating,
+				ProvisioningState:        api.ProvisioningStateAdminUpdating,
+				StartTime:                now,
+			},
+		}
+	}
+
+	target := func(name string, state api.FleetOperationTargetState, startTime, endTime *time.Time, err string) *api.FleetOperationTarget {
+		return &api.FleetOperationTarget{
+			ResourceID: testdatabase.GetResourcePath(mockSubID, name),
+			State:      state,
+			Error:      err,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		}
+	}
+
+	startedTarget := func(name, asyncOperationID string, startTime *time.Time) *api.FleetOperationTarget {
+		t := target(name, api.FleetOperationTargetStateRunning, startTime, nil, "")
+		t.AsyncOperationID = asyncOperationID
+		return t
+	}
+
+	fleetOperationDoc := func(state api.FleetOperationState, maxConcurrency, failureBudget int, targets ...*api.FleetOperationTarget) *api.FleetOperationDocument {
+		return &api.FleetOperationDocument{
+			ID: fleetOperationID,
+			FleetOperation: &api.FleetOperation{
+				ID: fleetOperationID,
+				Selector: api.FleetSelector{
+					Locations: []string{"eastus"},
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+				MaxConcurrency:  maxConcurrency,
+				FailureBudget:   failureBudget,
+				State:           state,
+				StartTime:       then,
+				Targets:         targets,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name       string
+		fixture    func(*testdatabase.Fixture)
+		checker    func(*testdatabase.Checker)
+		dequeues   int
+		wantNoWork bool
+	}{
+		{
+			name: "starts pending targets up to the concurrency limit",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("c", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 2, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+					target("c", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					startedClusterDoc("a", "02020202-0202-0202-0202-020202020001"),
+					startedClusterDoc("b", "02020202-0202-0202-0202-020202020002"),
+					clusterDoc("c", api.ProvisioningStateSucceeded, ""),
+				)
+				c.AddAsyncOperationDocuments(
+					asyncOperationDoc("02020202-0202-0202-0202-020202020001", "a"),
+					asyncOperationDoc("02020202-0202-0202-0202-020202020002", "b"),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 2, 0,
+					startedTarget("a", "02020202-0202-0202-0202-020202020001", &now),
+					startedTarget("b", "02020202-0202-0202-0202-020202020002", &now),
+					target("c", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+		},
+		{
+			name: "records finished targets and starts the next",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateAdminUpdating, ""),
+					clusterDoc("c", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 2, 0,
+					target("a", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					target("b", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					target("c", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateAdminUpdating, ""),
+					startedClusterDoc("c", "02020202-0202-0202-0202-020202020001"),
+				)
+				c.AddAsyncOperationDocuments(
+					asyncOperationDoc("02020202-0202-0202-0202-020202020001", "c"),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 2, 0,
+					target("a", api.FleetOperationTargetStateSucceeded, &then, &now, ""),
+					target("b", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					startedTarget("c", "02020202-0202-0202-0202-020202020001", &now),
+				))
+			},
+		},
+		{
+			name: "tracks pending targets whose cluster was started by an earlier pass",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					startedClusterDoc("a", "02020202-0202-0202-0202-0202020200aa"),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				pending := target("a", api.FleetOperationTargetStatePending, nil, nil, "")
+				pending.AsyncOperationID = "02020202-0202-0202-0202-0202020200aa"
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					pending,
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					startedClusterDoc("a", "02020202-0202-0202-0202-0202020200aa"),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				c.AddAsyncOperationDocuments(
+					asyncOperationDoc("02020202-0202-0202-0202-0202020200aa", "a"),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					startedTarget("a", "02020202-0202-0202-0202-0202020200aa", &now),
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+		},
+		{
+			name: "starts pending targets whose recorded async operation was not started",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+				)
+				pending := target("a", api.FleetOperationTargetStatePending, nil, nil, "")
+				pending.AsyncOperationID = "02020202-0202-0202-0202-0202020200aa"
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					pending,
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					startedClusterDoc("a", "02020202-0202-0202-0202-020202020001"),
+				)
+				c.AddAsyncOperationDocuments(
+					asyncOperationDoc("02020202-0202-0202-0202-020202020001", "a"),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					startedTarget("a", "02020202-0202-0202-0202-020202020001", &now),
+				))
+			},
+		},
+		{
+			name: "does not dequeue paused operations without running targets",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStatePaused, 1, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStatePaused, 1, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			wantNoWork: true,
+		},
+		{
+			name: "pauses when the failure budget is exceeded",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, "oh no!"),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					target("a", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, "oh no!"),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				doc := fleetOperationDoc(api.FleetOperationStatePaused, 1, 0,
+					target("a", api.FleetOperationTargetStateFailed, &then, &now, "oh no!"),
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+				)
+				doc.FleetOperation.Message = "The fleet operation was paused after 1 clusters failed to update."
+				c.AddFleetOperationDocuments(doc)
+			},
+		},
+		{
+			name: "skips deleted and failed clusters and waits for busy clusters",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("b", api.ProvisioningStateFailed, ""),
+					clusterDoc("c", api.ProvisioningStateUpdating, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+					target("b", api.FleetOperationTargetStatePending, nil, nil, ""),
+					target("c", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("b", api.ProvisioningStateFailed, ""),
+					clusterDoc("c", api.ProvisioningStateUpdating, ""),
+				)
+				c.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					target("a", api.FleetOperationTargetStateSkipped, nil, &now, "The cluster was deleted."),
+					target("b", api.FleetOperationTargetStateSkipped, nil, &now, "The cluster is in provisioning state 'Failed'."),
+					target("c", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+		},
+		{
+			name: "fails when all targets are done and one failed",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateSucceeded, "oh no!"),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 2, 1,
+					target("a", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					target("b", api.FleetOperationTargetStateRunning, &then, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateSucceeded, "oh no!"),
+				)
+				doc := fleetOperationDoc(api.FleetOperationStateFailed, 2, 1,
+					target("a", api.FleetOperationTargetStateSucceeded, &then, &now, ""),
+					target("b", api.FleetOperationTargetStateFailed, &then, &now, "oh no!"),
+				)
+				doc.FleetOperation.Message = "1 clusters failed to update."
+				doc.FleetOperation.EndTime = &now
+				c.AddFleetOperationDocuments(doc)
+			},
+		},
+		{
+			name: "cancels once the running targets have finished",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateCanceling, 1, 0,
+					target("a", api.FleetOperationTargetStateRunning, &then, nil, ""),
+					target("b", api.FleetOperationTargetStateSkipped, nil, &then, "The fleet operation was canceled."),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+					clusterDoc("b", api.ProvisioningStateSucceeded, ""),
+				)
+				doc := fleetOperationDoc(api.FleetOperationStateCanceled, 1, 0,
+					target("a", api.FleetOperationTargetStateSucceeded, &then, &now, ""),
+					target("b", api.FleetOperationTargetStateSkipped, nil, &then, "The fleet operation was canceled."),
+				)
+				doc.FleetOperation.EndTime = &now
+				c.AddFleetOperationDocuments(doc)
+			},
+		},
+		{
+			name:     "fails after too many dequeues",
+			dequeues: maxDequeueCount,
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+				)
+				f.AddFleetOperationDocuments(fleetOperationDoc(api.FleetOperationStateRunning, 1, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+				))
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(
+					clusterDoc("a", api.ProvisioningStateSucceeded, ""),
+				)
+				doc := fleetOperationDoc(api.FleetOperationStateFailed, 1, 0,
+					target("a", api.FleetOperationTargetStatePending, nil, nil, ""),
+				)
+				doc.FleetOperation.Message = "The fleet operation was dequeued 6 times."
+				doc.FleetOperation.EndTime = &now
+				c.AddFleetOperationDocuments(doc)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := logrus.NewEntry(logrus.StandardLogger())
+
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockInterface(controller)
+			_env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			dbOpenShiftClusters, clientOpenShiftClusters := testdatabase.NewFakeOpenShiftClusters()
+			dbAsyncOperations, clientAsyncOperations := testdatabase.NewFakeAsyncOperations()
+			dbFleetOperations, clientFleetOperations := testdatabase.NewFakeFleetOperations()
+
+			f := testdatabase.NewFixture().
+				WithOpenShiftClusters(dbOpenShiftClusters).
+				WithFleetOperations(dbFleetOperations)
+			tt.fixture(f)
+			err := f.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.dequeues > 0 {
+				_, err = dbFleetOperations.Patch(ctx, fleetOperationID, func(doc *api.FleetOperationDocument) error {
+					doc.Dequeues = tt.dequeues
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			b, err := newBackend(ctx, log, _env, dbAsyncOperations, nil, dbFleetOperations, nil, dbOpenShiftClusters, nil, nil, nil, &noop.Noop{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b.fb = newFleetOperationBackend(b)
+			b.fb.now = func() time.Time { return now }
+
+			worked, err := b.fb.try(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if worked == tt.wantNoWork {
+				t.Fatalf("got worked %v", worked)
+			}
+
+			// wait on the workers to finish their tasks
+			b.waitForWorkerCompletion()
+
+			c := testdatabase.NewChecker()
+			tt.checker(c)
+
+			errs := c.CheckOpenShiftClusters(clientOpenShiftClusters)
+			errs = append(errs, c.CheckAsyncOperations(clientAsyncOperations)...)
+			errs = append(errs, c.CheckFleetOperations(clientFleetOperations)...)
+			for _, err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
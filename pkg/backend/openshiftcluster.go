@@ -29,7 +29,7 @@ import (
 type openShiftClusterBackend struct {
 	*backend
 
-	newManager func(context.Context, *logrus.Entry, env.Interface, database.OpenShiftClusters, database.Gateway, database.OpenShiftVersions, encryption.AEAD, billing.Manager, *api.OpenShiftClusterDocument, *api.SubscriptionDocument, hive.ClusterManager, metrics.Emitter) (cluster.Interface, error)
+	newManager func(context.Context, *logrus.Entry, env.Interface, database.OpenShiftClusters, database.Gateway, database.OpenShiftVersions, database.AsyncOperations, encryption.AEAD, billing.Manager, *api.OpenShiftClusterDocument, *api.SubscriptionDocument, hive.ClusterManager, metrics.Emitter) (cluster.Interface, error)
 }
 
 func newOpenShiftClusterBackend(b *backend) *openShiftClusterBackend {
@@ -40,11 +40,11 @@ func newOpenShiftClusterBackend(b *backend) *openShiftClusterBackend {
 }
 
 // try tries to dequeue an OpenShiftClusterDocument for work, and works it on a
-// new goroutine.  It returns a boolean to the caller indicating whether it
-// succeeded in dequeuing anything - if this is false, the caller should sleep
-// before calling again
+// new goroutine.  The document is chosen by the scheduler.  It returns a
+// boolean to the caller indicating whether it succeeded in dequeuing anything
+// - if this is false, the caller should sleep before calling again
 func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
-	doc, err := ocb.dbOpenShiftClusters.Dequeue(ctx)
+	doc, err := ocb.dequeue(ctx)
 	if err != nil || doc == nil {
 		return false, err
 	}
@@ -63,6 +63,7 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 	log.Print("dequeued")
 	atomic.AddInt32(&ocb.workers, 1)
 	ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
+	ocb.scheduler.start(doc.PartitionKey)
 
 	go func() {
 		defer recover.Panic(log)
@@ -70,6 +71,7 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 		t := time.Now()
 
 		defer func() {
+			ocb.scheduler.done(doc.PartitionKey)
 			atomic.AddInt32(&ocb.workers, -1)
 			ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
 			ocb.cond.Signal()
@@ -86,6 +88,33 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// dequeue leases the queued document the scheduler ranks first, falling back
+// to the next one if another backend got there first.  It returns nil if there
+// is nothing which can be worked now.
+func (ocb *openShiftClusterBackend) dequeue(ctx context.Context) (*api.OpenShiftClusterDocument, error) {
+	docs, err := ocb.dbOpenShiftClusters.ListQueued(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, depths := ocb.scheduler.order(docs.OpenShiftClusterDocuments)
+
+	for class, depth := range depths {
+		dims := map[string]string{"priorityClass": class}
+		ocb.m.EmitGauge("backend.openshiftcluster.queue.depth", int64(depth.queued), dims)
+		ocb.m.EmitGauge("backend.openshiftcluster.queue.throttled", int64(depth.throttled), dims)
+	}
+
+	for _, candidate := range candidates {
+		doc, err := ocb.dbOpenShiftClusters.DequeueDocument(ctx, candidate)
+		if err != nil || doc != nil {
+			return doc, err
+		}
+	}
+
+	return nil, nil
+}
+
 // handle is responsible for handling backend operation and lease
 func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument) error {
 	ctx, cancel := context.WithCancel(ctx)
@@ -128,7 +157,7 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 		}
 	}
 
-	m, err := ocb.newManager(ctx, log, ocb.env, ocb.dbOpenShiftClusters, ocb.dbGateway, ocb.dbOpenShiftVersions, ocb.aead, ocb.billing, doc, subscriptionDoc, hr, ocb.m)
+	m, err := ocb.newManager(ctx, log, ocb.env, ocb.dbOpenShiftClusters, ocb.dbGateway, ocb.dbOpenShiftVersions, ocb.dbAsyncOperations, ocb.aead, ocb.billing, doc, subscriptionDoc, hr, ocb.m)
 	if err != nil {
 		return ocb.endLease(ctx, log, stop, doc, api.ProvisioningStateFailed, err)
 	}
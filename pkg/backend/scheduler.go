@@ -0,0 +1,198 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+const (
+	// defaultMaxWorkersPerSubscriptionPerInstance is the default number of
+	// documents of a single subscription which each backend instance may work
+	// concurrently.  The cap is not shared between instances: with N
+	// backends, up to N times the cap may be worked at once.  It can be
+	// overridden by setting maxWorkersPerSubscriptionPerInstanceEnvVar, and
+	// for individual subscriptions by setting
+	// maxWorkersPerSubscriptionPerInstanceOverridesEnvVar to a comma
+	// separated list of subscriptionID=N entries.
+	defaultMaxWorkersPerSubscriptionPerInstance         = 20
+	maxWorkersPerSubscriptionPerInstanceEnvVar          = "ARO_BACKEND_MAX_WORKERS_PER_SUBSCRIPTION_PER_INSTANCE"
+	maxWorkersPerSubscriptionPerInstanceOverridesEnvVar = "ARO_BACKEND_MAX_WORKERS_PER_SUBSCRIPTION_PER_INSTANCE_OVERRIDES"
+)
+
+// Priority classes of queued documents, highest priority first.  Deletes and
+// admin updates are worked ahead of customer updates, which are worked ahead
+// of creates.
+const (
+	priorityClassHigh   = "high"
+	priorityClassNormal = "normal"
+	priorityClassLow    = "low"
+)
+
+var priorityClasses = []string{priorityClassHigh, priorityClassNormal, priorityClassLow}
+
+func priorityClass(doc *api.OpenShiftClusterDocument) string {
+	switch doc.OpenShiftCluster.Properties.ProvisioningState {
+	case api.ProvisioningStateDeleting, api.ProvisioningStateAdminUpdating:
+		return priorityClassHigh
+	case api.ProvisioningStateUpdating:
+		return priorityClassNormal
+	default:
+		return priorityClassLow
+	}
+}
+
+func priority(class string) int {
+	for i, c := range priorityClasses {
+		if c == class {
+			return i
+		}
+	}
+	return len(priorityClasses)
+}
+
+// scheduler chooses which queued OpenShiftClusterDocument the backend should
+// work next.  It keeps track of the number of documents being worked per
+// subscription by this backend instance, so that no single subscription can
+// take every worker of the instance.
+type scheduler struct {
+	mu                                   sync.Mutex
+	running                              map[string]int
+	maxWorkersPerSubscriptionPerInstance int
+
+	// overrides holds the caps of the subscriptions which do not use
+	// maxWorkersPerSubscriptionPerInstance, by lower case subscription ID
+	overrides map[string]int
+}
+
+func newScheduler(maxWorkersPerSubscriptionPerInstance int, overrides map[string]int) *scheduler {
+	return &scheduler{
+		running:                              map[string]int{},
+		maxWorkersPerSubscriptionPerInstance: maxWorkersPerSubscriptionPerInstance,
+		overrides:                            overrides,
+	}
+}
+
+// maxWorkers returns the per-instance cap of the given subscription.
+func (s *scheduler) maxWorkers(subscriptionID string) int {
+	if n, ok := s.overrides[strings.ToLower(subscriptionID)]; ok {
+		return n
+	}
+
+	return s.maxWorkersPerSubscriptionPerInstance
+}
+
+// maxWorkersPerSubscriptionPerInstanceFromEnv returns the per-instance
+// per-subscription worker cap configured in the environment, or the default.
+func maxWorkersPerSubscriptionPerInstanceFromEnv() (int, error) {
+	v := os.Getenv(maxWorkersPerSubscriptionPerInstanceEnvVar)
+	if v == "" {
+		return defaultMaxWorkersPerSubscriptionPerInstance, nil
+	}
+
+	return strconv.Atoi(v)
+}
+
+// maxWorkersPerSubscriptionPerInstanceOverridesFromEnv returns the
+// per-instance worker caps of individual subscriptions configured in the
+// environment, by lower case subscription ID.  The caps are configured as a
+// comma separated list of subscriptionID=N entries.
+func maxWorkersPerSubscriptionPerInstanceOverridesFromEnv() (map[string]int, error) {
+	overrides := map[string]int{}
+
+	v := os.Getenv(maxWorkersPerSubscriptionPerInstanceOverridesEnvVar)
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		subscriptionID, limit, found := strings.Cut(entry, "=")
+		subscriptionID = strings.TrimSpace(subscriptionID)
+		if !found || subscriptionID == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: must be subscriptionID=N", maxWorkersPerSubscriptionPerInstanceOverridesEnvVar, entry)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid %s entry %q: N must be a positive integer", maxWorkersPerSubscriptionPerInstanceOverridesEnvVar, entry)
+		}
+
+		overrides[strings.ToLower(subscriptionID)] = n
+	}
+
+	return overrides, nil
+}
+
+// queueDepth is the number of queued documents in a priority class, and how
+// many of those are being held back by the per-subscription cap.
+type queueDepth struct {
+	queued    int
+	throttled int
+}
+
+// order returns the queued documents which may be started now, in the order
+// in which they should be tried: by priority class, then preferring the
+// subscriptions with the fewest documents being worked, then in queue order.
+// Documents of subscriptions which have reached their cap are omitted.  order
+// also returns the queue depth per priority class.
+func (s *scheduler) order(docs []*api.OpenShiftClusterDocument) ([]*api.OpenShiftClusterDocument, map[string]*queueDepth) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	depths := map[string]*queueDepth{}
+	for _, class := range priorityClasses {
+		depths[class] = &queueDepth{}
+	}
+
+	candidates := make([]*api.OpenShiftClusterDocument, 0, len(docs))
+	for _, doc := range docs {
+		depth := depths[priorityClass(doc)]
+		depth.queued++
+
+		if s.running[doc.PartitionKey] >= s.maxWorkers(doc.PartitionKey) {
+			depth.throttled++
+			continue
+		}
+
+		candidates = append(candidates, doc)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi, pj := priority(priorityClass(candidates[i])), priority(priorityClass(candidates[j]))
+		if pi != pj {
+			return pi < pj
+		}
+
+		return s.running[candidates[i].PartitionKey] < s.running[candidates[j].PartitionKey]
+	})
+
+	return candidates, depths
+}
+
+// start records that a document of the given subscription is being worked.
+func (s *scheduler) start(subscriptionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running[subscriptionID]++
+}
+
+// done records that a document of the given subscription has been worked.
+func (s *scheduler) done(subscriptionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.running[subscriptionID]--
+	if s.running[subscriptionID] <= 0 {
+		delete(s.running, subscriptionID)
+	}
+}
@@ -0,0 +1,225 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func queuedDocument(subscriptionID, name string, provisioningState api.ProvisioningState) *api.OpenShiftClusterDocument {
+	return &api.OpenShiftClusterDocument{
+		Key:          strings.ToLower(testdatabase.GetResourcePath(subscriptionID, name)),
+		PartitionKey: subscriptionID,
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID: testdatabase.GetResourcePath(subscriptionID, name),
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: provisioningState,
+			},
+		},
+	}
+}
+
+func TestSchedulerOrder(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		overrides  map[string]int
+		running    []string
+		docs       []*api.OpenShiftClusterDocument
+		wantOrder  []string
+		wantDepths map[string]*queueDepth
+	}{
+		{
+			name: "deletes and admin updates are ahead of updates, which are ahead of creates",
+			docs: []*api.OpenShiftClusterDocument{
+				queuedDocument("sub1", "create", api.ProvisioningStateCreating),
+				queuedDocument("sub1", "update", api.ProvisioningStateUpdating),
+				queuedDocument("sub1", "delete", api.ProvisioningStateDeleting),
+				queuedDocument("sub1", "adminupdate", api.ProvisioningStateAdminUpdating),
+			},
+			wantOrder: []string{"delete", "adminupdate", "update", "create"},
+			wantDepths: map[string]*queueDepth{
+				priorityClassHigh:   {queued: 2},
+				priorityClassNormal: {queued: 1},
+				priorityClassLow:    {queued: 1},
+			},
+		},
+		{
+			name:    "subscriptions with fewer running workers go first",
+			running: []string{"sub1"},
+			docs: []*api.OpenShiftClusterDocument{
+				queuedDocument("sub1", "create1", api.ProvisioningStateCreating),
+				queuedDocument("sub1", "create2", api.ProvisioningStateCreating),
+				queuedDocument("sub2", "create3", api.ProvisioningStateCreating),
+			},
+			wantOrder: []string{"create3", "create1", "create2"},
+			wantDepths: map[string]*queueDepth{
+				priorityClassHigh:   {},
+				priorityClassNormal: {},
+				priorityClassLow:    {queued: 3},
+			},
+		},
+		{
+			name:    "subscriptions at their cap are throttled",
+			running: []string{"sub1", "sub1"},
+			docs: []*api.OpenShiftClusterDocument{
+				queuedDocument("sub1", "delete", api.ProvisioningStateDeleting),
+				queuedDocument("sub2", "create", api.ProvisioningStateCreating),
+			},
+			wantOrder: []string{"create"},
+			wantDepths: map[string]*queueDepth{
+				priorityClassHigh:   {queued: 1, throttled: 1},
+				priorityClassNormal: {},
+				priorityClassLow:    {queued: 1},
+			},
+		},
+		{
+			name:      "subscriptions with an override are throttled at their own cap",
+			overrides: map[string]int{"sub2": 1, "sub3": 3},
+			running:   []string{"sub1", "sub1", "SUB2", "SUB3", "SUB3"},
+			docs: []*api.OpenShiftClusterDocument{
+				queuedDocument("sub1", "update1", api.ProvisioningStateUpdating),
+				queuedDocument("SUB2", "update2", api.ProvisioningStateUpdating),
+				queuedDocument("SUB3", "update3", api.ProvisioningStateUpdating),
+			},
+			wantOrder: []string{"update3"},
+			wantDepths: map[string]*queueDepth{
+				priorityClassHigh:   {},
+				priorityClassNormal: {queued: 3, throttled: 2},
+				priorityClassLow:    {},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScheduler(2, tt.overrides)
+			for _, subscriptionID := range tt.running {
+				s.start(subscriptionID)
+			}
+
+			candidates, depths := s.order(tt.docs)
+
+			var gotOrder []string
+			for _, doc := range candidates {
+				gotOrder = append(gotOrder, doc.OpenShiftCluster.ID[strings.LastIndex(doc.OpenShiftCluster.ID, "/")+1:])
+			}
+
+			for _, d := range deep.Equal(gotOrder, tt.wantOrder) {
+				t.Error(d)
+			}
+			for _, d := range deep.Equal(depths, tt.wantDepths) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestSchedulerDone(t *testing.T) {
+	s := newScheduler(1, nil)
+	s.start("sub1")
+	s.done("sub1")
+
+	if len(s.running) != 0 {
+		t.Errorf("got running %v, want empty", s.running)
+	}
+}
+
+func TestMaxWorkersPerSubscriptionPerInstanceOverridesFromEnv(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		value   string
+		want    map[string]int
+		wantErr string
+	}{
+		{
+			name: "unset",
+			want: map[string]int{},
+		},
+		{
+			name:  "overrides are parsed",
+			value: "00000000-0000-0000-0000-00000000000A=5, 11111111-1111-1111-1111-111111111111 = 50,",
+			want: map[string]int{
+				"00000000-0000-0000-0000-00000000000a": 5,
+				"11111111-1111-1111-1111-111111111111": 50,
+			},
+		},
+		{
+			name:    "entries without a cap are rejected",
+			value:   "00000000-0000-0000-0000-000000000000",
+			wantErr: `invalid ARO_BACKEND_MAX_WORKERS_PER_SUBSCRIPTION_PER_INSTANCE_OVERRIDES entry "00000000-0000-0000-0000-000000000000": must be subscriptionID=N`,
+		},
+		{
+			name:    "caps which are not positive are rejected",
+			value:   "00000000-0000-0000-0000-000000000000=0",
+			wantErr: `invalid ARO_BACKEND_MAX_WORKERS_PER_SUBSCRIPTION_PER_INSTANCE_OVERRIDES entry "00000000-0000-0000-0000-000000000000=0": N must be a positive integer`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(maxWorkersPerSubscriptionPerInstanceOverridesEnvVar, tt.value)
+
+			got, err := maxWorkersPerSubscriptionPerInstanceOverridesFromEnv()
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			for _, d := range deep.Equal(got, tt.want) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestOpenShiftClusterBackendDequeue(t *testing.T) {
+	ctx := context.Background()
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	_env := mock_env.NewMockInterface(controller)
+	m := mock_metrics.NewMockEmitter(controller)
+	m.EXPECT().EmitGauge("backend.openshiftcluster.queue.depth", int64(1), map[string]string{"priorityClass": priorityClassHigh})
+	m.EXPECT().EmitGauge("backend.openshiftcluster.queue.depth", int64(0), map[string]string{"priorityClass": priorityClassNormal})
+	m.EXPECT().EmitGauge("backend.openshiftcluster.queue.depth", int64(1), map[string]string{"priorityClass": priorityClassLow})
+	m.EXPECT().EmitGauge("backend.openshiftcluster.queue.throttled", int64(0), gomock.Any()).Times(3)
+
+	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+	dbSubscriptions, _ := testdatabase.NewFakeSubscriptions()
+
+	f := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters)
+	f.AddOpenShiftClusterDocuments(
+		queuedDocument(mockSubID, "create", api.ProvisioningStateCreating),
+		queuedDocument(mockSubID, "delete", api.ProvisioningStateDeleting),
+	)
+	err := f.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := newBackend(ctx, logrus.NewEntry(logrus.StandardLogger()), _env, nil, nil, nil, nil, dbOpenShiftClusters, dbSubscriptions, nil, nil, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ocb := &openShiftClusterBackend{backend: b}
+
+	doc, err := ocb.dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc == nil || doc.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateDeleting {
+		t.Errorf("got %v, want the deleting document", doc)
+	}
+	if doc != nil && doc.Dequeues != 1 {
+		t.Errorf("got %d dequeues, want 1", doc.Dequeues)
+	}
+}
@@ -0,0 +1,87 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+// stepCheckpointer persists completed steps in the cluster document so that a
+// backend which re-dequeues the document during the same asynchronous
+// operation can resume where the previous one left off.
+type stepCheckpointer struct {
+	m     *manager
+	topic string
+}
+
+// newStepCheckpointer returns a steps.Checkpointer for the given topic, or nil
+// if there is no asynchronous operation to tie checkpoints to.
+func (m *manager) newStepCheckpointer(topic string) steps.Checkpointer {
+	if m.db == nil || m.doc == nil || m.doc.AsyncOperationID == "" {
+		return nil
+	}
+
+	return &stepCheckpointer{
+		m:     m,
+		topic: m.stepsTopic(topic),
+	}
+}
+
+// stepsTopic qualifies topic with the install phase, if an install is in
+// progress, as each install phase runs a different list of steps.
+func (m *manager) stepsTopic(topic string) string {
+	if m.doc.OpenShiftCluster.Properties.Install != nil {
+		topic += "." + m.doc.OpenShiftCluster.Properties.Install.Phase.String()
+	}
+
+	return topic
+}
+
+func (c *stepCheckpointer) matches(cp *api.StepCheckpoint) bool {
+	return cp != nil &&
+		cp.AsyncOperationID == c.m.doc.AsyncOperationID &&
+		cp.Topic == c.topic
+}
+
+func (c *stepCheckpointer) Completed() []string {
+	if !c.matches(c.m.doc.StepCheckpoint) {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.m.doc.StepCheckpoint.CompletedSteps))
+	for _, s := range c.m.doc.StepCheckpoint.CompletedSteps {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+func (c *stepCheckpointer) Checkpoint(ctx context.Context, i int, name string, duration time.Duration) error {
+	var err error
+	c.m.doc, err = c.m.db.PatchWithLease(ctx, c.m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		if !c.matches(doc.StepCheckpoint) {
+			doc.StepCheckpoint = &api.StepCheckpoint{
+				AsyncOperationID: doc.AsyncOperationID,
+				Topic:            c.topic,
+			}
+		}
+
+		if len(doc.StepCheckpoint.CompletedSteps) > i {
+			doc.StepCheckpoint.CompletedSteps = doc.StepCheckpoint.CompletedSteps[:i]
+		}
+
+		doc.StepCheckpoint.CompletedSteps = append(doc.StepCheckpoint.CompletedSteps, api.CompletedStep{
+			Name:            name,
+			CompletedAt:     time.Now().UTC(),
+			DurationSeconds: int64(duration.Seconds()),
+		})
+
+		return nil
+	})
+	return err
+}
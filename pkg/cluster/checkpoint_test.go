@@ -0,0 +1,262 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestStepCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	key := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName1"
+
+	for _, tt := range []struct {
+		name          string
+		checkpoint    *api.StepCheckpoint
+		wantCompleted []string
+		wantTopic     string
+		wantNames     []string
+	}{
+		{
+			name:      "no checkpoint",
+			wantTopic: "install.InstallPhaseBootstrap",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "matching checkpoint is resumed and truncated",
+			checkpoint: &api.StepCheckpoint{
+				AsyncOperationID: "operation",
+				Topic:            "install.InstallPhaseBootstrap",
+				CompletedSteps: []api.CompletedStep{
+					{Name: "a"},
+					{Name: "stale"},
+				},
+			},
+			wantCompleted: []string{"a", "stale"},
+			wantTopic:     "install.InstallPhaseBootstrap",
+			wantNames:     []string{"a", "b"},
+		},
+		{
+			name: "checkpoint from another operation is ignored",
+			checkpoint: &api.StepCheckpoint{
+				AsyncOperationID: "other",
+				Topic:            "install.InstallPhaseBootstrap",
+				CompletedSteps: []api.CompletedStep{
+					{Name: "a"},
+				},
+			},
+			wantTopic: "install.InstallPhaseBootstrap",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "checkpoint from another install phase is ignored",
+			checkpoint: &api.StepCheckpoint{
+				AsyncOperationID: "operation",
+				Topic:            "install.InstallPhaseRemoveBootstrap",
+				CompletedSteps: []api.CompletedStep{
+					{Name: "a"},
+				},
+			},
+			wantTopic: "install.InstallPhaseBootstrap",
+			wantNames: []string{"a", "b"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			openShiftClustersDatabase, _ := testdatabase.NewFakeOpenShiftClusters()
+			fixture := testdatabase.NewFixture().WithOpenShiftClusters(openShiftClustersDatabase)
+			fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key:              strings.ToLower(key),
+				AsyncOperationID: "operation",
+				StepCheckpoint:   tt.checkpoint,
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: key,
+					Properties: api.OpenShiftClusterProperties{
+						ProvisioningState: api.ProvisioningStateCreating,
+						Install:           &api.Install{},
+					},
+				},
+			})
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := openShiftClustersDatabase.Dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, log := testlog.New()
+			m := &manager{
+				log: log,
+				doc: doc,
+				db:  openShiftClustersDatabase,
+			}
+
+			cp := m.newStepCheckpointer("install")
+
+			for _, d := range deep.Equal(cp.Completed(), tt.wantCompleted) {
+				t.Error(d)
+			}
+
+			err = cp.Checkpoint(ctx, 0, "a", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = cp.Checkpoint(ctx, 1, "b", time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err = openShiftClustersDatabase.Get(ctx, strings.ToLower(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if doc.StepCheckpoint.Topic != tt.wantTopic {
+				t.Errorf("got topic %q, want %q", doc.StepCheckpoint.Topic, tt.wantTopic)
+			}
+			if doc.StepCheckpoint.AsyncOperationID != "operation" {
+				t.Errorf("got asyncOperationId %q", doc.StepCheckpoint.AsyncOperationID)
+			}
+
+			var names []string
+			for _, s := range doc.StepCheckpoint.CompletedSteps {
+				names = append(names, s.Name)
+			}
+			for _, d := range deep.Equal(names, tt.wantNames) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestNewStepCheckpointerWithoutAsyncOperation(t *testing.T) {
+	m := &manager{
+		doc: &api.OpenShiftClusterDocument{
+			OpenShiftCluster: &api.OpenShiftCluster{},
+		},
+	}
+
+	if cp := m.newStepCheckpointer("adminUpdate"); cp != nil {
+		t.Error("expected nil checkpointer")
+	}
+}
+
+func TestRunStepsResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	key := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName1"
+
+	openShiftClustersDatabase, _ := testdatabase.NewFakeOpenShiftClusters()
+	_, log := testlog.New()
+	m := &manager{
+		log:            log,
+		db:             openShiftClustersDatabase,
+		metricsEmitter: &noop.Noop{},
+	}
+
+	var ran []string
+	initializeKubernetesClients := func(ctx context.Context) error {
+		ran = append(ran, "initializeKubernetesClients")
+		m.kubernetescli = fake.NewSimpleClientset()
+		return nil
+	}
+	initializeConfigClients := func(ctx context.Context) error {
+		ran = append(ran, "initializeConfigClients")
+		m.configcli = configfake.NewSimpleClientset()
+		return nil
+	}
+	createResources := func(ctx context.Context) error {
+		ran = append(ran, "createResources")
+		return nil
+	}
+	createMoreResources := func(ctx context.Context) error {
+		ran = append(ran, "createMoreResources")
+		return nil
+	}
+	useClients := func(ctx context.Context) error {
+		ran = append(ran, "useClients")
+		if m.kubernetescli == nil || m.configcli == nil {
+			return errors.New("clients not initialized")
+		}
+		return nil
+	}
+
+	s := []steps.Step{
+		steps.AlwaysRun(steps.Action(initializeKubernetesClients)),
+		steps.Action(createResources),
+		steps.Parallel(
+			steps.AlwaysRun(steps.Action(initializeConfigClients)),
+			steps.Action(createMoreResources),
+		),
+		steps.Action(useClients),
+	}
+
+	// the previous backend completed every step but the last
+	fixture := testdatabase.NewFixture().WithOpenShiftClusters(openShiftClustersDatabase)
+	fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+		Key:              strings.ToLower(key),
+		AsyncOperationID: "operation",
+		StepCheckpoint: &api.StepCheckpoint{
+			AsyncOperationID: "operation",
+			Topic:            "adminUpdate",
+			CompletedSteps: []api.CompletedStep{
+				{Name: s[0].String()},
+				{Name: s[1].String()},
+				{Name: s[2].String()},
+			},
+		},
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID: key,
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: api.ProvisioningStateAdminUpdating,
+			},
+		},
+	})
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.doc, err = openShiftClustersDatabase.Dequeue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.runSteps(ctx, s, "adminUpdate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range deep.Equal(ran, []string{"initializeKubernetesClients", "initializeConfigClients", "useClients"}) {
+		t.Error(d)
+	}
+
+	doc, err := openShiftClustersDatabase.Get(ctx, strings.ToLower(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, cs := range doc.StepCheckpoint.CompletedSteps {
+		names = append(names, cs.Name)
+	}
+	for _, d := range deep.Equal(names, []string{s[0].String(), s[1].String(), s[2].String(), s[3].String()}) {
+		t.Error(d)
+	}
+}
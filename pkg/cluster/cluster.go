@@ -56,6 +56,7 @@ type manager struct {
 	db                  database.OpenShiftClusters
 	dbGateway           database.Gateway
 	dbOpenShiftVersions database.OpenShiftVersions
+	dbAsyncOperations   database.AsyncOperations
 
 	billing           billing.Manager
 	doc               *api.OpenShiftClusterDocument
@@ -111,8 +112,8 @@ type manager struct {
 }
 
 // New returns a cluster manager
-func New(ctx context.Context, log *logrus.Entry, _env env.Interface, db database.OpenShiftClusters, dbGateway database.Gateway, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD,
-	billing billing.Manager, doc *api.OpenShiftClusterDocument, subscriptionDoc *api.SubscriptionDocument, hiveClusterManager hive.ClusterManager, metricsEmitter metrics.Emitter,
+func New(ctx context.Context, log *logrus.Entry, _env env.Interface, db database.OpenShiftClusters, dbGateway database.Gateway, dbOpenShiftVersions database.OpenShiftVersions, dbAsyncOperations database.AsyncOperations,
+	aead encryption.AEAD, billing billing.Manager, doc *api.OpenShiftClusterDocument, subscriptionDoc *api.SubscriptionDocument, hiveClusterManager hive.ClusterManager, metricsEmitter metrics.Emitter,
 ) (Interface, error) {
 	r, err := azure.ParseResourceID(doc.OpenShiftCluster.ID)
 	if err != nil {
@@ -152,6 +153,7 @@ func New(ctx context.Context, log *logrus.Entry, _env env.Interface, db database
 		db:                    db,
 		dbGateway:             dbGateway,
 		dbOpenShiftVersions:   dbOpenShiftVersions,
+		dbAsyncOperations:     dbAsyncOperations,
 		billing:               billing,
 		doc:                   doc,
 		subscriptionDoc:       subscriptionDoc,
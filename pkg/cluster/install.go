@@ -48,8 +48,8 @@ func (m *manager) adminUpdate() []steps.Step {
 	// Generic fix-up or setup actions that are fairly safe to always take, and
 	// don't require a running cluster
 	toRun := []steps.Step{
-		steps.Action(m.initializeKubernetesClients), // must be first
-		steps.Action(m.ensureBillingRecord),         // belt and braces
+		steps.AlwaysRun(steps.Action(m.initializeKubernetesClients)), // must be first
+		steps.Action(m.ensureBillingRecord),                          // belt and braces
 		steps.Action(m.ensureDefaults),
 
 		// TODO: this relies on an authorizer that isn't exposed in the manager
@@ -122,7 +122,7 @@ func (m *manager) adminUpdate() []steps.Step {
 
 	if isEverything || isOperator || isRenewCerts {
 		toRun = append(toRun,
-			steps.Action(m.initializeOperatorDeployer))
+			steps.AlwaysRun(steps.Action(m.initializeOperatorDeployer)))
 	}
 
 	if isRenewCerts {
@@ -180,11 +180,15 @@ func (m *manager) clusterWasCreatedByHive() bool {
 }
 
 func (m *manager) Update(ctx context.Context) error {
+	return m.runSteps(ctx, m.update(), "update")
+}
+
+func (m *manager) update() []steps.Step {
 	s := []steps.Step{
 		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.validateResources),
-		steps.Action(m.initializeKubernetesClients), // All init steps are first
-		steps.Action(m.initializeOperatorDeployer),  // depends on kube clients
-		steps.Action(m.initializeClusterSPClients),
+		steps.AlwaysRun(steps.Action(m.initializeKubernetesClients)), // All init steps are first
+		steps.AlwaysRun(steps.Action(m.initializeOperatorDeployer)),  // depends on kube clients
+		steps.AlwaysRun(steps.Action(m.initializeClusterSPClients)),
 
 		// TODO: this relies on an authorizer that isn't exposed in the manager
 		// struct, so we'll rebuild the fpAuthorizer and use the error catching
@@ -215,7 +219,7 @@ func (m *manager) Update(ctx context.Context) error {
 		)
 	}
 
-	return m.runSteps(ctx, s, "update")
+	return s
 }
 
 func (m *manager) runPodmanInstaller(ctx context.Context) error {
@@ -268,7 +272,7 @@ func (m *manager) bootstrap() []steps.Step {
 		steps.Action(m.populateMTUSize),
 
 		steps.Action(m.createDNS),
-		steps.Action(m.initializeClusterSPClients), // must run before clusterSPObjectID
+		steps.AlwaysRun(steps.Action(m.initializeClusterSPClients)), // must run before clusterSPObjectID
 
 		// TODO: this relies on an authorizer that isn't exposed in the manager
 		// struct, so we'll rebuild the fpAuthorizer and use the error catching
@@ -277,13 +281,17 @@ func (m *manager) bootstrap() []steps.Step {
 		steps.Action(m.ensureResourceGroup),
 		steps.Action(m.ensureServiceEndpoints),
 		steps.Action(m.setMasterSubnetPolicies),
-		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.deployBaseResourceTemplate),
+		// the certificates are issued while the base resources are deployed:
+		// the two are independent and neither updates the cluster document
+		steps.Parallel(
+			steps.AuthorizationRetryingAction(m.fpAuthorizer, m.deployBaseResourceTemplate),
+			steps.Action(m.createCertificates),
+		),
 		steps.Action(m.attachNSGs),
 		steps.Action(m.updateAPIIPEarly),
 		steps.Action(m.createOrUpdateRouterIPEarly),
 		steps.Action(m.ensureGatewayCreate),
 		steps.Action(m.createAPIServerPrivateEndpoint),
-		steps.Action(m.createCertificates),
 	}
 
 	if m.adoptViaHive || m.installViaHive {
@@ -324,8 +332,8 @@ func (m *manager) bootstrap() []steps.Step {
 
 	s = append(s,
 		steps.Action(m.ensureBillingRecord),
-		steps.Action(m.initializeKubernetesClients),
-		steps.Action(m.initializeOperatorDeployer), // depends on kube clients
+		steps.AlwaysRun(steps.Action(m.initializeKubernetesClients)),
+		steps.AlwaysRun(steps.Action(m.initializeOperatorDeployer)), // depends on kube clients
 		steps.Condition(m.apiServersReady, 30*time.Minute, true),
 		steps.Action(m.ensureAROOperator),
 		steps.Action(m.incrInstallPhase),
@@ -339,8 +347,8 @@ func (m *manager) Install(ctx context.Context) error {
 	steps := map[api.InstallPhase][]steps.Step{
 		api.InstallPhaseBootstrap: m.bootstrap(),
 		api.InstallPhaseRemoveBootstrap: {
-			steps.Action(m.initializeKubernetesClients),
-			steps.Action(m.initializeOperatorDeployer), // depends on kube clients
+			steps.AlwaysRun(steps.Action(m.initializeKubernetesClients)),
+			steps.AlwaysRun(steps.Action(m.initializeOperatorDeployer)), // depends on kube clients
 			steps.Action(m.removeBootstrap),
 			steps.Action(m.removeBootstrapIgnition),
 			steps.Action(m.configureAPIServerCertificate),
@@ -349,9 +357,11 @@ func (m *manager) Install(ctx context.Context) error {
 			steps.Condition(m.operatorConsoleExists, 30*time.Minute, true),
 			steps.Action(m.updateConsoleBranding),
 			steps.Condition(m.operatorConsoleReady, 20*time.Minute, true),
-			steps.Action(m.disableSamples),
-			steps.Action(m.disableOperatorHubSources),
-			steps.Action(m.disableUpdates),
+			steps.Parallel(
+				steps.Action(m.disableSamples),
+				steps.Action(m.disableOperatorHubSources),
+				steps.Action(m.disableUpdates),
+			),
 			steps.Condition(m.clusterVersionReady, 30*time.Minute, true),
 			steps.Condition(m.aroDeploymentReady, 20*time.Minute, true),
 			steps.Action(m.updateClusterData),
@@ -378,17 +388,17 @@ func (m *manager) runSteps(ctx context.Context, s []steps.Step, metricsTopic str
 	var err error
 	if metricsTopic != "" {
 		var stepsTimeRun map[string]int64
-		stepsTimeRun, err = steps.Run(ctx, m.log, 10*time.Second, s, m.now)
+		stepsTimeRun, err = steps.RunResumable(ctx, m.log, 10*time.Second, s, m.now, m.newStepCheckpointer(metricsTopic), m.newStepRecorder(metricsTopic))
 		if err == nil {
 			var totalInstallTime int64
 			for stepName, duration := range stepsTimeRun {
 				metricName := fmt.Sprintf("backend.openshiftcluster.%s.%s.duration.seconds", metricsTopic, stepName)
-				m.metricsEmitter.EmitGauge(metricName, duration, nil)
+				m.metricsEmitter.EmitHistogram(metricName, float64(duration), nil)
 				totalInstallTime += duration
 			}
 
 			metricName := fmt.Sprintf("backend.openshiftcluster.%s.duration.total.seconds", metricsTopic)
-			m.metricsEmitter.EmitGauge(metricName, totalInstallTime, nil)
+			m.metricsEmitter.EmitHistogram(metricName, float64(totalInstallTime), nil)
 		}
 	} else {
 		_, err = steps.Run(ctx, m.log, 10*time.Second, s, nil)
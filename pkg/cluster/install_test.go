@@ -55,6 +55,13 @@ func (e *fakeMetricsEmitter) EmitGauge(metricName string, metricValue int64, dim
 func (e *fakeMetricsEmitter) EmitFloat(metricName string, metricValue float64, dimensions map[string]string) {
 }
 
+func (e *fakeMetricsEmitter) EmitCounter(metricName string, metricValue int64, dimensions map[string]string) {
+}
+
+func (e *fakeMetricsEmitter) EmitHistogram(metricName string, metricValue float64, dimensions map[string]string) {
+	e.Metrics[metricName] = int64(metricValue)
+}
+
 var clusterOperator = &configv1.ClusterOperator{
 	ObjectMeta: metav1.ObjectMeta{
 		Name: "operator",
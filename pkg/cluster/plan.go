@@ -0,0 +1,116 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+// PlanStep describes a step which an operation would run
+type PlanStep struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+
+	// Timeout and FailOnTimeout are only set for conditions which would be
+	// waited on
+	Timeout       string `json:"timeout,omitempty"`
+	FailOnTimeout bool   `json:"failOnTimeout,omitempty"`
+
+	// Resources lists the Azure and Kubernetes resources the step may touch
+	Resources []string `json:"resources,omitempty"`
+
+	// Steps lists the steps of a parallel group
+	Steps []PlanStep `json:"steps,omitempty"`
+}
+
+// stepResources maps step function names to the Azure and Kubernetes
+// resources which they may read or modify.  Steps which only initialise
+// in-memory state are omitted.
+var stepResources = map[string][]string{
+	"ensureBillingRecord":                       {"Cosmos DB: Billing"},
+	"ensureDefaults":                            {"Cosmos DB: OpenShiftClusters"},
+	"fixupClusterSPObjectID":                    {"Microsoft Graph: servicePrincipals", "Cosmos DB: OpenShiftClusters"},
+	"clusterSPObjectID":                         {"Microsoft Graph: servicePrincipals", "Cosmos DB: OpenShiftClusters"},
+	"fixInfraID":                                {"Cosmos DB: OpenShiftClusters"},
+	"ensureResourceGroup":                       {"Microsoft.Resources/resourceGroups", "Microsoft.Authorization/roleAssignments"},
+	"createOrUpdateDenyAssignment":              {"Microsoft.Resources/deployments", "Microsoft.Authorization/denyAssignments"},
+	"createOrUpdateClusterServicePrincipalRBAC": {"Microsoft.Authorization/roleAssignments"},
+	"ensureServiceEndpoints":                    {"Microsoft.Network/virtualNetworks/subnets"},
+	"populateRegistryStorageAccountName":        {"imageregistry.operator.openshift.io/configs", "Cosmos DB: OpenShiftClusters"},
+	"migrateStorageAccounts":                    {"Microsoft.Resources/deployments", "Microsoft.Storage/storageAccounts"},
+	"fixSSH":                                    {"Microsoft.Network/loadBalancers", "Microsoft.Network/networkInterfaces"},
+	"populateDatabaseIntIP":                     {"Microsoft.Network/loadBalancers", "Cosmos DB: OpenShiftClusters"},
+	"startVMs":                                  {"Microsoft.Compute/virtualMachines"},
+	"apiServersReady":                           {"config.openshift.io/clusteroperators"},
+	"fixSREKubeconfig":                          {"Cosmos DB: OpenShiftClusters"},
+	"fixUserAdminKubeconfig":                    {"Cosmos DB: OpenShiftClusters"},
+	"createOrUpdateRouterIPFromCluster":         {"v1/services", "Microsoft.Network/dnszones", "Cosmos DB: OpenShiftClusters"},
+	"fixMCSCert":                                {"v1/secrets"},
+	"fixMCSUserData":                            {"v1/secrets"},
+	"ensureGatewayUpgrade":                      {"Microsoft.Resources/deployments", "Microsoft.Network/privateEndpoints", "Microsoft.Network/virtualNetworks/subnets"},
+	"rotateACRTokenPassword":                    {"Microsoft.ContainerRegistry/registries/tokens", "v1/secrets", "Cosmos DB: OpenShiftClusters"},
+	"configureAPIServerCertificate":             {"v1/secrets", "config.openshift.io/apiservers"},
+	"configureIngressCertificate":               {"v1/secrets", "operator.openshift.io/ingresscontrollers"},
+	"ensureMTUSize":                             {"machineconfiguration.openshift.io/machineconfigs"},
+	"renewMDSDCertificate":                      {"v1/secrets"},
+	"ensureAROOperator":                         {"aro.openshift.io/clusters", "apps/v1/deployments", "v1/secrets"},
+	"aroDeploymentReady":                        {"apps/v1/deployments"},
+	"ensureAROOperatorRunningDesiredVersion":    {"apps/v1/deployments"},
+	"hiveCreateNamespace":                       {"Hive: v1/namespaces", "Cosmos DB: OpenShiftClusters"},
+	"hiveEnsureResources":                       {"Hive: hive.openshift.io/clusterdeployments", "Hive: v1/secrets"},
+	"hiveClusterDeploymentReady":                {"Hive: hive.openshift.io/clusterdeployments"},
+	"hiveResetCorrelationData":                  {"Hive: hive.openshift.io/clusterdeployments"},
+	"updateProvisionedBy":                       {"Cosmos DB: OpenShiftClusters"},
+	"validateResources":                         {"Microsoft.Network/virtualNetworks", "Microsoft.Network/routeTables"},
+	"updateOpenShiftSecret":                     {"v1/secrets"},
+	"updateAROSecret":                           {"v1/secrets"},
+	"reconcileLoadBalancerProfile":              {"Microsoft.Resources/deployments", "Microsoft.Network/loadBalancers", "Microsoft.Network/publicIPAddresses"},
+}
+
+// AdminUpdatePlan returns the steps which the backend would run for an admin
+// update of doc with its current MaintenanceTask, in order.  Nothing is
+// executed.
+func AdminUpdatePlan(doc *api.OpenShiftClusterDocument, adoptViaHive bool) []PlanStep {
+	m := &manager{
+		doc:          doc,
+		adoptViaHive: adoptViaHive,
+	}
+
+	// A PUCM pending admin update is run by the backend as an ordinary update
+	if doc.OpenShiftCluster.Properties.MaintenanceTask == api.MaintenanceTaskPucmPending {
+		return plan(m.update())
+	}
+
+	return plan(m.adminUpdate())
+}
+
+func plan(s []steps.Step) []PlanStep {
+	p := make([]PlanStep, 0, len(s))
+	for _, step := range s {
+		p = append(p, planStep(steps.Describe(step)))
+	}
+
+	return p
+}
+
+func planStep(d steps.Description) PlanStep {
+	ps := PlanStep{
+		Name:      d.Func,
+		Kind:      d.Kind,
+		Resources: stepResources[d.Func],
+	}
+
+	switch d.Kind {
+	case steps.KindCondition:
+		ps.Timeout = d.Timeout.String()
+		ps.FailOnTimeout = d.FailOnTimeout
+	case steps.KindParallel:
+		for _, child := range d.Steps {
+			ps.Steps = append(ps.Steps, planStep(child))
+		}
+	}
+
+	return ps
+}
@@ -0,0 +1,60 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+// maxStepTimelineEntries bounds the size of the step timeline kept on an
+// asynchronous operation document; the oldest entries are discarded first.
+const maxStepTimelineEntries = 1000
+
+// stepRecorder appends a timeline entry to the asynchronous operation
+// document for each step run, so that the progress of an operation can be
+// inspected via the admin API.
+type stepRecorder struct {
+	m     *manager
+	topic string
+}
+
+// newStepRecorder returns a steps.Recorder for the given topic, or nil if
+// there is no asynchronous operation to record steps against.
+func (m *manager) newStepRecorder(topic string) steps.Recorder {
+	if m.dbAsyncOperations == nil || m.doc == nil || m.doc.AsyncOperationID == "" {
+		return nil
+	}
+
+	return &stepRecorder{
+		m:     m,
+		topic: m.stepsTopic(topic),
+	}
+}
+
+func (r *stepRecorder) Record(ctx context.Context, rec steps.Record) {
+	_, err := r.m.dbAsyncOperations.Patch(ctx, r.m.doc.AsyncOperationID, func(asyncdoc *api.AsyncOperationDocument) error {
+		asyncdoc.StepTimeline = append(asyncdoc.StepTimeline, api.StepTimelineEntry{
+			Topic:      r.topic,
+			Name:       rec.Name,
+			StartTime:  rec.StartTime.UTC(),
+			EndTime:    rec.EndTime.UTC(),
+			Outcome:    rec.Outcome,
+			ErrorClass: rec.ErrorClass,
+			Retries:    rec.Retries,
+		})
+
+		if len(asyncdoc.StepTimeline) > maxStepTimelineEntries {
+			asyncdoc.StepTimeline = asyncdoc.StepTimeline[len(asyncdoc.StepTimeline)-maxStepTimelineEntries:]
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the timeline is informational: don't fail the operation
+		r.m.log.Warnf("failed to record step %s: %s", rec.Name, err)
+	}
+}
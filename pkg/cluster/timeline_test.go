@@ -0,0 +1,97 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestStepRecorder(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	asyncOperationsDatabase, _ := testdatabase.NewFakeAsyncOperations()
+	fixture := testdatabase.NewFixture().WithAsyncOperations(asyncOperationsDatabase)
+	fixture.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+		ID: "operation",
+		StepTimeline: []api.StepTimelineEntry{
+			{Topic: "install.InstallPhaseBootstrap", Name: "action.earlier", Outcome: steps.OutcomeSucceeded},
+		},
+	})
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, log := testlog.New()
+	m := &manager{
+		log:               log,
+		dbAsyncOperations: asyncOperationsDatabase,
+		doc: &api.OpenShiftClusterDocument{
+			AsyncOperationID: "operation",
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					Install: &api.Install{
+						Phase: api.InstallPhaseRemoveBootstrap,
+					},
+				},
+			},
+		},
+	}
+
+	rec := m.newStepRecorder("install")
+	rec.Record(ctx, steps.Record{
+		Name:       "condition.apiServersReady",
+		StartTime:  start,
+		EndTime:    start.Add(time.Minute),
+		Outcome:    steps.OutcomeFailed,
+		ErrorClass: "Timeout",
+		Retries:    5,
+	})
+
+	asyncdoc, err := asyncOperationsDatabase.Get(ctx, "operation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []api.StepTimelineEntry{
+		{Topic: "install.InstallPhaseBootstrap", Name: "action.earlier", Outcome: steps.OutcomeSucceeded},
+		{
+			Topic:      "install.InstallPhaseRemoveBootstrap",
+			Name:       "condition.apiServersReady",
+			StartTime:  start,
+			EndTime:    start.Add(time.Minute),
+			Outcome:    steps.OutcomeFailed,
+			ErrorClass: "Timeout",
+			Retries:    5,
+		},
+	}
+
+	for _, d := range deep.Equal(asyncdoc.StepTimeline, want) {
+		t.Error(d)
+	}
+}
+
+func TestNewStepRecorderWithoutAsyncOperation(t *testing.T) {
+	asyncOperationsDatabase, _ := testdatabase.NewFakeAsyncOperations()
+	m := &manager{
+		dbAsyncOperations: asyncOperationsDatabase,
+		doc: &api.OpenShiftClusterDocument{
+			OpenShiftCluster: &api.OpenShiftCluster{},
+		},
+	}
+
+	if rec := m.newStepRecorder("adminUpdate"); rec != nil {
+		t.Error("expected nil recorder")
+	}
+}
@@ -0,0 +1,72 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const AdminActionsGetQuery = `SELECT * FROM AdminActions doc WHERE doc.key = @key`
+
+type adminActions struct {
+	c             cosmosdb.AdminActionDocumentClient
+	uuidGenerator uuid.Generator
+}
+
+// AdminActions is the database interface for AdminActionDocuments
+type AdminActions interface {
+	Create(context.Context, *api.AdminActionDocument) (*api.AdminActionDocument, error)
+	ListByKey(context.Context, string) (*api.AdminActionDocuments, error)
+	NewUUID() string
+}
+
+// NewAdminActions returns a new AdminActions
+func NewAdminActions(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (AdminActions, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewAdminActionDocumentClient(collc, collAdminActions)
+	return NewAdminActionsWithProvidedClient(documentClient, uuid.DefaultGenerator), nil
+}
+
+func NewAdminActionsWithProvidedClient(client cosmosdb.AdminActionDocumentClient, uuidGenerator uuid.Generator) AdminActions {
+	return &adminActions{
+		c:             client,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *adminActions) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *adminActions) Create(ctx context.Context, doc *api.AdminActionDocument) (*api.AdminActionDocument, error) {
+	if doc.Key != strings.ToLower(doc.Key) {
+		return nil, fmt.Errorf("key %q is not lower case", doc.Key)
+	}
+
+	return c.c.Create(ctx, doc.Key, doc, nil)
+}
+
+// ListByKey returns the admin actions taken on the cluster with the given key
+func (c *adminActions) ListByKey(ctx context.Context, key string) (*api.AdminActionDocuments, error) {
+	if key != strings.ToLower(key) {
+		return nil, fmt.Errorf("key %q is not lower case", key)
+	}
+
+	return c.c.QueryAll(ctx, key, &cosmosdb.Query{
+		Query: AdminActionsGetQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@key",
+				Value: key,
+			},
+		},
+	}, nil)
+}
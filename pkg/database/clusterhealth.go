@@ -0,0 +1,83 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+type clusterHealth struct {
+	c cosmosdb.ClusterHealthDocumentClient
+}
+
+// ClusterHealth is the database interface for ClusterHealthDocuments
+type ClusterHealth interface {
+	Create(context.Context, *api.ClusterHealthDocument) (*api.ClusterHealthDocument, error)
+	Get(context.Context, string) (*api.ClusterHealthDocument, error)
+	Patch(context.Context, string, func(*api.ClusterHealthDocument) error) (*api.ClusterHealthDocument, error)
+}
+
+// NewClusterHealth returns a new ClusterHealth
+func NewClusterHealth(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (ClusterHealth, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewClusterHealthDocumentClient(collc, collClusterHealth)
+	return NewClusterHealthWithProvidedClient(documentClient), nil
+}
+
+func NewClusterHealthWithProvidedClient(client cosmosdb.ClusterHealthDocumentClient) ClusterHealth {
+	return &clusterHealth{
+		c: client,
+	}
+}
+
+func (c *clusterHealth) Create(ctx context.Context, doc *api.ClusterHealthDocument) (*api.ClusterHealthDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Create(ctx, doc.ID, doc, nil)
+}
+
+func (c *clusterHealth) Get(ctx context.Context, id string) (*api.ClusterHealthDocument, error) {
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, id, id, nil)
+}
+
+func (c *clusterHealth) Patch(ctx context.Context, id string, f func(*api.ClusterHealthDocument) error) (*api.ClusterHealthDocument, error) {
+	var doc *api.ClusterHealthDocument
+
+	err := cosmosdb.RetryOnPreconditionFailed(func() (err error) {
+		doc, err = c.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		err = f(doc)
+		if err != nil {
+			return
+		}
+
+		doc, err = c.update(ctx, doc)
+		return
+	})
+
+	return doc, err
+}
+
+func (c *clusterHealth) update(ctx context.Context, doc *api.ClusterHealthDocument) (*api.ClusterHealthDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Replace(ctx, doc.ID, doc, nil)
+}
@@ -0,0 +1,33 @@
+package cosmosdb
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TokenPermissionClient is a permission client which can also choose how long
+// the resource token returned with a permission is valid for
+type TokenPermissionClient interface {
+	PermissionClient
+	GetWithExpiry(context.Context, string, time.Duration) (*Permission, error)
+}
+
+// NewTokenPermissionClient returns a new token permission client
+func NewTokenPermissionClient(userc UserClient, userid string) TokenPermissionClient {
+	return NewPermissionClient(userc, userid).(*permissionClient)
+}
+
+// GetWithExpiry returns a permission whose resource token is valid for expiry.
+// Cosmos DB accepts validities of up to five hours and defaults to one hour.
+func (c *permissionClient) GetWithExpiry(ctx context.Context, permissionid string, expiry time.Duration) (permission *Permission, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Expiry-Seconds", strconv.Itoa(int(expiry/time.Second)))
+
+	err = c.do(ctx, http.MethodGet, c.path+"/permissions/"+permissionid, "permissions", c.path+"/permissions/"+permissionid, http.StatusOK, nil, &permission, headers)
+	return
+}
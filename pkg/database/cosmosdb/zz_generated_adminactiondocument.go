@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type adminActionDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// AdminActionDocumentClient is a adminActionDocument client
+type AdminActionDocumentClient interface {
+	Create(context.Context, string, *pkg.AdminActionDocument, *Options) (*pkg.AdminActionDocument, error)
+	List(*Options) AdminActionDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.AdminActionDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.AdminActionDocument, error)
+	Replace(context.Context, string, *pkg.AdminActionDocument, *Options) (*pkg.AdminActionDocument, error)
+	Delete(context.Context, string, *pkg.AdminActionDocument, *Options) error
+	Query(string, *Query, *Options) AdminActionDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.AdminActionDocuments, error)
+	ChangeFeed(*Options) AdminActionDocumentIterator
+}
+
+type adminActionDocumentChangeFeedIterator struct {
+	*adminActionDocumentClient
+	continuation string
+	options      *Options
+}
+
+type adminActionDocumentListIterator struct {
+	*adminActionDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type adminActionDocumentQueryIterator struct {
+	*adminActionDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// AdminActionDocumentIterator is a adminActionDocument iterator
+type AdminActionDocumentIterator interface {
+	Next(context.Context, int) (*pkg.AdminActionDocuments, error)
+	Continuation() string
+}
+
+// AdminActionDocumentRawIterator is a adminActionDocument raw iterator
+type AdminActionDocumentRawIterator interface {
+	AdminActionDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewAdminActionDocumentClient returns a new adminActionDocument client
+func NewAdminActionDocumentClient(collc CollectionClient, collid string) AdminActionDocumentClient {
+	return &adminActionDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *adminActionDocumentClient) all(ctx context.Context, i AdminActionDocumentIterator) (*pkg.AdminActionDocuments, error) {
+	alladminActionDocuments := &pkg.AdminActionDocuments{}
+
+	for {
+		adminActionDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if adminActionDocuments == nil {
+			break
+		}
+
+		alladminActionDocuments.Count += adminActionDocuments.Count
+		alladminActionDocuments.ResourceID = adminActionDocuments.ResourceID
+		alladminActionDocuments.AdminActionDocuments = append(alladminActionDocuments.AdminActionDocuments, adminActionDocuments.AdminActionDocuments...)
+	}
+
+	return alladminActionDocuments, nil
+}
+
+func (c *adminActionDocumentClient) Create(ctx context.Context, partitionkey string, newadminActionDocument *pkg.AdminActionDocument, options *Options) (adminActionDocument *pkg.AdminActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newadminActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newadminActionDocument, &adminActionDocument, headers)
+	return
+}
+
+func (c *adminActionDocumentClient) List(options *Options) AdminActionDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminActionDocumentListIterator{adminActionDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *adminActionDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.AdminActionDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *adminActionDocumentClient) Get(ctx context.Context, partitionkey, adminActionDocumentid string, options *Options) (adminActionDocument *pkg.AdminActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+adminActionDocumentid, "docs", c.path+"/docs/"+adminActionDocumentid, http.StatusOK, nil, &adminActionDocument, headers)
+	return
+}
+
+func (c *adminActionDocumentClient) Replace(ctx context.Context, partitionkey string, newadminActionDocument *pkg.AdminActionDocument, options *Options) (adminActionDocument *pkg.AdminActionDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newadminActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newadminActionDocument.ID, "docs", c.path+"/docs/"+newadminActionDocument.ID, http.StatusOK, &newadminActionDocument, &adminActionDocument, headers)
+	return
+}
+
+func (c *adminActionDocumentClient) Delete(ctx context.Context, partitionkey string, adminActionDocument *pkg.AdminActionDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, adminActionDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+adminActionDocument.ID, "docs", c.path+"/docs/"+adminActionDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *adminActionDocumentClient) Query(partitionkey string, query *Query, options *Options) AdminActionDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminActionDocumentQueryIterator{adminActionDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *adminActionDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.AdminActionDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *adminActionDocumentClient) ChangeFeed(options *Options) AdminActionDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &adminActionDocumentChangeFeedIterator{adminActionDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *adminActionDocumentClient) setOptions(options *Options, adminActionDocument *pkg.AdminActionDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if adminActionDocument != nil && !options.NoETag {
+		if adminActionDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", adminActionDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *adminActionDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (adminActionDocuments *pkg.AdminActionDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &adminActionDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *adminActionDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *adminActionDocumentListIterator) Next(ctx context.Context, maxItemCount int) (adminActionDocuments *pkg.AdminActionDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &adminActionDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *adminActionDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *adminActionDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (adminActionDocuments *pkg.AdminActionDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &adminActionDocuments)
+	return
+}
+
+func (i *adminActionDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *adminActionDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeAdminActionDocumentTriggerHandler func(context.Context, *pkg.AdminActionDocument) error
+type fakeAdminActionDocumentQueryHandler func(AdminActionDocumentClient, *Query, *Options) AdminActionDocumentRawIterator
+
+var _ AdminActionDocumentClient = &FakeAdminActionDocumentClient{}
+
+// NewFakeAdminActionDocumentClient returns a FakeAdminActionDocumentClient
+func NewFakeAdminActionDocumentClient(h *codec.JsonHandle) *FakeAdminActionDocumentClient {
+	return &FakeAdminActionDocumentClient{
+		jsonHandle:           h,
+		adminActionDocuments: make(map[string]*pkg.AdminActionDocument),
+		triggerHandlers:      make(map[string]fakeAdminActionDocumentTriggerHandler),
+		queryHandlers:        make(map[string]fakeAdminActionDocumentQueryHandler),
+	}
+}
+
+// FakeAdminActionDocumentClient is a FakeAdminActionDocumentClient
+type FakeAdminActionDocumentClient struct {
+	lock                 sync.RWMutex
+	jsonHandle           *codec.JsonHandle
+	adminActionDocuments map[string]*pkg.AdminActionDocument
+	triggerHandlers      map[string]fakeAdminActionDocumentTriggerHandler
+	queryHandlers        map[string]fakeAdminActionDocumentQueryHandler
+	sorter               func([]*pkg.AdminActionDocument)
+	etag                 int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.AdminActionDocument, *pkg.AdminActionDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeAdminActionDocumentClient method invocation
+func (c *FakeAdminActionDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeAdminActionDocumentClient) SetSorter(sorter func([]*pkg.AdminActionDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a AdminActionDocument
+func (c *FakeAdminActionDocumentClient) SetConflictChecker(conflictChecker func(*pkg.AdminActionDocument, *pkg.AdminActionDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeAdminActionDocumentClient) SetTriggerHandler(triggerName string, trigger fakeAdminActionDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeAdminActionDocumentClient) SetQueryHandler(queryName string, query fakeAdminActionDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeAdminActionDocumentClient) deepCopy(adminActionDocument *pkg.AdminActionDocument) (*pkg.AdminActionDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(adminActionDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	adminActionDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&adminActionDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return adminActionDocument, nil
+}
+
+func (c *FakeAdminActionDocumentClient) apply(ctx context.Context, partitionkey string, adminActionDocument *pkg.AdminActionDocument, options *Options, isCreate bool) (*pkg.AdminActionDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	adminActionDocument, err := c.deepCopy(adminActionDocument) // copy now because pretriggers can mutate adminActionDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, adminActionDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingAdminActionDocument, exists := c.adminActionDocuments[adminActionDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if adminActionDocument.ETag != existingAdminActionDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, adminActionDocumentToCheck := range c.adminActionDocuments {
+			if c.conflictChecker(adminActionDocumentToCheck, adminActionDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	adminActionDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.adminActionDocuments[adminActionDocument.ID] = adminActionDocument
+
+	return c.deepCopy(adminActionDocument)
+}
+
+// Create creates a AdminActionDocument in the database
+func (c *FakeAdminActionDocumentClient) Create(ctx context.Context, partitionkey string, adminActionDocument *pkg.AdminActionDocument, options *Options) (*pkg.AdminActionDocument, error) {
+	return c.apply(ctx, partitionkey, adminActionDocument, options, true)
+}
+
+// Replace replaces a AdminActionDocument in the database
+func (c *FakeAdminActionDocumentClient) Replace(ctx context.Context, partitionkey string, adminActionDocument *pkg.AdminActionDocument, options *Options) (*pkg.AdminActionDocument, error) {
+	return c.apply(ctx, partitionkey, adminActionDocument, options, false)
+}
+
+// List returns a AdminActionDocumentIterator to list all AdminActionDocuments in the database
+func (c *FakeAdminActionDocumentClient) List(*Options) AdminActionDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminActionDocumentErroringRawIterator(c.err)
+	}
+
+	adminActionDocuments := make([]*pkg.AdminActionDocument, 0, len(c.adminActionDocuments))
+	for _, adminActionDocument := range c.adminActionDocuments {
+		adminActionDocument, err := c.deepCopy(adminActionDocument)
+		if err != nil {
+			return NewFakeAdminActionDocumentErroringRawIterator(err)
+		}
+		adminActionDocuments = append(adminActionDocuments, adminActionDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(adminActionDocuments)
+	}
+
+	return NewFakeAdminActionDocumentIterator(adminActionDocuments, 0)
+}
+
+// ListAll lists all AdminActionDocuments in the database
+func (c *FakeAdminActionDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.AdminActionDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a AdminActionDocument from the database
+func (c *FakeAdminActionDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.AdminActionDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	adminActionDocument, exists := c.adminActionDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(adminActionDocument)
+}
+
+// Delete deletes a AdminActionDocument from the database
+func (c *FakeAdminActionDocumentClient) Delete(ctx context.Context, partitionKey string, adminActionDocument *pkg.AdminActionDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.adminActionDocuments[adminActionDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.adminActionDocuments, adminActionDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeAdminActionDocumentClient) ChangeFeed(*Options) AdminActionDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminActionDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeAdminActionDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeAdminActionDocumentClient) processPreTriggers(ctx context.Context, adminActionDocument *pkg.AdminActionDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, adminActionDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeAdminActionDocumentClient) Query(name string, query *Query, options *Options) AdminActionDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeAdminActionDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeAdminActionDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeAdminActionDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.AdminActionDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeAdminActionDocumentIterator(adminActionDocuments []*pkg.AdminActionDocument, continuation int) AdminActionDocumentRawIterator {
+	return &fakeAdminActionDocumentIterator{adminActionDocuments: adminActionDocuments, continuation: continuation}
+}
+
+type fakeAdminActionDocumentIterator struct {
+	adminActionDocuments []*pkg.AdminActionDocument
+	continuation         int
+	done                 bool
+}
+
+func (i *fakeAdminActionDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeAdminActionDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.AdminActionDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var adminActionDocuments []*pkg.AdminActionDocument
+	if maxItemCount == -1 {
+		adminActionDocuments = i.adminActionDocuments[i.continuation:]
+		i.continuation = len(i.adminActionDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.adminActionDocuments) {
+			max = len(i.adminActionDocuments)
+		}
+		adminActionDocuments = i.adminActionDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.AdminActionDocuments{
+		AdminActionDocuments: adminActionDocuments,
+		Count:                len(adminActionDocuments),
+	}, nil
+}
+
+func (i *fakeAdminActionDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.adminActionDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeAdminActionDocumentErroringRawIterator returns a AdminActionDocumentRawIterator which
+// whose methods return the given error
+func NewFakeAdminActionDocumentErroringRawIterator(err error) AdminActionDocumentRawIterator {
+	return &fakeAdminActionDocumentErroringRawIterator{err: err}
+}
+
+type fakeAdminActionDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeAdminActionDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.AdminActionDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeAdminActionDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeAdminActionDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
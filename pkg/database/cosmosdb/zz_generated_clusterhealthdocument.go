@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type clusterHealthDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// ClusterHealthDocumentClient is a clusterHealthDocument client
+type ClusterHealthDocumentClient interface {
+	Create(context.Context, string, *pkg.ClusterHealthDocument, *Options) (*pkg.ClusterHealthDocument, error)
+	List(*Options) ClusterHealthDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.ClusterHealthDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.ClusterHealthDocument, error)
+	Replace(context.Context, string, *pkg.ClusterHealthDocument, *Options) (*pkg.ClusterHealthDocument, error)
+	Delete(context.Context, string, *pkg.ClusterHealthDocument, *Options) error
+	Query(string, *Query, *Options) ClusterHealthDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.ClusterHealthDocuments, error)
+	ChangeFeed(*Options) ClusterHealthDocumentIterator
+}
+
+type clusterHealthDocumentChangeFeedIterator struct {
+	*clusterHealthDocumentClient
+	continuation string
+	options      *Options
+}
+
+type clusterHealthDocumentListIterator struct {
+	*clusterHealthDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type clusterHealthDocumentQueryIterator struct {
+	*clusterHealthDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// ClusterHealthDocumentIterator is a clusterHealthDocument iterator
+type ClusterHealthDocumentIterator interface {
+	Next(context.Context, int) (*pkg.ClusterHealthDocuments, error)
+	Continuation() string
+}
+
+// ClusterHealthDocumentRawIterator is a clusterHealthDocument raw iterator
+type ClusterHealthDocumentRawIterator interface {
+	ClusterHealthDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewClusterHealthDocumentClient returns a new clusterHealthDocument client
+func NewClusterHealthDocumentClient(collc CollectionClient, collid string) ClusterHealthDocumentClient {
+	return &clusterHealthDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *clusterHealthDocumentClient) all(ctx context.Context, i ClusterHealthDocumentIterator) (*pkg.ClusterHealthDocuments, error) {
+	allclusterHealthDocuments := &pkg.ClusterHealthDocuments{}
+
+	for {
+		clusterHealthDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if clusterHealthDocuments == nil {
+			break
+		}
+
+		allclusterHealthDocuments.Count += clusterHealthDocuments.Count
+		allclusterHealthDocuments.ResourceID = clusterHealthDocuments.ResourceID
+		allclusterHealthDocuments.ClusterHealthDocuments = append(allclusterHealthDocuments.ClusterHealthDocuments, clusterHealthDocuments.ClusterHealthDocuments...)
+	}
+
+	return allclusterHealthDocuments, nil
+}
+
+func (c *clusterHealthDocumentClient) Create(ctx context.Context, partitionkey string, newclusterHealthDocument *pkg.ClusterHealthDocument, options *Options) (clusterHealthDocument *pkg.ClusterHealthDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newclusterHealthDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newclusterHealthDocument, &clusterHealthDocument, headers)
+	return
+}
+
+func (c *clusterHealthDocumentClient) List(options *Options) ClusterHealthDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterHealthDocumentListIterator{clusterHealthDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *clusterHealthDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.ClusterHealthDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *clusterHealthDocumentClient) Get(ctx context.Context, partitionkey, clusterHealthDocumentid string, options *Options) (clusterHealthDocument *pkg.ClusterHealthDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+clusterHealthDocumentid, "docs", c.path+"/docs/"+clusterHealthDocumentid, http.StatusOK, nil, &clusterHealthDocument, headers)
+	return
+}
+
+func (c *clusterHealthDocumentClient) Replace(ctx context.Context, partitionkey string, newclusterHealthDocument *pkg.ClusterHealthDocument, options *Options) (clusterHealthDocument *pkg.ClusterHealthDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newclusterHealthDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newclusterHealthDocument.ID, "docs", c.path+"/docs/"+newclusterHealthDocument.ID, http.StatusOK, &newclusterHealthDocument, &clusterHealthDocument, headers)
+	return
+}
+
+func (c *clusterHealthDocumentClient) Delete(ctx context.Context, partitionkey string, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, clusterHealthDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+clusterHealthDocument.ID, "docs", c.path+"/docs/"+clusterHealthDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *clusterHealthDocumentClient) Query(partitionkey string, query *Query, options *Options) ClusterHealthDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterHealthDocumentQueryIterator{clusterHealthDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *clusterHealthDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.ClusterHealthDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *clusterHealthDocumentClient) ChangeFeed(options *Options) ClusterHealthDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &clusterHealthDocumentChangeFeedIterator{clusterHealthDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *clusterHealthDocumentClient) setOptions(options *Options, clusterHealthDocument *pkg.ClusterHealthDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if clusterHealthDocument != nil && !options.NoETag {
+		if clusterHealthDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", clusterHealthDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *clusterHealthDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (clusterHealthDocuments *pkg.ClusterHealthDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &clusterHealthDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *clusterHealthDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *clusterHealthDocumentListIterator) Next(ctx context.Context, maxItemCount int) (clusterHealthDocuments *pkg.ClusterHealthDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &clusterHealthDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *clusterHealthDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *clusterHealthDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (clusterHealthDocuments *pkg.ClusterHealthDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &clusterHealthDocuments)
+	return
+}
+
+func (i *clusterHealthDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *clusterHealthDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
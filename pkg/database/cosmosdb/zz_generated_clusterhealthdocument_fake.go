@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeClusterHealthDocumentTriggerHandler func(context.Context, *pkg.ClusterHealthDocument) error
+type fakeClusterHealthDocumentQueryHandler func(ClusterHealthDocumentClient, *Query, *Options) ClusterHealthDocumentRawIterator
+
+var _ ClusterHealthDocumentClient = &FakeClusterHealthDocumentClient{}
+
+// NewFakeClusterHealthDocumentClient returns a FakeClusterHealthDocumentClient
+func NewFakeClusterHealthDocumentClient(h *codec.JsonHandle) *FakeClusterHealthDocumentClient {
+	return &FakeClusterHealthDocumentClient{
+		jsonHandle:             h,
+		clusterHealthDocuments: make(map[string]*pkg.ClusterHealthDocument),
+		triggerHandlers:        make(map[string]fakeClusterHealthDocumentTriggerHandler),
+		queryHandlers:          make(map[string]fakeClusterHealthDocumentQueryHandler),
+	}
+}
+
+// FakeClusterHealthDocumentClient is a FakeClusterHealthDocumentClient
+type FakeClusterHealthDocumentClient struct {
+	lock                   sync.RWMutex
+	jsonHandle             *codec.JsonHandle
+	clusterHealthDocuments map[string]*pkg.ClusterHealthDocument
+	triggerHandlers        map[string]fakeClusterHealthDocumentTriggerHandler
+	queryHandlers          map[string]fakeClusterHealthDocumentQueryHandler
+	sorter                 func([]*pkg.ClusterHealthDocument)
+	etag                   int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.ClusterHealthDocument, *pkg.ClusterHealthDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeClusterHealthDocumentClient method invocation
+func (c *FakeClusterHealthDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeClusterHealthDocumentClient) SetSorter(sorter func([]*pkg.ClusterHealthDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a ClusterHealthDocument
+func (c *FakeClusterHealthDocumentClient) SetConflictChecker(conflictChecker func(*pkg.ClusterHealthDocument, *pkg.ClusterHealthDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeClusterHealthDocumentClient) SetTriggerHandler(triggerName string, trigger fakeClusterHealthDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeClusterHealthDocumentClient) SetQueryHandler(queryName string, query fakeClusterHealthDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeClusterHealthDocumentClient) deepCopy(clusterHealthDocument *pkg.ClusterHealthDocument) (*pkg.ClusterHealthDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(clusterHealthDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterHealthDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&clusterHealthDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterHealthDocument, nil
+}
+
+func (c *FakeClusterHealthDocumentClient) apply(ctx context.Context, partitionkey string, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options, isCreate bool) (*pkg.ClusterHealthDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	clusterHealthDocument, err := c.deepCopy(clusterHealthDocument) // copy now because pretriggers can mutate clusterHealthDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, clusterHealthDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingClusterHealthDocument, exists := c.clusterHealthDocuments[clusterHealthDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if clusterHealthDocument.ETag != existingClusterHealthDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, clusterHealthDocumentToCheck := range c.clusterHealthDocuments {
+			if c.conflictChecker(clusterHealthDocumentToCheck, clusterHealthDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	clusterHealthDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.clusterHealthDocuments[clusterHealthDocument.ID] = clusterHealthDocument
+
+	return c.deepCopy(clusterHealthDocument)
+}
+
+// Create creates a ClusterHealthDocument in the database
+func (c *FakeClusterHealthDocumentClient) Create(ctx context.Context, partitionkey string, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options) (*pkg.ClusterHealthDocument, error) {
+	return c.apply(ctx, partitionkey, clusterHealthDocument, options, true)
+}
+
+// Replace replaces a ClusterHealthDocument in the database
+func (c *FakeClusterHealthDocumentClient) Replace(ctx context.Context, partitionkey string, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options) (*pkg.ClusterHealthDocument, error) {
+	return c.apply(ctx, partitionkey, clusterHealthDocument, options, false)
+}
+
+// List returns a ClusterHealthDocumentIterator to list all ClusterHealthDocuments in the database
+func (c *FakeClusterHealthDocumentClient) List(*Options) ClusterHealthDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterHealthDocumentErroringRawIterator(c.err)
+	}
+
+	clusterHealthDocuments := make([]*pkg.ClusterHealthDocument, 0, len(c.clusterHealthDocuments))
+	for _, clusterHealthDocument := range c.clusterHealthDocuments {
+		clusterHealthDocument, err := c.deepCopy(clusterHealthDocument)
+		if err != nil {
+			return NewFakeClusterHealthDocumentErroringRawIterator(err)
+		}
+		clusterHealthDocuments = append(clusterHealthDocuments, clusterHealthDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(clusterHealthDocuments)
+	}
+
+	return NewFakeClusterHealthDocumentIterator(clusterHealthDocuments, 0)
+}
+
+// ListAll lists all ClusterHealthDocuments in the database
+func (c *FakeClusterHealthDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.ClusterHealthDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a ClusterHealthDocument from the database
+func (c *FakeClusterHealthDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.ClusterHealthDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	clusterHealthDocument, exists := c.clusterHealthDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(clusterHealthDocument)
+}
+
+// Delete deletes a ClusterHealthDocument from the database
+func (c *FakeClusterHealthDocumentClient) Delete(ctx context.Context, partitionKey string, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.clusterHealthDocuments[clusterHealthDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.clusterHealthDocuments, clusterHealthDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeClusterHealthDocumentClient) ChangeFeed(*Options) ClusterHealthDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterHealthDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeClusterHealthDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeClusterHealthDocumentClient) processPreTriggers(ctx context.Context, clusterHealthDocument *pkg.ClusterHealthDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, clusterHealthDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeClusterHealthDocumentClient) Query(name string, query *Query, options *Options) ClusterHealthDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeClusterHealthDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeClusterHealthDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeClusterHealthDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.ClusterHealthDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeClusterHealthDocumentIterator(clusterHealthDocuments []*pkg.ClusterHealthDocument, continuation int) ClusterHealthDocumentRawIterator {
+	return &fakeClusterHealthDocumentIterator{clusterHealthDocuments: clusterHealthDocuments, continuation: continuation}
+}
+
+type fakeClusterHealthDocumentIterator struct {
+	clusterHealthDocuments []*pkg.ClusterHealthDocument
+	continuation           int
+	done                   bool
+}
+
+func (i *fakeClusterHealthDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeClusterHealthDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.ClusterHealthDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var clusterHealthDocuments []*pkg.ClusterHealthDocument
+	if maxItemCount == -1 {
+		clusterHealthDocuments = i.clusterHealthDocuments[i.continuation:]
+		i.continuation = len(i.clusterHealthDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.clusterHealthDocuments) {
+			max = len(i.clusterHealthDocuments)
+		}
+		clusterHealthDocuments = i.clusterHealthDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.ClusterHealthDocuments{
+		ClusterHealthDocuments: clusterHealthDocuments,
+		Count:                  len(clusterHealthDocuments),
+	}, nil
+}
+
+func (i *fakeClusterHealthDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.clusterHealthDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeClusterHealthDocumentErroringRawIterator returns a ClusterHealthDocumentRawIterator which
+// whose methods return the given error
+func NewFakeClusterHealthDocumentErroringRawIterator(err error) ClusterHealthDocumentRawIterator {
+	return &fakeClusterHealthDocumentErroringRawIterator{err: err}
+}
+
+type fakeClusterHealthDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeClusterHealthDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.ClusterHealthDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeClusterHealthDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeClusterHealthDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
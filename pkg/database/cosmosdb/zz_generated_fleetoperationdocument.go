@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fleetOperationDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// FleetOperationDocumentClient is a fleetOperationDocument client
+type FleetOperationDocumentClient interface {
+	Create(context.Context, string, *pkg.FleetOperationDocument, *Options) (*pkg.FleetOperationDocument, error)
+	List(*Options) FleetOperationDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.FleetOperationDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.FleetOperationDocument, error)
+	Replace(context.Context, string, *pkg.FleetOperationDocument, *Options) (*pkg.FleetOperationDocument, error)
+	Delete(context.Context, string, *pkg.FleetOperationDocument, *Options) error
+	Query(string, *Query, *Options) FleetOperationDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.FleetOperationDocuments, error)
+	ChangeFeed(*Options) FleetOperationDocumentIterator
+}
+
+type fleetOperationDocumentChangeFeedIterator struct {
+	*fleetOperationDocumentClient
+	continuation string
+	options      *Options
+}
+
+type fleetOperationDocumentListIterator struct {
+	*fleetOperationDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type fleetOperationDocumentQueryIterator struct {
+	*fleetOperationDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// FleetOperationDocumentIterator is a fleetOperationDocument iterator
+type FleetOperationDocumentIterator interface {
+	Next(context.Context, int) (*pkg.FleetOperationDocuments, error)
+	Continuation() string
+}
+
+// FleetOperationDocumentRawIterator is a fleetOperationDocument raw iterator
+type FleetOperationDocumentRawIterator interface {
+	FleetOperationDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewFleetOperationDocumentClient returns a new fleetOperationDocument client
+func NewFleetOperationDocumentClient(collc CollectionClient, collid string) FleetOperationDocumentClient {
+	return &fleetOperationDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *fleetOperationDocumentClient) all(ctx context.Context, i FleetOperationDocumentIterator) (*pkg.FleetOperationDocuments, error) {
+	allfleetOperationDocuments := &pkg.FleetOperationDocuments{}
+
+	for {
+		fleetOperationDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if fleetOperationDocuments == nil {
+			break
+		}
+
+		allfleetOperationDocuments.Count += fleetOperationDocuments.Count
+		allfleetOperationDocuments.ResourceID = fleetOperationDocuments.ResourceID
+		allfleetOperationDocuments.FleetOperationDocuments = append(allfleetOperationDocuments.FleetOperationDocuments, fleetOperationDocuments.FleetOperationDocuments...)
+	}
+
+	return allfleetOperationDocuments, nil
+}
+
+func (c *fleetOperationDocumentClient) Create(ctx context.Context, partitionkey string, newfleetOperationDocument *pkg.FleetOperationDocument, options *Options) (fleetOperationDocument *pkg.FleetOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newfleetOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newfleetOperationDocument, &fleetOperationDocument, headers)
+	return
+}
+
+func (c *fleetOperationDocumentClient) List(options *Options) FleetOperationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &fleetOperationDocumentListIterator{fleetOperationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *fleetOperationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.FleetOperationDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *fleetOperationDocumentClient) Get(ctx context.Context, partitionkey, fleetOperationDocumentid string, options *Options) (fleetOperationDocument *pkg.FleetOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+fleetOperationDocumentid, "docs", c.path+"/docs/"+fleetOperationDocumentid, http.StatusOK, nil, &fleetOperationDocument, headers)
+	return
+}
+
+func (c *fleetOperationDocumentClient) Replace(ctx context.Context, partitionkey string, newfleetOperationDocument *pkg.FleetOperationDocument, options *Options) (fleetOperationDocument *pkg.FleetOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newfleetOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newfleetOperationDocument.ID, "docs", c.path+"/docs/"+newfleetOperationDocument.ID, http.StatusOK, &newfleetOperationDocument, &fleetOperationDocument, headers)
+	return
+}
+
+func (c *fleetOperationDocumentClient) Delete(ctx context.Context, partitionkey string, fleetOperationDocument *pkg.FleetOperationDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, fleetOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+fleetOperationDocument.ID, "docs", c.path+"/docs/"+fleetOperationDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *fleetOperationDocumentClient) Query(partitionkey string, query *Query, options *Options) FleetOperationDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &fleetOperationDocumentQueryIterator{fleetOperationDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *fleetOperationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.FleetOperationDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *fleetOperationDocumentClient) ChangeFeed(options *Options) FleetOperationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &fleetOperationDocumentChangeFeedIterator{fleetOperationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *fleetOperationDocumentClient) setOptions(options *Options, fleetOperationDocument *pkg.FleetOperationDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if fleetOperationDocument != nil && !options.NoETag {
+		if fleetOperationDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", fleetOperationDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *fleetOperationDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (fleetOperationDocuments *pkg.FleetOperationDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &fleetOperationDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *fleetOperationDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *fleetOperationDocumentListIterator) Next(ctx context.Context, maxItemCount int) (fleetOperationDocuments *pkg.FleetOperationDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &fleetOperationDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *fleetOperationDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *fleetOperationDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (fleetOperationDocuments *pkg.FleetOperationDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &fleetOperationDocuments)
+	return
+}
+
+func (i *fleetOperationDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *fleetOperationDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeFleetOperationDocumentTriggerHandler func(context.Context, *pkg.FleetOperationDocument) error
+type fakeFleetOperationDocumentQueryHandler func(FleetOperationDocumentClient, *Query, *Options) FleetOperationDocumentRawIterator
+
+var _ FleetOperationDocumentClient = &FakeFleetOperationDocumentClient{}
+
+// NewFakeFleetOperationDocumentClient returns a FakeFleetOperationDocumentClient
+func NewFakeFleetOperationDocumentClient(h *codec.JsonHandle) *FakeFleetOperationDocumentClient {
+	return &FakeFleetOperationDocumentClient{
+		jsonHandle:              h,
+		fleetOperationDocuments: make(map[string]*pkg.FleetOperationDocument),
+		triggerHandlers:         make(map[string]fakeFleetOperationDocumentTriggerHandler),
+		queryHandlers:           make(map[string]fakeFleetOperationDocumentQueryHandler),
+	}
+}
+
+// FakeFleetOperationDocumentClient is a FakeFleetOperationDocumentClient
+type FakeFleetOperationDocumentClient struct {
+	lock                    sync.RWMutex
+	jsonHandle              *codec.JsonHandle
+	fleetOperationDocuments map[string]*pkg.FleetOperationDocument
+	triggerHandlers         map[string]fakeFleetOperationDocumentTriggerHandler
+	queryHandlers           map[string]fakeFleetOperationDocumentQueryHandler
+	sorter                  func([]*pkg.FleetOperationDocument)
+	etag                    int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.FleetOperationDocument, *pkg.FleetOperationDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeFleetOperationDocumentClient method invocation
+func (c *FakeFleetOperationDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeFleetOperationDocumentClient) SetSorter(sorter func([]*pkg.FleetOperationDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a FleetOperationDocument
+func (c *FakeFleetOperationDocumentClient) SetConflictChecker(conflictChecker func(*pkg.FleetOperationDocument, *pkg.FleetOperationDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeFleetOperationDocumentClient) SetTriggerHandler(triggerName string, trigger fakeFleetOperationDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeFleetOperationDocumentClient) SetQueryHandler(queryName string, query fakeFleetOperationDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeFleetOperationDocumentClient) deepCopy(fleetOperationDocument *pkg.FleetOperationDocument) (*pkg.FleetOperationDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(fleetOperationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	fleetOperationDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&fleetOperationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return fleetOperationDocument, nil
+}
+
+func (c *FakeFleetOperationDocumentClient) apply(ctx context.Context, partitionkey string, fleetOperationDocument *pkg.FleetOperationDocument, options *Options, isCreate bool) (*pkg.FleetOperationDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	fleetOperationDocument, err := c.deepCopy(fleetOperationDocument) // copy now because pretriggers can mutate fleetOperationDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, fleetOperationDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingFleetOperationDocument, exists := c.fleetOperationDocuments[fleetOperationDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if fleetOperationDocument.ETag != existingFleetOperationDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, fleetOperationDocumentToCheck := range c.fleetOperationDocuments {
+			if c.conflictChecker(fleetOperationDocumentToCheck, fleetOperationDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	fleetOperationDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.fleetOperationDocuments[fleetOperationDocument.ID] = fleetOperationDocument
+
+	return c.deepCopy(fleetOperationDocument)
+}
+
+// Create creates a FleetOperationDocument in the database
+func (c *FakeFleetOperationDocumentClient) Create(ctx context.Context, partitionkey string, fleetOperationDocument *pkg.FleetOperationDocument, options *Options) (*pkg.FleetOperationDocument, error) {
+	return c.apply(ctx, partitionkey, fleetOperationDocument, options, true)
+}
+
+// Replace replaces a FleetOperationDocument in the database
+func (c *FakeFleetOperationDocumentClient) Replace(ctx context.Context, partitionkey string, fleetOperationDocument *pkg.FleetOperationDocument, options *Options) (*pkg.FleetOperationDocument, error) {
+	return c.apply(ctx, partitionkey, fleetOperationDocument, options, false)
+}
+
+// List returns a FleetOperationDocumentIterator to list all FleetOperationDocuments in the database
+func (c *FakeFleetOperationDocumentClient) List(*Options) FleetOperationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeFleetOperationDocumentErroringRawIterator(c.err)
+	}
+
+	fleetOperationDocuments := make([]*pkg.FleetOperationDocument, 0, len(c.fleetOperationDocuments))
+	for _, fleetOperationDocument := range c.fleetOperationDocuments {
+		fleetOperationDocument, err := c.deepCopy(fleetOperationDocument)
+		if err != nil {
+			return NewFakeFleetOperationDocumentErroringRawIterator(err)
+		}
+		fleetOperationDocuments = append(fleetOperationDocuments, fleetOperationDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(fleetOperationDocuments)
+	}
+
+	return NewFakeFleetOperationDocumentIterator(fleetOperationDocuments, 0)
+}
+
+// ListAll lists all FleetOperationDocuments in the database
+func (c *FakeFleetOperationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.FleetOperationDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a FleetOperationDocument from the database
+func (c *FakeFleetOperationDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.FleetOperationDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	fleetOperationDocument, exists := c.fleetOperationDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(fleetOperationDocument)
+}
+
+// Delete deletes a FleetOperationDocument from the database
+func (c *FakeFleetOperationDocumentClient) Delete(ctx context.Context, partitionKey string, fleetOperationDocument *pkg.FleetOperationDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.fleetOperationDocuments[fleetOperationDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.fleetOperationDocuments, fleetOperationDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeFleetOperationDocumentClient) ChangeFeed(*Options) FleetOperationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeFleetOperationDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeFleetOperationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeFleetOperationDocumentClient) processPreTriggers(ctx context.Context, fleetOperationDocument *pkg.FleetOperationDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, fleetOperationDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeFleetOperationDocumentClient) Query(name string, query *Query, options *Options) FleetOperationDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeFleetOperationDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeFleetOperationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeFleetOperationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.FleetOperationDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeFleetOperationDocumentIterator(fleetOperationDocuments []*pkg.FleetOperationDocument, continuation int) FleetOperationDocumentRawIterator {
+	return &fakeFleetOperationDocumentIterator{fleetOperationDocuments: fleetOperationDocuments, continuation: continuation}
+}
+
+type fakeFleetOperationDocumentIterator struct {
+	fleetOperationDocuments []*pkg.FleetOperationDocument
+	continuation            int
+	done                    bool
+}
+
+func (i *fakeFleetOperationDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeFleetOperationDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.FleetOperationDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var fleetOperationDocuments []*pkg.FleetOperationDocument
+	if maxItemCount == -1 {
+		fleetOperationDocuments = i.fleetOperationDocuments[i.continuation:]
+		i.continuation = len(i.fleetOperationDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.fleetOperationDocuments) {
+			max = len(i.fleetOperationDocuments)
+		}
+		fleetOperationDocuments = i.fleetOperationDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.FleetOperationDocuments{
+		FleetOperationDocuments: fleetOperationDocuments,
+		Count:                   len(fleetOperationDocuments),
+	}, nil
+}
+
+func (i *fakeFleetOperationDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.fleetOperationDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeFleetOperationDocumentErroringRawIterator returns a FleetOperationDocumentRawIterator which
+// whose methods return the given error
+func NewFakeFleetOperationDocumentErroringRawIterator(err error) FleetOperationDocumentRawIterator {
+	return &fakeFleetOperationDocumentErroringRawIterator{err: err}
+}
+
+type fakeFleetOperationDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeFleetOperationDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.FleetOperationDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeFleetOperationDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeFleetOperationDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
@@ -24,9 +24,12 @@ import (
 )
 
 const (
+	collAdminActions      = "AdminActions"
 	collAsyncOperations   = "AsyncOperations"
 	collBilling           = "Billing"
+	collClusterHealth     = "ClusterHealth"
 	collClusterManager    = "ClusterManagerConfigurations"
+	collFleetOperations   = "FleetOperations"
 	collGateway           = "Gateway"
 	collMonitors          = "Monitors"
 	collOpenShiftClusters = "OpenShiftClusters"
@@ -0,0 +1,207 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const FleetOperationsDequeueQuery string = `SELECT * FROM FleetOperations doc WHERE (doc.fleetOperation.state IN ("Running", "Canceling") OR (doc.fleetOperation.state = "Paused" AND EXISTS(SELECT VALUE t FROM t IN doc.fleetOperation.targets WHERE t.state = "Running"))) AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
+
+type fleetOperations struct {
+	c             cosmosdb.FleetOperationDocumentClient
+	uuid          string
+	uuidGenerator uuid.Generator
+}
+
+// FleetOperations is the database interface for FleetOperationDocuments
+type FleetOperations interface {
+	Create(context.Context, *api.FleetOperationDocument) (*api.FleetOperationDocument, error)
+	Get(context.Context, string) (*api.FleetOperationDocument, error)
+	ListAll(context.Context) (*api.FleetOperationDocuments, error)
+	Patch(context.Context, string, func(*api.FleetOperationDocument) error) (*api.FleetOperationDocument, error)
+	PatchWithLease(context.Context, string, func(*api.FleetOperationDocument) error) (*api.FleetOperationDocument, error)
+	Dequeue(context.Context) (*api.FleetOperationDocument, error)
+	Lease(context.Context, string) (*api.FleetOperationDocument, error)
+	EndLease(context.Context, string) (*api.FleetOperationDocument, error)
+	NewUUID() string
+}
+
+// NewFleetOperations returns a new FleetOperations
+func NewFleetOperations(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (FleetOperations, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	triggers := []*cosmosdb.Trigger{
+		{
+			ID:               "renewLease",
+			TriggerOperation: cosmosdb.TriggerOperationAll,
+			TriggerType:      cosmosdb.TriggerTypePre,
+			Body: `function trigger() {
+	var request = getContext().getRequest();
+	var body = request.getBody();
+	var date = new Date();
+	body["leaseExpires"] = Math.floor(date.getTime() / 1000) + 60;
+	request.setBody(body);
+}`,
+		},
+		{
+			ID:               "retryLater",
+			TriggerOperation: cosmosdb.TriggerOperationAll,
+			TriggerType:      cosmosdb.TriggerTypePre,
+			Body: `function trigger() {
+	var request = getContext().getRequest();
+	var body = request.getBody();
+	var date = new Date();
+	body["leaseExpires"] = Math.floor(date.getTime() / 1000) + 60;
+	request.setBody(body);
+}`,
+		},
+	}
+
+	triggerc := cosmosdb.NewTriggerClient(collc, collFleetOperations)
+	for _, trigger := range triggers {
+		_, err := triggerc.Create(ctx, trigger)
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+			return nil, err
+		}
+	}
+
+	documentClient := cosmosdb.NewFleetOperationDocumentClient(collc, collFleetOperations)
+	return NewFleetOperationsWithProvidedClient(documentClient, uuid.DefaultGenerator.Generate(), uuid.DefaultGenerator), nil
+}
+
+func NewFleetOperationsWithProvidedClient(client cosmosdb.FleetOperationDocumentClient, uuid string, uuidGenerator uuid.Generator) FleetOperations {
+	return &fleetOperations{
+		c:             client,
+		uuid:          uuid,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *fleetOperations) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *fleetOperations) Create(ctx context.Context, doc *api.FleetOperationDocument) (*api.FleetOperationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	doc, err := c.c.Create(ctx, doc.ID, doc, nil)
+
+	if err, ok := err.(*cosmosdb.Error); ok && err.StatusCode == http.StatusConflict {
+		err.StatusCode = http.StatusPreconditionFailed
+	}
+
+	return doc, err
+}
+
+func (c *fleetOperations) Get(ctx context.Context, id string) (*api.FleetOperationDocument, error) {
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, id, id, nil)
+}
+
+func (c *fleetOperations) ListAll(ctx context.Context) (*api.FleetOperationDocuments, error) {
+	return c.c.ListAll(ctx, nil)
+}
+
+func (c *fleetOperations) Patch(ctx context.Context, id string, f func(*api.FleetOperationDocument) error) (*api.FleetOperationDocument, error) {
+	return c.patch(ctx, id, f, nil)
+}
+
+func (c *fleetOperations) patch(ctx context.Context, id string, f func(*api.FleetOperationDocument) error, options *cosmosdb.Options) (*api.FleetOperationDocument, error) {
+	var doc *api.FleetOperationDocument
+
+	err := cosmosdb.RetryOnPreconditionFailed(func() (err error) {
+		doc, err = c.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		err = f(doc)
+		if err != nil {
+			return
+		}
+
+		doc, err = c.update(ctx, doc, options)
+		return
+	})
+
+	return doc, err
+}
+
+func (c *fleetOperations) PatchWithLease(ctx context.Context, id string, f func(*api.FleetOperationDocument) error) (*api.FleetOperationDocument, error) {
+	return c.patchWithLease(ctx, id, f, nil)
+}
+
+func (c *fleetOperations) patchWithLease(ctx context.Context, id string, f func(*api.FleetOperationDocument) error, options *cosmosdb.Options) (*api.FleetOperationDocument, error) {
+	return c.patch(ctx, id, func(doc *api.FleetOperationDocument) error {
+		if doc.LeaseOwner != c.uuid {
+			return fmt.Errorf("lost lease")
+		}
+
+		return f(doc)
+	}, options)
+}
+
+func (c *fleetOperations) update(ctx context.Context, doc *api.FleetOperationDocument, options *cosmosdb.Options) (*api.FleetOperationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Replace(ctx, doc.ID, doc, options)
+}
+
+func (c *fleetOperations) Dequeue(ctx context.Context) (*api.FleetOperationDocument, error) {
+	i := c.c.Query("", &cosmosdb.Query{Query: FleetOperationsDequeueQuery}, nil)
+
+	for {
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if docs == nil {
+			return nil, nil
+		}
+
+		for _, doc := range docs.FleetOperationDocuments {
+			doc.LeaseOwner = c.uuid
+			doc.Dequeues++
+			doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+			if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
+				continue
+			}
+			return doc, err
+		}
+	}
+}
+
+func (c *fleetOperations) Lease(ctx context.Context, id string) (*api.FleetOperationDocument, error) {
+	return c.patchWithLease(ctx, id, func(doc *api.FleetOperationDocument) error {
+		return nil
+	}, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+}
+
+// EndLease releases the lease on a fleet operation.  The operation is not
+// dequeued again until the retryLater trigger's delay has passed, so that its
+// clusters are polled rather than watched continuously.
+func (c *fleetOperations) EndLease(ctx context.Context, id string) (*api.FleetOperationDocument, error) {
+	return c.patchWithLease(ctx, id, func(doc *api.FleetOperationDocument) error {
+		doc.LeaseOwner = ""
+		doc.LeaseExpires = 0
+		doc.Dequeues = 0
+
+		return nil
+	}, &cosmosdb.Options{PreTriggers: []string{"retryLater"}})
+}
@@ -0,0 +1,532 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+var (
+	secureBytesType  = reflect.TypeOf(api.SecureBytes{})
+	secureStringType = reflect.TypeOf(api.SecureString(""))
+)
+
+// KeyRotationProgress reports how far a key rotation or verification has got
+type KeyRotationProgress struct {
+	// Documents is the number of documents scanned
+	Documents int
+	// Rotated is the number of documents re-sealed with the current key
+	Rotated int
+	// Failed is the number of documents which could not be re-sealed
+	Failed int
+	// KeyVersions is the number of encrypted fields found sealed with each
+	// key version, before any re-sealing
+	KeyVersions map[string]int
+}
+
+// KeyRotation re-seals the encrypted fields of stored documents with the
+// current encryption key, so that older keys can be retired
+type KeyRotation interface {
+	// Rotate re-seals every encrypted field which is not sealed with the
+	// current key
+	Rotate(context.Context) (*KeyRotationProgress, error)
+	// Verify returns an error if any encrypted field is still sealed with the
+	// given key version
+	Verify(context.Context, string) (*KeyRotationProgress, error)
+}
+
+type keyRotation struct {
+	log       *logrus.Entry
+	aead      encryption.AEAD
+	versioner encryption.KeyVersioner
+	batchSize int
+
+	collections []keyRotationCollection
+}
+
+// NewKeyRotation returns a KeyRotation for the collections which hold
+// encrypted fields.  dbc must have been created without an AEAD, so that
+// encrypted fields are read and written exactly as they are stored; aead must
+// hold all the keys in use.
+func NewKeyRotation(log *logrus.Entry, dbc cosmosdb.DatabaseClient, dbName string, aead encryption.AEAD, batchSize int) (KeyRotation, error) {
+	versioner, ok := aead.(encryption.KeyVersioner)
+	if !ok {
+		return nil, fmt.Errorf("the AEAD does not report key versions")
+	}
+
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	return &keyRotation{
+		log:       log,
+		aead:      aead,
+		versioner: versioner,
+		batchSize: batchSize,
+
+		collections: []keyRotationCollection{
+			&asyncOperationsKeyRotation{c: cosmosdb.NewAsyncOperationDocumentClient(collc, collAsyncOperations)},
+			&clusterManagerConfigurationsKeyRotation{c: cosmosdb.NewClusterManagerConfigurationDocumentClient(collc, collClusterManager)},
+			&openShiftClustersKeyRotation{c: cosmosdb.NewOpenShiftClusterDocumentClient(collc, collOpenShiftClusters)},
+		},
+	}, nil
+}
+
+func (r *keyRotation) Rotate(ctx context.Context) (*KeyRotationProgress, error) {
+	sealKeyVersion := r.versioner.SealKeyVersion()
+	r.log.Printf("re-sealing encrypted fields with key version %s", sealKeyVersion)
+
+	p := &KeyRotationProgress{
+		KeyVersions: map[string]int{},
+	}
+
+	for _, c := range r.collections {
+		err := c.walk(ctx, r.batchSize, func(docs []interface{}) error {
+			for _, doc := range docs {
+				p.Documents++
+
+				versions, err := r.keyVersions(doc)
+				if err != nil {
+					p.Failed++
+					r.log.Errorf("%s %s: %v", c.name(), documentID(doc), err)
+					continue
+				}
+
+				for version, count := range versions {
+					p.KeyVersions[version] += count
+				}
+
+				if len(versions) == 0 || len(versions) == 1 && versions[sealKeyVersion] > 0 {
+					continue
+				}
+
+				rotated, err := r.rotateDocument(ctx, c, doc)
+				if err != nil {
+					p.Failed++
+					r.log.Errorf("%s %s: %v", c.name(), documentID(doc), err)
+					continue
+				}
+
+				if rotated {
+					p.Rotated++
+				}
+			}
+
+			r.log.Printf("%s: %d documents scanned, %d re-sealed, %d failed", c.name(), p.Documents, p.Rotated, p.Failed)
+
+			return nil
+		})
+		if err != nil {
+			return p, err
+		}
+	}
+
+	if p.Failed > 0 {
+		return p, fmt.Errorf("%d documents could not be re-sealed", p.Failed)
+	}
+
+	return p, nil
+}
+
+// rotateDocument re-seals the encrypted fields of doc.  The document is
+// replaced only if it has not changed since it was read; otherwise it is read
+// again and the re-sealing retried.  It returns false if the document no
+// longer needed re-sealing.
+func (r *keyRotation) rotateDocument(ctx context.Context, c keyRotationCollection, doc interface{}) (rotated bool, err error) {
+	var reread bool
+
+	err = cosmosdb.RetryOnPreconditionFailed(func() error {
+		if reread {
+			doc, err = c.get(ctx, doc)
+			if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		reread = true
+
+		rotated, err = r.reseal(doc)
+		if err != nil || !rotated {
+			return err
+		}
+
+		err = c.replace(ctx, doc)
+		if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+			rotated = false
+			return nil
+		}
+
+		return err
+	})
+
+	return rotated, err
+}
+
+// reseal re-seals the encrypted fields of doc which are not sealed with the
+// current key.  It returns true if any field was re-sealed.
+func (r *keyRotation) reseal(doc interface{}) (bool, error) {
+	sealKeyVersion := r.versioner.SealKeyVersion()
+
+	var changed bool
+	err := walkSecureFields(reflect.ValueOf(doc), func(v reflect.Value) error {
+		sealed, err := sealedValue(v)
+		if err != nil {
+			return err
+		}
+
+		version, err := r.versioner.OpenKeyVersion(sealed)
+		if err != nil {
+			return err
+		}
+
+		if version == sealKeyVersion {
+			return nil
+		}
+
+		b, err := r.aead.Open(sealed)
+		if err != nil {
+			return err
+		}
+
+		sealed, err = r.aead.Seal(b)
+		if err != nil {
+			return err
+		}
+
+		setSealedValue(v, sealed)
+		changed = true
+
+		return nil
+	})
+
+	return changed, err
+}
+
+func (r *keyRotation) Verify(ctx context.Context, keyVersion string) (*KeyRotationProgress, error) {
+	p := &KeyRotationProgress{
+		KeyVersions: map[string]int{},
+	}
+
+	var dependent int
+	for _, c := range r.collections {
+		err := c.walk(ctx, r.batchSize, func(docs []interface{}) error {
+			for _, doc := range docs {
+				p.Documents++
+
+				versions, err := r.keyVersions(doc)
+				if err != nil {
+					p.Failed++
+					r.log.Errorf("%s %s: %v", c.name(), documentID(doc), err)
+					continue
+				}
+
+				for version, count := range versions {
+					p.KeyVersions[version] += count
+				}
+
+				if versions[keyVersion] > 0 {
+					dependent++
+					r.log.Warnf("%s %s depends on key version %s", c.name(), documentID(doc), keyVersion)
+				}
+			}
+
+			r.log.Printf("%s: %d documents scanned", c.name(), p.Documents)
+
+			return nil
+		})
+		if err != nil {
+			return p, err
+		}
+	}
+
+	switch {
+	case dependent > 0:
+		return p, fmt.Errorf("%d documents depend on key version %s", dependent, keyVersion)
+	case p.Failed > 0:
+		return p, fmt.Errorf("%d documents could not be checked", p.Failed)
+	}
+
+	return p, nil
+}
+
+// keyVersions returns the number of encrypted fields of doc sealed with each
+// key version
+func (r *keyRotation) keyVersions(doc interface{}) (map[string]int, error) {
+	versions := map[string]int{}
+
+	err := walkSecureFields(reflect.ValueOf(doc), func(v reflect.Value) error {
+		sealed, err := sealedValue(v)
+		if err != nil {
+			return err
+		}
+
+		version, err := r.versioner.OpenKeyVersion(sealed)
+		if err != nil {
+			return err
+		}
+
+		versions[version]++
+
+		return nil
+	})
+
+	return versions, err
+}
+
+// walkSecureFields calls f for each non-empty api.SecureBytes and
+// api.SecureString value reachable from v
+func walkSecureFields(v reflect.Value, f func(reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return walkSecureFields(v.Elem(), f)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			err := walkSecureFields(v.Field(i), f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		if v.Type() == secureBytesType {
+			if v.Len() == 0 {
+				return nil
+			}
+			return f(v)
+		}
+		fallthrough
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := walkSecureFields(v.Index(i), f)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		// map elements are not addressable, so walk a copy of each and put it
+		// back
+		for _, k := range v.MapKeys() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+
+			err := walkSecureFields(e, f)
+			if err != nil {
+				return err
+			}
+
+			v.SetMapIndex(k, e)
+		}
+
+	case reflect.String:
+		if v.Type() == secureStringType && v.Len() > 0 {
+			return f(v)
+		}
+	}
+
+	return nil
+}
+
+// sealedValue returns the sealed value of an encrypted field read without an
+// AEAD.  SecureBytes are stored as the base64 encoding of the sealed value,
+// which the codec decodes; SecureStrings are stored the same way, but the
+// codec leaves them encoded.
+func sealedValue(v reflect.Value) ([]byte, error) {
+	if v.Type() == secureStringType {
+		return base64.StdEncoding.DecodeString(v.String())
+	}
+
+	return v.Bytes(), nil
+}
+
+func setSealedValue(v reflect.Value, sealed []byte) {
+	if v.Type() == secureStringType {
+		v.SetString(base64.StdEncoding.EncodeToString(sealed))
+		return
+	}
+
+	v.SetBytes(sealed)
+}
+
+func documentID(doc interface{}) string {
+	return reflect.ValueOf(doc).Elem().FieldByName("ID").String()
+}
+
+// keyRotationCollection gives KeyRotation uniform access to the documents of
+// a collection which holds encrypted fields
+type keyRotationCollection interface {
+	name() string
+	// walk calls f with each batch of documents in the collection
+	walk(ctx context.Context, batchSize int, f func([]interface{}) error) error
+	// get reads a document again
+	get(ctx context.Context, doc interface{}) (interface{}, error)
+	// replace replaces a document if it has not changed since it was read
+	replace(ctx context.Context, doc interface{}) error
+}
+
+type asyncOperationsKeyRotation struct {
+	c cosmosdb.AsyncOperationDocumentClient
+}
+
+func (c *asyncOperationsKeyRotation) name() string {
+	return collAsyncOperations
+}
+
+func (c *asyncOperationsKeyRotation) walk(ctx context.Context, batchSize int, f func([]interface{}) error) error {
+	i := c.c.List(nil)
+	for {
+		docs, err := i.Next(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		if docs == nil {
+			return nil
+		}
+
+		batch := make([]interface{}, 0, len(docs.AsyncOperationDocuments))
+		for _, doc := range docs.AsyncOperationDocuments {
+			batch = append(batch, doc)
+		}
+
+		err = f(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *asyncOperationsKeyRotation) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.AsyncOperationDocument)
+
+	d, err := c.c.Get(ctx, d.ID, d.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (c *asyncOperationsKeyRotation) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.AsyncOperationDocument)
+
+	_, err := c.c.Replace(ctx, d.ID, d, &cosmosdb.Options{})
+	return err
+}
+
+type clusterManagerConfigurationsKeyRotation struct {
+	c cosmosdb.ClusterManagerConfigurationDocumentClient
+}
+
+func (c *clusterManagerConfigurationsKeyRotation) name() string {
+	return collClusterManager
+}
+
+func (c *clusterManagerConfigurationsKeyRotation) walk(ctx context.Context, batchSize int, f func([]interface{}) error) error {
+	i := c.c.List(nil)
+	for {
+		docs, err := i.Next(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		if docs == nil {
+			return nil
+		}
+
+		batch := make([]interface{}, 0, len(docs.ClusterManagerConfigurationDocuments))
+		for _, doc := range docs.ClusterManagerConfigurationDocuments {
+			batch = append(batch, doc)
+		}
+
+		err = f(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *clusterManagerConfigurationsKeyRotation) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.ClusterManagerConfigurationDocument)
+
+	d, err := c.c.Get(ctx, d.PartitionKey, d.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (c *clusterManagerConfigurationsKeyRotation) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.ClusterManagerConfigurationDocument)
+
+	_, err := c.c.Replace(ctx, d.PartitionKey, d, &cosmosdb.Options{})
+	return err
+}
+
+type openShiftClustersKeyRotation struct {
+	c cosmosdb.OpenShiftClusterDocumentClient
+}
+
+func (c *openShiftClustersKeyRotation) name() string {
+	return collOpenShiftClusters
+}
+
+func (c *openShiftClustersKeyRotation) walk(ctx context.Context, batchSize int, f func([]interface{}) error) error {
+	i := c.c.List(nil)
+	for {
+		docs, err := i.Next(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		if docs == nil {
+			return nil
+		}
+
+		batch := make([]interface{}, 0, len(docs.OpenShiftClusterDocuments))
+		for _, doc := range docs.OpenShiftClusterDocuments {
+			batch = append(batch, doc)
+		}
+
+		err = f(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (c *openShiftClustersKeyRotation) get(ctx context.Context, doc interface{}) (interface{}, error) {
+	d := doc.(*api.OpenShiftClusterDocument)
+
+	d, err := c.c.Get(ctx, d.PartitionKey, d.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (c *openShiftClustersKeyRotation) replace(ctx context.Context, doc interface{}) error {
+	d := doc.(*api.OpenShiftClusterDocument)
+
+	_, err := c.c.Replace(ctx, d.PartitionKey, d, &cosmosdb.Options{})
+	return err
+}
@@ -0,0 +1,353 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+// fakeVersionedAEAD seals values by prefixing them with the current key
+// version, and opens values prefixed with any known key version
+type fakeVersionedAEAD struct {
+	current string
+	known   []string
+}
+
+func (a *fakeVersionedAEAD) Seal(b []byte) ([]byte, error) {
+	return append([]byte(a.current+":"), b...), nil
+}
+
+func (a *fakeVersionedAEAD) Open(b []byte) ([]byte, error) {
+	version, err := a.OpenKeyVersion(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b[len(version)+1:], nil
+}
+
+func (a *fakeVersionedAEAD) SealKeyVersion() string {
+	return a.current
+}
+
+func (a *fakeVersionedAEAD) OpenKeyVersion(b []byte) (string, error) {
+	for _, version := range a.known {
+		if bytes.HasPrefix(b, []byte(version+":")) {
+			return version, nil
+		}
+	}
+
+	return "", errors.New("message authentication failed")
+}
+
+func sealedString(s string) api.SecureString {
+	return api.SecureString(base64.StdEncoding.EncodeToString([]byte(s)))
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name             string
+		adminKubeconfig  string
+		wantRotate       *KeyRotationProgress
+		wantRotateErr    string
+		wantVerifyBefore string
+		wantVerifyErr    string
+	}{
+		{
+			name:            "documents sealed with an old key are re-sealed",
+			adminKubeconfig: "v1:kubeconfig",
+			wantRotate: &KeyRotationProgress{
+				Documents:   3,
+				Rotated:     2,
+				KeyVersions: map[string]int{"v1": 2, "v2": 2},
+			},
+			wantVerifyBefore: "2 documents depend on key version v1",
+		},
+		{
+			name:            "documents which cannot be opened are reported",
+			adminKubeconfig: "v0:kubeconfig",
+			wantRotate: &KeyRotationProgress{
+				Documents:   3,
+				Rotated:     1,
+				Failed:      1,
+				KeyVersions: map[string]int{"v1": 1, "v2": 1},
+			},
+			wantRotateErr:    "1 documents could not be re-sealed",
+			wantVerifyBefore: "1 documents depend on key version v1",
+			wantVerifyErr:    "1 documents could not be checked",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewJSONHandle(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			openShiftClusters := cosmosdb.NewFakeOpenShiftClusterDocumentClient(h)
+			asyncOperations := cosmosdb.NewFakeAsyncOperationDocumentClient(h)
+			clusterManagerConfigurations := cosmosdb.NewFakeClusterManagerConfigurationDocumentClient(h)
+
+			_, err = openShiftClusters.Create(ctx, "partition", &api.OpenShiftClusterDocument{
+				ID:           "cluster",
+				PartitionKey: "partition",
+				OpenShiftCluster: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						AdminKubeconfig:   api.SecureBytes(tt.adminKubeconfig),
+						KubeadminPassword: sealedString("v2:password"),
+					},
+				},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = asyncOperations.Create(ctx, "asyncoperation", &api.AsyncOperationDocument{
+				ID: "asyncoperation",
+				OpenShiftCluster: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						ServicePrincipalProfile: api.ServicePrincipalProfile{
+							ClientSecret: sealedString("v1:secret"),
+						},
+					},
+				},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = clusterManagerConfigurations.Create(ctx, "partition", &api.ClusterManagerConfigurationDocument{
+				ID:           "secret",
+				PartitionKey: "partition",
+				Secret: &api.Secret{
+					Properties: api.SecretProperties{
+						SecretResources: sealedString("v2:resources"),
+					},
+				},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r := &keyRotation{
+				log: logrus.NewEntry(logrus.StandardLogger()),
+				aead: &fakeVersionedAEAD{
+					current: "v2",
+					known:   []string{"v1", "v2"},
+				},
+				batchSize: 1,
+				collections: []keyRotationCollection{
+					&asyncOperationsKeyRotation{c: asyncOperations},
+					&clusterManagerConfigurationsKeyRotation{c: clusterManagerConfigurations},
+					&openShiftClustersKeyRotation{c: openShiftClusters},
+				},
+			}
+			r.versioner = r.aead.(*fakeVersionedAEAD)
+
+			_, err = r.Verify(ctx, "v1")
+			utilerror.AssertErrorMessage(t, err, tt.wantVerifyBefore)
+
+			p, err := r.Rotate(ctx)
+			utilerror.AssertErrorMessage(t, err, tt.wantRotateErr)
+
+			if !reflect.DeepEqual(p, tt.wantRotate) {
+				t.Errorf("%#v", p)
+			}
+
+			p, err = r.Verify(ctx, "v1")
+			utilerror.AssertErrorMessage(t, err, tt.wantVerifyErr)
+
+			if p.KeyVersions["v1"] != 0 {
+				t.Error(p.KeyVersions)
+			}
+
+			asyncOperation, err := asyncOperations.Get(ctx, "asyncoperation", "asyncoperation", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if asyncOperation.OpenShiftCluster.Properties.ServicePrincipalProfile.ClientSecret != sealedString("v2:secret") {
+				t.Error(asyncOperation.OpenShiftCluster.Properties.ServicePrincipalProfile.ClientSecret)
+			}
+		})
+	}
+}
+
+// conflictingCollection changes each document behind the KeyRotation's back
+// the first time that it tries to replace it
+type conflictingCollection struct {
+	keyRotationCollection
+	update   func(context.Context, interface{}) error
+	replaced map[string]bool
+}
+
+func (c *conflictingCollection) replace(ctx context.Context, doc interface{}) error {
+	if !c.replaced[documentID(doc)] {
+		c.replaced[documentID(doc)] = true
+
+		err := c.update(ctx, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.keyRotationCollection.replace(ctx, doc)
+}
+
+func TestKeyRotationConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	h, err := NewJSONHandle(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openShiftClusters := cosmosdb.NewFakeOpenShiftClusterDocumentClient(h)
+
+	_, err = openShiftClusters.Create(ctx, "partition", &api.OpenShiftClusterDocument{
+		ID:           "cluster",
+		PartitionKey: "partition",
+		OpenShiftCluster: &api.OpenShiftCluster{
+			Properties: api.OpenShiftClusterProperties{
+				AdminKubeconfig: api.SecureBytes("v1:kubeconfig"),
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead := &fakeVersionedAEAD{
+		current: "v2",
+		known:   []string{"v1", "v2"},
+	}
+
+	r := &keyRotation{
+		log:       logrus.NewEntry(logrus.StandardLogger()),
+		aead:      aead,
+		versioner: aead,
+		batchSize: 10,
+		collections: []keyRotationCollection{
+			&conflictingCollection{
+				keyRotationCollection: &openShiftClustersKeyRotation{c: openShiftClusters},
+				update: func(ctx context.Context, doc interface{}) error {
+					current, err := openShiftClusters.Get(ctx, "partition", documentID(doc), nil)
+					if err != nil {
+						return err
+					}
+
+					current.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateUpdating
+
+					_, err = openShiftClusters.Replace(ctx, "partition", current, nil)
+					return err
+				},
+				replaced: map[string]bool{},
+			},
+		},
+	}
+
+	p, err := r.Rotate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Rotated != 1 {
+		t.Error(p.Rotated)
+	}
+
+	doc, err := openShiftClusters.Get(ctx, "partition", "cluster", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateUpdating {
+		t.Error("concurrent update was lost")
+	}
+
+	if string(doc.OpenShiftCluster.Properties.AdminKubeconfig) != "v2:kubeconfig" {
+		t.Error(string(doc.OpenShiftCluster.Properties.AdminKubeconfig))
+	}
+}
+
+// TestKeyRotationCollections checks that every document type which holds
+// encrypted fields is covered by KeyRotation
+func TestKeyRotationCollections(t *testing.T) {
+	documents := map[string]interface{}{
+		collAdminActions:      api.AdminActionDocument{},
+		collAsyncOperations:   api.AsyncOperationDocument{},
+		collBilling:           api.BillingDocument{},
+		collClusterHealth:     api.ClusterHealthDocument{},
+		collClusterManager:    api.ClusterManagerConfigurationDocument{},
+		collFleetOperations:   api.FleetOperationDocument{},
+		collGateway:           api.GatewayDocument{},
+		collMonitors:          api.MonitorDocument{},
+		collOpenShiftClusters: api.OpenShiftClusterDocument{},
+		collOpenShiftVersion:  api.OpenShiftVersionDocument{},
+		collPortal:            api.PortalDocument{},
+		collSubscriptions:     api.SubscriptionDocument{},
+	}
+
+	var want []string
+	for name, doc := range documents {
+		if hasSecureFields(reflect.TypeOf(doc), map[reflect.Type]bool{}) {
+			want = append(want, name)
+		}
+	}
+	sort.Strings(want)
+
+	dbc := cosmosdb.NewDatabaseClient(nil, nil, nil, "", nil)
+
+	r, err := NewKeyRotation(nil, dbc, "", &fakeVersionedAEAD{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range r.(*keyRotation).collections {
+		got = append(got, c.name())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func hasSecureFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == secureBytesType || t == secureStringType {
+		return true
+	}
+
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasSecureFields(t.Elem(), seen)
+	case reflect.Map:
+		return hasSecureFields(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && hasSecureFields(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -26,7 +26,7 @@ type Monitors interface {
 	TryLease(context.Context) (*api.MonitorDocument, error)
 	ListBuckets(context.Context) ([]int, error)
 	ListMonitors(context.Context) (*api.MonitorDocuments, error)
-	MonitorHeartbeat(context.Context) error
+	MonitorHeartbeat(context.Context, []api.BucketWorkTime) error
 }
 
 // NewMonitors returns a new Monitors
@@ -167,11 +167,18 @@ func (c *monitors) ListMonitors(ctx context.Context) (*api.MonitorDocuments, err
 	}, nil)
 }
 
-func (c *monitors) MonitorHeartbeat(ctx context.Context) error {
+// MonitorHeartbeat registers the monitor, with the measured work times of the
+// buckets it owns
+func (c *monitors) MonitorHeartbeat(ctx context.Context, workTimes []api.BucketWorkTime) error {
 	doc := &api.MonitorDocument{
 		ID:  c.uuid,
 		TTL: 60,
 	}
+	if len(workTimes) > 0 {
+		doc.Monitor = &api.Monitor{
+			WorkTimes: workTimes,
+		}
+	}
 	_, err := c.update(ctx, doc, &cosmosdb.Options{NoETag: true})
 	if err != nil && cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
 		_, err = c.Create(ctx, doc)
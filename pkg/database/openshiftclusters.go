@@ -48,6 +48,8 @@ type OpenShiftClusters interface {
 	ListAll(context.Context) (*api.OpenShiftClusterDocuments, error)
 	ListByPrefix(string, string, string) (cosmosdb.OpenShiftClusterDocumentIterator, error)
 	Dequeue(context.Context) (*api.OpenShiftClusterDocument, error)
+	ListQueued(context.Context) (*api.OpenShiftClusterDocuments, error)
+	DequeueDocument(context.Context, *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
 	Lease(context.Context, string) (*api.OpenShiftClusterDocument, error)
 	EndLease(context.Context, string, api.ProvisioningState, api.ProvisioningState, *string) (*api.OpenShiftClusterDocument, error)
 	GetByClientID(ctx context.Context, partitionKey, clientID string) (*api.OpenShiftClusterDocuments, error)
@@ -289,10 +291,8 @@ func (c *openShiftClusters) Dequeue(ctx context.Context) (*api.OpenShiftClusterD
 		}
 
 		for _, doc := range docs.OpenShiftClusterDocuments {
-			doc.LeaseOwner = c.uuid
-			doc.Dequeues++
-			doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
-			if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
+			doc, err = c.DequeueDocument(ctx, doc)
+			if doc == nil && err == nil { // someone else got there first
 				continue
 			}
 			return doc, err
@@ -300,6 +300,42 @@ func (c *openShiftClusters) Dequeue(ctx context.Context) (*api.OpenShiftClusterD
 	}
 }
 
+// ListQueued returns all the documents which are waiting to be dequeued, so
+// that the caller can choose which to dequeue with DequeueDocument.
+func (c *openShiftClusters) ListQueued(ctx context.Context) (*api.OpenShiftClusterDocuments, error) {
+	i := c.c.Query("", &cosmosdb.Query{
+		Query: OpenShiftClustersDequeueQuery,
+	}, nil)
+
+	all := &api.OpenShiftClusterDocuments{}
+	for {
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if docs == nil {
+			break
+		}
+
+		all.OpenShiftClusterDocuments = append(all.OpenShiftClusterDocuments, docs.OpenShiftClusterDocuments...)
+	}
+
+	all.Count = len(all.OpenShiftClusterDocuments)
+	return all, nil
+}
+
+// DequeueDocument takes the lease on a document returned by ListQueued.  It
+// returns nil and no error if someone else got there first.
+func (c *openShiftClusters) DequeueDocument(ctx context.Context, doc *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error) {
+	doc.LeaseOwner = c.uuid
+	doc.Dequeues++
+	doc, err := c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) {
+		return nil, nil
+	}
+	return doc, err
+}
+
 func (c *openShiftClusters) Lease(ctx context.Context, key string) (*api.OpenShiftClusterDocument, error) {
 	return c.patchWithLease(ctx, key, func(doc *api.OpenShiftClusterDocument) error {
 		return nil
@@ -328,6 +364,7 @@ func (c *openShiftClusters) EndLease(ctx context.Context, key string, provisioni
 			doc.CorrelationData = nil
 			doc.OpenShiftCluster.Properties.LastProvisioningState = ""
 			doc.AsyncOperationID = ""
+			doc.StepCheckpoint = nil
 		}
 
 		return nil
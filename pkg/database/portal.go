@@ -14,6 +14,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
 
+const (
+	PortalElevatedAccessQuery = `SELECT * FROM Portals doc WHERE doc.portal.id = @id AND IS_DEFINED(doc.portal.elevatedAccess)`
+	PortalKubeconfigsQuery    = `SELECT * FROM Portals doc WHERE doc.portal.id = @id AND IS_DEFINED(doc.portal.kubeconfig)`
+)
+
 type portals struct {
 	c             cosmosdb.PortalDocumentClient
 	uuidGenerator uuid.Generator
@@ -24,6 +29,9 @@ type Portal interface {
 	Create(context.Context, *api.PortalDocument) (*api.PortalDocument, error)
 	Get(context.Context, string) (*api.PortalDocument, error)
 	Patch(context.Context, string, func(*api.PortalDocument) error) (*api.PortalDocument, error)
+	ListElevatedAccess(context.Context, string) (*api.PortalDocuments, error)
+	ListKubeconfigs(context.Context, string) (*api.PortalDocuments, error)
+	Delete(context.Context, *api.PortalDocument) error
 	NewUUID() string
 }
 
@@ -88,3 +96,39 @@ func (c *portals) Patch(ctx context.Context, id string, f func(*api.PortalDocume
 
 	return doc, err
 }
+
+// ListElevatedAccess returns the just-in-time elevated access requests for the
+// cluster with the given resource ID
+func (c *portals) ListElevatedAccess(ctx context.Context, resourceID string) (*api.PortalDocuments, error) {
+	return c.listByResourceID(ctx, PortalElevatedAccessQuery, resourceID)
+}
+
+// ListKubeconfigs returns the outstanding kubeconfig tokens for the cluster
+// with the given resource ID
+func (c *portals) ListKubeconfigs(ctx context.Context, resourceID string) (*api.PortalDocuments, error) {
+	return c.listByResourceID(ctx, PortalKubeconfigsQuery, resourceID)
+}
+
+func (c *portals) listByResourceID(ctx context.Context, query, resourceID string) (*api.PortalDocuments, error) {
+	if resourceID != strings.ToLower(resourceID) {
+		return nil, fmt.Errorf("resourceID %q is not lower case", resourceID)
+	}
+
+	return c.c.QueryAll(ctx, "", &cosmosdb.Query{
+		Query: query,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@id",
+				Value: resourceID,
+			},
+		},
+	}, nil)
+}
+
+func (c *portals) Delete(ctx context.Context, doc *api.PortalDocument) error {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Delete(ctx, doc.ID, doc, &cosmosdb.Options{NoETag: true})
+}
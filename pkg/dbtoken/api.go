@@ -3,6 +3,11 @@ package dbtoken
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	"time"
+)
+
 type tokenResponse struct {
-	Token string `json:"token,omitempty"`
+	Token     string     `json:"token,omitempty"`
+	ExpiresOn *time.Time `json:"expiresOn,omitempty"`
 }
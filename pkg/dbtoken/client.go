@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 
@@ -17,7 +18,9 @@ import (
 )
 
 type Client interface {
-	Token(context.Context, string) (string, error)
+	// Token returns a resource token for the permission and when it expires.
+	// The expiry is zero if the server does not report it.
+	Token(context.Context, string) (string, time.Time, error)
 }
 
 type doer interface {
@@ -46,10 +49,10 @@ func NewClient(_env env.Core, authorizer autorest.Authorizer, insecureSkipVerify
 	}
 }
 
-func (c *client) Token(ctx context.Context, permission string) (string, error) {
+func (c *client) Token(ctx context.Context, permission string) (string, time.Time, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/token", nil)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	q := url.Values{
@@ -60,10 +63,15 @@ func (c *client) Token(ctx context.Context, permission string) (string, error) {
 	var tr *tokenResponse
 	err = c.do(req, &tr)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
-	return tr.Token, nil
+	var expiresOn time.Time
+	if tr.ExpiresOn != nil {
+		expiresOn = *tr.ExpiresOn
+	}
+
+	return tr.Token, expiresOn, nil
 }
 
 func (c *client) do(req *http.Request, i interface{}) (err error) {
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest"
 
@@ -39,10 +40,11 @@ func TestClient(t *testing.T) {
 	ctx := context.Background()
 
 	for _, tt := range []struct {
-		name       string
-		fakeClient *fakeClient
-		wantToken  string
-		wantErr    string
+		name          string
+		fakeClient    *fakeClient
+		wantToken     string
+		wantExpiresOn time.Time
+		wantErr       string
 	}{
 		{
 			name: "works",
@@ -59,6 +61,22 @@ func TestClient(t *testing.T) {
 			},
 			wantToken: "token",
 		},
+		{
+			name: "works with expiry",
+			fakeClient: &fakeClient{
+				wantMethod: http.MethodPost,
+				wantURL:    "https://localhost/token?permission=permission",
+				resp: &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						"Content-Type": []string{"application/json"},
+					},
+					Body: io.NopCloser(strings.NewReader(`{"token":"token","expiresOn":"2026-01-01T13:00:00Z"}`)),
+				},
+			},
+			wantToken:     "token",
+			wantExpiresOn: time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
 		{
 			name: "404",
 			fakeClient: &fakeClient{
@@ -93,12 +111,16 @@ func TestClient(t *testing.T) {
 				url:        "https://localhost",
 			}
 
-			token, err := c.Token(ctx, "permission")
+			token, expiresOn, err := c.Token(ctx, "permission")
 			utilerror.AssertErrorMessage(t, err, tt.wantErr)
 
 			if token != tt.wantToken {
 				t.Error(token)
 			}
+
+			if !expiresOn.Equal(tt.wantExpiresOn) {
+				t.Error(expiresOn)
+			}
 		})
 	}
 }
@@ -6,30 +6,126 @@ package dbtoken
 import (
 	"context"
 	"net/http"
-	"os"
+	"sort"
 
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 )
 
-func ConfigurePermissions(ctx context.Context, dbid string, userc cosmosdb.UserClient) error {
-	gateway := os.Getenv("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID")
+// ConfigurePermissions makes the database users and permissions match those
+// needed by the clients in the policy.  Permissions which differ from the
+// policy are replaced, and users and permissions which are not in the policy
+// are deleted, so that the database never grants more than the policy does.
+func ConfigurePermissions(ctx context.Context, dbid string, userc cosmosdb.UserClient, policy Policy) error {
+	return configurePermissions(ctx, dbid, userc, func(userid string) cosmosdb.PermissionClient {
+		return cosmosdb.NewPermissionClient(userc, userid)
+	}, policy)
+}
+
+func configurePermissions(ctx context.Context, dbid string, userc cosmosdb.UserClient, permissionClientFactory func(userid string) cosmosdb.PermissionClient, policy Policy) error {
+	// wanted holds the permissions of each client, by permission name
+	wanted := map[string]map[string]*cosmosdb.Permission{}
+	for name, pp := range policy {
+		for _, client := range pp.Clients {
+			if wanted[client] == nil {
+				wanted[client] = map[string]*cosmosdb.Permission{}
+			}
+			wanted[client][name] = &cosmosdb.Permission{
+				ID:             name,
+				PermissionMode: pp.Mode,
+				Resource:       "dbs/" + dbid + "/colls/" + pp.Collection,
+			}
+		}
+	}
+
+	clients := make([]string, 0, len(wanted))
+	for client := range wanted {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	for _, client := range clients {
+		_, err := userc.Create(ctx, &cosmosdb.User{
+			ID: client,
+		})
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+			return err
+		}
+
+		err = configureUserPermissions(ctx, permissionClientFactory(client), wanted[client])
+		if err != nil {
+			return err
+		}
+	}
 
-	_, err := userc.Create(ctx, &cosmosdb.User{
-		ID: gateway,
-	})
-	if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+	users, err := userc.ListAll(ctx)
+	if err != nil {
 		return err
 	}
 
-	permc := cosmosdb.NewPermissionClient(userc, gateway)
-	_, err = permc.Create(ctx, &cosmosdb.Permission{
-		ID:             "gateway",
-		PermissionMode: cosmosdb.PermissionModeRead,
-		Resource:       "dbs/" + dbid + "/colls/Gateway",
-	})
-	if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+	for _, user := range users.Users {
+		if wanted[user.ID] != nil {
+			continue
+		}
+
+		// deleting a user deletes its permissions
+		err = userc.Delete(ctx, user)
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// configureUserPermissions makes the permissions of a user match wanted
+func configureUserPermissions(ctx context.Context, permc cosmosdb.PermissionClient, wanted map[string]*cosmosdb.Permission) error {
+	names := make([]string, 0, len(wanted))
+	for name := range wanted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, err := permc.Create(ctx, wanted[name])
+		if cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+			err = replacePermission(ctx, permc, wanted[name])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	permissions, err := permc.ListAll(ctx)
+	if err != nil {
 		return err
 	}
 
+	for _, permission := range permissions.Permissions {
+		if wanted[permission.ID] != nil {
+			continue
+		}
+
+		err = permc.Delete(ctx, permission)
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// replacePermission replaces an existing permission with p, unless it already
+// grants the same access
+func replacePermission(ctx context.Context, permc cosmosdb.PermissionClient, p *cosmosdb.Permission) error {
+	existing, err := permc.Get(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing.PermissionMode == p.PermissionMode && existing.Resource == p.Resource {
+		return nil
+	}
+
+	_, err = permc.Replace(ctx, p)
+	return err
+}
@@ -0,0 +1,139 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	mock_cosmosdb "github.com/Azure/ARO-RP/pkg/util/mocks/cosmosdb"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestConfigurePermissions(t *testing.T) {
+	ctx := context.Background()
+
+	policy := Policy{
+		"gateway": {
+			Clients:    []string{"gateway-client"},
+			Collection: "Gateway",
+			Mode:       cosmosdb.PermissionModeRead,
+		},
+	}
+
+	gatewayPermission := &cosmosdb.Permission{
+		ID:             "gateway",
+		PermissionMode: cosmosdb.PermissionModeRead,
+		Resource:       "dbs/db/colls/Gateway",
+	}
+
+	conflict := &cosmosdb.Error{StatusCode: http.StatusConflict}
+
+	for _, tt := range []struct {
+		name    string
+		mocks   func(*mock_cosmosdb.MockUserClient, *mock_cosmosdb.MockPermissionClient)
+		wantErr string
+	}{
+		{
+			name: "users and permissions are created",
+			mocks: func(userc *mock_cosmosdb.MockUserClient, permc *mock_cosmosdb.MockPermissionClient) {
+				userc.EXPECT().Create(gomock.Any(), &cosmosdb.User{ID: "gateway-client"}).Return(nil, nil)
+				permc.EXPECT().Create(gomock.Any(), gatewayPermission).Return(nil, nil)
+				permc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Permissions{
+					Permissions: []*cosmosdb.Permission{gatewayPermission},
+				}, nil)
+				userc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Users{
+					Users: []*cosmosdb.User{{ID: "gateway-client"}},
+				}, nil)
+			},
+		},
+		{
+			name: "existing permissions which match the policy are kept",
+			mocks: func(userc *mock_cosmosdb.MockUserClient, permc *mock_cosmosdb.MockPermissionClient) {
+				userc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, conflict)
+				permc.EXPECT().Create(gomock.Any(), gatewayPermission).Return(nil, conflict)
+				permc.EXPECT().Get(gomock.Any(), "gateway").Return(&cosmosdb.Permission{
+					ID:             "gateway",
+					PermissionMode: cosmosdb.PermissionModeRead,
+					Resource:       "dbs/db/colls/Gateway",
+					ETag:           "etag",
+				}, nil)
+				permc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Permissions{
+					Permissions: []*cosmosdb.Permission{gatewayPermission},
+				}, nil)
+				userc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Users{
+					Users: []*cosmosdb.User{{ID: "gateway-client"}},
+				}, nil)
+			},
+		},
+		{
+			name: "existing permissions which differ from the policy are replaced",
+			mocks: func(userc *mock_cosmosdb.MockUserClient, permc *mock_cosmosdb.MockPermissionClient) {
+				userc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, conflict)
+				permc.EXPECT().Create(gomock.Any(), gatewayPermission).Return(nil, conflict)
+				permc.EXPECT().Get(gomock.Any(), "gateway").Return(&cosmosdb.Permission{
+					ID:             "gateway",
+					PermissionMode: cosmosdb.PermissionModeAll,
+					Resource:       "dbs/db/colls/Gateway",
+					ETag:           "etag",
+				}, nil)
+				permc.EXPECT().Replace(gomock.Any(), gatewayPermission).Return(nil, nil)
+				permc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Permissions{
+					Permissions: []*cosmosdb.Permission{gatewayPermission},
+				}, nil)
+				userc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Users{
+					Users: []*cosmosdb.User{{ID: "gateway-client"}},
+				}, nil)
+			},
+		},
+		{
+			name: "users and permissions which are not in the policy are deleted",
+			mocks: func(userc *mock_cosmosdb.MockUserClient, permc *mock_cosmosdb.MockPermissionClient) {
+				stalePermission := &cosmosdb.Permission{ID: "billing", ETag: "etag"}
+				staleUser := &cosmosdb.User{ID: "old-client", ETag: "etag"}
+
+				userc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, conflict)
+				permc.EXPECT().Create(gomock.Any(), gatewayPermission).Return(nil, nil)
+				permc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Permissions{
+					Permissions: []*cosmosdb.Permission{gatewayPermission, stalePermission},
+				}, nil)
+				permc.EXPECT().Delete(gomock.Any(), stalePermission).Return(nil)
+				userc.EXPECT().ListAll(gomock.Any()).Return(&cosmosdb.Users{
+					Users: []*cosmosdb.User{{ID: "gateway-client"}, staleUser},
+				}, nil)
+				userc.EXPECT().Delete(gomock.Any(), staleUser).Return(nil)
+			},
+		},
+		{
+			name: "errors are returned",
+			mocks: func(userc *mock_cosmosdb.MockUserClient, permc *mock_cosmosdb.MockPermissionClient) {
+				userc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, nil)
+				permc.EXPECT().Create(gomock.Any(), gatewayPermission).Return(nil, errors.New("sad database"))
+			},
+			wantErr: "sad database",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			userc := mock_cosmosdb.NewMockUserClient(controller)
+			permc := mock_cosmosdb.NewMockPermissionClient(controller)
+			tt.mocks(userc, permc)
+
+			err := configurePermissions(ctx, "db", userc, func(userid string) cosmosdb.PermissionClient {
+				if userid != "gateway-client" {
+					t.Errorf("unexpected user %q", userid)
+				}
+				return permc
+			}, policy)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
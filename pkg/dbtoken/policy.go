@@ -0,0 +1,107 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+const (
+	defaultTokenTTL = time.Hour
+	minTokenTTL     = 10 * time.Minute
+	// maxTokenTTL is the longest validity Cosmos DB allows for a resource token
+	maxTokenTTL = 5 * time.Hour
+)
+
+// PermissionPolicy describes a permission which the dbtoken service issues
+// resource tokens for
+type PermissionPolicy struct {
+	// Clients are the object IDs of the service principals which may request
+	// the permission
+	Clients []string
+
+	Collection string
+	Mode       cosmosdb.PermissionMode
+
+	// TTL is how long the resource tokens issued for the permission are valid
+	TTL time.Duration
+}
+
+// Policy is the central definition of which permissions each client of the
+// dbtoken service may request, keyed by permission name.  A permission which is
+// not in the policy is never issued, whatever exists in the database.
+type Policy map[string]*PermissionPolicy
+
+// NewPolicy returns the policy of the dbtoken service.  The TTLs of its
+// permissions can be overridden by setting DBTOKEN_PERMISSION_TTLS to a comma
+// separated list of permission=duration pairs, e.g. "gateway=30m".
+func NewPolicy() (Policy, error) {
+	p := Policy{
+		"gateway": {
+			Clients:    []string{os.Getenv("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID")},
+			Collection: "Gateway",
+			Mode:       cosmosdb.PermissionModeRead,
+			TTL:        defaultTokenTTL,
+		},
+	}
+
+	err := p.setTTLs(os.Getenv("DBTOKEN_PERMISSION_TTLS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p Policy) setTTLs(s string) error {
+	if s == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("invalid permission TTL %q", pair)
+		}
+
+		pp, found := p[name]
+		if !found {
+			return fmt.Errorf("permission %q is not in the dbtoken policy", name)
+		}
+
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid TTL for permission %q: %w", name, err)
+		}
+
+		if ttl < minTokenTTL || ttl > maxTokenTTL {
+			return fmt.Errorf("TTL %s for permission %q must be between %s and %s", ttl, name, minTokenTTL, maxTokenTTL)
+		}
+
+		pp.TTL = ttl
+	}
+
+	return nil
+}
+
+// permission returns the policy of the permission if client may request it
+func (p Policy) permission(client, permission string) (*PermissionPolicy, bool) {
+	pp, found := p[permission]
+	if !found {
+		return nil, false
+	}
+
+	for _, c := range pp.Clients {
+		if c != "" && strings.EqualFold(c, client) {
+			return pp, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,108 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestPolicySetTTLs(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		ttls    string
+		wantTTL time.Duration
+		wantErr string
+	}{
+		{
+			name:    "default",
+			wantTTL: defaultTokenTTL,
+		},
+		{
+			name:    "override",
+			ttls:    " gateway=30m ",
+			wantTTL: 30 * time.Minute,
+		},
+		{
+			name:    "unknown permission",
+			ttls:    "gateway=30m,other=1h",
+			wantErr: `permission "other" is not in the dbtoken policy`,
+		},
+		{
+			name:    "missing duration",
+			ttls:    "gateway",
+			wantErr: `invalid permission TTL "gateway"`,
+		},
+		{
+			name:    "invalid duration",
+			ttls:    "gateway=soon",
+			wantErr: `invalid TTL for permission "gateway": time: invalid duration "soon"`,
+		},
+		{
+			name:    "too long",
+			ttls:    "gateway=6h",
+			wantErr: `TTL 6h0m0s for permission "gateway" must be between 10m0s and 5h0m0s`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Policy{
+				"gateway": {
+					TTL: defaultTokenTTL,
+				},
+			}
+
+			err := p.setTTLs(tt.ttls)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if tt.wantErr == "" && p["gateway"].TTL != tt.wantTTL {
+				t.Error(p["gateway"].TTL)
+			}
+		})
+	}
+}
+
+func TestPolicyPermission(t *testing.T) {
+	p := Policy{
+		"gateway": {
+			Clients: []string{"", "AAAAAAAA-0000-0000-0000-000000000000"},
+		},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		client     string
+		permission string
+		wantOK     bool
+	}{
+		{
+			name:       "allowed, case insensitive",
+			client:     "aaaaaaaa-0000-0000-0000-000000000000",
+			permission: "gateway",
+			wantOK:     true,
+		},
+		{
+			name:       "empty client never matches",
+			permission: "gateway",
+		},
+		{
+			name:       "other client",
+			client:     "bbbbbbbb-0000-0000-0000-000000000000",
+			permission: "gateway",
+		},
+		{
+			name:       "permission not in policy",
+			client:     "aaaaaaaa-0000-0000-0000-000000000000",
+			permission: "other",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := p.permission(tt.client, tt.permission)
+			if ok != tt.wantOK {
+				t.Error(ok)
+			}
+		})
+	}
+}
@@ -33,6 +33,12 @@ type refresher struct {
 	permission string
 
 	lastRefresh atomic.Value //time.Time
+	// expiresOn is when the current token expires, or zero if unknown
+	expiresOn atomic.Value //time.Time
+
+	// refreshAfter is when the current token is next due to be refreshed
+	refreshAfter time.Time
+	now          func() time.Time
 
 	m              metrics.Emitter
 	metricPrefix   string
@@ -49,15 +55,22 @@ func NewRefresher(log *logrus.Entry, env env.Core, authorizer autorest.Authorize
 
 		m:            m,
 		metricPrefix: metricPrefix,
+
+		now: time.Now,
 	}
 }
 
+// checkRefreshAndReset reports whether the refresher is healthy: whether it
+// fetched a token since the last heartbeat or, between refreshes, still holds
+// a token which has not expired
 func (r *refresher) checkRefreshAndReset() bool {
 	if r.tokenRefreshed {
 		r.tokenRefreshed = false
 		return true
 	}
-	return false
+
+	expiresOn, _ := r.expiresOn.Load().(time.Time)
+	return !expiresOn.IsZero() && r.now().Before(expiresOn)
 }
 
 func (r *refresher) Run(ctx context.Context) error {
@@ -69,19 +82,29 @@ func (r *refresher) Run(ctx context.Context) error {
 	defer t.Stop()
 
 	for {
-		err := r.runOnce(ctx)
-		if err != nil {
-			r.log.Error(err)
-		} else {
-			r.lastRefresh.Store(time.Now())
-			r.tokenRefreshed = true
-		}
+		r.refresh(ctx)
 
 		<-t.C
 	}
 }
 
-func (r *refresher) runOnce(ctx context.Context) (err error) {
+// refresh calls runOnce and records when it last fetched a token
+func (r *refresher) refresh(ctx context.Context) {
+	refreshed, err := r.runOnce(ctx)
+	if err != nil {
+		r.log.Error(err)
+		return
+	}
+
+	if refreshed {
+		r.lastRefresh.Store(r.now())
+		r.tokenRefreshed = true
+	}
+}
+
+// runOnce fetches a new token if the current one is due to be refreshed.  It
+// returns false if it skipped the refresh.
+func (r *refresher) runOnce(ctx context.Context) (refreshed bool, err error) {
 	// extra hardening to prevent a panic under runOnce taking out the refresher
 	// goroutine
 	defer func() {
@@ -90,17 +113,30 @@ func (r *refresher) runOnce(ctx context.Context) (err error) {
 		}
 	}()
 
+	now := r.now()
+	if now.Before(r.refreshAfter) {
+		return false, nil
+	}
+
 	timeoutCtx, done := context.WithTimeout(ctx, time.Minute)
 	defer done()
 
-	token, err := r.c.Token(timeoutCtx, r.permission)
+	token, expiresOn, err := r.c.Token(timeoutCtx, r.permission)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	r.dbc.SetAuthorizer(cosmosdb.NewTokenAuthorizer(token))
+	r.expiresOn.Store(expiresOn)
+
+	// refresh once half of the token's validity has passed, leaving time to
+	// retry on failure.  Tokens without an expiry are refreshed on every run.
+	r.refreshAfter = time.Time{}
+	if !expiresOn.IsZero() {
+		r.refreshAfter = now.Add(expiresOn.Sub(now) / 2)
+	}
 
-	return nil
+	return true, nil
 }
 
 func (r *refresher) HasSyncedOnce() bool {
@@ -0,0 +1,111 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+type fakeTokenClient struct {
+	calls     int
+	expiresOn time.Time
+	err       error
+}
+
+func (c *fakeTokenClient) Token(context.Context, string) (string, time.Time, error) {
+	c.calls++
+	return "token", c.expiresOn, c.err
+}
+
+type fakeDatabaseClient struct {
+	cosmosdb.DatabaseClient
+	authorizer cosmosdb.Authorizer
+}
+
+func (c *fakeDatabaseClient) SetAuthorizer(authorizer cosmosdb.Authorizer) {
+	c.authorizer = authorizer
+}
+
+func TestRefresherRefresh(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	_, log := testlog.New()
+	c := &fakeTokenClient{expiresOn: start.Add(time.Hour)}
+	dbc := &fakeDatabaseClient{}
+
+	r := &refresher{
+		log: log,
+		c:   c,
+		dbc: dbc,
+		now: func() time.Time { return now },
+	}
+
+	if r.HasSyncedOnce() || r.checkRefreshAndReset() {
+		t.Fatal("expected no refresh yet")
+	}
+
+	// the first run fetches a token
+	r.refresh(ctx)
+	if c.calls != 1 || dbc.authorizer == nil {
+		t.Fatal(c.calls)
+	}
+	if lastRefresh, _ := r.lastRefresh.Load().(time.Time); !lastRefresh.Equal(start) {
+		t.Error(lastRefresh)
+	}
+	if !r.checkRefreshAndReset() {
+		t.Error("expected a refresh")
+	}
+
+	// runs before half the token's validity has passed skip the refresh and
+	// do not count as one, but the refresher stays healthy
+	now = start.Add(20 * time.Minute)
+	r.refresh(ctx)
+	if c.calls != 1 {
+		t.Fatal(c.calls)
+	}
+	if lastRefresh, _ := r.lastRefresh.Load().(time.Time); !lastRefresh.Equal(start) {
+		t.Error(lastRefresh)
+	}
+	if r.tokenRefreshed {
+		t.Error("skipped refresh recorded as a refresh")
+	}
+	if !r.checkRefreshAndReset() {
+		t.Error("expected a healthy refresher while the token is valid")
+	}
+
+	// failed refreshes are retried on the next run
+	now = start.Add(40 * time.Minute)
+	c.err = errors.New("dbtoken unavailable")
+	r.refresh(ctx)
+	if c.calls != 2 || r.tokenRefreshed {
+		t.Fatal(c.calls, r.tokenRefreshed)
+	}
+
+	now = start.Add(41 * time.Minute)
+	c.err = nil
+	c.expiresOn = now.Add(time.Hour)
+	r.refresh(ctx)
+	if c.calls != 3 || !r.tokenRefreshed {
+		t.Fatal(c.calls, r.tokenRefreshed)
+	}
+	if lastRefresh, _ := r.lastRefresh.Load().(time.Time); !lastRefresh.Equal(now) {
+		t.Error(lastRefresh)
+	}
+
+	// once the token has expired without a refresh, the refresher is
+	// unhealthy
+	r.tokenRefreshed = false
+	now = now.Add(2 * time.Hour)
+	if r.checkRefreshAndReset() {
+		t.Error("expected an unhealthy refresher once the token expired")
+	}
+}
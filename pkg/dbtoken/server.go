@@ -9,6 +9,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
@@ -24,6 +25,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
@@ -37,23 +39,29 @@ type Server interface {
 type server struct {
 	env                     env.Core
 	log                     *logrus.Entry
+	audit                   *logrus.Entry
 	accessLog               *logrus.Entry
 	l                       net.Listener
 	verifier                oidc.Verifier
-	permissionClientFactory func(userid string) cosmosdb.PermissionClient
+	policy                  Policy
+	permissionClientFactory func(userid string) cosmosdb.TokenPermissionClient
 	m                       metrics.Emitter
+
+	now func() time.Time
 }
 
 func NewServer(
 	ctx context.Context,
 	env env.Core,
 	log *logrus.Entry,
+	audit *logrus.Entry,
 	accessLog *logrus.Entry,
 	l net.Listener,
 	servingKey *rsa.PrivateKey,
 	servingCerts []*x509.Certificate,
 	verifier oidc.Verifier,
 	userc cosmosdb.UserClient,
+	policy Policy,
 	m metrics.Emitter,
 ) (Server, error) {
 	config := &tls.Config{
@@ -86,13 +94,16 @@ func NewServer(
 	return &server{
 		env:       env,
 		log:       log,
+		audit:     audit,
 		accessLog: accessLog,
 		l:         tls.NewListener(l, config),
 		verifier:  verifier,
-		permissionClientFactory: func(userid string) cosmosdb.PermissionClient {
-			return cosmosdb.NewPermissionClient(userc, userid)
+		policy:    policy,
+		permissionClientFactory: func(userid string) cosmosdb.TokenPermissionClient {
+			return cosmosdb.NewTokenPermissionClient(userc, userid)
 		},
-		m: m,
+		m:   m,
+		now: time.Now,
 	}, nil
 }
 
@@ -163,9 +174,18 @@ func (s *server) token(w http.ResponseWriter, r *http.Request) {
 	}
 
 	username, _ := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	pp, allowed := s.policy.permission(username, permission)
+	if !allowed {
+		s.deny(r, username, permission)
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
 	permc := s.permissionClientFactory(username)
 
-	perm, err := permc.Get(ctx, permission)
+	expiresOn := s.now().UTC().Add(pp.TTL)
+	perm, err := permc.GetWithExpiry(ctx, permission, pp.TTL)
 	if err != nil {
 		s.log.Error(err)
 		if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
@@ -176,12 +196,60 @@ func (s *server) token(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.m.EmitCounter("dbtoken.tokens.issued", 1, map[string]string{
+		"client":     username,
+		"permission": permission,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 
 	e := json.NewEncoder(w)
 	e.SetIndent("", "    ")
 
 	_ = e.Encode(&tokenResponse{
-		Token: perm.Token,
+		Token:     perm.Token,
+		ExpiresOn: &expiresOn,
 	})
 }
+
+// deny records a request for a permission which the policy does not allow the
+// client, in the audit log and metrics
+func (s *server) deny(r *http.Request, username, permission string) {
+	s.log.Warnf("denied permission %q to client %s", permission, username)
+
+	s.m.EmitCounter("dbtoken.tokens.denied", 1, map[string]string{
+		"client":     username,
+		"permission": permission,
+	})
+
+	s.audit.WithFields(logrus.Fields{
+		audit.MetadataCreatedTime:     s.now().UTC().Format(time.RFC3339),
+		audit.MetadataLogKind:         audit.IFXAuditLogKind,
+		audit.MetadataSource:          audit.SourceDBToken,
+		audit.MetadataAdminOperation:  false,
+		audit.EnvKeyAppID:             audit.SourceDBToken,
+		audit.EnvKeyCloudRole:         audit.CloudRoleRP,
+		audit.EnvKeyEnvironment:       s.env.Environment().Name,
+		audit.EnvKeyHostname:          s.env.Hostname(),
+		audit.EnvKeyLocation:          s.env.Location(),
+		audit.PayloadKeyCategory:      audit.CategoryAuthorization,
+		audit.PayloadKeyOperationName: fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+		audit.PayloadKeyCallerIdentities: []audit.CallerIdentity{
+			{
+				CallerIdentityType:  audit.CallerIdentityTypeObjectID,
+				CallerIdentityValue: username,
+				CallerIPAddress:     r.RemoteAddr,
+			},
+		},
+		audit.PayloadKeyTargetResources: []audit.TargetResource{
+			{
+				TargetResourceName: permission,
+				TargetResourceType: "permission",
+			},
+		},
+		audit.PayloadKeyResult: audit.Result{
+			ResultType:        audit.ResultTypeFail,
+			ResultDescription: fmt.Sprintf("Permission %q is not allowed for the client", permission),
+		},
+	}).Info(audit.DefaultLogMessage)
+}
@@ -10,25 +10,51 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
 	mock_cosmosdb "github.com/Azure/ARO-RP/pkg/util/mocks/cosmosdb"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 	"github.com/Azure/ARO-RP/test/util/listener"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
 )
 
 func TestServer(t *testing.T) {
 	ctx := context.Background()
 
+	mockCurrentTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := Policy{
+		"perm": {
+			Clients: []string{"00000000-0000-0000-0000-000000000000"},
+			TTL:     time.Hour,
+		},
+		"notexist": {
+			Clients: []string{"00000000-0000-0000-0000-000000000000"},
+			TTL:     time.Hour,
+		},
+		"gateway": {
+			Clients: []string{"11111111-1111-1111-1111-111111111111"},
+			TTL:     time.Hour,
+		},
+	}
+
 	for _, tt := range []struct {
 		name                    string
-		permissionClientFactory func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient
+		permissionClientFactory func(controller *gomock.Controller) func(userid string) cosmosdb.TokenPermissionClient
+		metrics                 func(*mock_metrics.MockEmitter)
 		req                     *http.Request
 		wantStatusCode          int
 		wantToken               string
+		wantExpiresOn           time.Time
+		wantAudit               []*audit.Payload
 	}{
 		{
 			name: "GET /random returns 404",
@@ -144,10 +170,10 @@ func TestServer(t *testing.T) {
 		},
 		{
 			name: "POST /token?permission=notexist returns 400",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "notexist").Return(nil, &cosmosdb.Error{StatusCode: http.StatusNotFound})
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.TokenPermissionClient {
+				return func(userid string) cosmosdb.TokenPermissionClient {
+					permc := mock_cosmosdb.NewMockTokenPermissionClient(controller)
+					permc.EXPECT().GetWithExpiry(gomock.Any(), "notexist", time.Hour).Return(nil, &cosmosdb.Error{StatusCode: http.StatusNotFound})
 					return permc
 				}
 			},
@@ -167,10 +193,10 @@ func TestServer(t *testing.T) {
 		},
 		{
 			name: "POST /token?permission=perm and database error returns 500",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(nil, errors.New("sad database"))
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.TokenPermissionClient {
+				return func(userid string) cosmosdb.TokenPermissionClient {
+					permc := mock_cosmosdb.NewMockTokenPermissionClient(controller)
+					permc.EXPECT().GetWithExpiry(gomock.Any(), "perm", time.Hour).Return(nil, errors.New("sad database"))
 					return permc
 				}
 			},
@@ -190,10 +216,10 @@ func TestServer(t *testing.T) {
 		},
 		{
 			name: "get /token?permission=perm returns 405",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(&cosmosdb.Permission{
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.TokenPermissionClient {
+				return func(userid string) cosmosdb.TokenPermissionClient {
+					permc := mock_cosmosdb.NewMockTokenPermissionClient(controller)
+					permc.EXPECT().GetWithExpiry(gomock.Any(), "perm", time.Hour).Return(&cosmosdb.Permission{
 						Token: "token",
 					}, nil)
 					return permc
@@ -215,10 +241,10 @@ func TestServer(t *testing.T) {
 		},
 		{
 			name: "POST /token?permission=perm returns 200",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(&cosmosdb.Permission{
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.TokenPermissionClient {
+				return func(userid string) cosmosdb.TokenPermissionClient {
+					permc := mock_cosmosdb.NewMockTokenPermissionClient(controller)
+					permc.EXPECT().GetWithExpiry(gomock.Any(), "perm", time.Hour).Return(&cosmosdb.Permission{
 						Token: "token",
 					}, nil)
 					return permc
@@ -236,8 +262,92 @@ func TestServer(t *testing.T) {
 					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
 				},
 			},
+			metrics: func(m *mock_metrics.MockEmitter) {
+				m.EXPECT().EmitCounter("dbtoken.tokens.issued", int64(1), map[string]string{
+					"client":     "00000000-0000-0000-0000-000000000000",
+					"permission": "perm",
+				})
+			},
 			wantStatusCode: http.StatusOK,
 			wantToken:      "token",
+			wantExpiresOn:  mockCurrentTime.Add(time.Hour),
+		},
+		{
+			name: "POST /token?permission=gateway returns 403 (not allowed for the client)",
+			req: &http.Request{
+				Method: http.MethodPost,
+				URL: &url.URL{
+					Scheme:   "http",
+					Host:     "localhost",
+					Path:     "/token",
+					RawQuery: "permission=gateway",
+				},
+				Header: http.Header{
+					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
+				},
+			},
+			metrics: func(m *mock_metrics.MockEmitter) {
+				m.EXPECT().EmitCounter("dbtoken.tokens.denied", int64(1), map[string]string{
+					"client":     "00000000-0000-0000-0000-000000000000",
+					"permission": "gateway",
+				})
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantAudit: []*audit.Payload{
+				{
+					EnvVer:               audit.IFXAuditVersion,
+					EnvName:              audit.IFXAuditName,
+					EnvFlags:             257,
+					EnvAppID:             audit.SourceDBToken,
+					EnvCloudName:         azureclient.PublicCloud.Name,
+					EnvCloudRole:         audit.CloudRoleRP,
+					EnvCloudRoleInstance: "testhost",
+					EnvCloudEnvironment:  azureclient.PublicCloud.Name,
+					EnvCloudLocation:     "eastus",
+					EnvCloudVer:          audit.IFXAuditCloudVer,
+					CallerIdentities: []audit.CallerIdentity{
+						{
+							CallerIdentityType:  audit.CallerIdentityTypeObjectID,
+							CallerIdentityValue: "00000000-0000-0000-0000-000000000000",
+							CallerIPAddress:     "bufferedpipe",
+						},
+					},
+					Category:      audit.CategoryAuthorization,
+					OperationName: "POST /token",
+					Result: audit.Result{
+						ResultType:        audit.ResultTypeFail,
+						ResultDescription: `Permission "gateway" is not allowed for the client`,
+					},
+					TargetResources: []audit.TargetResource{
+						{
+							TargetResourceType: "permission",
+							TargetResourceName: "gateway",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "POST /token?permission=unknown returns 403 (not in the policy)",
+			req: &http.Request{
+				Method: http.MethodPost,
+				URL: &url.URL{
+					Scheme:   "http",
+					Host:     "localhost",
+					Path:     "/token",
+					RawQuery: "permission=unknown",
+				},
+				Header: http.Header{
+					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
+				},
+			},
+			metrics: func(m *mock_metrics.MockEmitter) {
+				m.EXPECT().EmitCounter("dbtoken.tokens.denied", int64(1), map[string]string{
+					"client":     "00000000-0000-0000-0000-000000000000",
+					"permission": "unknown",
+				})
+			},
+			wantStatusCode: http.StatusForbidden,
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
@@ -247,11 +357,29 @@ func TestServer(t *testing.T) {
 			l := listener.NewListener()
 			defer l.Close()
 
+			_env := mock_env.NewMockCore(controller)
+			_env.EXPECT().Environment().AnyTimes().Return(&azureclient.PublicCloud)
+			_env.EXPECT().Hostname().AnyTimes().Return("testhost")
+			_env.EXPECT().Location().AnyTimes().Return("eastus")
+
+			m := mock_metrics.NewMockEmitter(controller)
+			m.EXPECT().EmitGauge("dbtoken.heartbeat", int64(1), nil).AnyTimes()
+			if tt.metrics != nil {
+				tt.metrics(m)
+			}
+
+			h, auditLog := testlog.NewAudit()
+
 			s := &server{
+				env:       _env,
 				log:       logrus.NewEntry(logrus.StandardLogger()),
+				audit:     auditLog,
 				accessLog: logrus.NewEntry(logrus.StandardLogger()),
 				l:         l,
 				verifier:  &oidc.NoopVerifier{},
+				policy:    policy,
+				m:         m,
+				now:       func() time.Time { return mockCurrentTime },
 			}
 
 			if tt.permissionClientFactory != nil {
@@ -278,6 +406,10 @@ func TestServer(t *testing.T) {
 				t.Error(resp.StatusCode)
 			}
 
+			if tt.wantAudit != nil {
+				testlog.AssertAuditPayloads(t, h, tt.wantAudit)
+			}
+
 			if tt.wantToken == "" {
 				return
 			}
@@ -295,6 +427,10 @@ func TestServer(t *testing.T) {
 			if tr.Token != tt.wantToken {
 				t.Error(tr.Token)
 			}
+
+			if tr.ExpiresOn == nil || !tr.ExpiresOn.Equal(tt.wantExpiresOn) {
+				t.Error(tr.ExpiresOn)
+			}
 		})
 	}
 }
@@ -440,6 +440,7 @@ KEYVAULT_PREFIX='$KEYVAULTPREFIX'
 MDM_ACCOUNT='$RPMDMACCOUNT'
 MDM_NAMESPACE=Portal
 PORTAL_HOSTNAME='$LOCATION.admin.$RPPARENTDOMAINNAME'
+PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT='${STORAGEACCOUNTDOMAIN%%.*}'
 RPIMAGE='$RPIMAGE'
 EOF
 
@@ -465,6 +466,7 @@ ExecStart=/usr/bin/docker run \
   -e MDM_ACCOUNT \
   -e MDM_NAMESPACE \
   -e PORTAL_HOSTNAME \
+  -e PORTAL_SSH_RECORDINGS_STORAGE_ACCOUNT \
   -m 2g \
   -p 444:8444 \
   -p 2222:2222 \
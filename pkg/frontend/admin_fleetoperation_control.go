@@ -0,0 +1,142 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+type fleetOperationResumeRequest struct {
+	FailureBudget *int `json:"failureBudget,omitempty"`
+}
+
+// /admin/fleetoperations/{fleetOperationId}/resume
+func (f *frontend) postAdminFleetOperationResume(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	body := ctx.Value(middleware.ContextKeyBody).([]byte)
+
+	b, err := f._postAdminFleetOperationResume(ctx, log, chi.URLParam(r, "fleetOperationId"), body)
+	adminReply(log, w, nil, b, err)
+}
+
+// _postAdminFleetOperationResume resumes a paused fleet operation.  Unless a
+// higher one is given, the failure budget is raised to the number of clusters
+// which have failed so far, so that the operation pauses again on the next
+// failure.
+func (f *frontend) _postAdminFleetOperationResume(ctx context.Context, log *logrus.Entry, id string, body []byte) ([]byte, error) {
+	req := &fleetOperationResumeRequest{}
+	if len(body) > 0 {
+		err := json.Unmarshal(body, req)
+		if err != nil {
+			return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content was invalid and could not be deserialized.")
+		}
+	}
+
+	_, err := f.getFleetOperationDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := f.dbFleetOperations.Patch(ctx, strings.ToLower(id), func(doc *api.FleetOperationDocument) error {
+		op := doc.FleetOperation
+		if op.State != api.FleetOperationStatePaused {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeRequestNotAllowed, "", "Request is not allowed on a fleet operation in state '%s'.", op.State)
+		}
+
+		failures := countFleetOperationTargets(op, api.FleetOperationTargetStateFailed)
+
+		budget := op.FailureBudget
+		if failures > budget {
+			budget = failures
+		}
+		if req.FailureBudget != nil {
+			if *req.FailureBudget < failures {
+				return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "failureBudget", "The provided failureBudget '%d' is invalid: %d clusters have already failed.", *req.FailureBudget, failures)
+			}
+			budget = *req.FailureBudget
+		}
+
+		op.FailureBudget = budget
+		op.State = api.FleetOperationStateRunning
+		op.Message = ""
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("fleet_operation", doc.ID).Infof("resumed fleet operation with failure budget %d", doc.FleetOperation.FailureBudget)
+
+	return json.MarshalIndent(doc.FleetOperation, "", "    ")
+}
+
+// /admin/fleetoperations/{fleetOperationId}/cancel
+func (f *frontend) postAdminFleetOperationCancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._postAdminFleetOperationCancel(ctx, log, chi.URLParam(r, "fleetOperationId"))
+	adminReply(log, w, nil, b, err)
+}
+
+// _postAdminFleetOperationCancel stops a fleet operation from updating any
+// more clusters.  Clusters which are already updating are left to finish; the
+// operation is Canceled once they have.
+func (f *frontend) _postAdminFleetOperationCancel(ctx context.Context, log *logrus.Entry, id string) ([]byte, error) {
+	_, err := f.getFleetOperationDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := f.dbFleetOperations.Patch(ctx, strings.ToLower(id), func(doc *api.FleetOperationDocument) error {
+		op := doc.FleetOperation
+		switch op.State {
+		case api.FleetOperationStateRunning, api.FleetOperationStatePaused:
+		default:
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeRequestNotAllowed, "", "Request is not allowed on a fleet operation in state '%s'.", op.State)
+		}
+
+		for _, target := range op.Targets {
+			if target.State == api.FleetOperationTargetStatePending {
+				target.State = api.FleetOperationTargetStateSkipped
+				target.Error = "The fleet operation was canceled."
+			}
+		}
+
+		op.State = api.FleetOperationStateCanceling
+		op.Message = ""
+		if countFleetOperationTargets(op, api.FleetOperationTargetStateRunning) == 0 {
+			now := f.now().UTC()
+			op.State = api.FleetOperationStateCanceled
+			op.EndTime = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("fleet_operation", doc.ID).Info("canceled fleet operation")
+
+	return json.MarshalIndent(doc.FleetOperation, "", "    ")
+}
+
+func countFleetOperationTargets(op *api.FleetOperation, state api.FleetOperationTargetState) (n int) {
+	for _, target := range op.Targets {
+		if target.State == state {
+			n++
+		}
+	}
+	return n
+}
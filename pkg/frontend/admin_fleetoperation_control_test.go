@@ -0,0 +1,195 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestPostAdminFleetOperationControl(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	fleetOperationID := "07070707-0707-0707-0707-070707070001"
+	startTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockCurrentTime := startTime.Add(time.Hour)
+
+	target := func(name string, state api.FleetOperationTargetState, err string) *api.FleetOperationTarget {
+		return &api.FleetOperationTarget{
+			ResourceID: testdatabase.GetResourcePath(mockSubID, name),
+			State:      state,
+			Error:      err,
+		}
+	}
+
+	fleetOperation := func(state api.FleetOperationState, failureBudget int, targets ...*api.FleetOperationTarget) *api.FleetOperation {
+		return &api.FleetOperation{
+			ID: fleetOperationID,
+			Selector: api.FleetSelector{
+				Locations: []string{"eastus"},
+			},
+			MaintenanceTask: api.MaintenanceTaskEverything,
+			MaxConcurrency:  1,
+			FailureBudget:   failureBudget,
+			State:           state,
+			StartTime:       startTime,
+			Targets:         targets,
+		}
+	}
+
+	paused := func() *api.FleetOperation {
+		op := fleetOperation(api.FleetOperationStatePaused, 0,
+			target("a", api.FleetOperationTargetStateFailed, "oh no!"),
+			target("b", api.FleetOperationTargetStateFailed, "oh no!"),
+			target("c", api.FleetOperationTargetStatePending, ""),
+		)
+		op.Message = "The fleet operation was paused after 2 clusters failed to update."
+		return op
+	}
+
+	type test struct {
+		name           string
+		operation      *api.FleetOperation
+		action         string
+		body           interface{}
+		wantStatusCode int
+		wantResponse   *api.FleetOperation
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "resume raises the failure budget to the failures so far",
+			operation:      paused(),
+			action:         "resume",
+			wantStatusCode: http.StatusOK,
+			wantResponse: fleetOperation(api.FleetOperationStateRunning, 2,
+				target("a", api.FleetOperationTargetStateFailed, "oh no!"),
+				target("b", api.FleetOperationTargetStateFailed, "oh no!"),
+				target("c", api.FleetOperationTargetStatePending, ""),
+			),
+		},
+		{
+			name:           "resume with a new failure budget",
+			operation:      paused(),
+			action:         "resume",
+			body:           map[string]int{"failureBudget": 5},
+			wantStatusCode: http.StatusOK,
+			wantResponse: fleetOperation(api.FleetOperationStateRunning, 5,
+				target("a", api.FleetOperationTargetStateFailed, "oh no!"),
+				target("b", api.FleetOperationTargetStateFailed, "oh no!"),
+				target("c", api.FleetOperationTargetStatePending, ""),
+			),
+		},
+		{
+			name:           "resume with a failure budget below the failures so far",
+			operation:      paused(),
+			action:         "resume",
+			body:           map[string]int{"failureBudget": 1},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: failureBudget: The provided failureBudget '1' is invalid: 2 clusters have already failed.",
+		},
+		{
+			name: "resume of a running operation",
+			operation: fleetOperation(api.FleetOperationStateRunning, 0,
+				target("a", api.FleetOperationTargetStatePending, ""),
+			),
+			action:         "resume",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: RequestNotAllowed: : Request is not allowed on a fleet operation in state 'Running'.",
+		},
+		{
+			name: "cancel skips pending targets and waits for running ones",
+			operation: fleetOperation(api.FleetOperationStateRunning, 0,
+				target("a", api.FleetOperationTargetStateSucceeded, ""),
+				target("b", api.FleetOperationTargetStateRunning, ""),
+				target("c", api.FleetOperationTargetStatePending, ""),
+			),
+			action:         "cancel",
+			wantStatusCode: http.StatusOK,
+			wantResponse: fleetOperation(api.FleetOperationStateCanceling, 0,
+				target("a", api.FleetOperationTargetStateSucceeded, ""),
+				target("b", api.FleetOperationTargetStateRunning, ""),
+				target("c", api.FleetOperationTargetStateSkipped, "The fleet operation was canceled."),
+			),
+		},
+		{
+			name:           "cancel of a paused operation with nothing running",
+			operation:      paused(),
+			action:         "cancel",
+			wantStatusCode: http.StatusOK,
+			wantResponse: func() *api.FleetOperation {
+				op := fleetOperation(api.FleetOperationStateCanceled, 0,
+					target("a", api.FleetOperationTargetStateFailed, "oh no!"),
+					target("b", api.FleetOperationTargetStateFailed, "oh no!"),
+					target("c", api.FleetOperationTargetStateSkipped, "The fleet operation was canceled."),
+				)
+				op.EndTime = &mockCurrentTime
+				return op
+			}(),
+		},
+		{
+			name: "cancel of a finished operation",
+			operation: fleetOperation(api.FleetOperationStateSucceeded, 0,
+				target("a", api.FleetOperationTargetStateSucceeded, ""),
+			),
+			action:         "cancel",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: RequestNotAllowed: : Request is not allowed on a fleet operation in state 'Succeeded'.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithFleetOperations()
+			defer ti.done()
+
+			err := ti.buildFixtures(func(f *testdatabase.Fixture) {
+				f.AddFleetOperationDocuments(&api.FleetOperationDocument{
+					ID:             fleetOperationID,
+					FleetOperation: tt.operation,
+				})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, nil, nil, nil, ti.fleetOperationsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return mockCurrentTime }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost, "https://server/admin/fleetoperations/"+fleetOperationID+"/"+tt.action,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				}, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantResponse != nil {
+				ti.checker.AddFleetOperationDocuments(&api.FleetOperationDocument{
+					ID:             fleetOperationID,
+					FleetOperation: tt.wantResponse,
+				})
+				for _, err := range ti.checker.CheckFleetOperations(ti.fleetOperationsClient) {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// /admin/fleetoperations
+func (f *frontend) getAdminFleetOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminFleetOperations(ctx)
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminFleetOperations(ctx context.Context) ([]byte, error) {
+	docs, err := f.dbFleetOperations.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the list omits the targets, which can be long; they are returned by
+	// the GET of an individual fleet operation
+	ops := make([]*api.FleetOperation, 0, len(docs.FleetOperationDocuments))
+	for _, doc := range docs.FleetOperationDocuments {
+		op := *doc.FleetOperation
+		op.Targets = nil
+		ops = append(ops, &op)
+	}
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].StartTime.After(ops[j].StartTime)
+	})
+
+	return json.MarshalIndent(map[string][]*api.FleetOperation{
+		"value": ops,
+	}, "", "    ")
+}
+
+// /admin/fleetoperations/{fleetOperationId}
+func (f *frontend) getAdminFleetOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminFleetOperation(ctx, chi.URLParam(r, "fleetOperationId"))
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminFleetOperation(ctx context.Context, id string) ([]byte, error) {
+	doc, err := f.getFleetOperationDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(doc.FleetOperation, "", "    ")
+}
+
+func (f *frontend) getFleetOperationDocument(ctx context.Context, id string) (*api.FleetOperationDocument, error) {
+	doc, err := f.dbFleetOperations.Get(ctx, strings.ToLower(id))
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The fleet operation '%s' was not found.", id)
+	case err != nil:
+		return nil, err
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,119 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestGetAdminFleetOperation(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	older := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	fleetOperation := func(id string, startTime time.Time) *api.FleetOperation {
+		return &api.FleetOperation{
+			ID: id,
+			Selector: api.FleetSelector{
+				Locations: []string{"eastus"},
+			},
+			MaintenanceTask: api.MaintenanceTaskEverything,
+			MaxConcurrency:  1,
+			State:           api.FleetOperationStateRunning,
+			StartTime:       startTime,
+			Targets: []*api.FleetOperationTarget{
+				{
+					ResourceID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+					State:      api.FleetOperationTargetStatePending,
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name           string
+		path           string
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+	}
+
+	listed := func(op *api.FleetOperation) *api.FleetOperation {
+		op.Targets = nil
+		return op
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "list returns the operations newest first without their targets",
+			path:           "/admin/fleetoperations",
+			wantStatusCode: http.StatusOK,
+			wantResponse: &map[string][]*api.FleetOperation{
+				"value": {
+					listed(fleetOperation("07070707-0707-0707-0707-070707070002", newer)),
+					listed(fleetOperation("07070707-0707-0707-0707-070707070001", older)),
+				},
+			},
+		},
+		{
+			name:           "get returns the operation with its targets",
+			path:           "/admin/fleetoperations/07070707-0707-0707-0707-070707070001",
+			wantStatusCode: http.StatusOK,
+			wantResponse:   fleetOperation("07070707-0707-0707-0707-070707070001", older),
+		},
+		{
+			name:           "get of an unknown operation",
+			path:           "/admin/fleetoperations/07070707-0707-0707-0707-070707070003",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The fleet operation '07070707-0707-0707-0707-070707070003' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithFleetOperations()
+			defer ti.done()
+
+			err := ti.buildFixtures(func(f *testdatabase.Fixture) {
+				f.AddFleetOperationDocuments(
+					&api.FleetOperationDocument{
+						ID:             "07070707-0707-0707-0707-070707070001",
+						FleetOperation: fleetOperation("07070707-0707-0707-0707-070707070001", older),
+					},
+					&api.FleetOperationDocument{
+						ID:             "07070707-0707-0707-0707-070707070002",
+						FleetOperation: fleetOperation("07070707-0707-0707-0707-070707070002", newer),
+					},
+				)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, nil, nil, nil, ti.fleetOperationsDatabase, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet, "https://server"+tt.path, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
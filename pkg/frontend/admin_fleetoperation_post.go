@@ -0,0 +1,202 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+const maxFleetOperationConcurrency = 50
+
+// /admin/fleetoperations
+func (f *frontend) postAdminFleetOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	body := ctx.Value(middleware.ContextKeyBody).([]byte)
+
+	b, err := f._postAdminFleetOperation(ctx, log, body)
+	if err == nil {
+		err = statusCodeError(http.StatusCreated)
+	}
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _postAdminFleetOperation(ctx context.Context, log *logrus.Entry, body []byte) ([]byte, error) {
+	var op *api.FleetOperation
+	err := json.Unmarshal(body, &op)
+	if err != nil || op == nil {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content was invalid and could not be deserialized.")
+	}
+
+	err = validateFleetOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := f.dbOpenShiftClusters.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*api.FleetOperationTarget
+	for _, doc := range docs.OpenShiftClusterDocuments {
+		if !fleetSelectorMatches(&op.Selector, doc) {
+			continue
+		}
+
+		switch doc.OpenShiftCluster.Properties.ProvisioningState {
+		case api.ProvisioningStateCreating, api.ProvisioningStateDeleting:
+			continue
+		}
+		if doc.OpenShiftCluster.Properties.FailedProvisioningState == api.ProvisioningStateCreating {
+			continue
+		}
+
+		targets = append(targets, &api.FleetOperationTarget{
+			ResourceID: doc.OpenShiftCluster.ID,
+			State:      api.FleetOperationTargetStatePending,
+		})
+	}
+
+	if len(targets) == 0 {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "selector", "The selector matched no clusters.")
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return strings.ToLower(targets[i].ResourceID) < strings.ToLower(targets[j].ResourceID)
+	})
+
+	id := f.dbFleetOperations.NewUUID()
+
+	op.ID = id
+	op.State = api.FleetOperationStateRunning
+	op.Message = ""
+	op.StartTime = f.now().UTC()
+	op.EndTime = nil
+	op.Targets = targets
+
+	doc, err := f.dbFleetOperations.Create(ctx, &api.FleetOperationDocument{
+		ID:             id,
+		FleetOperation: op,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithField("fleet_operation", id).Infof("created fleet operation %s on %d clusters", op.MaintenanceTask, len(targets))
+
+	return json.MarshalIndent(doc.FleetOperation, "", "    ")
+}
+
+// validateFleetOperation validates a new fleet operation and sets its defaults
+func validateFleetOperation(op *api.FleetOperation) error {
+	switch op.MaintenanceTask {
+	case api.MaintenanceTaskEverything, api.MaintenanceTaskOperator, api.MaintenanceTaskRenewCerts:
+	default:
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "maintenanceTask", "The provided maintenance task '%s' is invalid.", op.MaintenanceTask)
+	}
+
+	if op.MaxConcurrency == 0 {
+		op.MaxConcurrency = 1
+	}
+	if op.MaxConcurrency < 1 || op.MaxConcurrency > maxFleetOperationConcurrency {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "maxConcurrency", "The provided maxConcurrency '%d' is invalid: it must be between 1 and %d.", op.MaxConcurrency, maxFleetOperationConcurrency)
+	}
+
+	if op.FailureBudget < 0 {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "failureBudget", "The provided failureBudget '%d' is invalid: it must not be negative.", op.FailureBudget)
+	}
+
+	s := &op.Selector
+	if len(s.Locations) == 0 && len(s.SubscriptionIDs) == 0 &&
+		s.MinVersion == "" && s.MaxVersion == "" && len(s.OperatorFlags) == 0 {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "selector", "The selector must set at least one criterion.")
+	}
+
+	var minVersion, maxVersion *version.Version
+	var err error
+	if s.MinVersion != "" {
+		minVersion, err = version.ParseVersion(s.MinVersion)
+		if err != nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "selector.minVersion", "The provided minVersion '%s' is invalid.", s.MinVersion)
+		}
+	}
+	if s.MaxVersion != "" {
+		maxVersion, err = version.ParseVersion(s.MaxVersion)
+		if err != nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "selector.maxVersion", "The provided maxVersion '%s' is invalid.", s.MaxVersion)
+		}
+	}
+	if minVersion != nil && maxVersion != nil && maxVersion.Lt(minVersion) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "selector.maxVersion", "The provided maxVersion '%s' is lower than minVersion '%s'.", s.MaxVersion, s.MinVersion)
+	}
+
+	return nil
+}
+
+// fleetSelectorMatches returns true if the cluster matches every criterion
+// set in the selector
+func fleetSelectorMatches(s *api.FleetSelector, doc *api.OpenShiftClusterDocument) bool {
+	oc := doc.OpenShiftCluster
+
+	if len(s.Locations) > 0 && !containsFold(s.Locations, oc.Location) {
+		return false
+	}
+
+	if len(s.SubscriptionIDs) > 0 {
+		parts := strings.Split(oc.ID, "/")
+		if len(parts) < 3 || !containsFold(s.SubscriptionIDs, parts[2]) {
+			return false
+		}
+	}
+
+	if s.MinVersion != "" || s.MaxVersion != "" {
+		v, err := version.ParseVersion(oc.Properties.ClusterProfile.Version)
+		if err != nil {
+			return false
+		}
+
+		if s.MinVersion != "" {
+			minVersion, err := version.ParseVersion(s.MinVersion)
+			if err != nil || v.Lt(minVersion) {
+				return false
+			}
+		}
+
+		if s.MaxVersion != "" {
+			maxVersion, err := version.ParseVersion(s.MaxVersion)
+			if err != nil || maxVersion.Lt(v) {
+				return false
+			}
+		}
+	}
+
+	for k, v := range s.OperatorFlags {
+		if oc.Properties.OperatorFlags[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsFold(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if strings.EqualFold(s, needle) {
+			return true
+		}
+	}
+
+	return false
+}
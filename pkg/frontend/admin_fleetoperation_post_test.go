@@ -0,0 +1,231 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestPostAdminFleetOperation(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	otherSubID := "11111111-1111-1111-1111-111111111111"
+	fleetOperationID := "07070707-0707-0707-0707-070707070001"
+	mockCurrentTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clusterDoc := func(subID, name, location, version string, state api.ProvisioningState) *api.OpenShiftClusterDocument {
+		resourceID := testdatabase.GetResourcePath(subID, name)
+		return &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:       resourceID,
+				Name:     name,
+				Type:     "Microsoft.RedHatOpenShift/openShiftClusters",
+				Location: location,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: state,
+					ClusterProfile: api.ClusterProfile{
+						Version: version,
+					},
+				},
+			},
+		}
+	}
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(
+			clusterDoc(mockSubID, "a", "eastus", "4.12.25", api.ProvisioningStateSucceeded),
+			clusterDoc(mockSubID, "b", "westus", "4.12.25", api.ProvisioningStateSucceeded),
+			clusterDoc(mockSubID, "c", "eastus", "4.12.25", api.ProvisioningStateCreating),
+			clusterDoc(mockSubID, "d", "eastus", "4.13.4", api.ProvisioningStateFailed),
+			clusterDoc(otherSubID, "e", "EastUS", "4.11.44", api.ProvisioningStateSucceeded),
+		)
+	}
+
+	type test struct {
+		name           string
+		body           *api.FleetOperation
+		wantStatusCode int
+		wantResponse   *api.FleetOperation
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "creates an operation on the selected clusters",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					Locations: []string{"eastus"},
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+				FailureBudget:   1,
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &api.FleetOperation{
+				ID: fleetOperationID,
+				Selector: api.FleetSelector{
+					Locations: []string{"eastus"},
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+				MaxConcurrency:  1,
+				FailureBudget:   1,
+				State:           api.FleetOperationStateRunning,
+				StartTime:       mockCurrentTime,
+				Targets: []*api.FleetOperationTarget{
+					{
+						ResourceID: testdatabase.GetResourcePath(mockSubID, "a"),
+						State:      api.FleetOperationTargetStatePending,
+					},
+					{
+						ResourceID: testdatabase.GetResourcePath(mockSubID, "d"),
+						State:      api.FleetOperationTargetStatePending,
+					},
+					{
+						ResourceID: testdatabase.GetResourcePath(otherSubID, "e"),
+						State:      api.FleetOperationTargetStatePending,
+					},
+				},
+			},
+		},
+		{
+			name: "selects by subscription and version",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					SubscriptionIDs: []string{mockSubID},
+					MinVersion:      "4.12.0",
+					MaxVersion:      "4.12.99",
+				},
+				MaintenanceTask: api.MaintenanceTaskOperator,
+				MaxConcurrency:  5,
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &api.FleetOperation{
+				ID: fleetOperationID,
+				Selector: api.FleetSelector{
+					SubscriptionIDs: []string{mockSubID},
+					MinVersion:      "4.12.0",
+					MaxVersion:      "4.12.99",
+				},
+				MaintenanceTask: api.MaintenanceTaskOperator,
+				MaxConcurrency:  5,
+				State:           api.FleetOperationStateRunning,
+				StartTime:       mockCurrentTime,
+				Targets: []*api.FleetOperationTarget{
+					{
+						ResourceID: testdatabase.GetResourcePath(mockSubID, "a"),
+						State:      api.FleetOperationTargetStatePending,
+					},
+					{
+						ResourceID: testdatabase.GetResourcePath(mockSubID, "b"),
+						State:      api.FleetOperationTargetStatePending,
+					},
+				},
+			},
+		},
+		{
+			name: "invalid maintenance task",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					Locations: []string{"eastus"},
+				},
+				MaintenanceTask: api.MaintenanceTaskPucmPending,
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: maintenanceTask: The provided maintenance task 'PucmPending' is invalid.",
+		},
+		{
+			name: "invalid max concurrency",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					Locations: []string{"eastus"},
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+				MaxConcurrency:  51,
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: maxConcurrency: The provided maxConcurrency '51' is invalid: it must be between 1 and 50.",
+		},
+		{
+			name: "empty selector",
+			body: &api.FleetOperation{
+				MaintenanceTask: api.MaintenanceTaskEverything,
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: selector: The selector must set at least one criterion.",
+		},
+		{
+			name: "inverted version range",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					MinVersion: "4.13.0",
+					MaxVersion: "4.12.0",
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: selector.maxVersion: The provided maxVersion '4.12.0' is lower than minVersion '4.13.0'.",
+		},
+		{
+			name: "no cluster matches",
+			body: &api.FleetOperation{
+				Selector: api.FleetSelector{
+					OperatorFlags: map[string]string{"aro.imageconfig.enabled": "false"},
+				},
+				MaintenanceTask: api.MaintenanceTaskEverything,
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: selector: The selector matched no clusters.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithFleetOperations()
+			defer ti.done()
+
+			err := ti.buildFixtures(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, nil, nil, nil, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return mockCurrentTime }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost, "https://server/admin/fleetoperations",
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				}, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantResponse != nil {
+				ti.checker.AddFleetOperationDocuments(&api.FleetOperationDocument{
+					ID:             fleetOperationID,
+					FleetOperation: tt.wantResponse,
+				})
+			}
+			for _, err := range ti.checker.CheckFleetOperations(ti.fleetOperationsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -89,10 +89,10 @@ func Test_getAdminHiveClusterDeployment(t *testing.T) {
 			if tt.hiveEnabled {
 				clusterManager := mock_hive.NewMockClusterManager(controller)
 				clusterManager.EXPECT().GetClusterDeployment(gomock.Any(), gomock.Any()).Return(&clusterDeployment, nil).Times(tt.expectedGetClusterDeploymentCallCount)
-				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase,
+				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase,
 					ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, clusterManager, nil, nil, nil)
 			} else {
-				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase,
+				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase,
 					ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			}
 
@@ -0,0 +1,222 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/admin"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// maxAdminActionErrorBytes bounds how much of a failed response is kept to
+// record the error of an admin action
+const maxAdminActionErrorBytes = 4096
+
+type adminActionResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	body       []byte
+}
+
+func (w *adminActionResponseWriter) WriteHeader(statusCode int) {
+	w.ResponseWriter.WriteHeader(statusCode)
+	w.statusCode = statusCode
+}
+
+func (w *adminActionResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode >= http.StatusBadRequest && len(w.body) < maxAdminActionErrorBytes {
+		n := len(b)
+		if n > maxAdminActionErrorBytes-len(w.body) {
+			n = maxAdminActionErrorBytes - len(w.body)
+		}
+		w.body = append(w.body, b[:n]...)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *adminActionResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// recordAdminAction stores every admin action taken on a cluster in the
+// AdminActions database, so that what was done to a cluster can be queried
+// afterwards.  Reads are not recorded.
+func (f *frontend) recordAdminAction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// handlers rewrite r.URL.Path, so work out what is being done first
+		var resourceID, action string
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/admin/"):
+			resourceID = strings.TrimPrefix(filepath.Dir(r.URL.Path), "/admin")
+			action = strings.ToLower(filepath.Base(r.URL.Path))
+		case r.URL.Query().Get(api.APIVersionKey) == admin.APIVersion:
+			resourceID = r.URL.Path
+			action = adminActionForMethod(r.Method)
+		default:
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+		correlationData := ctx.Value(middleware.ContextKeyCorrelationData).(*api.CorrelationData)
+
+		body, _ := ctx.Value(middleware.ContextKeyBody).([]byte)
+
+		aa := &api.AdminAction{
+			ResourceID:          resourceID,
+			Action:              action,
+			Method:              r.Method,
+			Parameters:          adminActionParameters(r, body),
+			ClientPrincipalName: correlationData.ClientPrincipalName,
+			UserAgent:           r.UserAgent(),
+			CorrelationID:       correlationData.CorrelationID,
+			ClientRequestID:     correlationData.ClientRequestID,
+			RequestID:           correlationData.RequestID,
+			StartTime:           f.now().UTC(),
+		}
+
+		aw := &adminActionResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		defer func() {
+			aa.EndTime = f.now().UTC()
+			aa.Result = adminActionResult(aw.statusCode, aw.body)
+
+			// record the action even if the caller has gone away
+			err := f.createAdminAction(context.Background(), aa)
+			if err != nil {
+				log.Errorf("failed to record admin action: %v", err)
+			}
+		}()
+
+		h.ServeHTTP(aw, r)
+	})
+}
+
+func (f *frontend) createAdminAction(ctx context.Context, aa *api.AdminAction) error {
+	aa.ID = f.dbAdminActions.NewUUID()
+
+	_, err := f.dbAdminActions.Create(ctx, &api.AdminActionDocument{
+		ID:          aa.ID,
+		Key:         strings.ToLower(aa.ResourceID),
+		AdminAction: aa,
+	})
+	return err
+}
+
+func adminActionForMethod(method string) string {
+	switch method {
+	case http.MethodPut, http.MethodPatch:
+		return "adminupdate"
+	default:
+		return strings.ToLower(method)
+	}
+}
+
+// adminActionParameters returns the query parameters of an admin action.  The
+// body of the request is not recorded as it may contain secrets, but for
+// Kubernetes objects the kind, namespace and name of the object are.
+func adminActionParameters(r *http.Request, body []byte) map[string]string {
+	parameters := map[string]string{}
+
+	for k, v := range r.URL.Query() {
+		if k == api.APIVersionKey {
+			continue
+		}
+		parameters[k] = strings.Join(v, ",")
+	}
+
+	var obj struct {
+		Kind     string `json:"kind,omitempty"`
+		Metadata struct {
+			Namespace string `json:"namespace,omitempty"`
+			Name      string `json:"name,omitempty"`
+		} `json:"metadata,omitempty"`
+	}
+	if len(body) > 0 && json.Unmarshal(body, &obj) == nil && obj.Kind != "" {
+		parameters["kind"] = obj.Kind
+		if obj.Metadata.Namespace != "" {
+			parameters["namespace"] = obj.Metadata.Namespace
+		}
+		if obj.Metadata.Name != "" {
+			parameters["name"] = obj.Metadata.Name
+		}
+	}
+
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	return parameters
+}
+
+func adminActionResult(statusCode int, body []byte) api.AdminActionResult {
+	result := api.AdminActionResult{
+		StatusCode: statusCode,
+	}
+
+	if statusCode < http.StatusBadRequest {
+		return result
+	}
+
+	cloudErr := &api.CloudError{}
+	if json.Unmarshal(body, cloudErr) == nil && cloudErr.CloudErrorBody != nil {
+		result.Error = cloudErr.CloudErrorBody.Code + ": " + cloudErr.CloudErrorBody.Message
+	} else {
+		result.Error = strings.TrimSpace(string(body))
+	}
+
+	return result
+}
+
+func (f *frontend) getAdminOpenShiftClusterActionHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	resourceID := strings.TrimPrefix(filepath.Dir(r.URL.Path), "/admin")
+
+	b, err := f._getAdminOpenShiftClusterActionHistory(ctx, resourceID)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _getAdminOpenShiftClusterActionHistory returns the admin actions taken on a
+// cluster, newest first.  The cluster need not exist any more, so that the
+// history of a deleted cluster can still be reviewed.
+func (f *frontend) _getAdminOpenShiftClusterActionHistory(ctx context.Context, resourceID string) ([]byte, error) {
+	docs, err := f.dbAdminActions.ListByKey(ctx, strings.ToLower(resourceID))
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*api.AdminAction, 0, len(docs.AdminActionDocuments))
+	for _, doc := range docs.AdminActionDocuments {
+		actions = append(actions, doc.AdminAction)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].StartTime.After(actions[j].StartTime)
+	})
+
+	return json.MarshalIndent(map[string][]*api.AdminAction{
+		"value": actions,
+	}, "", "    ")
+}
@@ -0,0 +1,261 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestRecordAdminAction(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	mockTenantID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	mockCurrentTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type test struct {
+		name         string
+		method       string
+		path         string
+		fixture      func(*testdatabase.Fixture)
+		mocks        func(*mock_adminactions.MockAzureActions)
+		wantRecorded *api.AdminAction
+	}
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile: api.ClusterProfile{
+						ResourceGroupID: fmt.Sprintf("/subscriptions/%s/resourceGroups/test-cluster", mockSubID),
+					},
+				},
+			},
+		})
+		f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+			ID: mockSubID,
+			Subscription: &api.Subscription{
+				State: api.SubscriptionStateRegistered,
+				Properties: &api.SubscriptionProperties{
+					TenantID: mockTenantID,
+				},
+			},
+		})
+	}
+
+	recorded := func(statusCode int, err string) *api.AdminAction {
+		return &api.AdminAction{
+			ID:                  "08080808-0808-0808-0808-080808080001",
+			ResourceID:          strings.ToLower(resourceID),
+			Action:              "redeployvm",
+			Method:              http.MethodPost,
+			Parameters:          map[string]string{"vmName": "aro-worker-1"},
+			ClientPrincipalName: "someone@example.com",
+			UserAgent:           "test-agent",
+			CorrelationID:       "correlation-id",
+			StartTime:           mockCurrentTime,
+			EndTime:             mockCurrentTime,
+			Result: api.AdminActionResult{
+				StatusCode: statusCode,
+				Error:      err,
+			},
+		}
+	}
+
+	for _, tt := range []*test{
+		{
+			name:    "successful action is recorded",
+			method:  http.MethodPost,
+			path:    "/admin" + resourceID + "/redeployvm?vmName=aro-worker-1",
+			fixture: fixture,
+			mocks: func(a *mock_adminactions.MockAzureActions) {
+				a.EXPECT().VMRedeployAndWait(gomock.Any(), "aro-worker-1").Return(nil)
+			},
+			wantRecorded: recorded(http.StatusOK, ""),
+		},
+		{
+			name:    "failed action is recorded with its error",
+			method:  http.MethodPost,
+			path:    "/admin" + resourceID + "/redeployvm?vmName=aro-worker-1",
+			fixture: fixture,
+			mocks: func(a *mock_adminactions.MockAzureActions) {
+				a.EXPECT().VMRedeployAndWait(gomock.Any(), "aro-worker-1").Return(errors.New("oh no!"))
+			},
+			wantRecorded: recorded(http.StatusInternalServerError, "InternalServerError: Internal server error."),
+		},
+		{
+			name:   "action on a missing cluster is recorded",
+			method: http.MethodPost,
+			path:   "/admin" + resourceID + "/redeployvm?vmName=aro-worker-1",
+			wantRecorded: recorded(http.StatusNotFound,
+				"ResourceNotFound: The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found."),
+		},
+		{
+			name:   "reads are not recorded",
+			method: http.MethodGet,
+			path:   "/admin" + resourceID + "/actionhistory",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions()
+			defer ti.done()
+
+			a := mock_adminactions.NewMockAzureActions(ti.controller)
+			if tt.mocks != nil {
+				tt.mocks(a)
+			}
+
+			if tt.fixture != nil {
+				err := ti.buildFixtures(tt.fixture)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error) {
+				return a, nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return mockCurrentTime }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, _, err := ti.request(tt.method, "https://server"+tt.path,
+				http.Header{
+					"User-Agent":                  []string{"test-agent"},
+					"X-Ms-Client-Principal-Name":  []string{"someone@example.com"},
+					"X-Ms-Correlation-Request-Id": []string{"correlation-id"},
+				}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantRecorded != nil {
+				tt.wantRecorded.RequestID = resp.Header.Get("X-Ms-Request-Id")
+				ti.checker.AddAdminActionDocuments(&api.AdminActionDocument{
+					ID:          tt.wantRecorded.ID,
+					Key:         strings.ToLower(resourceID),
+					AdminAction: tt.wantRecorded,
+				})
+			}
+			for _, err := range ti.checker.CheckAdminActions(ti.adminActionsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGetAdminOpenShiftClusterActionHistory(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	otherResourceID := testdatabase.GetResourcePath(mockSubID, "otherResourceName")
+	older := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	adminAction := func(id, resourceID, action string, startTime time.Time) *api.AdminAction {
+		return &api.AdminAction{
+			ID:                  id,
+			ResourceID:          resourceID,
+			Action:              action,
+			Method:              http.MethodPost,
+			ClientPrincipalName: "someone@example.com",
+			StartTime:           startTime,
+			EndTime:             startTime.Add(time.Minute),
+			Result: api.AdminActionResult{
+				StatusCode: http.StatusOK,
+			},
+		}
+	}
+
+	type test struct {
+		name           string
+		resourceID     string
+		wantStatusCode int
+		wantResponse   interface{}
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "history is returned newest first",
+			resourceID:     resourceID,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &map[string][]*api.AdminAction{
+				"value": {
+					adminAction("08080808-0808-0808-0808-080808080002", resourceID, "stopvm", newer),
+					adminAction("08080808-0808-0808-0808-080808080001", resourceID, "redeployvm", older),
+				},
+			},
+		},
+		{
+			name:           "cluster without history",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "unknownResourceName"),
+			wantStatusCode: http.StatusOK,
+			wantResponse: &map[string][]*api.AdminAction{
+				"value": {},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t)
+			defer ti.done()
+
+			err := ti.buildFixtures(func(f *testdatabase.Fixture) {
+				for _, aa := range []*api.AdminAction{
+					adminAction("08080808-0808-0808-0808-080808080001", resourceID, "redeployvm", older),
+					adminAction("08080808-0808-0808-0808-080808080002", resourceID, "stopvm", newer),
+					adminAction("08080808-0808-0808-0808-080808080003", otherResourceID, "startvm", newer),
+				} {
+					f.AddAdminActionDocuments(&api.AdminActionDocument{
+						ID:          aa.ID,
+						Key:         strings.ToLower(aa.ResourceID),
+						AdminAction: aa,
+					})
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, nil, nil, nil, nil, nil, nil, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet, "https://server/admin"+tt.resourceID+"/actionhistory", nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, "", tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
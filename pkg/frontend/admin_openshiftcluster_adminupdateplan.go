@@ -0,0 +1,65 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/cluster"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/adminupdateplan
+func (f *frontend) getAdminOpenShiftClusterAdminUpdatePlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+	b, err := f._getAdminOpenShiftClusterAdminUpdatePlan(ctx, r)
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterAdminUpdatePlan(ctx context.Context, r *http.Request) ([]byte, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	task := api.MaintenanceTask(r.URL.Query().Get("maintenanceTask"))
+	switch task {
+	case "":
+		task = api.MaintenanceTaskEverything
+	case api.MaintenanceTaskEverything, api.MaintenanceTaskOperator, api.MaintenanceTaskRenewCerts, api.MaintenanceTaskPucmPending:
+	default:
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "maintenanceTask", "Invalid enum parameter.")
+	}
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+			"The Resource '%s/%s' under resource group '%s' was not found.",
+			resType, resName, resGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	adoptViaHive, err := f.env.LiveConfig().AdoptByHive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// doc is a local copy which is never written back, so the requested
+	// maintenance task can be set on it to build the plan
+	doc.OpenShiftCluster.Properties.MaintenanceTask = task
+
+	return json.MarshalIndent(cluster.AdminUpdatePlan(doc, adoptViaHive), "", "    ")
+}
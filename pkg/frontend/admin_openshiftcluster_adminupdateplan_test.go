@@ -0,0 +1,144 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/cluster"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	"github.com/Azure/ARO-RP/test/util/testliveconfig"
+)
+
+func TestAdminUpdatePlan(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+
+	type test struct {
+		name           string
+		resourceID     string
+		query          string
+		fixture        func(f *testdatabase.Fixture)
+		wantStatusCode int
+		wantSteps      []string
+		wantError      string
+	}
+
+	clusterFixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateSucceeded,
+					ClusterProfile: api.ClusterProfile{
+						Version: "4.10.0",
+					},
+				},
+			},
+		})
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "operator update plan",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			query:          "?maintenanceTask=OperatorUpdate",
+			fixture:        clusterFixture,
+			wantStatusCode: http.StatusOK,
+			wantSteps: []string{
+				"Action initializeKubernetesClients",
+				"Action ensureBillingRecord",
+				"Action ensureDefaults",
+				"AuthorizationRetryingAction fixupClusterSPObjectID",
+				"Action fixInfraID",
+				"Action startVMs",
+				"Condition apiServersReady 30m0s",
+				"Action initializeOperatorDeployer",
+				"Action ensureAROOperator",
+				"Condition aroDeploymentReady 20m0s",
+				"Condition ensureAROOperatorRunningDesiredVersion 5m0s",
+			},
+		},
+		{
+			name:           "invalid maintenance task",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			query:          "?maintenanceTask=Nonsense",
+			fixture:        clusterFixture,
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: maintenanceTask: Invalid enum parameter.",
+		},
+		{
+			name:           "cluster not found",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        func(f *testdatabase.Fixture) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      `404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_env := ti.env.(*mock_env.MockInterface)
+			_env.EXPECT().LiveConfig().AnyTimes().Return(testliveconfig.NewTestLiveConfig(false, false, false))
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet,
+				fmt.Sprintf("https://server/admin%s/adminupdateplan%s", tt.resourceID, tt.query),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantError != "" {
+				err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, nil)
+				if err != nil {
+					t.Error(err)
+				}
+				return
+			}
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Fatalf("unexpected status code %d, wanted %d: %s", resp.StatusCode, tt.wantStatusCode, string(b))
+			}
+
+			var plan []cluster.PlanStep
+			err = json.Unmarshal(b, &plan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotSteps []string
+			for _, s := range plan {
+				gotSteps = append(gotSteps, strings.TrimSpace(fmt.Sprintf("%s %s %s", s.Kind, s.Name, s.Timeout)))
+			}
+
+			for _, d := range deep.Equal(gotSteps, tt.wantSteps) {
+				t.Error(d)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/egresspolicy
+func (f *frontend) getAdminOpenShiftClusterEgressPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getAdminOpenShiftClusterEgressPolicy(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterEgressPolicy(ctx context.Context, r *http.Request) ([]byte, error) {
+	linkID, err := f.gatewayLinkID(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	gwyDoc, err := f.dbGateway.Get(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := gwyDoc.Gateway.EgressPolicy
+	if policy == nil {
+		policy = &api.GatewayEgressPolicy{}
+	}
+
+	return json.MarshalIndent(policy, "", "    ")
+}
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/egresspolicy
+func (f *frontend) putAdminOpenShiftClusterEgressPolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._putAdminOpenShiftClusterEgressPolicy(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _putAdminOpenShiftClusterEgressPolicy replaces the egress policy in the
+// cluster's gateway record.  Gateways pick up the change through the gateway
+// changefeed.
+func (f *frontend) _putAdminOpenShiftClusterEgressPolicy(ctx context.Context, r *http.Request) ([]byte, error) {
+	body := ctx.Value(middleware.ContextKeyBody).([]byte)
+
+	var policy *api.GatewayEgressPolicy
+	err := json.Unmarshal(body, &policy)
+	if err != nil {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content could not be deserialized: "+err.Error())
+	}
+
+	err = validateAdminEgressPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	linkID, err := f.gatewayLinkID(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	gwyDoc, err := f.dbGateway.Patch(ctx, linkID, func(doc *api.GatewayDocument) error {
+		if policy == nil || len(policy.Rules) == 0 {
+			doc.Gateway.EgressPolicy = nil
+		} else {
+			doc.Gateway.EgressPolicy = policy
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policy = gwyDoc.Gateway.EgressPolicy
+	if policy == nil {
+		policy = &api.GatewayEgressPolicy{}
+	}
+
+	return json.MarshalIndent(policy, "", "    ")
+}
+
+// gatewayLinkID returns the ID of the gateway record of the cluster
+func (f *frontend) gatewayLinkID(ctx context.Context, r *http.Request) (string, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return "", api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+			"The Resource '%s/%s' under resource group '%s' was not found.",
+			resType, resName, resGroupName)
+	case err != nil:
+		return "", err
+	}
+
+	linkID := doc.OpenShiftCluster.Properties.NetworkProfile.GatewayPrivateLinkID
+	if linkID == "" {
+		return "", api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "",
+			"The cluster does not use the gateway.")
+	}
+
+	return linkID, nil
+}
@@ -0,0 +1,190 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminEgressPolicy(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	policy := &api.GatewayEgressPolicy{
+		Rules: []api.GatewayEgressRule{
+			{
+				FQDNPattern: "myregistry.azurecr.io",
+			},
+			{
+				FQDNPattern: "*.example.com",
+				Ports:       []int{443, 8443},
+				ExpiresAt:   &expiresAt,
+				Reason:      "temporary access for a support case",
+			},
+		},
+	}
+
+	clusterFixture := func(linkID string) func(f *testdatabase.Fixture) {
+		return func(f *testdatabase.Fixture) {
+			f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				ID:  "00000000-0000-0000-0000-000000000001",
+				Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+					Properties: api.OpenShiftClusterProperties{
+						NetworkProfile: api.NetworkProfile{
+							GatewayPrivateLinkID: linkID,
+						},
+					},
+				},
+			})
+		}
+	}
+
+	gatewayFixture := func(policy *api.GatewayEgressPolicy) func(f *testdatabase.Fixture) {
+		return func(f *testdatabase.Fixture) {
+			clusterFixture("1234")(f)
+			f.AddGatewayDocuments(&api.GatewayDocument{
+				ID: "1234",
+				Gateway: &api.Gateway{
+					ID:           testdatabase.GetResourcePath(mockSubID, "resourceName"),
+					EgressPolicy: policy,
+				},
+			})
+		}
+	}
+
+	type test struct {
+		name           string
+		method         string
+		body           interface{}
+		fixture        func(f *testdatabase.Fixture)
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+		wantPolicy     *api.GatewayEgressPolicy
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "get policy",
+			method:         http.MethodGet,
+			fixture:        gatewayFixture(policy),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   policy,
+			wantPolicy:     policy,
+		},
+		{
+			name:           "get empty policy",
+			method:         http.MethodGet,
+			fixture:        gatewayFixture(nil),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("{}\n"),
+		},
+		{
+			name:           "put policy",
+			method:         http.MethodPut,
+			body:           policy,
+			fixture:        gatewayFixture(nil),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   policy,
+			wantPolicy:     policy,
+		},
+		{
+			name:           "put empty policy clears it",
+			method:         http.MethodPut,
+			body:           &api.GatewayEgressPolicy{},
+			fixture:        gatewayFixture(policy),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("{}\n"),
+		},
+		{
+			name:   "put invalid pattern",
+			method: http.MethodPut,
+			body: &api.GatewayEgressPolicy{
+				Rules: []api.GatewayEgressRule{{FQDNPattern: "*.*.example.com"}},
+			},
+			fixture:        gatewayFixture(policy),
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: rules[0].fqdnPattern: The provided FQDN pattern '*.*.example.com' is invalid.",
+			wantPolicy:     policy,
+		},
+		{
+			name:   "put invalid port",
+			method: http.MethodPut,
+			body: &api.GatewayEgressPolicy{
+				Rules: []api.GatewayEgressRule{{FQDNPattern: "example.com", Ports: []int{0}}},
+			},
+			fixture:        gatewayFixture(policy),
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: rules[0].ports[0]: The provided port '0' is invalid.",
+			wantPolicy:     policy,
+		},
+		{
+			name:           "cluster does not use the gateway",
+			method:         http.MethodGet,
+			fixture:        clusterFixture(""),
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidRequestContent: : The cluster does not use the gateway.",
+		},
+		{
+			name:           "cluster not found",
+			method:         http.MethodGet,
+			fixture:        func(f *testdatabase.Fixture) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      `404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions().WithGateway()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, ti.gatewayDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method,
+				fmt.Sprintf("https://server/admin%s/egresspolicy", testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				}, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			gwyDoc, err := ti.gatewayDatabase.Get(ctx, "1234")
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(gwyDoc.Gateway.EgressPolicy, tt.wantPolicy) {
+				t.Errorf("unexpected egress policy %#v", gwyDoc.Gateway.EgressPolicy)
+			}
+		})
+	}
+}
@@ -377,8 +377,12 @@ func TestAdminEtcdCertificateRenew(t *testing.T) {
 				ti.audit,
 				ti.log,
 				ti.env,
+				ti.adminActionsDatabase,
 				ti.asyncOperationsDatabase,
+				ti.clusterHealthDatabase,
 				ti.clusterManagerDatabase,
+				ti.fleetOperationsDatabase,
+				nil,
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
@@ -594,8 +598,12 @@ func TestAdminEtcdCertificateRecovery(t *testing.T) {
 				ti.audit,
 				ti.log,
 				ti.env,
+				ti.adminActionsDatabase,
 				ti.asyncOperationsDatabase,
+				ti.clusterHealthDatabase,
 				ti.clusterManagerDatabase,
+				ti.fleetOperationsDatabase,
+				nil,
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
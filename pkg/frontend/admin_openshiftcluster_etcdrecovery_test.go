@@ -162,8 +162,12 @@ func TestAdminEtcdRecovery(t *testing.T) {
 				ti.audit,
 				ti.log,
 				ti.env,
+				ti.adminActionsDatabase,
 				ti.asyncOperationsDatabase,
+				ti.clusterHealthDatabase,
 				ti.clusterManagerDatabase,
+				ti.fleetOperationsDatabase,
+				nil,
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
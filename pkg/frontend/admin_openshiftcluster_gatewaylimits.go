@@ -0,0 +1,98 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/gatewaylimits
+func (f *frontend) getAdminOpenShiftClusterGatewayLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getAdminOpenShiftClusterGatewayLimits(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterGatewayLimits(ctx context.Context, r *http.Request) ([]byte, error) {
+	linkID, err := f.gatewayLinkID(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	gwyDoc, err := f.dbGateway.Get(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := gwyDoc.Gateway.Limits
+	if limits == nil {
+		limits = &api.GatewayLimits{}
+	}
+
+	return json.MarshalIndent(limits, "", "    ")
+}
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/gatewaylimits
+func (f *frontend) putAdminOpenShiftClusterGatewayLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._putAdminOpenShiftClusterGatewayLimits(ctx, r)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _putAdminOpenShiftClusterGatewayLimits replaces the overrides of the
+// gateway's default limits in the cluster's gateway record
+func (f *frontend) _putAdminOpenShiftClusterGatewayLimits(ctx context.Context, r *http.Request) ([]byte, error) {
+	body := ctx.Value(middleware.ContextKeyBody).([]byte)
+
+	var limits *api.GatewayLimits
+	err := json.Unmarshal(body, &limits)
+	if err != nil {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content could not be deserialized: "+err.Error())
+	}
+
+	err = validateAdminGatewayLimits(limits)
+	if err != nil {
+		return nil, err
+	}
+
+	linkID, err := f.gatewayLinkID(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	gwyDoc, err := f.dbGateway.Patch(ctx, linkID, func(doc *api.GatewayDocument) error {
+		if limits == nil || limits.MaxConnections == 0 && limits.BandwidthBytesPerSecond == 0 {
+			doc.Gateway.Limits = nil
+		} else {
+			doc.Gateway.Limits = limits
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limits = gwyDoc.Gateway.Limits
+	if limits == nil {
+		limits = &api.GatewayLimits{}
+	}
+
+	return json.MarshalIndent(limits, "", "    ")
+}
@@ -0,0 +1,146 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminGatewayLimits(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+
+	limits := &api.GatewayLimits{
+		MaxConnections:          1000,
+		BandwidthBytesPerSecond: 100 << 20,
+	}
+
+	gatewayFixture := func(limits *api.GatewayLimits) func(f *testdatabase.Fixture) {
+		return func(f *testdatabase.Fixture) {
+			f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				ID:  "00000000-0000-0000-0000-000000000001",
+				Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+					Properties: api.OpenShiftClusterProperties{
+						NetworkProfile: api.NetworkProfile{
+							GatewayPrivateLinkID: "1234",
+						},
+					},
+				},
+			})
+			f.AddGatewayDocuments(&api.GatewayDocument{
+				ID: "1234",
+				Gateway: &api.Gateway{
+					ID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+					Limits: limits,
+				},
+			})
+		}
+	}
+
+	type test struct {
+		name           string
+		method         string
+		body           interface{}
+		fixture        func(f *testdatabase.Fixture)
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+		wantLimits     *api.GatewayLimits
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "get limits",
+			method:         http.MethodGet,
+			fixture:        gatewayFixture(limits),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   limits,
+			wantLimits:     limits,
+		},
+		{
+			name:           "get no overrides",
+			method:         http.MethodGet,
+			fixture:        gatewayFixture(nil),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("{}\n"),
+		},
+		{
+			name:           "put limits",
+			method:         http.MethodPut,
+			body:           limits,
+			fixture:        gatewayFixture(nil),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   limits,
+			wantLimits:     limits,
+		},
+		{
+			name:           "put empty limits clears overrides",
+			method:         http.MethodPut,
+			body:           &api.GatewayLimits{},
+			fixture:        gatewayFixture(limits),
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("{}\n"),
+		},
+		{
+			name:           "put invalid limits",
+			method:         http.MethodPut,
+			body:           &api.GatewayLimits{MaxConnections: -1},
+			fixture:        gatewayFixture(limits),
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: maxConnections: The provided maximum connections '-1' is invalid.",
+			wantLimits:     limits,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions().WithGateway()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, ti.gatewayDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method,
+				fmt.Sprintf("https://server/admin%s/gatewaylimits", testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				}, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			gwyDoc, err := ti.gatewayDatabase.Get(ctx, "1234")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(gwyDoc.Gateway.Limits, tt.wantLimits) {
+				t.Errorf("unexpected limits %#v", gwyDoc.Gateway.Limits)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/health
+func (f *frontend) getAdminOpenShiftClusterHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+	b, err := f._getAdminOpenShiftClusterHealth(ctx, r)
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterHealth(ctx context.Context, r *http.Request) ([]byte, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+			"The Resource '%s/%s' under resource group '%s' was not found.",
+			resType, resName, resGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	healthDoc, err := f.dbClusterHealth.Get(ctx, doc.ID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) ||
+		err == nil && healthDoc.ClusterHealth == nil:
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "",
+			"The health of the resource has not been evaluated yet.")
+	case err != nil:
+		return nil, err
+	}
+
+	return json.MarshalIndent(healthDoc.ClusterHealth, "", "    ")
+}
@@ -0,0 +1,131 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminClusterHealth(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+	evaluatedTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	health := &api.ClusterHealth{
+		State: api.HealthStateDegraded,
+		Reasons: []api.HealthReason{
+			{
+				Signal:  "clusterOperatorConditions",
+				State:   api.HealthStateDegraded,
+				Message: "clusteroperator ingress is degraded",
+			},
+		},
+		EvaluatedTime: evaluatedTime,
+		History: []api.HealthTransition{
+			{
+				State: api.HealthStateHealthy,
+				Time:  evaluatedTime.Add(-time.Hour),
+			},
+			{
+				State: api.HealthStateDegraded,
+				Reasons: []api.HealthReason{
+					{
+						Signal:  "clusterOperatorConditions",
+						State:   api.HealthStateDegraded,
+						Message: "clusteroperator ingress is degraded",
+					},
+				},
+				Time: evaluatedTime,
+			},
+		},
+	}
+
+	clusterFixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			ID:  "00000000-0000-0000-0000-000000000001",
+			Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			},
+		})
+	}
+
+	type test struct {
+		name           string
+		resourceID     string
+		fixture        func(f *testdatabase.Fixture)
+		wantStatusCode int
+		wantResponse   *api.ClusterHealth
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:       "health evaluated",
+			resourceID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture: func(f *testdatabase.Fixture) {
+				clusterFixture(f)
+				f.AddClusterHealthDocuments(&api.ClusterHealthDocument{
+					ID:            "00000000-0000-0000-0000-000000000001",
+					Key:           strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+					ClusterHealth: health,
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   health,
+		},
+		{
+			name:           "health not evaluated yet",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        clusterFixture,
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The health of the resource has not been evaluated yet.",
+		},
+		{
+			name:           "cluster not found",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        func(f *testdatabase.Fixture) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      `404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions().WithClusterHealth()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet,
+				fmt.Sprintf("https://server/admin%s/health", tt.resourceID),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
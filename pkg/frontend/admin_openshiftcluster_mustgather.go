@@ -0,0 +1,301 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+// adminMustGatherTimeout bounds a must-gather run in the background.  A run
+// which is still Running after it was most likely lost when the RP restarted.
+const adminMustGatherTimeout = time.Hour
+
+type adminMustGatherStatus string
+
+const (
+	adminMustGatherStatusRunning   adminMustGatherStatus = "Running"
+	adminMustGatherStatusSucceeded adminMustGatherStatus = "Succeeded"
+	adminMustGatherStatusFailed    adminMustGatherStatus = "Failed"
+)
+
+// adminMustGather is a reference to a must-gather tarball stored in the
+// cluster storage account.  It can be downloaded through the admin API by
+// name once its status is Succeeded; no SAS is handed out.
+type adminMustGather struct {
+	Name      string                `json:"name"`
+	CreatedAt time.Time             `json:"createdAt"`
+	CreatedBy string                `json:"createdBy,omitempty"`
+	Status    adminMustGatherStatus `json:"status,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+func (mg *adminMustGather) statusMetadata() map[string]string {
+	return map[string]string{
+		"createdat": mg.CreatedAt.Format(time.RFC3339),
+		"createdby": mg.CreatedBy,
+		"status":    string(mg.Status),
+		"error":     mg.Error,
+	}
+}
+
+func (f *frontend) postAdminOpenShiftClusterMustGather(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._postAdminOpenShiftClusterMustGather(ctx, r, log)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _postAdminOpenShiftClusterMustGather starts must-gather on the cluster and
+// returns the reference of the tarball it will produce.  Its status is listed
+// by _getAdminOpenShiftClusterMustGathers.
+func (f *frontend) _postAdminOpenShiftClusterMustGather(ctx context.Context, r *http.Request, log *logrus.Entry) ([]byte, error) {
+	correlationData := ctx.Value(middleware.ContextKeyCorrelationData).(*api.CorrelationData)
+
+	doc, a, err := f.prepareAdminMustGatherActions(ctx, r, log, strings.TrimPrefix(r.URL.Path, "/admin"))
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := f.kubeActionsFactory(log, f.env, doc.OpenShiftCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	now := f.now().UTC()
+	mg := &adminMustGather{
+		Name:      "must-gather-" + now.Format("20060102-150405") + ".tar.gz",
+		CreatedAt: now,
+		CreatedBy: correlationData.ClientPrincipalName,
+		Status:    adminMustGatherStatusRunning,
+	}
+
+	err = a.MustGatherPutStatus(ctx, mg.Name, mg.statusMetadata())
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.MarshalIndent(mg, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	go f.runAdminMustGather(log, k, a, mg)
+
+	return b, nil
+}
+
+// runAdminMustGather runs must-gather, streams the result into the cluster
+// storage account as it is produced and records whether it succeeded
+func (f *frontend) runAdminMustGather(log *logrus.Entry, k adminactions.KubeActions, a adminactions.AzureActions, mg *adminMustGather) {
+	defer recover.Panic(log)
+
+	// the run outlives the request which started it
+	ctx, cancel := context.WithTimeout(context.Background(), adminMustGatherTimeout)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+
+	go func() {
+		defer recover.Panic(log)
+		defer close(done)
+		pw.CloseWithError(k.RunMustGather(ctx, pw))
+	}()
+
+	err := a.MustGatherUpload(ctx, mg.Name, pr, map[string]string{
+		"createdby": mg.CreatedBy,
+	})
+	// unblock RunMustGather if the upload stopped reading, and wait for it to
+	// clean up
+	pr.CloseWithError(err)
+	<-done
+
+	if err != nil {
+		log.Errorf("must-gather %s failed: %v", mg.Name, err)
+		mg.Status = adminMustGatherStatusFailed
+		mg.Error = err.Error()
+	} else {
+		log.Infof("stored must-gather %s", mg.Name)
+		mg.Status = adminMustGatherStatusSucceeded
+	}
+
+	// record the result even if the run timed out
+	statusCtx, statusCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer statusCancel()
+
+	err = a.MustGatherPutStatus(statusCtx, mg.Name, mg.statusMetadata())
+	if err != nil {
+		log.Errorf("failed to record the status of must-gather %s: %v", mg.Name, err)
+	}
+}
+
+func (f *frontend) getAdminOpenShiftClusterMustGathers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getAdminOpenShiftClusterMustGathers(ctx, r, log)
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _getAdminOpenShiftClusterMustGathers lists the must-gathers of the cluster
+// and their status, newest first
+func (f *frontend) _getAdminOpenShiftClusterMustGathers(ctx context.Context, r *http.Request, log *logrus.Entry) ([]byte, error) {
+	_, a, err := f.prepareAdminMustGatherActions(ctx, r, log, strings.TrimPrefix(r.URL.Path, "/admin"))
+	if err != nil {
+		return nil, err
+	}
+
+	blobs, err := a.MustGatherList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mgs := make([]*adminMustGather, 0, len(blobs))
+	byName := map[string]*adminMustGather{}
+	get := func(name string) *adminMustGather {
+		mg := byName[name]
+		if mg == nil {
+			mg = &adminMustGather{Name: name}
+			byName[name] = mg
+			mgs = append(mgs, mg)
+		}
+		return mg
+	}
+
+	for _, blob := range blobs {
+		name := strings.TrimSuffix(blob.Name, adminactions.MustGatherStatusSuffix)
+		mg := get(name)
+
+		if name == blob.Name {
+			// the tarball is only stored once its run succeeded.  Tarballs
+			// stored before runs recorded their status have no status blob.
+			mg.Status = adminMustGatherStatusSucceeded
+			mg.Error = ""
+			if mg.CreatedAt.IsZero() {
+				mg.CreatedAt = blob.LastModified
+			}
+			if mg.CreatedBy == "" {
+				mg.CreatedBy = blob.Metadata["createdby"]
+			}
+			continue
+		}
+
+		if createdAt, err := time.Parse(time.RFC3339, blob.Metadata["createdat"]); err == nil {
+			mg.CreatedAt = createdAt
+		}
+		mg.CreatedBy = blob.Metadata["createdby"]
+
+		if mg.Status == adminMustGatherStatusSucceeded {
+			continue
+		}
+
+		mg.Status = adminMustGatherStatus(blob.Metadata["status"])
+		mg.Error = blob.Metadata["error"]
+
+		if mg.Status == adminMustGatherStatusRunning && f.now().Sub(blob.LastModified) > adminMustGatherTimeout {
+			mg.Status = adminMustGatherStatusFailed
+			mg.Error = "The must-gather did not finish."
+		}
+	}
+
+	sort.SliceStable(mgs, func(i, j int) bool {
+		return mgs[i].CreatedAt.After(mgs[j].CreatedAt)
+	})
+
+	return json.MarshalIndent(map[string][]*adminMustGather{
+		"value": mgs,
+	}, "", "    ")
+}
+
+func (f *frontend) getAdminOpenShiftClusterMustGather(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(filepath.Dir(r.URL.Path))
+
+	err := f._getAdminOpenShiftClusterMustGather(ctx, w, r, log)
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+	}
+}
+
+// _getAdminOpenShiftClusterMustGather streams a stored must-gather tarball
+// back to the caller
+func (f *frontend) _getAdminOpenShiftClusterMustGather(ctx context.Context, w http.ResponseWriter, r *http.Request, log *logrus.Entry) error {
+	name := chi.URLParam(r, "mustGatherName")
+	err := validateAdminMustGatherName(name)
+	if err != nil {
+		return err
+	}
+
+	_, a, err := f.prepareAdminMustGatherActions(ctx, r, log, strings.TrimPrefix(r.URL.Path, "/admin"))
+	if err != nil {
+		return err
+	}
+
+	rc, err := a.MustGatherGet(ctx, name)
+	if err == blobstore.ErrNotFound {
+		return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The must-gather '%s' was not found.", name)
+	}
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+name)
+
+	// the status has been sent, so failures can only be logged
+	_, err = io.Copy(w, rc)
+	if err != nil {
+		log.Error(err)
+	}
+
+	return nil
+}
+
+func (f *frontend) prepareAdminMustGatherActions(ctx context.Context, r *http.Request, log *logrus.Entry, resourceID string) (*api.OpenShiftClusterDocument, adminactions.AzureActions, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resType, resName, resGroupName)
+	case err != nil:
+		return nil, nil, err
+	}
+
+	subscriptionDoc, err := f.getSubscriptionDocument(ctx, doc.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := f.azureActionsFactory(log, f.env, doc.OpenShiftCluster, subscriptionDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, a, nil
+}
@@ -0,0 +1,363 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminMustGather(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	mockTenantID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+	mockCurrentTime := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	ctx := context.Background()
+
+	type test struct {
+		name           string
+		fixture        func(*testdatabase.Fixture)
+		method         string
+		path           string
+		mocks          func(*mock_adminactions.MockKubeActions, *mock_adminactions.MockAzureActions, chan<- struct{})
+		wantBackground bool
+		wantStatusCode int
+		wantResponse   interface{}
+		wantError      string
+	}
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile: api.ClusterProfile{
+						ResourceGroupID: fmt.Sprintf("/subscriptions/%s/resourceGroups/test-cluster", mockSubID),
+					},
+				},
+			},
+		})
+		f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+			ID: mockSubID,
+			Subscription: &api.Subscription{
+				State: api.SubscriptionStateRegistered,
+				Properties: &api.SubscriptionProperties{
+					TenantID: mockTenantID,
+				},
+			},
+		})
+	}
+
+	// upload reads the tarball as the storage account would
+	upload := func(ctx context.Context, name string, r io.Reader, metadata map[string]string) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if string(b) != "tarball" {
+			return fmt.Errorf("uploaded %q", string(b))
+		}
+		return nil
+	}
+
+	status := func(status, error string) map[string]string {
+		return map[string]string{
+			"createdat": "2026-01-01T12:00:00Z",
+			"createdby": "someone@example.com",
+			"status":    status,
+			"error":     error,
+		}
+	}
+
+	// recorded closes done once the run in the background has recorded its
+	// status
+	recorded := func(done chan<- struct{}) func(context.Context, string, map[string]string) error {
+		return func(context.Context, string, map[string]string) error {
+			close(done)
+			return nil
+		}
+	}
+
+	for _, tt := range []*test{
+		{
+			name:    "must-gather is started and uploaded",
+			fixture: fixture,
+			method:  http.MethodPost,
+			path:    "/mustgather",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				gomock.InOrder(
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Running", "")).
+						Return(nil),
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Succeeded", "")).
+						DoAndReturn(recorded(done)),
+				)
+				k.EXPECT().RunMustGather(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, w io.Writer) error {
+						_, err := w.Write([]byte("tarball"))
+						return err
+					})
+				a.EXPECT().MustGatherUpload(gomock.Any(), "must-gather-20260101-120000.tar.gz", gomock.Any(), map[string]string{"createdby": "someone@example.com"}).
+					DoAndReturn(upload)
+			},
+			wantBackground: true,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &adminMustGather{
+				Name:      "must-gather-20260101-120000.tar.gz",
+				CreatedAt: mockCurrentTime,
+				CreatedBy: "someone@example.com",
+				Status:    adminMustGatherStatusRunning,
+			},
+		},
+		{
+			name:    "must-gather fails",
+			fixture: fixture,
+			method:  http.MethodPost,
+			path:    "/mustgather",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				gomock.InOrder(
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Running", "")).
+						Return(nil),
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Failed", "must-gather container gather exited with code 1")).
+						DoAndReturn(recorded(done)),
+				)
+				k.EXPECT().RunMustGather(gomock.Any(), gomock.Any()).
+					Return(errors.New("must-gather container gather exited with code 1"))
+				a.EXPECT().MustGatherUpload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(upload)
+			},
+			wantBackground: true,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &adminMustGather{
+				Name:      "must-gather-20260101-120000.tar.gz",
+				CreatedAt: mockCurrentTime,
+				CreatedBy: "someone@example.com",
+				Status:    adminMustGatherStatusRunning,
+			},
+		},
+		{
+			name:    "upload fails",
+			fixture: fixture,
+			method:  http.MethodPost,
+			path:    "/mustgather",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				gomock.InOrder(
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Running", "")).
+						Return(nil),
+					a.EXPECT().MustGatherPutStatus(gomock.Any(), "must-gather-20260101-120000.tar.gz", status("Failed", "storage account unreachable")).
+						DoAndReturn(recorded(done)),
+				)
+				k.EXPECT().RunMustGather(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, w io.Writer) error {
+						_, err := w.Write([]byte("tarball"))
+						return err
+					})
+				a.EXPECT().MustGatherUpload(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("storage account unreachable"))
+			},
+			wantBackground: true,
+			wantStatusCode: http.StatusOK,
+			wantResponse: &adminMustGather{
+				Name:      "must-gather-20260101-120000.tar.gz",
+				CreatedAt: mockCurrentTime,
+				CreatedBy: "someone@example.com",
+				Status:    adminMustGatherStatusRunning,
+			},
+		},
+		{
+			name:    "status cannot be recorded",
+			fixture: fixture,
+			method:  http.MethodPost,
+			path:    "/mustgather",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				a.EXPECT().MustGatherPutStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("storage account unreachable"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantError:      "500: InternalServerError: : Internal server error.",
+		},
+		{
+			name:           "cluster not found",
+			fixture:        func(f *testdatabase.Fixture) {},
+			method:         http.MethodPost,
+			path:           "/mustgather",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+		{
+			name:    "must-gathers are listed newest first with their status",
+			fixture: fixture,
+			method:  http.MethodGet,
+			path:    "/mustgather",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				a.EXPECT().MustGatherList(gomock.Any()).Return([]*blobstore.Blob{
+					{
+						Name:         "must-gather-20260101-090000.tar.gz.status",
+						Metadata:     map[string]string{"createdat": "2026-01-01T09:00:00Z", "status": "Running"},
+						LastModified: mockCurrentTime.Add(-3 * time.Hour),
+					},
+					{
+						Name:         "must-gather-20260101-100000.tar.gz.status",
+						Metadata:     map[string]string{"createdat": "2026-01-01T10:00:00Z", "status": "Failed", "error": "must-gather container gather exited with code 1"},
+						LastModified: mockCurrentTime.Add(-2 * time.Hour),
+					},
+					{
+						Name:         "must-gather-20260101-110000.tar.gz",
+						LastModified: mockCurrentTime.Add(-time.Hour),
+					},
+					{
+						Name:         "must-gather-20260101-113000.tar.gz",
+						Metadata:     map[string]string{"createdby": "someone@example.com"},
+						LastModified: mockCurrentTime.Add(-20 * time.Minute),
+					},
+					{
+						Name:         "must-gather-20260101-113000.tar.gz.status",
+						Metadata:     map[string]string{"createdat": "2026-01-01T11:30:00Z", "createdby": "someone@example.com", "status": "Running"},
+						LastModified: mockCurrentTime.Add(-30 * time.Minute),
+					},
+					{
+						Name:         "must-gather-20260101-120000.tar.gz.status",
+						Metadata:     map[string]string{"createdat": "2026-01-01T12:00:00Z", "createdby": "someone@example.com", "status": "Running"},
+						LastModified: mockCurrentTime,
+					},
+				}, nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &map[string][]*adminMustGather{
+				"value": {
+					{
+						Name:      "must-gather-20260101-120000.tar.gz",
+						CreatedAt: mockCurrentTime,
+						CreatedBy: "someone@example.com",
+						Status:    adminMustGatherStatusRunning,
+					},
+					{
+						Name:      "must-gather-20260101-113000.tar.gz",
+						CreatedAt: mockCurrentTime.Add(-30 * time.Minute),
+						CreatedBy: "someone@example.com",
+						Status:    adminMustGatherStatusSucceeded,
+					},
+					{
+						Name:      "must-gather-20260101-110000.tar.gz",
+						CreatedAt: mockCurrentTime.Add(-time.Hour),
+						Status:    adminMustGatherStatusSucceeded,
+					},
+					{
+						Name:      "must-gather-20260101-100000.tar.gz",
+						CreatedAt: mockCurrentTime.Add(-2 * time.Hour),
+						Status:    adminMustGatherStatusFailed,
+						Error:     "must-gather container gather exited with code 1",
+					},
+					{
+						Name:      "must-gather-20260101-090000.tar.gz",
+						CreatedAt: mockCurrentTime.Add(-3 * time.Hour),
+						Status:    adminMustGatherStatusFailed,
+						Error:     "The must-gather did not finish.",
+					},
+				},
+			},
+		},
+		{
+			name:    "must-gather is downloaded",
+			fixture: fixture,
+			method:  http.MethodGet,
+			path:    "/mustgather/must-gather-20260101-120000.tar.gz",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				a.EXPECT().MustGatherGet(gomock.Any(), "must-gather-20260101-120000.tar.gz").
+					Return(io.NopCloser(bytes.NewReader([]byte("tarball"))), nil)
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("tarball"),
+		},
+		{
+			name:    "must-gather does not exist",
+			fixture: fixture,
+			method:  http.MethodGet,
+			path:    "/mustgather/must-gather-20260101-120000.tar.gz",
+			mocks: func(k *mock_adminactions.MockKubeActions, a *mock_adminactions.MockAzureActions, done chan<- struct{}) {
+				a.EXPECT().MustGatherGet(gomock.Any(), "must-gather-20260101-120000.tar.gz").
+					Return(nil, blobstore.ErrNotFound)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The must-gather 'must-gather-20260101-120000.tar.gz' was not found.",
+		},
+		{
+			name:           "invalid must-gather name",
+			fixture:        fixture,
+			method:         http.MethodGet,
+			path:           "/mustgather/kubeconfig",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: : The provided must-gather name 'kubeconfig' is invalid.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions()
+			defer ti.done()
+
+			k := mock_adminactions.NewMockKubeActions(ti.controller)
+			a := mock_adminactions.NewMockAzureActions(ti.controller)
+			done := make(chan struct{})
+			if tt.mocks != nil {
+				tt.mocks(k, a, done)
+			}
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster) (adminactions.KubeActions, error) {
+				return k, nil
+			}, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error) {
+				return a, nil
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return mockCurrentTime }
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method, "https://server/admin"+resourceID+tt.path,
+				http.Header{
+					"X-Ms-Client-Principal-Name": []string{"someone@example.com"},
+				}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantBackground {
+				select {
+				case <-done:
+				case <-time.After(10 * time.Second):
+					t.Error("timed out waiting for the must-gather to finish")
+				}
+			}
+		})
+	}
+}
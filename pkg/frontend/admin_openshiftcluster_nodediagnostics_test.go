@@ -0,0 +1,128 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminNodeDiagnostics(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+
+	ctx := context.Background()
+
+	type test struct {
+		name           string
+		fixture        func(*testdatabase.Fixture)
+		vmName         string
+		diagnostic     string
+		mocks          func(*test, *mock_adminactions.MockKubeActions)
+		wantStatusCode int
+		wantResponse   []byte
+		wantError      string
+	}
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+			},
+		})
+	}
+
+	for _, tt := range []*test{
+		{
+			name:       "output is streamed back",
+			fixture:    fixture,
+			vmName:     "aro-master-0",
+			diagnostic: "journalctl-kubelet",
+			mocks: func(tt *test, k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().RunNodeDiagnostic(gomock.Any(), gomock.Any(), tt.vmName, tt.diagnostic).
+					DoAndReturn(func(ctx context.Context, w http.ResponseWriter, nodeName, diagnostic string) error {
+						w.Header().Set("Content-Type", "text/plain")
+						_, err := w.Write([]byte("-- No entries --\n"))
+						return err
+					})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse:   []byte("-- No entries --\n"),
+		},
+		{
+			name:           "diagnostic not in the allow-list",
+			fixture:        fixture,
+			vmName:         "aro-master-0",
+			diagnostic:     "rm",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: : The provided diagnostic 'rm' is invalid: it must be one of crictl-pods, crictl-ps, df, ip-address, ip-route, journalctl-crio, journalctl-kubelet, systemctl-failed.",
+		},
+		{
+			name:           "invalid vmName",
+			fixture:        fixture,
+			vmName:         "aro-master-0!",
+			diagnostic:     "df",
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: : The provided vmName 'aro-master-0!' is invalid.",
+		},
+		{
+			name:           "cluster not found",
+			fixture:        func(f *testdatabase.Fixture) {},
+			vmName:         "aro-master-0",
+			diagnostic:     "df",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters()
+			defer ti.done()
+
+			k := mock_adminactions.NewMockKubeActions(ti.controller)
+			if tt.mocks != nil {
+				tt.mocks(tt, k)
+			}
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster) (adminactions.KubeActions, error) {
+				return k, nil
+			}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost,
+				fmt.Sprintf("https://server/admin%s/nodediagnostics?vmName=%s&diagnostic=%s", resourceID, tt.vmName, tt.diagnostic),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+type adminOperationTimeline struct {
+	OperationID       string                  `json:"operationId"`
+	ProvisioningState api.ProvisioningState   `json:"status,omitempty"`
+	StartTime         time.Time               `json:"startTime,omitempty"`
+	EndTime           *time.Time              `json:"endTime,omitempty"`
+	Error             *api.CloudErrorBody     `json:"error,omitempty"`
+	Steps             []api.StepTimelineEntry `json:"steps"`
+}
+
+// /admin/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}/operationtimeline
+func (f *frontend) getAdminOpenShiftClusterOperationTimeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+	b, err := f._getAdminOpenShiftClusterOperationTimeline(ctx, r)
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterOperationTimeline(ctx context.Context, r *http.Request) ([]byte, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "",
+			"The Resource '%s/%s' under resource group '%s' was not found.",
+			resType, resName, resGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	// default to the operation in progress, if there is one
+	operationID := strings.ToLower(r.URL.Query().Get("operationId"))
+	if operationID == "" {
+		operationID = doc.AsyncOperationID
+	}
+	if operationID == "" {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "operationId",
+			"No operation is in progress on the resource; the operationId parameter is required.")
+	}
+
+	asyncdoc, err := f.dbAsyncOperations.Get(ctx, operationID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "",
+			"The operation '%s' was not found.", operationID)
+	case err != nil:
+		return nil, err
+	case asyncdoc.OpenShiftClusterKey != doc.Key:
+		// don't leak the operations of other clusters
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "",
+			"The operation '%s' was not found.", operationID)
+	}
+
+	timeline := &adminOperationTimeline{
+		OperationID: operationID,
+		Steps:       asyncdoc.StepTimeline,
+	}
+	if timeline.Steps == nil {
+		timeline.Steps = []api.StepTimelineEntry{}
+	}
+	if asyncdoc.AsyncOperation != nil {
+		timeline.ProvisioningState = asyncdoc.AsyncOperation.ProvisioningState
+		timeline.StartTime = asyncdoc.AsyncOperation.StartTime
+		timeline.EndTime = asyncdoc.AsyncOperation.EndTime
+		timeline.Error = asyncdoc.AsyncOperation.Error
+	}
+
+	return json.MarshalIndent(timeline, "", "    ")
+}
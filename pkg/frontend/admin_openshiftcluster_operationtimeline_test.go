@@ -0,0 +1,166 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminOperationTimeline(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type test struct {
+		name           string
+		resourceID     string
+		query          string
+		fixture        func(f *testdatabase.Fixture)
+		wantStatusCode int
+		wantResponse   *adminOperationTimeline
+		wantError      string
+	}
+
+	timeline := []api.StepTimelineEntry{
+		{
+			Topic:     "install.InstallPhaseBootstrap",
+			Name:      "action.ensureResourceGroup",
+			StartTime: startTime,
+			EndTime:   startTime.Add(time.Second),
+			Outcome:   "Succeeded",
+		},
+		{
+			Topic:      "install.InstallPhaseBootstrap",
+			Name:       "condition.apiServersReady",
+			StartTime:  startTime.Add(time.Second),
+			EndTime:    startTime.Add(time.Hour),
+			Outcome:    "Failed",
+			ErrorClass: "CloudError/DeploymentFailed",
+			Retries:    180,
+		},
+	}
+
+	clusterFixture := func(asyncOperationID string) func(f *testdatabase.Fixture) {
+		return func(f *testdatabase.Fixture) {
+			f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key:              strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				AsyncOperationID: asyncOperationID,
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+				},
+			})
+			f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "otherName")),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: testdatabase.GetResourcePath(mockSubID, "otherName"),
+				},
+			})
+			f.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+				ID:                  "11111111-1111-1111-1111-111111111111",
+				OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+				AsyncOperation: &api.AsyncOperation{
+					ProvisioningState: api.ProvisioningStateCreating,
+					StartTime:         startTime,
+				},
+				StepTimeline: timeline,
+			})
+		}
+	}
+
+	for _, tt := range []*test{
+		{
+			name:           "operation in progress",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        clusterFixture("11111111-1111-1111-1111-111111111111"),
+			wantStatusCode: http.StatusOK,
+			wantResponse: &adminOperationTimeline{
+				OperationID:       "11111111-1111-1111-1111-111111111111",
+				ProvisioningState: api.ProvisioningStateCreating,
+				StartTime:         startTime,
+				Steps:             timeline,
+			},
+		},
+		{
+			name:           "previous operation by id",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			query:          "?operationId=11111111-1111-1111-1111-111111111111",
+			fixture:        clusterFixture(""),
+			wantStatusCode: http.StatusOK,
+			wantResponse: &adminOperationTimeline{
+				OperationID:       "11111111-1111-1111-1111-111111111111",
+				ProvisioningState: api.ProvisioningStateCreating,
+				StartTime:         startTime,
+				Steps:             timeline,
+			},
+		},
+		{
+			name:           "no operation in progress",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        clusterFixture(""),
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: operationId: No operation is in progress on the resource; the operationId parameter is required.",
+		},
+		{
+			name:           "operation of another cluster",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "otherName"),
+			query:          "?operationId=11111111-1111-1111-1111-111111111111",
+			fixture:        clusterFixture(""),
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The operation '11111111-1111-1111-1111-111111111111' was not found.",
+		},
+		{
+			name:           "operation not found",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			query:          "?operationId=22222222-2222-2222-2222-222222222222",
+			fixture:        clusterFixture(""),
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The operation '22222222-2222-2222-2222-222222222222' was not found.",
+		},
+		{
+			name:           "cluster not found",
+			resourceID:     testdatabase.GetResourcePath(mockSubID, "resourceName"),
+			fixture:        func(f *testdatabase.Fixture) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      `404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions().WithAsyncOperations()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.adminActionsDatabase, ti.asyncOperationsDatabase, ti.clusterHealthDatabase, ti.clusterManagerDatabase, ti.fleetOperationsDatabase, nil, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet,
+				fmt.Sprintf("https://server/admin%s/operationtimeline%s", tt.resourceID, tt.query),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
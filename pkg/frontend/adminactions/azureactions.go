@@ -21,6 +21,8 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/features"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/network"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/storage"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	utilstorage "github.com/Azure/ARO-RP/pkg/util/storage"
 	"github.com/Azure/ARO-RP/pkg/util/stringutils"
 )
 
@@ -39,6 +41,10 @@ type AzureActions interface {
 	VMSerialConsole(ctx context.Context, w http.ResponseWriter, log *logrus.Entry, vmName string) error
 	AppLensGetDetector(ctx context.Context, detectorId string) ([]byte, error)
 	AppLensListDetectors(ctx context.Context) ([]byte, error)
+	MustGatherUpload(ctx context.Context, name string, r io.Reader, metadata map[string]string) error
+	MustGatherPutStatus(ctx context.Context, name string, metadata map[string]string) error
+	MustGatherList(ctx context.Context) ([]*blobstore.Blob, error)
+	MustGatherGet(ctx context.Context, name string) (io.ReadCloser, error)
 }
 
 type azureActions struct {
@@ -55,6 +61,8 @@ type azureActions struct {
 	storageAccounts    storage.AccountsClient
 	networkInterfaces  network.InterfacesClient
 	appLens            applens.AppLensClient
+
+	mustGathers blobstore.Store
 }
 
 // NewAzureActions returns an azureActions
@@ -76,6 +84,8 @@ func NewAzureActions(log *logrus.Entry, env env.Interface, oc *api.OpenShiftClus
 		return nil, err
 	}
 
+	clusterRGName := stringutils.LastTokenByte(oc.Properties.ClusterProfile.ResourceGroupID, '/')
+
 	return &azureActions{
 		log: log,
 		env: env,
@@ -90,6 +100,8 @@ func NewAzureActions(log *logrus.Entry, env env.Interface, oc *api.OpenShiftClus
 		storageAccounts:    storage.NewAccountsClient(env.Environment(), subscriptionDoc.ID, fpAuth),
 		networkInterfaces:  network.NewInterfacesClient(env.Environment(), subscriptionDoc.ID, fpAuth),
 		appLens:            appLensClient,
+
+		mustGathers: blobstore.NewAzure(utilstorage.NewManager(env, subscriptionDoc.ID, fpAuth), clusterRGName, "cluster"+oc.Properties.StorageSuffix, mustGatherContainerName),
 	}, nil
 }
 
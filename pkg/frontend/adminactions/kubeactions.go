@@ -5,6 +5,7 @@ package adminactions
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/Azure/go-autorest/autorest/to"
@@ -37,6 +38,8 @@ type KubeActions interface {
 	ApproveCsr(ctx context.Context, csrName string) error
 	ApproveAllCsrs(ctx context.Context) error
 	KubeGetPodLogs(ctx context.Context, namespace, name, containerName string) ([]byte, error)
+	RunNodeDiagnostic(ctx context.Context, w http.ResponseWriter, nodeName, diagnostic string) error
+	RunMustGather(ctx context.Context, w io.Writer) error
 	// kubeWatch returns a watch object for the provided label selector key
 	KubeWatch(ctx context.Context, o *unstructured.Unstructured, label string) (watch.Interface, error)
 }
@@ -49,6 +52,8 @@ type kubeActions struct {
 
 	dyn     dynamic.Interface
 	kubecli kubernetes.Interface
+
+	operatorImage string
 }
 
 // NewKubeActions returns a kubeActions
@@ -81,6 +86,8 @@ func NewKubeActions(log *logrus.Entry, env env.Interface, oc *api.OpenShiftClust
 
 		dyn:     dyn,
 		kubecli: kubecli,
+
+		operatorImage: env.AROOperatorImage(),
 	}, nil
 }
 
@@ -0,0 +1,326 @@
+package adminactions
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	mustGatherPodName = "must-gather"
+	// mustGatherGatherContainerName and mustGatherCopyContainerName match the
+	// container names used by `oc adm must-gather`
+	mustGatherGatherContainerName = "gather"
+	mustGatherCopyContainerName   = "copy"
+
+	mustGatherTimeout     = 30 * time.Minute
+	mustGatherCopyTimeout = time.Minute
+)
+
+const mustGatherCopyScript = `set -o errexit -o pipefail
+tar --create --gzip --directory /must-gather . >/tmp/must-gather.tar.gz
+echo "$(sha256sum </tmp/must-gather.tar.gz | cut -d ' ' -f 1) $(stat --format %s /tmp/must-gather.tar.gz)" >/dev/termination-log
+base64 /tmp/must-gather.tar.gz
+`
+
+var imageStreamsGVR = schema.GroupVersionResource{Group: "image.openshift.io", Version: "v1", Resource: "imagestreams"}
+
+// mustGatherSuffix returns the random suffix of the namespace and cluster role
+// binding created for a must-gather run.  It is a variable so that it can be
+// overridden in unit tests, as the fake clientset does not support
+// GenerateName.
+var mustGatherSuffix = func() string {
+	return utilrand.String(5)
+}
+
+// RunMustGather runs the cluster's must-gather image in a temporary namespace,
+// as `oc adm must-gather` does, and writes the gathered data to w as a gzipped
+// tarball.  The data is returned through the pod logs rather than by exec or
+// rsync, because those use SPDY connections which do not go through the
+// dialer the RP uses to reach the cluster API server.  As log rotation can cut
+// the stream short, the tarball is checked against the sha256 and length
+// which the copy container reports; an error is returned, and so nothing is
+// committed by a streaming upload reading from w, if they do not match.
+func (k *kubeActions) RunMustGather(ctx context.Context, w io.Writer) error {
+	is, err := k.dyn.Resource(imageStreamsGVR).Namespace("openshift").Get(ctx, "must-gather", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	image, err := mustGatherImage(is)
+	if err != nil {
+		return err
+	}
+
+	return k.runMustGather(ctx, w, image)
+}
+
+// mustGatherImage returns the image referenced by the latest tag of the
+// must-gather image stream
+func mustGatherImage(is *unstructured.Unstructured) (string, error) {
+	tags, _, err := unstructured.NestedSlice(is.Object, "status", "tags")
+	if err != nil {
+		return "", err
+	}
+
+	for _, tag := range tags {
+		tag, ok := tag.(map[string]interface{})
+		if !ok || tag["tag"] != "latest" {
+			continue
+		}
+
+		items, _, _ := unstructured.NestedSlice(tag, "items")
+		if len(items) == 0 {
+			break
+		}
+
+		item, ok := items[0].(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		image, _, _ := unstructured.NestedString(item, "dockerImageReference")
+		if image != "" {
+			return image, nil
+		}
+	}
+
+	return "", errors.New("the must-gather image stream has no latest image")
+}
+
+func (k *kubeActions) runMustGather(ctx context.Context, w io.Writer, image string) error {
+	suffix := mustGatherSuffix()
+
+	ns, err := k.kubecli.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "openshift-must-gather-" + suffix,
+			Labels: map[string]string{
+				"openshift.io/run-level":             "0",
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+			Annotations: map[string]string{
+				"openshift.io/node-selector": "",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// clean up even if the caller has gone away
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := k.kubecli.CoreV1().Namespaces().Delete(ctx, ns.Name, metav1.DeleteOptions{})
+		if err != nil {
+			k.log.Errorf("failed to delete must-gather namespace %s: %v", ns.Name, err)
+		}
+	}()
+
+	crb, err := k.kubecli.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "must-gather-" + suffix,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "default",
+				Namespace: ns.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := k.kubecli.RbacV1().ClusterRoleBindings().Delete(ctx, crb.Name, metav1.DeleteOptions{})
+		if err != nil {
+			k.log.Errorf("failed to delete must-gather cluster role binding %s: %v", crb.Name, err)
+		}
+	}()
+
+	pod, err := k.kubecli.CoreV1().Pods(ns.Name).Create(ctx, mustGatherPod(ns.Name, image), metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	k.log.Infof("running must-gather in %s", ns.Name)
+
+	_, err = k.waitForMustGatherContainer(ctx, pod, mustGatherGatherContainerName, mustGatherTimeout)
+	if err != nil {
+		return err
+	}
+
+	rc, err := k.kubecli.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: mustGatherCopyContainerName,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), base64.NewDecoder(base64.StdEncoding, rc))
+	if err != nil {
+		return err
+	}
+
+	// the log stream ends when the container exits, but only its exit code
+	// says whether the tarball is complete, and only its termination message
+	// whether the stream carried all of it
+	message, err := k.waitForMustGatherContainer(ctx, pod, mustGatherCopyContainerName, mustGatherCopyTimeout)
+	if err != nil {
+		return err
+	}
+
+	return verifyMustGather(message, h.Sum(nil), n)
+}
+
+// verifyMustGather checks the sha256 sum and length of the tarball read from
+// the pod logs against the "<sha256> <length>" termination message of the
+// copy container
+func verifyMustGather(message string, sum []byte, n int64) error {
+	var wantSum string
+	var wantN int64
+	_, err := fmt.Sscanf(message, "%s %d", &wantSum, &wantN)
+	if err != nil {
+		return fmt.Errorf("invalid must-gather termination message %q: %w", message, err)
+	}
+
+	if hex.EncodeToString(sum) != wantSum || n != wantN {
+		return fmt.Errorf("must-gather tarball is corrupt: read %d bytes with sha256 %x, expected %d bytes with sha256 %s", n, sum, wantN, wantSum)
+	}
+
+	return nil
+}
+
+// waitForMustGatherContainer waits for the named container of the must-gather
+// pod to terminate and returns its termination message, or an error if it did
+// not succeed
+func (k *kubeActions) waitForMustGatherContainer(ctx context.Context, pod *corev1.Pod, containerName string, timeout time.Duration) (string, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var exitCode int32
+	var message string
+	err := wait.PollImmediateUntil(10*time.Second, func() (bool, error) {
+		p, err := k.kubecli.CoreV1().Pods(pod.Namespace).Get(timeoutCtx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		for _, cs := range append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...) {
+			if cs.Name == containerName && cs.State.Terminated != nil {
+				exitCode = cs.State.Terminated.ExitCode
+				message = cs.State.Terminated.Message
+				return true, nil
+			}
+		}
+
+		if p.Status.Phase == corev1.PodFailed {
+			return false, fmt.Errorf("must-gather pod %s/%s failed: %s", p.Namespace, p.Name, p.Status.Message)
+		}
+
+		return false, nil
+	}, timeoutCtx.Done())
+	if err == wait.ErrWaitTimeout {
+		return "", fmt.Errorf("must-gather container %s did not finish within %s", containerName, timeout)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if exitCode != 0 {
+		return "", fmt.Errorf("must-gather container %s exited with code %d", containerName, exitCode)
+	}
+
+	return message, nil
+}
+
+// mustGatherPod returns a pod which gathers into a shared volume and then
+// writes the result to its logs as a base64 encoded, gzipped tarball.  The
+// sha256 sum and length of the tarball are written to the termination log of
+// the copy container.
+func mustGatherPod(namespace, image string) *corev1.Pod {
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "must-gather-output",
+			MountPath: "/must-gather",
+		},
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mustGatherPodName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "must-gather",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: to.Int64Ptr(0),
+			PriorityClassName:             "system-cluster-critical",
+			NodeSelector: map[string]string{
+				"kubernetes.io/os": "linux",
+			},
+			Tolerations: []corev1.Toleration{
+				{
+					Operator: corev1.TolerationOpExists,
+				},
+			},
+			InitContainers: []corev1.Container{
+				{
+					Name:         mustGatherGatherContainerName,
+					Image:        image,
+					Command:      []string{"/usr/bin/gather"},
+					VolumeMounts: volumeMounts,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:         mustGatherCopyContainerName,
+					Image:        image,
+					Command:      []string{"/bin/bash", "-c", mustGatherCopyScript},
+					VolumeMounts: volumeMounts,
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "must-gather-output",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package adminactions
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+)
+
+// mustGatherContainerName is the blob container in the cluster storage account
+// which holds must-gather tarballs
+const mustGatherContainerName = "must-gather"
+
+// MustGatherStatusSuffix is appended to the name of a must-gather tarball to
+// name the blob which records the status of the run producing it
+const MustGatherStatusSuffix = ".status"
+
+// MustGatherUpload stores the must-gather tarball read from r in the cluster
+// storage account.  Nothing is stored if reading r fails.
+func (a *azureActions) MustGatherUpload(ctx context.Context, name string, r io.Reader, metadata map[string]string) error {
+	return a.mustGathers.PutStream(ctx, name, r, metadata)
+}
+
+// MustGatherPutStatus records the status of the run producing the named
+// must-gather tarball as the metadata of an empty status blob
+func (a *azureActions) MustGatherPutStatus(ctx context.Context, name string, metadata map[string]string) error {
+	return a.mustGathers.Put(ctx, name+MustGatherStatusSuffix, nil, metadata)
+}
+
+// MustGatherList lists the must-gather tarballs and status blobs stored for
+// the cluster
+func (a *azureActions) MustGatherList(ctx context.Context) ([]*blobstore.Blob, error) {
+	return a.mustGathers.List(ctx, "")
+}
+
+// MustGatherGet returns a stored must-gather tarball, which the caller must
+// close
+func (a *azureActions) MustGatherGet(ctx context.Context, name string) (io.ReadCloser, error) {
+	return a.mustGathers.GetStream(ctx, name)
+}
@@ -0,0 +1,212 @@
+package adminactions
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestRunMustGather(t *testing.T) {
+	ctx := context.Background()
+
+	mustGatherSuffix = func() string { return "abcde" }
+
+	// runPod makes created pods run to completion with gatherExitCode,
+	// as there is no kubelet to do so
+	runPod := func(gatherExitCode int32) ktesting.ReactionFunc {
+		return func(action ktesting.Action) (bool, kruntime.Object, error) {
+			pod := action.(ktesting.CreateAction).GetObject().(*corev1.Pod)
+			pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: mustGatherGatherContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: gatherExitCode,
+						},
+					},
+				},
+			}
+			if gatherExitCode != 0 {
+				pod.Status.Phase = corev1.PodFailed
+				return false, nil, nil
+			}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: mustGatherCopyContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: 0,
+						},
+					},
+				},
+			}
+			return false, nil, nil
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		gatherExitCode int32
+		wantErr        string
+	}{
+		{
+			name:           "gather fails",
+			gatherExitCode: 1,
+			wantErr:        "must-gather container gather exited with code 1",
+		},
+		{
+			// the fake clientset always returns "fake logs"
+			name:    "output is decoded",
+			wantErr: "illegal base64 data at input byte 4",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubecli := fake.NewSimpleClientset()
+			kubecli.PrependReactor("create", "pods", runPod(tt.gatherExitCode))
+
+			k := &kubeActions{
+				log:     logrus.NewEntry(logrus.StandardLogger()),
+				kubecli: kubecli,
+			}
+
+			err := k.runMustGather(ctx, &bytes.Buffer{}, "quay.io/openshift/must-gather:latest")
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			var createdPod bool
+			for _, action := range kubecli.Actions() {
+				if action.Matches("create", "pods") {
+					createdPod = true
+					if action.GetNamespace() != "openshift-must-gather-abcde" {
+						t.Errorf("pod created in namespace %q", action.GetNamespace())
+					}
+				}
+			}
+			if !createdPod {
+				t.Error("expected a must-gather pod to be created")
+			}
+
+			_, err = kubecli.CoreV1().Namespaces().Get(ctx, "openshift-must-gather-abcde", metav1.GetOptions{})
+			if !kerrors.IsNotFound(err) {
+				t.Errorf("expected the namespace to be deleted, got %v", err)
+			}
+
+			_, err = kubecli.RbacV1().ClusterRoleBindings().Get(ctx, "must-gather-abcde", metav1.GetOptions{})
+			if !kerrors.IsNotFound(err) {
+				t.Errorf("expected the cluster role binding to be deleted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyMustGather(t *testing.T) {
+	sum := sha256.Sum256([]byte("tarball"))
+
+	for _, tt := range []struct {
+		name    string
+		message string
+		n       int64
+		wantErr string
+	}{
+		{
+			name:    "tarball matches",
+			message: "db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0 7\n",
+			n:       7,
+		},
+		{
+			name:    "tarball cut short",
+			message: "db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0 4096\n",
+			n:       7,
+			wantErr: "must-gather tarball is corrupt: read 7 bytes with sha256 db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0, expected 4096 bytes with sha256 db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0",
+		},
+		{
+			name:    "tarball corrupt",
+			message: "0000000000000000000000000000000000000000000000000000000000000000 7\n",
+			n:       7,
+			wantErr: "must-gather tarball is corrupt: read 7 bytes with sha256 db4b4d0d1cb480bf9aeea253771c00febe627f236765fa37d6a5614f079a3aa0, expected 7 bytes with sha256 0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name:    "no termination message",
+			n:       7,
+			wantErr: `invalid must-gather termination message "": EOF`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyMustGather(tt.message, sum[:], tt.n)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMustGatherImage(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		tags      []interface{}
+		wantImage string
+		wantErr   string
+	}{
+		{
+			name: "latest tag",
+			tags: []interface{}{
+				map[string]interface{}{
+					"tag": "other",
+					"items": []interface{}{
+						map[string]interface{}{"dockerImageReference": "quay.io/other@sha256:1234"},
+					},
+				},
+				map[string]interface{}{
+					"tag": "latest",
+					"items": []interface{}{
+						map[string]interface{}{"dockerImageReference": "quay.io/must-gather@sha256:5678"},
+						map[string]interface{}{"dockerImageReference": "quay.io/must-gather@sha256:0000"},
+					},
+				},
+			},
+			wantImage: "quay.io/must-gather@sha256:5678",
+		},
+		{
+			name: "latest tag without items",
+			tags: []interface{}{
+				map[string]interface{}{
+					"tag": "latest",
+				},
+			},
+			wantErr: "the must-gather image stream has no latest image",
+		},
+		{
+			name:    "no tags",
+			wantErr: "the must-gather image stream has no latest image",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			is := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tt.tags != nil {
+				err := unstructured.SetNestedSlice(is.Object, tt.tags, "status", "tags")
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			image, err := mustGatherImage(is)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if image != tt.wantImage {
+				t.Errorf("got image %q, want %q", image, tt.wantImage)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package adminactions
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/operator"
+)
+
+// NodeDiagnostics is the allow-list of diagnostic commands which can be run on
+// a node.  Commands are run in the root filesystem of the node, mounted read
+// only, and never take arguments from the caller.
+var NodeDiagnostics = map[string][]string{
+	"crictl-pods":        {"crictl", "pods"},
+	"crictl-ps":          {"crictl", "ps", "--all"},
+	"df":                 {"df", "--human-readable"},
+	"ip-address":         {"ip", "address", "show"},
+	"ip-route":           {"ip", "route", "show", "table", "all"},
+	"journalctl-crio":    {"journalctl", "--no-pager", "--unit", "crio", "--since", "-1h"},
+	"journalctl-kubelet": {"journalctl", "--no-pager", "--unit", "kubelet", "--since", "-1h"},
+	"systemctl-failed":   {"systemctl", "--failed", "--no-pager"},
+}
+
+// NodeDiagnosticNames returns the names of the allowed node diagnostics,
+// sorted
+func NodeDiagnosticNames() []string {
+	names := make([]string, 0, len(NodeDiagnostics))
+	for name := range NodeDiagnostics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const (
+	// nodeDiagnosticContainerName matches the container name used by
+	// `oc debug node`
+	nodeDiagnosticContainerName = "container-00"
+	nodeDiagnosticLabel         = "aro.openshift.io/node-diagnostic"
+
+	nodeDiagnosticDeadline     = 5 * time.Minute
+	nodeDiagnosticStartTimeout = 2 * time.Minute
+)
+
+// RunNodeDiagnostic runs an allowed diagnostic command on a node in a short
+// lived privileged debug pod, streams its output to w and deletes the pod.
+// Only one diagnostic can run on a node at a time; a pod left over from an
+// earlier diagnostic is deleted once it can no longer be running.
+func (k *kubeActions) RunNodeDiagnostic(ctx context.Context, w http.ResponseWriter, nodeName, diagnostic string) error {
+	command, ok := NodeDiagnostics[diagnostic]
+	if !ok {
+		return fmt.Errorf("unknown node diagnostic %q", diagnostic)
+	}
+
+	_, err := k.kubecli.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	err = k.deleteStaleNodeDiagnosticPod(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	pod, err := k.kubecli.CoreV1().Pods(operator.Namespace).Create(ctx, k.nodeDiagnosticPod(nodeName, diagnostic, command), metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "", "A node diagnostic is already running on node '%s'.", nodeName)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// clean up even if the caller has gone away
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		err := k.kubecli.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: to.Int64Ptr(0)})
+		if err != nil {
+			k.log.Errorf("failed to delete node diagnostic pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}()
+
+	err = k.waitForPodStarted(ctx, pod)
+	if err != nil {
+		return err
+	}
+
+	var limit int64 = 52428800
+	rc, err := k.kubecli.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  nodeDiagnosticContainerName,
+		Follow:     true,
+		LimitBytes: &limit,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "text/plain")
+
+	_, err = io.Copy(&flushWriter{w: w}, rc)
+	if err != nil {
+		return err
+	}
+
+	// the output has been sent, so report a failing command in-band
+	p, err := k.kubecli.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+			_, err = fmt.Fprintf(w, "\n%s exited with code %d\n", diagnostic, cs.State.Terminated.ExitCode)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *kubeActions) waitForPodStarted(ctx context.Context, pod *corev1.Pod) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, nodeDiagnosticStartTimeout)
+	defer cancel()
+
+	err := wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		p, err := k.kubecli.CoreV1().Pods(pod.Namespace).Get(timeoutCtx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		return p.Status.Phase != corev1.PodPending && p.Status.Phase != "", nil
+	}, timeoutCtx.Done())
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("node diagnostic pod %s/%s did not start within %s", pod.Namespace, pod.Name, nodeDiagnosticStartTimeout)
+	}
+
+	return err
+}
+
+// deleteStaleNodeDiagnosticPod deletes the diagnostic pod of a node if it has
+// terminated or is older than any diagnostic can run for.  Such a pod is left
+// behind if the frontend restarts mid-diagnostic or fails to delete it, and
+// would otherwise block diagnostics on the node forever.  Pods which are not
+// labelled as node diagnostics are left alone.
+func (k *kubeActions) deleteStaleNodeDiagnosticPod(ctx context.Context, nodeName string) error {
+	pod, err := k.kubecli.CoreV1().Pods(operator.Namespace).Get(ctx, nodeDiagnosticPodName(nodeName), metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, ok := pod.Labels[nodeDiagnosticLabel]; !ok {
+		return nil
+	}
+
+	if pod.Status.Phase != corev1.PodSucceeded &&
+		pod.Status.Phase != corev1.PodFailed &&
+		time.Since(pod.CreationTimestamp.Time) < nodeDiagnosticStartTimeout+nodeDiagnosticDeadline {
+		return nil
+	}
+
+	k.log.Infof("deleting stale node diagnostic pod %s/%s", pod.Namespace, pod.Name)
+
+	err = k.kubecli.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+		GracePeriodSeconds: to.Int64Ptr(0),
+		Preconditions:      &metav1.Preconditions{UID: &pod.UID},
+	})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func nodeDiagnosticPodName(nodeName string) string {
+	return nodeName + "-debug"
+}
+
+// nodeDiagnosticPod returns a pod which runs command on the node, constructed
+// like the pods of `oc debug node`
+func (k *kubeActions) nodeDiagnosticPod(nodeName, diagnostic string, command []string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeDiagnosticPodName(nodeName),
+			Namespace: operator.Namespace,
+			Labels: map[string]string{
+				nodeDiagnosticLabel: diagnostic,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:              nodeName,
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: to.Int64Ptr(int64(nodeDiagnosticDeadline / time.Second)),
+			HostNetwork:           true,
+			HostPID:               true,
+			Tolerations: []corev1.Toleration{
+				{
+					Operator: corev1.TolerationOpExists,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    nodeDiagnosticContainerName,
+					Image:   k.operatorImage,
+					Command: append([]string{"chroot", "/host"}, command...),
+					SecurityContext: &corev1.SecurityContext{
+						Privileged: to.BoolPtr(true),
+						RunAsUser:  to.Int64Ptr(0),
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "host",
+							MountPath: "/host",
+							ReadOnly:  true,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "host",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: "/",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// flushWriter flushes w after every write, so that output reaches the caller
+// as the command produces it
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw *flushWriter) Write(b []byte) (int, error) {
+	n, err := fw.w.Write(b)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,217 @@
+package adminactions
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestRunNodeDiagnostic(t *testing.T) {
+	ctx := context.Background()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "master-0",
+		},
+	}
+
+	// startPod makes created pods run to completion with exitCode, as there
+	// is no kubelet to do so
+	startPod := func(exitCode int32) ktesting.ReactionFunc {
+		return func(action ktesting.Action) (bool, kruntime.Object, error) {
+			pod := action.(ktesting.CreateAction).GetObject().(*corev1.Pod)
+			pod.Status.Phase = corev1.PodSucceeded
+			if exitCode != 0 {
+				pod.Status.Phase = corev1.PodFailed
+			}
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{
+					Name: nodeDiagnosticContainerName,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode: exitCode,
+						},
+					},
+				},
+			}
+			return false, nil, nil
+		}
+	}
+
+	// diagnosticPod returns a diagnostic pod left over on master-0
+	diagnosticPod := func(created time.Time, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "master-0-debug",
+				Namespace:         "openshift-azure-operator",
+				Labels:            map[string]string{nodeDiagnosticLabel: "df"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name       string
+		objects    []kruntime.Object
+		nodeName   string
+		exitCode   int32
+		rereadErr  error
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:       "streams the output and deletes the pod",
+			objects:    []kruntime.Object{node},
+			nodeName:   "master-0",
+			wantOutput: "fake logs",
+		},
+		{
+			name:       "reports a failing command in-band",
+			objects:    []kruntime.Object{node},
+			nodeName:   "master-0",
+			exitCode:   1,
+			wantOutput: "fake logs\njournalctl-kubelet exited with code 1\n",
+		},
+		{
+			name:       "pod cannot be read after the command",
+			objects:    []kruntime.Object{node},
+			nodeName:   "master-0",
+			rereadErr:  errors.New("connection reset"),
+			wantOutput: "fake logs",
+			wantErr:    "connection reset",
+		},
+		{
+			name:     "node does not exist",
+			nodeName: "master-1",
+			wantErr:  `nodes "master-1" not found`,
+		},
+		{
+			name: "a diagnostic is already running on the node",
+			objects: []kruntime.Object{
+				node,
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "master-0-debug",
+						Namespace: "openshift-azure-operator",
+					},
+				},
+			},
+			nodeName: "master-0",
+			wantErr:  "409: RequestNotAllowed: : A node diagnostic is already running on node 'master-0'.",
+		},
+		{
+			name: "a recent diagnostic pod is still running on the node",
+			objects: []kruntime.Object{
+				node,
+				diagnosticPod(time.Now().Add(-time.Minute), corev1.PodRunning),
+			},
+			nodeName: "master-0",
+			wantErr:  "409: RequestNotAllowed: : A node diagnostic is already running on node 'master-0'.",
+		},
+		{
+			name: "a terminated diagnostic pod is replaced",
+			objects: []kruntime.Object{
+				node,
+				diagnosticPod(time.Now().Add(-time.Minute), corev1.PodSucceeded),
+			},
+			nodeName:   "master-0",
+			wantOutput: "fake logs",
+		},
+		{
+			name: "a stale diagnostic pod is replaced",
+			objects: []kruntime.Object{
+				node,
+				diagnosticPod(time.Now().Add(-time.Hour), corev1.PodPending),
+			},
+			nodeName:   "master-0",
+			wantOutput: "fake logs",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubecli := fake.NewSimpleClientset(tt.objects...)
+			kubecli.PrependReactor("create", "pods", startPod(tt.exitCode))
+			if tt.rereadErr != nil {
+				// the first get looks for a stale pod and the second waits
+				// for the pod to start; fail the third
+				var gets int
+				kubecli.PrependReactor("get", "pods", func(action ktesting.Action) (bool, kruntime.Object, error) {
+					if action.GetSubresource() != "" {
+						return false, nil, nil
+					}
+					gets++
+					if gets == 3 {
+						return true, nil, tt.rereadErr
+					}
+					return false, nil, nil
+				})
+			}
+
+			k := &kubeActions{
+				log:           logrus.NewEntry(logrus.StandardLogger()),
+				kubecli:       kubecli,
+				operatorImage: "arosvc.azurecr.io/aro:latest",
+			}
+
+			w := httptest.NewRecorder()
+
+			err := k.RunNodeDiagnostic(ctx, w, tt.nodeName, "journalctl-kubelet")
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if w.Body.String() != tt.wantOutput {
+				t.Errorf("got output %q, want %q", w.Body.String(), tt.wantOutput)
+			}
+
+			if tt.wantErr == "" || tt.rereadErr != nil {
+				_, err = kubecli.CoreV1().Pods("openshift-azure-operator").Get(ctx, "master-0-debug", metav1.GetOptions{})
+				if !kerrors.IsNotFound(err) {
+					t.Errorf("expected the pod to be deleted, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeDiagnosticPod(t *testing.T) {
+	k := &kubeActions{
+		operatorImage: "arosvc.azurecr.io/aro:latest",
+	}
+
+	pod := k.nodeDiagnosticPod("master-0", "ip-route", NodeDiagnostics["ip-route"])
+
+	if pod.Spec.NodeName != "master-0" {
+		t.Errorf("got node name %q", pod.Spec.NodeName)
+	}
+
+	c := pod.Spec.Containers[0]
+	wantCommand := []string{"chroot", "/host", "ip", "route", "show", "table", "all"}
+	if len(c.Command) != len(wantCommand) {
+		t.Fatalf("got command %v, want %v", c.Command, wantCommand)
+	}
+	for i := range wantCommand {
+		if c.Command[i] != wantCommand[i] {
+			t.Fatalf("got command %v, want %v", c.Command, wantCommand)
+		}
+	}
+
+	if !c.VolumeMounts[0].ReadOnly {
+		t.Error("expected the host filesystem to be mounted read only")
+	}
+}
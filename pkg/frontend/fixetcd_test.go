@@ -518,8 +518,12 @@ func TestFixEtcd(t *testing.T) {
 				ti.audit,
 				ti.log,
 				ti.env,
+				ti.adminActionsDatabase,
 				ti.asyncOperationsDatabase,
+				ti.clusterHealthDatabase,
 				ti.clusterManagerDatabase,
+				ti.fleetOperationsDatabase,
+				nil,
 				ti.openShiftClustersDatabase,
 				ti.subscriptionsDatabase,
 				nil,
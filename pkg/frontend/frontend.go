@@ -57,8 +57,12 @@ type frontend struct {
 	apiVersionMiddleware  middleware.ApiVersionValidator
 	maintenanceMiddleware middleware.MaintenanceMiddleware
 
+	dbAdminActions                database.AdminActions
 	dbAsyncOperations             database.AsyncOperations
+	dbClusterHealth               database.ClusterHealth
 	dbClusterManagerConfiguration database.ClusterManagerConfigurations
+	dbFleetOperations             database.FleetOperations
+	dbGateway                     database.Gateway
 	dbOpenShiftClusters           database.OpenShiftClusters
 	dbSubscriptions               database.Subscriptions
 	dbOpenShiftVersions           database.OpenShiftVersions
@@ -111,8 +115,12 @@ func NewFrontend(ctx context.Context,
 	auditLog *logrus.Entry,
 	baseLog *logrus.Entry,
 	_env env.Interface,
+	dbAdminActions database.AdminActions,
 	dbAsyncOperations database.AsyncOperations,
+	dbClusterHealth database.ClusterHealth,
 	dbClusterManagerConfiguration database.ClusterManagerConfigurations,
+	dbFleetOperations database.FleetOperations,
+	dbGateway database.Gateway,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbSubscriptions database.Subscriptions,
 	dbOpenShiftVersions database.OpenShiftVersions,
@@ -147,8 +155,12 @@ func NewFrontend(ctx context.Context,
 			AdminAuth: _env.AdminClientAuthorizer(),
 			ArmAuth:   _env.ArmClientAuthorizer(),
 		},
+		dbAdminActions:                dbAdminActions,
 		dbAsyncOperations:             dbAsyncOperations,
+		dbClusterHealth:               dbClusterHealth,
 		dbClusterManagerConfiguration: dbClusterManagerConfiguration,
+		dbFleetOperations:             dbFleetOperations,
+		dbGateway:                     dbGateway,
 		dbOpenShiftClusters:           dbOpenShiftClusters,
 		dbSubscriptions:               dbSubscriptions,
 		dbOpenShiftVersions:           dbOpenShiftVersions,
@@ -252,10 +264,10 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 						)
 					}
 
-					r.Delete("/", f.deleteOpenShiftCluster)
+					r.With(f.recordAdminAction).Delete("/", f.deleteOpenShiftCluster)
 					r.Get("/", f.getOpenShiftCluster)
-					r.Patch("/", f.putOrPatchOpenShiftCluster)
-					r.Put("/", f.putOrPatchOpenShiftCluster)
+					r.With(f.recordAdminAction).Patch("/", f.putOrPatchOpenShiftCluster)
+					r.With(f.recordAdminAction).Put("/", f.putOrPatchOpenShiftCluster)
 
 					r.Post("/listcredentials", f.postOpenShiftClusterCredentials)
 
@@ -297,8 +309,18 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 		})
 		r.Get("/supportedvmsizes", f.supportedvmsizes)
 
+		r.Route("/fleetoperations", func(r chi.Router) {
+			r.Get("/", f.getAdminFleetOperations)
+			r.Post("/", f.postAdminFleetOperation)
+			r.Get("/{fleetOperationId}", f.getAdminFleetOperation)
+			r.Post("/{fleetOperationId}/resume", f.postAdminFleetOperationResume)
+			r.Post("/{fleetOperationId}/cancel", f.postAdminFleetOperationCancel)
+		})
+
 		r.Route("/subscriptions/{subscriptionId}", func(r chi.Router) {
 			r.Route("/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}", func(r chi.Router) {
+				r.Use(f.recordAdminAction)
+
 				// Etcd recovery
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/etcdrecovery", f.postAdminOpenShiftClusterEtcdRecovery)
 
@@ -318,6 +340,20 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 
 				r.Get("/clusterdeployment", f.getAdminHiveClusterDeployment)
 
+				r.Get("/adminupdateplan", f.getAdminOpenShiftClusterAdminUpdatePlan)
+
+				r.Get("/health", f.getAdminOpenShiftClusterHealth)
+
+				r.Get("/operationtimeline", f.getAdminOpenShiftClusterOperationTimeline)
+
+				r.Get("/actionhistory", f.getAdminOpenShiftClusterActionHistory)
+
+				r.Get("/egresspolicy", f.getAdminOpenShiftClusterEgressPolicy)
+				r.Put("/egresspolicy", f.putAdminOpenShiftClusterEgressPolicy)
+
+				r.Get("/gatewaylimits", f.getAdminOpenShiftClusterGatewayLimits)
+				r.Put("/gatewaylimits", f.putAdminOpenShiftClusterGatewayLimits)
+
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/redeployvm", f.postAdminOpenShiftClusterRedeployVM)
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/stopvm", f.postAdminOpenShiftClusterStopVM)
@@ -335,6 +371,12 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/drainnode", f.postAdminOpenShiftClusterDrainNode)
 
+				r.Post("/nodediagnostics", f.postAdminOpenShiftClusterNodeDiagnostics)
+
+				r.Get("/mustgather", f.getAdminOpenShiftClusterMustGathers)
+				r.Post("/mustgather", f.postAdminOpenShiftClusterMustGather)
+				r.Get("/mustgather/{mustGatherName}", f.getAdminOpenShiftClusterMustGather)
+
 				r.With(f.maintenanceMiddleware.UnplannedMaintenanceSignal).Post("/etcdcertificaterenew", f.postAdminOpenShiftClusterEtcdCertificateRenew)
 			})
 		})
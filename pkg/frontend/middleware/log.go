@@ -38,6 +38,14 @@ func (w *logResponseWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 }
 
+// Flush allows handlers which stream their responses to flush through the
+// logResponseWriter
+func (w *logResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 type logReadCloser struct {
 	io.ReadCloser
 
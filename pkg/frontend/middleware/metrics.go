@@ -32,14 +32,14 @@ func (mm MetricsMiddleware) Metrics(h http.Handler) http.Handler {
 		//get the route pattern that matched
 		rctx := chi.RouteContext(r.Context())
 		routePattern := strings.Join(rctx.RoutePatterns, "")
-		mm.EmitGauge("frontend.count", 1, map[string]string{
+		mm.EmitCounter("frontend.count", 1, map[string]string{
 			"verb":        r.Method,
 			"api-version": apiVersion,
 			"code":        strconv.Itoa(w.(*logResponseWriter).statusCode),
 			"route":       routePattern,
 		})
 
-		mm.EmitGauge("frontend.duration", time.Since(t).Milliseconds(), map[string]string{
+		mm.EmitHistogram("frontend.duration", float64(time.Since(t).Milliseconds()), map[string]string{
 			"verb":        r.Method,
 			"api-version": apiVersion,
 			"code":        strconv.Itoa(w.(*logResponseWriter).statusCode),
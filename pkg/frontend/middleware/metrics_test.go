@@ -0,0 +1,40 @@
+package middleware
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m := mock_metrics.NewMockEmitter(controller)
+
+	dims := map[string]string{
+		"verb":        http.MethodGet,
+		"api-version": "2020-04-30",
+		"code":        "404",
+		"route":       "/subscriptions/{subscriptionId}",
+	}
+	m.EXPECT().EmitCounter("frontend.count", int64(1), dims)
+	m.EXPECT().EmitHistogram("frontend.duration", gomock.Any(), dims)
+
+	router := chi.NewRouter()
+	router.Use(MetricsMiddleware{m}.Metrics)
+	router.Get("/subscriptions/{subscriptionId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/subscriptions/00000000-0000-0000-0000-000000000000?api-version=2020-04-30", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+}
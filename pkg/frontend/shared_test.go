@@ -70,12 +70,20 @@ type testInfra struct {
 
 	openShiftClustersClient   *cosmosdb.FakeOpenShiftClusterDocumentClient
 	openShiftClustersDatabase database.OpenShiftClusters
+	adminActionsClient        *cosmosdb.FakeAdminActionDocumentClient
+	adminActionsDatabase      database.AdminActions
 	asyncOperationsClient     *cosmosdb.FakeAsyncOperationDocumentClient
 	asyncOperationsDatabase   database.AsyncOperations
 	billingClient             *cosmosdb.FakeBillingDocumentClient
 	billingDatabase           database.Billing
+	clusterHealthClient       *cosmosdb.FakeClusterHealthDocumentClient
+	clusterHealthDatabase     database.ClusterHealth
 	clusterManagerClient      *cosmosdb.FakeClusterManagerConfigurationDocumentClient
 	clusterManagerDatabase    database.ClusterManagerConfigurations
+	fleetOperationsClient     *cosmosdb.FakeFleetOperationDocumentClient
+	fleetOperationsDatabase   database.FleetOperations
+	gatewayClient             *cosmosdb.FakeGatewayDocumentClient
+	gatewayDatabase           database.Gateway
 	subscriptionsClient       *cosmosdb.FakeSubscriptionDocumentClient
 	subscriptionsDatabase     database.Subscriptions
 	openShiftVersionsClient   *cosmosdb.FakeOpenShiftVersionDocumentClient
@@ -117,7 +125,11 @@ func newTestInfraWithFeatures(t *testing.T, features map[env.Feature]bool) *test
 	_, auditEntry := testlog.NewAudit()
 	log := logrus.NewEntry(logrus.StandardLogger())
 
-	fixture := testdatabase.NewFixture()
+	// admin actions are recorded by every admin route, so their database is
+	// always present
+	adminActionsDatabase, adminActionsClient := testdatabase.NewFakeAdminActions()
+
+	fixture := testdatabase.NewFixture().WithAdminActions(adminActionsDatabase)
 	checker := testdatabase.NewChecker()
 
 	return &testInfra{
@@ -131,6 +143,9 @@ func newTestInfraWithFeatures(t *testing.T, features map[env.Feature]bool) *test
 		checker:    checker,
 		audit:      auditEntry,
 		log:        log,
+
+		adminActionsClient:   adminActionsClient,
+		adminActionsDatabase: adminActionsDatabase,
 		cli: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
@@ -185,6 +200,24 @@ func (ti *testInfra) WithClusterManagerConfigurations() *testInfra {
 	return ti
 }
 
+func (ti *testInfra) WithClusterHealth() *testInfra {
+	ti.clusterHealthDatabase, ti.clusterHealthClient = testdatabase.NewFakeClusterHealth()
+	ti.fixture.WithClusterHealth(ti.clusterHealthDatabase)
+	return ti
+}
+
+func (ti *testInfra) WithFleetOperations() *testInfra {
+	ti.fleetOperationsDatabase, ti.fleetOperationsClient = testdatabase.NewFakeFleetOperations()
+	ti.fixture.WithFleetOperations(ti.fleetOperationsDatabase)
+	return ti
+}
+
+func (ti *testInfra) WithGateway() *testInfra {
+	ti.gatewayDatabase, ti.gatewayClient = testdatabase.NewFakeGateway()
+	ti.fixture.WithGateway(ti.gatewayDatabase)
+	return ti
+}
+
 func (ti *testInfra) done() {
 	ti.controller.Finish()
 	ti.cli.CloseIdleConnections()
@@ -5,6 +5,7 @@ package frontend
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
 	utilnamespace "github.com/Azure/ARO-RP/pkg/util/namespace"
 	"github.com/Azure/ARO-RP/pkg/util/version"
 )
@@ -160,6 +162,67 @@ func validateAdminVMName(vmName string) error {
 	return nil
 }
 
+func validateAdminNodeDiagnostic(diagnostic string) error {
+	if _, ok := adminactions.NodeDiagnostics[diagnostic]; !ok {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided diagnostic '%s' is invalid: it must be one of %s.", diagnostic, strings.Join(adminactions.NodeDiagnosticNames(), ", "))
+	}
+
+	return nil
+}
+
+var rxMustGatherName = regexp.MustCompile(`^must-gather-[0-9]{8}-[0-9]{6}\.tar\.gz$`)
+
+func validateAdminMustGatherName(name string) error {
+	if !rxMustGatherName.MatchString(name) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided must-gather name '%s' is invalid.", name)
+	}
+
+	return nil
+}
+
+func validateAdminEgressPolicy(policy *api.GatewayEgressPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	for i, rule := range policy.Rules {
+		path := fmt.Sprintf("rules[%d]", i)
+
+		host := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(rule.FQDNPattern, "*."), "."))
+		if !strings.Contains(host, ".") || !validate.RxDomainNameRFC1123.MatchString(host) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".fqdnPattern",
+				"The provided FQDN pattern '%s' is invalid.", rule.FQDNPattern)
+		}
+
+		for j, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, fmt.Sprintf("%s.ports[%d]", path, j),
+					"The provided port '%d' is invalid.", port)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateAdminGatewayLimits(limits *api.GatewayLimits) error {
+	if limits == nil {
+		return nil
+	}
+
+	if limits.MaxConnections < 0 {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "maxConnections",
+			"The provided maximum connections '%d' is invalid.", limits.MaxConnections)
+	}
+
+	if limits.BandwidthBytesPerSecond < 0 {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "bandwidthBytesPerSecond",
+			"The provided bandwidth '%d' is invalid.", limits.BandwidthBytesPerSecond)
+	}
+
+	return nil
+}
+
 func validateAdminKubernetesPodLogs(namespace, podName, containerName string) error {
 	if podName == "" || !rxKubernetesString.MatchString(podName) {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided pod name '%s' is invalid.", podName)
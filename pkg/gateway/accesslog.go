@@ -0,0 +1,60 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/proxy"
+)
+
+// clusterStats aggregates the connections of a cluster which closed since
+// metrics were last emitted
+type clusterStats struct {
+	connections int64
+	bytesIn     int64
+	bytesOut    int64
+}
+
+// sourceIP returns the address of the client as reported by the PROXY protocol
+// header injected by PLS
+func sourceIP(conn *proxyproto.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+
+	return host
+}
+
+// logConnection writes a record of a closed connection to the access log and
+// adds it to the totals of the cluster
+func (g *gateway) logConnection(log *logrus.Entry, clusterResourceID string, start time.Time, stats *proxy.Stats) {
+	log.WithFields(logrus.Fields{
+		"bytes_in":     stats.BytesIn,
+		"bytes_out":    stats.BytesOut,
+		"duration":     g.now().Sub(start).Seconds(),
+		"close_reason": stats.CloseReason,
+	}).Print("connection closed")
+
+	g.statsMu.Lock()
+	defer g.statsMu.Unlock()
+
+	key := strings.ToLower(clusterResourceID)
+
+	s := g.clusterStats[key]
+	if s == nil {
+		s = &clusterStats{}
+		g.clusterStats[key] = s
+	}
+
+	s.connections++
+	s.bytesIn += stats.BytesIn
+	s.bytesOut += stats.BytesOut
+}
@@ -0,0 +1,69 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/proxy"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestLogConnection(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h, log := testlog.New()
+
+	g := &gateway{
+		clusterStats: map[string]*clusterStats{},
+		now:          func() time.Time { return start.Add(90 * time.Second) },
+	}
+
+	g.logConnection(log, "/subscriptions/SUB/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster", start, &proxy.Stats{
+		BytesIn:     100,
+		BytesOut:    2000,
+		CloseReason: proxy.CloseReasonServerClosed,
+	})
+	g.logConnection(log, "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster", start, &proxy.Stats{
+		CloseReason: proxy.CloseReasonDialFailed,
+	})
+
+	err := testlog.AssertLoggingOutput(h, []map[string]types.GomegaMatcher{
+		{
+			"msg":          gomega.Equal("connection closed"),
+			"level":        gomega.Equal(logrus.InfoLevel),
+			"bytes_in":     gomega.Equal(int64(100)),
+			"bytes_out":    gomega.Equal(int64(2000)),
+			"duration":     gomega.Equal(float64(90)),
+			"close_reason": gomega.Equal("server closed"),
+		},
+		{
+			"msg":          gomega.Equal("connection closed"),
+			"bytes_in":     gomega.Equal(int64(0)),
+			"bytes_out":    gomega.Equal(int64(0)),
+			"close_reason": gomega.Equal("dial failed"),
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := map[string]*clusterStats{
+		"/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster": {
+			connections: 2,
+			bytesIn:     100,
+			bytesOut:    2000,
+		},
+	}
+
+	if !reflect.DeepEqual(g.clusterStats, want) {
+		t.Error(g.clusterStats)
+	}
+}
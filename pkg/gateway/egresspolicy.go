@@ -0,0 +1,101 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// egressPolicyAllows returns true if a rule of the cluster's egress policy
+// which has not expired allows connections to host on port
+func egressPolicyAllows(policy *api.GatewayEgressPolicy, host string, port int, now time.Time) bool {
+	if policy == nil || host == "" {
+		return false
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, rule := range policy.Rules {
+		if rule.ExpiresAt != nil && !now.Before(*rule.ExpiresAt) {
+			continue
+		}
+
+		if !matchesFQDNPattern(rule.FQDNPattern, host) {
+			continue
+		}
+
+		if len(rule.Ports) == 0 {
+			if port == 443 {
+				return true
+			}
+			continue
+		}
+
+		for _, p := range rule.Ports {
+			if p == port {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesFQDNPattern returns true if host matches pattern.  A pattern of the
+// form *.example.com matches any subdomain of example.com.
+func matchesFQDNPattern(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+
+	if strings.HasPrefix(pattern, "*.") {
+		return len(host) > len(pattern)-1 && strings.HasSuffix(host, pattern[1:])
+	}
+
+	return host == pattern
+}
+
+// azurePlatformIP is the virtual IP of the Azure platform endpoint (WireServer),
+// which is reachable from every Azure VM
+var azurePlatformIP = net.IPv4(168, 63, 129, 16)
+
+// resolveEgressHost resolves a host allowed by a cluster's egress policy once
+// and returns the IP address to dial.  Unlike the static allow list, egress
+// policy rules are chosen per cluster and the names they match may resolve
+// anywhere, so connections to loopback, link-local (including IMDS), private,
+// unspecified and Azure platform addresses are refused.  All resolved
+// addresses are checked so that the result does not depend on which one is
+// picked.
+func (g *gateway) resolveEgressHost(ctx context.Context, host string) (net.IP, error) {
+	addrs, err := g.lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+
+	for _, addr := range addrs {
+		if !isAllowedEgressIP(addr.IP) {
+			return nil, fmt.Errorf("%s resolves to disallowed address %s", host, addr.IP)
+		}
+	}
+
+	return addrs[0].IP, nil
+}
+
+func isAllowedEgressIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.Equal(azurePlatformIP))
+}
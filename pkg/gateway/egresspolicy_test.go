@@ -0,0 +1,158 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestMatchesFQDNPattern(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		host    string
+		want    bool
+	}{
+		{
+			pattern: "example.com",
+			host:    "example.com",
+			want:    true,
+		},
+		{
+			pattern: "Example.COM.",
+			host:    "example.com",
+			want:    true,
+		},
+		{
+			pattern: "example.com",
+			host:    "www.example.com",
+		},
+		{
+			pattern: "*.example.com",
+			host:    "www.example.com",
+			want:    true,
+		},
+		{
+			pattern: "*.example.com",
+			host:    "a.b.example.com",
+			want:    true,
+		},
+		{
+			pattern: "*.example.com",
+			host:    "example.com",
+		},
+		{
+			pattern: "*.example.com",
+			host:    "notexample.com",
+		},
+		{
+			pattern: "*.example.com",
+			host:    ".example.com",
+		},
+	} {
+		t.Run(tt.pattern+" "+tt.host, func(t *testing.T) {
+			got := matchesFQDNPattern(tt.pattern, tt.host)
+			if got != tt.want {
+				t.Error(got)
+			}
+		})
+	}
+}
+
+func TestResolveEgressHost(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		addrs   []string
+		err     error
+		wantIP  string
+		wantErr string
+	}{
+		{
+			name:   "public",
+			addrs:  []string{"20.1.2.3", "2603:1030::1"},
+			wantIP: "20.1.2.3",
+		},
+		{
+			name:    "lookup fails",
+			err:     errors.New("no such host"),
+			wantErr: "no such host",
+		},
+		{
+			name:    "no addresses",
+			wantErr: "no addresses found for host.example.com",
+		},
+		{
+			name:    "imds",
+			addrs:   []string{"169.254.169.254"},
+			wantErr: "host.example.com resolves to disallowed address 169.254.169.254",
+		},
+		{
+			name:    "azure platform",
+			addrs:   []string{"168.63.129.16"},
+			wantErr: "host.example.com resolves to disallowed address 168.63.129.16",
+		},
+		{
+			name:    "loopback",
+			addrs:   []string{"127.0.0.1"},
+			wantErr: "host.example.com resolves to disallowed address 127.0.0.1",
+		},
+		{
+			name:    "ipv6 loopback",
+			addrs:   []string{"::1"},
+			wantErr: "host.example.com resolves to disallowed address ::1",
+		},
+		{
+			name:    "private",
+			addrs:   []string{"10.0.0.4"},
+			wantErr: "host.example.com resolves to disallowed address 10.0.0.4",
+		},
+		{
+			name:    "ipv4-mapped private",
+			addrs:   []string{"::ffff:192.168.0.1"},
+			wantErr: "host.example.com resolves to disallowed address 192.168.0.1",
+		},
+		{
+			name:    "ipv6 unique local",
+			addrs:   []string{"fd00::1"},
+			wantErr: "host.example.com resolves to disallowed address fd00::1",
+		},
+		{
+			name:    "unspecified",
+			addrs:   []string{"0.0.0.0"},
+			wantErr: "host.example.com resolves to disallowed address 0.0.0.0",
+		},
+		{
+			name:    "public and private",
+			addrs:   []string{"20.1.2.3", "172.16.0.1"},
+			wantErr: "host.example.com resolves to disallowed address 172.16.0.1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &gateway{
+				lookupIPAddr: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+					if host != "host.example.com" {
+						t.Fatal(host)
+					}
+
+					var addrs []net.IPAddr
+					for _, addr := range tt.addrs {
+						addrs = append(addrs, net.IPAddr{IP: net.ParseIP(addr)})
+					}
+					return addrs, tt.err
+				},
+			}
+
+			ip, err := g.resolveEgressHost(context.Background(), "host.example.com")
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if tt.wantIP != "" && !ip.Equal(net.ParseIP(tt.wantIP)) {
+				t.Error(ip)
+			}
+		})
+	}
+}
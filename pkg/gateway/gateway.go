@@ -51,6 +51,10 @@ type Runnable interface {
 //
 // Important note: regardless of mode, TLS traffic is never decrypted/re-
 // encrypted by the gateway.
+//
+// When an allowed connection closes, a record of it, including the bytes copied
+// in each direction, its duration and why it closed, is written to the access
+// log (accesslog.go).  Per-cluster totals are emitted as metrics every minute.
 type gateway struct {
 	env       env.Core
 	log       *logrus.Entry
@@ -74,6 +78,17 @@ type gateway struct {
 	m                metrics.Emitter
 	httpConnections  int64
 	httpsConnections int64
+
+	statsMu      sync.Mutex
+	clusterStats map[string]*clusterStats
+
+	defaultLimits api.GatewayLimits
+	limitersMu    sync.Mutex
+	limiters      map[string]*clusterLimiter
+
+	lookupIPAddr func(context.Context, string) ([]net.IPAddr, error)
+
+	now func() time.Time
 }
 
 type contextKey int
@@ -90,7 +105,7 @@ const SocketSize = 65536
 
 // TODO: may one day want to limit gateway readiness on # active connections
 
-func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.Entry, dbGateway database.Gateway, httpsl, httpl, httpHealthl net.Listener, acrResourceID, gatewayDomains string, m metrics.Emitter) (Runnable, error) {
+func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.Entry, dbGateway database.Gateway, httpsl, httpl, httpHealthl net.Listener, acrResourceID, gatewayDomains string, defaultLimits api.GatewayLimits, m metrics.Emitter) (Runnable, error) {
 	var domains []string
 	if gatewayDomains != "" {
 		domains = strings.Split(gatewayDomains, ",")
@@ -172,6 +187,15 @@ func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.En
 
 		allowList: allowList,
 		m:         m,
+
+		clusterStats: map[string]*clusterStats{},
+
+		defaultLimits: defaultLimits,
+		limiters:      map[string]*clusterLimiter{},
+
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+
+		now: time.Now,
 	}
 
 	panicMiddleware := middleware.Panic(baseLog)
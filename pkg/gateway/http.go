@@ -6,10 +6,12 @@ package gateway
 import (
 	"net"
 	"net/http"
+	"strconv"
 	"sync/atomic"
 	"time"
 
 	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
@@ -27,13 +29,28 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	host, port, err := net.SplitHostPort(r.Host)
+	host, _port, err := net.SplitHostPort(r.Host)
 	if err != nil {
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	clusterResourceID, isAllowed, err := g.isAllowed(conn, host)
+	port, err := strconv.Atoi(_port)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	linkID, err := linkID(conn)
+	if err != nil {
+		g.log.Error(err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// connections to ports other than 443 are only allowed by the cluster's
+	// egress policy
+	clusterResourceID, isAllowed, byPolicy, err := g.gatewayVerification(host, port, linkID)
 	if err != nil {
 		g.log.Error(err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -41,9 +58,14 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := utillog.EnrichWithResourceID(g.accessLog, clusterResourceID)
-	log = log.WithField("hostname", host)
+	log = log.WithFields(logrus.Fields{
+		"protocol":  "http",
+		"hostname":  host,
+		"port":      port,
+		"source_ip": sourceIP(conn),
+	})
 
-	if !isAllowed || port != "443" {
+	if !isAllowed {
 		log.Print("access denied")
 		g.m.EmitGauge("gateway.connections", 1, map[string]string{
 			"protocol": "http",
@@ -53,6 +75,33 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	addr := r.Host
+	if byPolicy {
+		ip, err := g.resolveEgressHost(ctx, host)
+		if err != nil {
+			log.Printf("access denied: %s", err)
+			g.m.EmitGauge("gateway.connections", 1, map[string]string{
+				"protocol": "http",
+				"action":   "denied",
+			})
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		addr = net.JoinHostPort(ip.String(), _port)
+	}
+
+	bandwidth, ok := g.acquireConnection(linkID, "http")
+	if !ok {
+		log.Print("access denied: too many connections")
+		g.m.EmitGauge("gateway.connections", 1, map[string]string{
+			"protocol": "http",
+			"action":   "rejected",
+		})
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+	defer g.releaseConnection(linkID)
+
 	log.Print("access allowed")
 	g.m.EmitGauge("gateway.connections", 1, map[string]string{
 		"protocol": "http",
@@ -62,7 +111,9 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 	atomic.AddInt64(&g.httpConnections, 1)
 	defer atomic.AddInt64(&g.httpConnections, -1)
 
-	proxy.Proxy(g.log, w, r, SocketSize)
+	start := g.now()
+	stats := proxy.ProxyTo(g.log, w, r, addr, SocketSize, bandwidth)
+	g.logConnection(log, clusterResourceID, start, stats)
 }
 
 func (g *gateway) checkReady(w http.ResponseWriter, r *http.Request) {
@@ -12,7 +12,9 @@ import (
 	"sync/atomic"
 
 	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/proxy"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	utilnet "github.com/Azure/ARO-RP/pkg/util/net"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
@@ -58,14 +60,24 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 	}
 
 	// 2. Determine if we allow the connection.
-	clusterResourceID, isAllowed, err := g.isAllowed(conn, serverName)
+	linkID, err := linkID(conn)
+	if err != nil {
+		g.log.Error(err)
+		return
+	}
+
+	clusterResourceID, isAllowed, byPolicy, err := g.gatewayVerification(serverName, 443, linkID)
 	if err != nil {
 		g.log.Error(err)
 		return
 	}
 
 	log := utillog.EnrichWithResourceID(g.accessLog, clusterResourceID)
-	log = log.WithField("hostname", serverName)
+	log = log.WithFields(logrus.Fields{
+		"protocol":  "https",
+		"hostname":  serverName,
+		"source_ip": sourceIP(conn),
+	})
 
 	if !isAllowed {
 		log.Print("access denied")
@@ -76,6 +88,31 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 		return
 	}
 
+	addr := serverName + ":443"
+	if byPolicy {
+		ip, err := g.resolveEgressHost(ctx, serverName)
+		if err != nil {
+			log.Printf("access denied: %s", err)
+			g.m.EmitGauge("gateway.connections", 1, map[string]string{
+				"protocol": "https",
+				"action":   "denied",
+			})
+			return
+		}
+		addr = net.JoinHostPort(ip.String(), "443")
+	}
+
+	bandwidth, ok := g.acquireConnection(linkID, "https")
+	if !ok {
+		log.Print("access denied: too many connections")
+		g.m.EmitGauge("gateway.connections", 1, map[string]string{
+			"protocol": "https",
+			"action":   "rejected",
+		})
+		return
+	}
+	defer g.releaseConnection(linkID)
+
 	log.Print("access allowed")
 	g.m.EmitGauge("gateway.connections", 1, map[string]string{
 		"protocol": "https",
@@ -85,9 +122,16 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 	atomic.AddInt64(&g.httpsConnections, 1)
 	defer atomic.AddInt64(&g.httpsConnections, -1)
 
+	start := g.now()
+	stats := &proxy.Stats{}
+	defer func() {
+		g.logConnection(log, clusterResourceID, start, stats)
+	}()
+
 	// 3. Dial the second leg of the connection (c2).
-	c2, err := utilnet.Dial("tcp", serverName+":443", SocketSize)
+	c2, err := utilnet.Dial("tcp", addr, SocketSize)
 	if err != nil {
+		stats.CloseReason = proxy.CloseReasonDialFailed
 		return
 	}
 
@@ -102,7 +146,7 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 			_ = conn.Raw().(*net.TCPConn).CloseWrite()
 		}()
 
-		_, _ = io.Copy(c1, c2)
+		stats.ServerDone(io.Copy(c1, proxy.NewLimitedReader(ctx, c2, bandwidth)))
 	}()
 
 	func() {
@@ -110,7 +154,7 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 			_ = c2.(*net.TCPConn).CloseWrite()
 		}()
 
-		_, _ = io.Copy(c2, c1)
+		stats.ClientDone(io.Copy(c2, proxy.NewLimitedReader(ctx, c1, bandwidth)))
 	}()
 
 	<-ch
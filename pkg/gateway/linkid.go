@@ -18,32 +18,24 @@ const (
 	pp2SubtypeAzurePrivateEndpointLinkID byte               = 1
 )
 
-// isAllowed reads the private endpoint link ID from the haproxy binary protocol
-// header injected on the front of the TCP stream by PLS.  It uses this to do a
-// lookup of the gateway collection record in the in-memory cache (this is
-// populated by the Cosmos DB change feed).  It then makes a decision about
-// whether to allow the connection based on a static allow list and the
-// additional hostnames in the gateway record. It returns the cluster ID and
-// deny/allow decision.
-func (g *gateway) isAllowed(conn *proxyproto.Conn, host string) (string, bool, error) {
-	linkID, err := linkID(conn)
-	if err != nil {
-		return "", false, err
-	}
-
-	return g.gatewayVerification(host, linkID)
-}
-
-func (g *gateway) gatewayVerification(host, linkID string) (string, bool, error) {
+// gatewayVerification looks up the gateway collection record of the private
+// endpoint link ID in the in-memory cache (this is populated by the Cosmos DB
+// change feed).  It then makes a decision about whether to allow a connection
+// to host:port based on a static allow list and the additional hostnames and
+// egress policy in the gateway record. It returns the cluster ID, the
+// deny/allow decision and whether the connection was allowed by the egress
+// policy, in which case the caller must resolve host with resolveEgressHost
+// before dialing it.
+func (g *gateway) gatewayVerification(host string, port int, linkID string) (string, bool, bool, error) {
 	g.mu.RLock()
 	gateway := g.gateways[linkID]
 	g.mu.RUnlock()
 
 	if gateway == nil {
-		return "", false, fmt.Errorf("gateway record not found for linkID %s", linkID)
+		return "", false, false, fmt.Errorf("gateway record not found for linkID %s", linkID)
 	}
 	if gateway.Deleting {
-		return gateway.ID, false, fmt.Errorf("gateway for linkId %s is being deleted", linkID)
+		return gateway.ID, false, false, fmt.Errorf("gateway for linkId %s is being deleted", linkID)
 	}
 
 	// Emit a gauge for the linkID if the host is empty
@@ -54,14 +46,22 @@ func (g *gateway) gatewayVerification(host, linkID string) (string, bool, error)
 		})
 	}
 
-	if _, found := g.allowList[strings.ToLower(host)]; found {
-		return gateway.ID, true, nil
+	if port == 443 {
+		if _, found := g.allowList[strings.ToLower(host)]; found {
+			return gateway.ID, true, false, nil
+		}
+
+		if strings.EqualFold(host, gateway.ImageRegistryStorageAccountName+".blob."+g.env.Environment().StorageEndpointSuffix) ||
+			strings.EqualFold(host, "cluster"+gateway.StorageSuffix+".blob."+g.env.Environment().StorageEndpointSuffix) {
+			return gateway.ID, true, false, nil
+		}
+	}
+
+	if egressPolicyAllows(gateway.EgressPolicy, host, port, g.now()) {
+		return gateway.ID, true, true, nil
 	}
 
-	return gateway.ID,
-		strings.EqualFold(host, gateway.ImageRegistryStorageAccountName+".blob."+g.env.Environment().StorageEndpointSuffix) ||
-			strings.EqualFold(host, "cluster"+gateway.StorageSuffix+".blob."+g.env.Environment().StorageEndpointSuffix),
-		nil
+	return gateway.ID, false, false, nil
 }
 
 // linkID retrieves the private endpoint link ID from the haproxy binary
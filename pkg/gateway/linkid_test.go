@@ -5,6 +5,7 @@ package gateway
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/golang/mock/gomock"
@@ -20,9 +21,11 @@ func TestGatewayVerification(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
 		host          string
+		port          int
 		idParam       string
 		wantId        string
 		wantIsAllowed bool
+		wantByPolicy  bool
 		wantErr       string
 		deleting      bool
 		allowList     map[string]struct{}
@@ -77,6 +80,63 @@ func TestGatewayVerification(t *testing.T) {
 			wantId:        "1",
 			wantIsAllowed: false,
 		},
+		{
+			name:          "storage account on another port",
+			host:          "account1.blob.storageEndpointSuffix",
+			port:          80,
+			idParam:       "1",
+			wantId:        "1",
+			wantIsAllowed: false,
+		},
+		{
+			name:          "allowlist on another port",
+			host:          "redhat.com",
+			port:          80,
+			idParam:       "2",
+			wantId:        "2",
+			wantIsAllowed: false,
+			allowList:     map[string]struct{}{"redhat.com": {}},
+		},
+		{
+			name:          "accepted by egress policy",
+			host:          "myregistry.azurecr.io",
+			idParam:       "policy",
+			wantId:        "policy",
+			wantIsAllowed: true,
+			wantByPolicy:  true,
+		},
+		{
+			name:          "accepted by egress policy wildcard on another port",
+			host:          "a.example.com",
+			port:          8443,
+			idParam:       "policy",
+			wantId:        "policy",
+			wantIsAllowed: true,
+			wantByPolicy:  true,
+		},
+		{
+			name:          "egress policy port not allowed",
+			host:          "myregistry.azurecr.io",
+			port:          80,
+			idParam:       "policy",
+			wantId:        "policy",
+			wantIsAllowed: false,
+		},
+		{
+			name:          "egress policy exception expired",
+			host:          "expired.example.org",
+			idParam:       "policy",
+			wantId:        "policy",
+			wantIsAllowed: false,
+		},
+		{
+			name:          "egress policy exception not yet expired",
+			host:          "current.example.org",
+			idParam:       "policy",
+			wantId:        "policy",
+			wantIsAllowed: true,
+			wantByPolicy:  true,
+		},
 		{
 			name:     "gateway deleting",
 			host:     "account2.blob.storageEndpointSuffix",
@@ -89,10 +149,25 @@ func TestGatewayVerification(t *testing.T) {
 			mockController := gomock.NewController(t)
 			defer mockController.Finish()
 
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			past := now.Add(-time.Hour)
+			future := now.Add(time.Hour)
+
 			gatewayMap := map[string]*api.Gateway{
 				"1":        {ID: "1", StorageSuffix: "suffix-1", ImageRegistryStorageAccountName: "account1"},
 				"2":        {ID: "2", StorageSuffix: "suffix-2", ImageRegistryStorageAccountName: "account2"},
 				"deleting": {ID: "deleting", StorageSuffix: "suffix-5", ImageRegistryStorageAccountName: "account5", Deleting: true},
+				"policy": {
+					ID: "policy",
+					EgressPolicy: &api.GatewayEgressPolicy{
+						Rules: []api.GatewayEgressRule{
+							{FQDNPattern: "myregistry.azurecr.io"},
+							{FQDNPattern: "*.example.com", Ports: []int{443, 8443}},
+							{FQDNPattern: "expired.example.org", ExpiresAt: &past},
+							{FQDNPattern: "current.example.org", ExpiresAt: &future},
+						},
+					},
+				},
 			}
 
 			mockCore := mock_env.NewMockCore(mockController)
@@ -116,9 +191,15 @@ func TestGatewayVerification(t *testing.T) {
 				gateways:  gatewayMap,
 				env:       mockCore,
 				allowList: tt.allowList,
+				now:       func() time.Time { return now },
 			}
 
-			gatewayID, isAllowed, err := gateway.gatewayVerification(tt.host, tt.idParam)
+			port := tt.port
+			if port == 0 {
+				port = 443
+			}
+
+			gatewayID, isAllowed, byPolicy, err := gateway.gatewayVerification(tt.host, port, tt.idParam)
 
 			if gatewayID != tt.wantId {
 				t.Error(gatewayID)
@@ -128,6 +209,10 @@ func TestGatewayVerification(t *testing.T) {
 				t.Error(isAllowed)
 			}
 
+			if byPolicy != tt.wantByPolicy {
+				t.Error(byPolicy)
+			}
+
 			utilerror.AssertErrorMessage(t, err, tt.wantErr)
 		})
 	}
@@ -33,4 +33,25 @@ func (g *gateway) _emitMetrics() {
 	if lastChangefeed, ok := g.lastChangefeed.Load().(time.Time); ok {
 		g.m.EmitGauge("gateway.lastchangefeed", lastChangefeed.Unix(), nil)
 	}
+
+	g.statsMu.Lock()
+	stats := g.clusterStats
+	g.clusterStats = map[string]*clusterStats{}
+	g.statsMu.Unlock()
+
+	for resourceID, s := range stats {
+		g.m.EmitGauge("gateway.cluster.connections", s.connections, map[string]string{
+			"resourceId": resourceID,
+		})
+
+		g.m.EmitGauge("gateway.cluster.bytes", s.bytesIn, map[string]string{
+			"resourceId": resourceID,
+			"direction":  "in",
+		})
+
+		g.m.EmitGauge("gateway.cluster.bytes", s.bytesOut, map[string]string{
+			"resourceId": resourceID,
+			"direction":  "out",
+		})
+	}
 }
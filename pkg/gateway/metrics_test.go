@@ -20,6 +20,7 @@ func TestEmitMetrics(t *testing.T) {
 		httpConnections    int64
 		httpsConnections   int64
 		lastChangefeedTime time.Time
+		clusterStats       map[string]*clusterStats
 	}{
 		{
 			name:               "1 http connection 1 https connection no lastChangefeed",
@@ -31,6 +32,17 @@ func TestEmitMetrics(t *testing.T) {
 			name:               "0 http connection 0 https connections lastChangefeed loads",
 			lastChangefeedTime: time.Date(2022, time.April, 19, 9, 0, 0, 0, time.UTC),
 		},
+		{
+			name:               "closed connections of a cluster",
+			lastChangefeedTime: testStartTime,
+			clusterStats: map[string]*clusterStats{
+				"/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster": {
+					connections: 3,
+					bytesIn:     100,
+					bytesOut:    2000,
+				},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			mockController := gomock.NewController(t)
@@ -40,10 +52,17 @@ func TestEmitMetrics(t *testing.T) {
 			mock_metrics.EXPECT().EmitGauge("gateway.connections.open", tt.httpConnections, map[string]string{"protocol": "http"}).Times(1)
 			mock_metrics.EXPECT().EmitGauge("gateway.connections.open", tt.httpsConnections, map[string]string{"protocol": "https"}).Times(1)
 
+			for resourceID, s := range tt.clusterStats {
+				mock_metrics.EXPECT().EmitGauge("gateway.cluster.connections", s.connections, map[string]string{"resourceId": resourceID}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.cluster.bytes", s.bytesIn, map[string]string{"resourceId": resourceID, "direction": "in"}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.cluster.bytes", s.bytesOut, map[string]string{"resourceId": resourceID, "direction": "out"}).Times(1)
+			}
+
 			gateway := gateway{
 				m:                mock_metrics,
 				httpConnections:  tt.httpConnections,
 				httpsConnections: tt.httpsConnections,
+				clusterStats:     tt.clusterStats,
 			}
 
 			if !tt.lastChangefeedTime.Equal(testStartTime) {
@@ -52,6 +71,10 @@ func TestEmitMetrics(t *testing.T) {
 			}
 
 			gateway._emitMetrics()
+
+			if len(gateway.clusterStats) != 0 {
+				t.Error(gateway.clusterStats)
+			}
 		})
 	}
 }
@@ -0,0 +1,118 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"strings"
+
+	"golang.org/x/time/rate"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// DefaultLimits are the limits which apply to each cluster unless the gateway
+// is configured with different defaults or the cluster's gateway record
+// overrides them
+var DefaultLimits = api.GatewayLimits{
+	MaxConnections:          500,
+	BandwidthBytesPerSecond: 50 << 20,
+}
+
+// clusterLimiter tracks the open connections of a cluster and shapes the
+// bandwidth which they share
+type clusterLimiter struct {
+	connections int
+	bandwidth   *rate.Limiter
+}
+
+// limits returns the limits which apply to the cluster with the given gateway
+// record
+func (g *gateway) limits(gateway *api.Gateway) api.GatewayLimits {
+	limits := g.defaultLimits
+
+	if gateway.Limits != nil {
+		if gateway.Limits.MaxConnections > 0 {
+			limits.MaxConnections = gateway.Limits.MaxConnections
+		}
+		if gateway.Limits.BandwidthBytesPerSecond > 0 {
+			limits.BandwidthBytesPerSecond = gateway.Limits.BandwidthBytesPerSecond
+		}
+	}
+
+	return limits
+}
+
+// acquireConnection reserves a connection for the cluster whose gateway record
+// has linkID, and returns the limiter which its connections share.  It returns
+// false, and emits a rejection metric, if the cluster already holds as many
+// connections open as it may.  Otherwise the caller must call
+// releaseConnection when the connection closes.  Limits are read from the
+// gateway record each time, so that changes received through the changefeed
+// apply to the cluster's open connections as well as to new ones.
+func (g *gateway) acquireConnection(linkID, protocol string) (*rate.Limiter, bool) {
+	g.mu.RLock()
+	gateway := g.gateways[linkID]
+	g.mu.RUnlock()
+
+	if gateway == nil {
+		return nil, false
+	}
+
+	limits := g.limits(gateway)
+
+	bandwidth := rate.Inf
+	burst := 0
+	if limits.BandwidthBytesPerSecond > 0 {
+		bandwidth = rate.Limit(limits.BandwidthBytesPerSecond)
+		burst = int(limits.BandwidthBytesPerSecond)
+	}
+
+	g.limitersMu.Lock()
+	defer g.limitersMu.Unlock()
+
+	l := g.limiters[linkID]
+	if l == nil {
+		l = &clusterLimiter{
+			bandwidth: rate.NewLimiter(bandwidth, burst),
+		}
+		g.limiters[linkID] = l
+	} else if l.bandwidth.Limit() != bandwidth || l.bandwidth.Burst() != burst {
+		l.bandwidth.SetLimit(bandwidth)
+		l.bandwidth.SetBurst(burst)
+	}
+
+	if limits.MaxConnections > 0 && l.connections >= limits.MaxConnections {
+		g.m.EmitCounter("gateway.connections.rejected", 1, map[string]string{
+			"protocol":   protocol,
+			"reason":     "maxConnections",
+			"resourceId": strings.ToLower(gateway.ID),
+		})
+
+		if l.connections == 0 {
+			delete(g.limiters, linkID)
+		}
+
+		return nil, false
+	}
+
+	l.connections++
+
+	return l.bandwidth, true
+}
+
+// releaseConnection releases a connection reserved by acquireConnection
+func (g *gateway) releaseConnection(linkID string) {
+	g.limitersMu.Lock()
+	defer g.limitersMu.Unlock()
+
+	l := g.limiters[linkID]
+	if l == nil {
+		return
+	}
+
+	l.connections--
+	if l.connections <= 0 {
+		delete(g.limiters, linkID)
+	}
+}
@@ -0,0 +1,166 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"golang.org/x/time/rate"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestAcquireConnection(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		defaultLimits  api.GatewayLimits
+		limits         *api.GatewayLimits
+		open           int
+		wantAcquired   int
+		wantBandwidth  rate.Limit
+		wantBurst      int
+		wantRejections int
+	}{
+		{
+			name:          "defaults",
+			defaultLimits: api.GatewayLimits{MaxConnections: 2, BandwidthBytesPerSecond: 1000},
+			open:          3,
+			wantAcquired:  2,
+			wantBandwidth: 1000,
+			wantBurst:     1000,
+			// the third connection is rejected
+			wantRejections: 1,
+		},
+		{
+			name:          "overrides",
+			defaultLimits: api.GatewayLimits{MaxConnections: 2, BandwidthBytesPerSecond: 1000},
+			limits:        &api.GatewayLimits{MaxConnections: 3, BandwidthBytesPerSecond: 2000},
+			open:          3,
+			wantAcquired:  3,
+			wantBandwidth: 2000,
+			wantBurst:     2000,
+		},
+		{
+			name:          "partial override keeps other defaults",
+			defaultLimits: api.GatewayLimits{MaxConnections: 2, BandwidthBytesPerSecond: 1000},
+			limits:        &api.GatewayLimits{BandwidthBytesPerSecond: 2000},
+			open:          3,
+			wantAcquired:  2,
+			wantBandwidth: 2000,
+			wantBurst:     2000,
+			// the third connection is rejected
+			wantRejections: 1,
+		},
+		{
+			name:          "no limits",
+			open:          3,
+			wantAcquired:  3,
+			wantBandwidth: rate.Inf,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			m := mock_metrics.NewMockEmitter(controller)
+			m.EXPECT().EmitCounter("gateway.connections.rejected", int64(1), map[string]string{
+				"protocol":   "https",
+				"reason":     "maxConnections",
+				"resourceId": "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+			}).Times(tt.wantRejections)
+
+			g := &gateway{
+				m: m,
+				gateways: map[string]*api.Gateway{
+					"1": {
+						ID:     "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster",
+						Limits: tt.limits,
+					},
+				},
+				defaultLimits: tt.defaultLimits,
+				limiters:      map[string]*clusterLimiter{},
+			}
+
+			var acquired int
+			var limiters []*rate.Limiter
+			for i := 0; i < tt.open; i++ {
+				l, ok := g.acquireConnection("1", "https")
+				if !ok {
+					continue
+				}
+				acquired++
+				limiters = append(limiters, l)
+			}
+
+			if acquired != tt.wantAcquired {
+				t.Error(acquired)
+			}
+
+			for _, l := range limiters {
+				if l != limiters[0] {
+					t.Error("expected connections to share a limiter")
+				}
+				if l.Limit() != tt.wantBandwidth {
+					t.Error(l.Limit())
+				}
+				if l.Burst() != tt.wantBurst {
+					t.Error(l.Burst())
+				}
+			}
+
+			for i := 0; i < acquired; i++ {
+				g.releaseConnection("1")
+			}
+
+			if len(g.limiters) != 0 {
+				t.Error(g.limiters)
+			}
+		})
+	}
+}
+
+func TestAcquireConnectionUpdatesLimits(t *testing.T) {
+	g := &gateway{
+		gateways: map[string]*api.Gateway{
+			"1": {ID: "cluster"},
+		},
+		defaultLimits: api.GatewayLimits{BandwidthBytesPerSecond: 1000},
+		limiters:      map[string]*clusterLimiter{},
+	}
+
+	l, ok := g.acquireConnection("1", "https")
+	if !ok {
+		t.Fatal("expected connection to be acquired")
+	}
+
+	// simulate an override arriving through the changefeed
+	g.gateways["1"] = &api.Gateway{
+		ID:     "cluster",
+		Limits: &api.GatewayLimits{BandwidthBytesPerSecond: 5000},
+	}
+
+	_, ok = g.acquireConnection("1", "https")
+	if !ok {
+		t.Fatal("expected connection to be acquired")
+	}
+
+	// the limiter of the open connection is updated too
+	if l.Limit() != 5000 {
+		t.Error(l.Limit())
+	}
+}
+
+func TestAcquireConnectionUnknownGateway(t *testing.T) {
+	g := &gateway{
+		gateways: map[string]*api.Gateway{},
+		limiters: map[string]*clusterLimiter{},
+	}
+
+	_, ok := g.acquireConnection("1", "https")
+	if ok {
+		t.Error("expected connection to be rejected")
+	}
+}
@@ -0,0 +1,63 @@
+package fanout
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/ARO-RP/pkg/metrics"
+)
+
+type fanout struct {
+	emitters []metrics.Emitter
+}
+
+// New returns a metrics.Emitter which emits each metric to all the given
+// emitters.
+func New(emitters ...metrics.Emitter) metrics.Emitter {
+	return &fanout{
+		emitters: emitters,
+	}
+}
+
+// EmitFloat records float information
+func (f *fanout) EmitFloat(metricName string, metricValue float64, dimensions map[string]string) {
+	for _, e := range f.emitters {
+		e.EmitFloat(metricName, metricValue, copyDimensions(dimensions))
+	}
+}
+
+// EmitGauge records gauge information
+func (f *fanout) EmitGauge(metricName string, metricValue int64, dimensions map[string]string) {
+	for _, e := range f.emitters {
+		e.EmitGauge(metricName, metricValue, copyDimensions(dimensions))
+	}
+}
+
+// EmitCounter records counter information
+func (f *fanout) EmitCounter(metricName string, metricValue int64, dimensions map[string]string) {
+	for _, e := range f.emitters {
+		e.EmitCounter(metricName, metricValue, copyDimensions(dimensions))
+	}
+}
+
+// EmitHistogram records histogram information
+func (f *fanout) EmitHistogram(metricName string, metricValue float64, dimensions map[string]string) {
+	for _, e := range f.emitters {
+		e.EmitHistogram(metricName, metricValue, copyDimensions(dimensions))
+	}
+}
+
+// copyDimensions gives each emitter its own copy of the dimensions, as some
+// emitters (e.g. statsd) add their own dimensions to the map they are given.
+func copyDimensions(dimensions map[string]string) map[string]string {
+	if dimensions == nil {
+		return nil
+	}
+
+	c := make(map[string]string, len(dimensions))
+	for k, v := range dimensions {
+		c[k] = v
+	}
+
+	return c
+}
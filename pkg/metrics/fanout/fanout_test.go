@@ -0,0 +1,38 @@
+package fanout
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestFanout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m1 := mock_metrics.NewMockEmitter(controller)
+	m2 := mock_metrics.NewMockEmitter(controller)
+
+	dims := map[string]string{"key": "value"}
+
+	// an emitter which modifies its dimensions must not affect the others
+	m1.EXPECT().EmitGauge("gauge", int64(42), dims).Do(func(_ string, _ int64, d map[string]string) {
+		d["hostname"] = "host"
+	})
+	m2.EXPECT().EmitGauge("gauge", int64(42), dims)
+	m1.EXPECT().EmitFloat("float", 4.2, nil)
+	m2.EXPECT().EmitFloat("float", 4.2, nil)
+
+	f := New(m1, m2)
+	f.EmitGauge("gauge", 42, dims)
+	f.EmitFloat("float", 4.2, nil)
+
+	if len(dims) != 1 {
+		t.Errorf("dimensions were modified: %v", dims)
+	}
+}
@@ -7,4 +7,12 @@ package metrics
 type Emitter interface {
 	EmitFloat(metricName string, metricValue float64, dimensions map[string]string)
 	EmitGauge(metricName string, metricValue int64, dimensions map[string]string)
+
+	// EmitCounter adds metricValue to a monotonically increasing count, e.g.
+	// of requests served
+	EmitCounter(metricName string, metricValue int64, dimensions map[string]string)
+
+	// EmitHistogram records an observation of a distribution, e.g. a request
+	// latency, so that percentiles can be computed
+	EmitHistogram(metricName string, metricValue float64, dimensions map[string]string)
 }
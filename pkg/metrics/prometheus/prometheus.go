@@ -0,0 +1,295 @@
+package prometheus
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/metrics"
+)
+
+const (
+	// namespace prefixes the names of all exported metrics
+	namespace = "aro"
+
+	// maxSeriesPerMetric bounds the number of label combinations kept for a
+	// single metric.  Series beyond the limit are dropped.
+	maxSeriesPerMetric = 1000
+
+	// seriesTTL is how long a series is exported for after it was last
+	// emitted, so that e.g. the metrics of deleted clusters go away.
+	seriesTTL = 15 * time.Minute
+)
+
+// histogramBuckets are the upper bounds of the buckets of exported
+// histograms.  They are wide enough to cover latencies measured in either
+// milliseconds or seconds.
+var histogramBuckets = prometheus.ExponentialBuckets(0.01, 2, 24)
+
+// Emitter is a metrics.Emitter which serves the metrics it is given for
+// scraping by Prometheus.  Gauges and floats are exported as gauges, counters
+// as counters and histograms as histograms, labelled with the metric's
+// dimensions.
+type Emitter interface {
+	metrics.Emitter
+	http.Handler
+}
+
+type kind int
+
+const (
+	kindGauge kind = iota
+	kindCounter
+	kindHistogram
+)
+
+type series struct {
+	labels  map[string]string
+	value   float64
+	updated time.Time
+
+	// histograms only
+	count   uint64
+	buckets []uint64
+}
+
+type metric struct {
+	kind   kind
+	series map[string]*series
+}
+
+type emitter struct {
+	log *logrus.Entry
+
+	mu      sync.Mutex
+	metrics map[string]*metric
+
+	handler http.Handler
+
+	lastDropLog time.Time
+	now         func() time.Time
+}
+
+// New returns a new Emitter.  Serve it on /metrics.
+func New(log *logrus.Entry) (Emitter, error) {
+	e := &emitter{
+		log:     log,
+		metrics: map[string]*metric{},
+		now:     time.Now,
+	}
+
+	r := prometheus.NewRegistry()
+	err := r.Register(e)
+	if err != nil {
+		return nil, err
+	}
+
+	e.handler = promhttp.HandlerFor(r, promhttp.HandlerOpts{
+		ErrorLog: e.log,
+	})
+
+	return e, nil
+}
+
+// EmitFloat records float information
+func (e *emitter) EmitFloat(metricName string, metricValue float64, dimensions map[string]string) {
+	e.emit(kindGauge, metricName, metricValue, dimensions)
+}
+
+// EmitGauge records gauge information
+func (e *emitter) EmitGauge(metricName string, metricValue int64, dimensions map[string]string) {
+	e.emit(kindGauge, metricName, float64(metricValue), dimensions)
+}
+
+// EmitCounter records counter information
+func (e *emitter) EmitCounter(metricName string, metricValue int64, dimensions map[string]string) {
+	e.emit(kindCounter, metricName, float64(metricValue), dimensions)
+}
+
+// EmitHistogram records histogram information
+func (e *emitter) EmitHistogram(metricName string, metricValue float64, dimensions map[string]string) {
+	e.emit(kindHistogram, metricName, metricValue, dimensions)
+}
+
+func (e *emitter) emit(k kind, metricName string, value float64, dimensions map[string]string) {
+	name := sanitize(namespace + "_" + metricName)
+
+	labels := make(map[string]string, len(dimensions))
+	for k, v := range dimensions {
+		labels[sanitize(k)] = v
+	}
+	key := seriesKey(labels)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	m := e.metrics[name]
+	if m == nil {
+		m = &metric{
+			kind:   k,
+			series: map[string]*series{},
+		}
+		e.metrics[name] = m
+	}
+
+	if m.kind != k {
+		e.logDrop("dropping series of metric %s: emitted as a different type", name)
+		return
+	}
+
+	s := m.series[key]
+	if s == nil {
+		if len(m.series) >= maxSeriesPerMetric {
+			e.logDrop("dropping series of metric %s: more than %d series", name, maxSeriesPerMetric)
+			return
+		}
+
+		s = &series{labels: labels}
+		if k == kindHistogram {
+			s.buckets = make([]uint64, len(histogramBuckets))
+		}
+		m.series[key] = s
+	}
+
+	switch k {
+	case kindGauge:
+		s.value = value
+	case kindCounter:
+		s.value += value
+	case kindHistogram:
+		s.value += value
+		s.count++
+		for i, upperBound := range histogramBuckets {
+			if value <= upperBound {
+				s.buckets[i]++
+			}
+		}
+	}
+	s.updated = e.now()
+}
+
+// logDrop logs that a series has been dropped, at most once a minute.  The
+// caller must hold e.mu.
+func (e *emitter) logDrop(format string, args ...interface{}) {
+	if e.now().After(e.lastDropLog.Add(time.Minute)) {
+		e.lastDropLog = e.now()
+		e.log.Warnf(format, args...)
+	}
+}
+
+func (e *emitter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.handler.ServeHTTP(w, r)
+}
+
+// Describe implements prometheus.Collector.  It sends no descriptors, making
+// the emitter an unchecked collector, as the set of metrics is not known in
+// advance.
+func (e *emitter) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector
+func (e *emitter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name, m := range e.metrics {
+		for key, s := range m.series {
+			if e.now().After(s.updated.Add(seriesTTL)) {
+				delete(m.series, key)
+			}
+		}
+		if len(m.series) == 0 {
+			delete(e.metrics, name)
+			continue
+		}
+
+		// all the series of a metric must have the same label names, so a
+		// label missing from a series is exported with an empty value
+		labelNames := unionLabelNames(m.series)
+		desc := prometheus.NewDesc(name, name, labelNames, nil)
+
+		for _, s := range m.series {
+			labelValues := make([]string, 0, len(labelNames))
+			for _, l := range labelNames {
+				labelValues = append(labelValues, s.labels[l])
+			}
+
+			var pm prometheus.Metric
+			var err error
+			switch m.kind {
+			case kindGauge:
+				pm, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, s.value, labelValues...)
+			case kindCounter:
+				pm, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, s.value, labelValues...)
+			case kindHistogram:
+				buckets := make(map[float64]uint64, len(histogramBuckets))
+				for i, upperBound := range histogramBuckets {
+					buckets[upperBound] = s.buckets[i]
+				}
+				pm, err = prometheus.NewConstHistogram(desc, s.count, s.value, buckets, labelValues...)
+			}
+			if err != nil {
+				e.log.Error(err)
+				continue
+			}
+
+			ch <- pm
+		}
+	}
+}
+
+func unionLabelNames(m map[string]*series) []string {
+	names := map[string]struct{}{}
+	for _, s := range m {
+		for l := range s.labels {
+			names[l] = struct{}{}
+		}
+	}
+
+	labelNames := make([]string, 0, len(names))
+	for l := range names {
+		labelNames = append(labelNames, l)
+	}
+	sort.Strings(labelNames)
+
+	return labelNames
+}
+
+func seriesKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(0)
+		sb.WriteString(labels[k])
+		sb.WriteByte(0)
+	}
+
+	return sb.String()
+}
+
+// sanitize converts a statsd-style name such as "backend.workers.count" into
+// a valid Prometheus metric or label name such as "backend_workers_count".
+func sanitize(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= '0' && c <= '9' && i > 0) {
+			b[i] = '_'
+		}
+	}
+
+	return string(b)
+}
@@ -0,0 +1,156 @@
+package prometheus
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func scrape(t *testing.T, e Emitter) string {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	e.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status code %d", rr.Code)
+	}
+
+	b, err := io.ReadAll(rr.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// drop comments, which only repeat the metric names
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestEmitter(t *testing.T) {
+	_, log := testlog.New()
+
+	e, err := New(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.EmitGauge("monitor.clusteroperators.count", 3, map[string]string{"resourceId": "cluster1", "available": "True"})
+	e.EmitGauge("monitor.clusteroperators.count", 1, map[string]string{"resourceId": "cluster1", "available": "False"})
+	e.EmitGauge("monitor.clusteroperators.count", 2, map[string]string{"resourceId": "cluster1", "available": "True"})
+	e.EmitFloat("monitor.duration", 1.5, map[string]string{"resourceId": "cluster1"})
+	e.EmitFloat("monitor.duration", 0.5, map[string]string{"resourceId": "cluster2", "extra-dimension": "x"})
+	e.EmitGauge("backend.workers.count", 7, nil)
+
+	want := strings.Join([]string{
+		`aro_backend_workers_count 7`,
+		`aro_monitor_clusteroperators_count{available="False",resourceId="cluster1"} 1`,
+		`aro_monitor_clusteroperators_count{available="True",resourceId="cluster1"} 2`,
+		`aro_monitor_duration{extra_dimension="",resourceId="cluster1"} 1.5`,
+		`aro_monitor_duration{extra_dimension="x",resourceId="cluster2"} 0.5`,
+	}, "\n")
+
+	if got := scrape(t, e); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEmitterBoundsCardinality(t *testing.T) {
+	_, log := testlog.New()
+
+	e, err := New(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < maxSeriesPerMetric+10; i++ {
+		e.EmitGauge("test", int64(i), map[string]string{"i": fmt.Sprint(i)})
+	}
+
+	if got := len(e.(*emitter).metrics["aro_test"].series); got != maxSeriesPerMetric {
+		t.Errorf("got %d series, want %d", got, maxSeriesPerMetric)
+	}
+}
+
+func TestEmitterExpiresSeries(t *testing.T) {
+	_, log := testlog.New()
+
+	e, err := New(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	e.(*emitter).now = func() time.Time { return now }
+
+	e.EmitGauge("test", 1, map[string]string{"cluster": "deleted"})
+
+	now = now.Add(seriesTTL / 2)
+	e.EmitGauge("test", 1, map[string]string{"cluster": "live"})
+
+	now = now.Add(seriesTTL/2 + time.Second)
+
+	if got, want := scrape(t, e), `aro_test{cluster="live"} 1`; got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for name, want := range map[string]string{
+		"backend.openshiftcluster.workers.count": "backend_openshiftcluster_workers_count",
+		"resourceId":                             "resourceId",
+		"extra-dimension":                        "extra_dimension",
+		"2xx":                                    "_xx",
+	} {
+		if got := sanitize(name); got != want {
+			t.Errorf("sanitize(%q): got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestEmitterCountersAndHistograms(t *testing.T) {
+	_, log := testlog.New()
+
+	e, err := New(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.EmitCounter("frontend.count", 1, map[string]string{"code": "200"})
+	e.EmitCounter("frontend.count", 2, map[string]string{"code": "200"})
+	e.EmitHistogram("frontend.duration", 0.015, nil)
+	e.EmitHistogram("frontend.duration", 0.03, nil)
+
+	// a metric can't change type
+	e.EmitGauge("frontend.count", 42, map[string]string{"code": "200"})
+
+	got := scrape(t, e) + "\n"
+
+	for _, want := range []string{
+		`aro_frontend_count{code="200"} 3`,
+		`aro_frontend_duration_bucket{le="0.01"} 0`,
+		`aro_frontend_duration_bucket{le="0.02"} 1`,
+		`aro_frontend_duration_bucket{le="0.04"} 2`,
+		`aro_frontend_duration_bucket{le="+Inf"} 2`,
+		`aro_frontend_duration_sum 0.045`,
+		`aro_frontend_duration_count 2`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("%q not found in\n%s", want, got)
+		}
+	}
+}
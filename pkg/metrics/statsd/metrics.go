@@ -18,8 +18,10 @@ type metric struct {
 	dimensions map[string]string
 	timestamp  time.Time
 
-	valueGauge *int64
-	valueFloat *float64
+	valueGauge     *int64
+	valueFloat     *float64
+	valueCounter   *int64
+	valueHistogram *float64
 }
 
 // MarshalJSON marshals a metric into JSON format.
@@ -55,16 +57,19 @@ func (m *metric) marshalStatsd() ([]byte, error) {
 		buf.Truncate(buf.Len() - 1)
 	}
 
-	if m.valueFloat != nil {
+	switch {
+	case m.valueFloat != nil:
 		_, err = fmt.Fprintf(buf, ":%f|f\n", *m.valueFloat)
-		if err != nil {
-			return nil, err
-		}
-	} else {
+	case m.valueCounter != nil:
+		_, err = fmt.Fprintf(buf, ":%d|c\n", *m.valueCounter)
+	case m.valueHistogram != nil:
+		// statsd timers are aggregated into percentiles, whatever the unit
+		_, err = fmt.Fprintf(buf, ":%f|ms\n", *m.valueHistogram)
+	default:
 		_, err = fmt.Fprintf(buf, ":%d|g\n", *m.valueGauge)
-		if err != nil {
-			return nil, err
-		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return buf.Bytes(), nil
@@ -47,3 +47,41 @@ func TestMarshalGauge(t *testing.T) {
 		t.Errorf("unexpected marshal output %s", string(b))
 	}
 }
+
+func TestMarshalCounter(t *testing.T) {
+	c := metric{
+		name:       "metric",
+		namespace:  "namespace",
+		dimensions: map[string]string{"key": "value"},
+
+		timestamp:    time.Unix(0, 0),
+		valueCounter: to.Int64Ptr(1),
+	}
+	b, err := c.marshalStatsd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"Metric":"metric","Namespace":"namespace","Dims":{"key":"value"},"TS":"1970-01-01T00:00:00.000"}:1|c`+"\n" {
+		t.Errorf("unexpected marshal output %s", string(b))
+	}
+}
+
+func TestMarshalHistogram(t *testing.T) {
+	h := metric{
+		name:       "metric",
+		namespace:  "namespace",
+		dimensions: map[string]string{"key": "value"},
+
+		timestamp:      time.Unix(0, 0),
+		valueHistogram: to.Float64Ptr(12.5),
+	}
+	b, err := h.marshalStatsd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != `{"Metric":"metric","Namespace":"namespace","Dims":{"key":"value"},"TS":"1970-01-01T00:00:00.000"}:12.500000|ms`+"\n" {
+		t.Errorf("unexpected marshal output %s", string(b))
+	}
+}
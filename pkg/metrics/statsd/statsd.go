@@ -78,6 +78,24 @@ func (s *statsd) EmitGauge(metricName string, metricValue int64, dimensions map[
 	})
 }
 
+// EmitCounter records counter information
+func (s *statsd) EmitCounter(metricName string, metricValue int64, dimensions map[string]string) {
+	s.emitMetric(&metric{
+		name:         metricName,
+		dimensions:   dimensions,
+		valueCounter: &metricValue,
+	})
+}
+
+// EmitHistogram records histogram information
+func (s *statsd) EmitHistogram(metricName string, metricValue float64, dimensions map[string]string) {
+	s.emitMetric(&metric{
+		name:           metricName,
+		dimensions:     dimensions,
+		valueHistogram: &metricValue,
+	})
+}
+
 func (s *statsd) emitMetric(m *metric) {
 	m.account = s.account
 	m.namespace = s.namespace
@@ -13,6 +13,11 @@ import (
 type cacheDoc struct {
 	doc  *api.OpenShiftClusterDocument
 	stop chan<- struct{}
+
+	// workTime is a moving average of the time spent monitoring the cluster
+	// per monitoring interval; measured is set once it has been measured
+	workTime time.Duration
+	measured bool
 }
 
 // deleteDoc deletes the given document from mon.docs, signalling the associated
@@ -5,6 +5,7 @@ package cluster
 
 import (
 	"context"
+	"sync"
 
 	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,46 +17,111 @@ import (
 
 // Anything that caches a List is an anti-pattern because of the potential
 // memory usage.  Don't add caches here: work to remove them.
+//
+// Collectors run concurrently, so each cached value is a cacheEntry: callers
+// share one request per value, and only successful results are cached.
+
+// cacheEntry caches the result of a single remote call.  Concurrent callers
+// share one call rather than each making it; callers waiting for another's
+// call give up when their own context is done, and retry with their own
+// context if it fails.  No lock is held while the call is made.
+type cacheEntry struct {
+	mu   sync.Mutex
+	v    interface{}
+	call *cacheCall
+}
+
+type cacheCall struct {
+	done chan struct{}
+	err  error
+}
+
+func (e *cacheEntry) get(ctx context.Context, f func(context.Context) (interface{}, error)) (interface{}, error) {
+	for {
+		e.mu.Lock()
+		if e.v != nil {
+			v := e.v
+			e.mu.Unlock()
+			return v, nil
+		}
+
+		c := e.call
+		if c == nil {
+			c = &cacheCall{done: make(chan struct{})}
+			e.call = c
+			e.mu.Unlock()
+
+			return e.do(ctx, c, f)
+		}
+		e.mu.Unlock()
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (e *cacheEntry) do(ctx context.Context, c *cacheCall, f func(context.Context) (interface{}, error)) (v interface{}, err error) {
+	defer func() {
+		e.mu.Lock()
+		if err == nil {
+			e.v = v
+		}
+		e.call = nil
+		e.mu.Unlock()
+
+		c.err = err
+		close(c.done)
+	}()
+
+	return f(ctx)
+}
 
 func (mon *Monitor) getClusterVersion(ctx context.Context) (*configv1.ClusterVersion, error) {
-	if mon.cache.cv != nil {
-		return mon.cache.cv, nil
+	cv, err := mon.cache.cv.get(ctx, func(ctx context.Context) (interface{}, error) {
+		return mon.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	mon.cache.cv, err = mon.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
-	return mon.cache.cv, err
+	return cv.(*configv1.ClusterVersion), nil
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listClusterOperators(ctx context.Context) (*configv1.ClusterOperatorList, error) {
-	if mon.cache.cos != nil {
-		return mon.cache.cos, nil
+	cos, err := mon.cache.cos.get(ctx, func(ctx context.Context) (interface{}, error) {
+		return mon.configcli.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	mon.cache.cos, err = mon.configcli.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
-	return mon.cache.cos, err
+	return cos.(*configv1.ClusterOperatorList), nil
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listNodes(ctx context.Context) (*corev1.NodeList, error) {
-	if mon.cache.ns != nil {
-		return mon.cache.ns, nil
+	ns, err := mon.cache.ns.get(ctx, func(ctx context.Context) (interface{}, error) {
+		return mon.cli.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	mon.cache.ns, err = mon.cli.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	return mon.cache.ns, err
+	return ns.(*corev1.NodeList), nil
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listARODeployments(ctx context.Context) (*appsv1.DeploymentList, error) {
-	if mon.cache.arodl != nil {
-		return mon.cache.arodl, nil
+	arodl, err := mon.cache.arodl.get(ctx, func(ctx context.Context) (interface{}, error) {
+		return mon.cli.AppsV1().Deployments(pkgoperator.Namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	mon.cache.arodl, err = mon.cli.AppsV1().Deployments(pkgoperator.Namespace).List(ctx, metav1.ListOptions{})
-	return mon.cache.arodl, err
+	return arodl.(*appsv1.DeploymentList), nil
 }
@@ -0,0 +1,96 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheEntry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("concurrent callers share one call", func(t *testing.T) {
+		var e cacheEntry
+		var calls int
+		release := make(chan struct{})
+
+		f := func(ctx context.Context) (interface{}, error) {
+			calls++
+			<-release
+			return "value", nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]interface{}, 5)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], _ = e.get(ctx, f)
+			}(i)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if calls != 1 {
+			t.Errorf("got %d calls", calls)
+		}
+		for _, v := range results {
+			if v != "value" {
+				t.Error(v)
+			}
+		}
+	})
+
+	t.Run("failures are not cached", func(t *testing.T) {
+		var e cacheEntry
+
+		_, err := e.get(ctx, func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("sad")
+		})
+		if err == nil || err.Error() != "sad" {
+			t.Error(err)
+		}
+
+		v, err := e.get(ctx, func(ctx context.Context) (interface{}, error) {
+			return "value", nil
+		})
+		if err != nil || v != "value" {
+			t.Error(v, err)
+		}
+	})
+
+	t.Run("waiters give up when their context is done", func(t *testing.T) {
+		var e cacheEntry
+		release := make(chan struct{})
+		defer close(release)
+
+		started := make(chan struct{})
+		go func() {
+			_, _ = e.get(ctx, func(ctx context.Context) (interface{}, error) {
+				close(started)
+				<-release
+				return "value", nil
+			})
+		}()
+		<-started
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err := e.get(waitCtx, func(ctx context.Context) (interface{}, error) {
+			t.Error("unexpected call")
+			return nil, nil
+		})
+		if err != context.DeadlineExceeded {
+			t.Error(err)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/operator"
 	"github.com/Azure/ARO-RP/pkg/operator/controllers/genevalogging"
 	utilcert "github.com/Azure/ARO-RP/pkg/util/cert"
@@ -35,6 +36,7 @@ func (mon *Monitor) emitCertificateExpirationStatuses(ctx context.Context) error
 	mdsdCert, err := mon.getCertificate(ctx, operator.Namespace, operator.SecretName, genevalogging.GenevaCertName)
 	if kerrors.IsNotFound(err) {
 		mon.emitGauge(secretMissingMetricName, int64(1), secretMissingMetric(operator.Namespace, operator.SecretName))
+		mon.reportSecretMissingHealth(operator.Namespace, operator.SecretName)
 	} else if err != nil {
 		return err
 	} else {
@@ -44,6 +46,7 @@ func (mon *Monitor) emitCertificateExpirationStatuses(ctx context.Context) error
 			"name":      operator.SecretName,
 			"namespace": operator.Namespace,
 		})
+		mon.reportCertificateExpirationHealth(operator.Namespace, operator.SecretName, mdsdCert)
 	}
 
 	if dns.IsManagedDomain(mon.oc.Properties.ClusterProfile.Domain) {
@@ -62,6 +65,7 @@ func (mon *Monitor) emitCertificateExpirationStatuses(ctx context.Context) error
 			certificate, err := mon.getCertificate(ctx, operator.Namespace, secretName, corev1.TLSCertKey)
 			if kerrors.IsNotFound(err) {
 				mon.emitGauge(secretMissingMetricName, int64(1), secretMissingMetric(operator.Namespace, secretName))
+				mon.reportSecretMissingHealth(operator.Namespace, secretName)
 			} else if err != nil {
 				return err
 			} else {
@@ -71,6 +75,7 @@ func (mon *Monitor) emitCertificateExpirationStatuses(ctx context.Context) error
 					"name":      secretName,
 					"namespace": operator.Namespace,
 				})
+				mon.reportCertificateExpirationHealth(operator.Namespace, secretName, certificate)
 			}
 		}
 	}
@@ -78,6 +83,24 @@ func (mon *Monitor) emitCertificateExpirationStatuses(ctx context.Context) error
 	return nil
 }
 
+// reportCertificateExpirationHealth reports an expired certificate as making
+// the cluster unhealthy, and a certificate close to expiry as making it
+// degraded.
+func (mon *Monitor) reportCertificateExpirationHealth(namespace, name string, certificate *x509.Certificate) {
+	daysUntilExpiration := time.Until(certificate.NotAfter) / (24 * time.Hour)
+
+	switch {
+	case time.Now().After(certificate.NotAfter):
+		mon.reportHealth("certificateExpirationStatuses", api.HealthStateUnhealthy, "certificate in secret %s/%s has expired", namespace, name)
+	case daysUntilExpiration < certificateExpiryDegradedDays:
+		mon.reportHealth("certificateExpirationStatuses", api.HealthStateDegraded, "certificate in secret %s/%s expires in %d days", namespace, name, daysUntilExpiration)
+	}
+}
+
+func (mon *Monitor) reportSecretMissingHealth(namespace, name string) {
+	mon.reportHealth("certificateExpirationStatuses", api.HealthStateDegraded, "certificate secret %s/%s is missing", namespace, name)
+}
+
 func (mon *Monitor) getCertificate(ctx context.Context, secretNamespace, secretName, secretKey string) (*x509.Certificate, error) {
 	secret := &corev1.Secret{}
 	err := mon.ocpclientset.Get(ctx, client.ObjectKey{
@@ -6,22 +6,19 @@ package cluster
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/Azure/go-autorest/autorest/azure"
-	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	machineclient "github.com/openshift/client-go/machine/clientset/versioned"
 	mcoclient "github.com/openshift/machine-config-operator/pkg/generated/clientset/versioned"
 	"github.com/sirupsen/logrus"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/metrics"
-	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 	aroclient "github.com/Azure/ARO-RP/pkg/operator/clientset/versioned"
 	"github.com/Azure/ARO-RP/pkg/util/steps"
 )
@@ -29,10 +26,18 @@ import (
 type Monitor struct {
 	log       *logrus.Entry
 	hourlyRun bool
+	schedule  *Schedule
 
 	oc   *api.OpenShiftCluster
 	dims map[string]string
 
+	// healthReasons are the health reasons reported in this run, by signal.
+	// Reasons reported by collectors which were abandoned after their
+	// timeout are dropped.
+	healthMu      sync.Mutex
+	healthReasons map[string][]api.HealthReason
+	abandoned     map[string]bool
+
 	restconfig *rest.Config
 	cli        kubernetes.Interface
 	configcli  configclient.Interface
@@ -46,15 +51,23 @@ type Monitor struct {
 
 	// access below only via the helper functions in cache.go
 	cache struct {
-		cos   *configv1.ClusterOperatorList
-		cs    *arov1alpha1.ClusterList
-		cv    *configv1.ClusterVersion
-		ns    *corev1.NodeList
-		arodl *appsv1.DeploymentList
+		cos   cacheEntry // *configv1.ClusterOperatorList
+		cs    cacheEntry // *arov1alpha1.ClusterList
+		cv    cacheEntry // *configv1.ClusterVersion
+		ns    cacheEntry // *corev1.NodeList
+		arodl cacheEntry // *appsv1.DeploymentList
 	}
 }
 
-func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, hourlyRun bool) (*Monitor, error) {
+func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, hourlyRun bool, schedule *Schedule) (*Monitor, error) {
+	if schedule == nil {
+		configs, err := CollectorConfigs("")
+		if err != nil {
+			return nil, err
+		}
+		schedule = NewSchedule(configs)
+	}
+
 	r, err := azure.ParseResourceID(oc.ID)
 	if err != nil {
 		return nil, err
@@ -105,6 +118,7 @@ func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftClu
 	return &Monitor{
 		log:       log,
 		hourlyRun: hourlyRun,
+		schedule:  schedule,
 
 		oc:   oc,
 		dims: dims,
@@ -150,8 +164,18 @@ func (mon *Monitor) Monitor(ctx context.Context) (errs []error) {
 		errs = append(errs, err)
 		mon.emitFailureToGatherMetric(steps.FriendlyName(mon.emitAPIServerHealthzCode), err)
 	}
+	defer func() {
+		mon.emitHealth(mon.Health())
+	}()
+
 	// If API is not returning 200, fallback to checking ping and short circuit the rest of the checks
 	if statusCode != http.StatusOK {
+		if statusCode == 0 {
+			mon.reportHealth(healthSignalAPIServer, api.HealthStateUnhealthy, "API server did not respond to the health check")
+		} else {
+			mon.reportHealth(healthSignalAPIServer, api.HealthStateUnhealthy, "API server health check returned status code %d", statusCode)
+		}
+
 		err := mon.emitAPIServerPingCode(ctx)
 		if err != nil {
 			errs = append(errs, err)
@@ -159,40 +183,8 @@ func (mon *Monitor) Monitor(ctx context.Context) (errs []error) {
 		}
 		return
 	}
-	for _, f := range []func(context.Context) error{
-		mon.emitAroOperatorHeartbeat,
-		mon.emitAroOperatorConditions,
-		mon.emitNSGReconciliation,
-		mon.emitClusterOperatorConditions,
-		mon.emitClusterOperatorVersions,
-		mon.emitClusterVersionConditions,
-		mon.emitClusterVersions,
-		mon.emitDaemonsetStatuses,
-		mon.emitDeploymentStatuses,
-		mon.emitMachineConfigPoolConditions,
-		mon.emitMachineConfigPoolUnmanagedNodeCounts,
-		mon.emitNodeConditions,
-		mon.emitPodConditions,
-		mon.emitDebugPodsCount,
-		mon.detectQuotaFailure,
-		mon.emitReplicasetStatuses,
-		mon.emitStatefulsetStatuses,
-		mon.emitJobConditions,
-		mon.emitSummary,
-		mon.emitHiveRegistrationStatus,
-		mon.emitOperatorFlagsAndSupportBanner,
-		mon.emitPucmState,
-		mon.emitCertificateExpirationStatuses,
-		mon.emitEtcdCertificateExpiry,
-		mon.emitPrometheusAlerts, // at the end for now because it's the slowest/least reliable
-	} {
-		err = f(ctx)
-		if err != nil {
-			errs = append(errs, err)
-			mon.emitFailureToGatherMetric(steps.FriendlyName(f), err)
-			// keep going
-		}
-	}
+
+	errs = append(errs, mon.runCollectors(ctx, mon.collectors())...)
 
 	return
 }
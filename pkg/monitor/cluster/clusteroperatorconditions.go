@@ -8,6 +8,8 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
 )
 
 type clusterOperatorConditionsIgnoreStruct struct {
@@ -52,6 +54,13 @@ func (mon *Monitor) emitClusterOperatorConditions(ctx context.Context) error {
 				"type":   string(c.Type),
 			})
 
+			switch {
+			case c.Type == configv1.OperatorAvailable:
+				mon.reportHealth("clusterOperatorConditions", api.HealthStateUnhealthy, "clusteroperator %s is not available", co.Name)
+			case c.Type == configv1.OperatorDegraded && c.Status == configv1.ConditionTrue:
+				mon.reportHealth("clusterOperatorConditions", api.HealthStateDegraded, "clusteroperator %s is degraded", co.Name)
+			}
+
 			if mon.hourlyRun {
 				mon.log.WithFields(logrus.Fields{
 					"metric":  "clusteroperator.conditions",
@@ -0,0 +1,335 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+const (
+	defaultCollectorInterval = time.Minute
+	defaultCollectorTimeout  = 30 * time.Second
+
+	// maxConcurrentCollectors is the number of collectors which may run
+	// against a cluster at once
+	maxConcurrentCollectors = 5
+
+	// collectorIntervalSlack allows for jitter in the once a minute ticks of
+	// the monitor worker, so that a collector with an interval of N minutes
+	// runs every N ticks.
+	collectorIntervalSlack = 5 * time.Second
+
+	// CollectorOperatorFlagPrefix prefixes the OperatorFlags which override
+	// the collector configuration of a single cluster, e.g.
+	// "aro.monitor.collectors.prometheusAlerts.enabled": "false".
+	CollectorOperatorFlagPrefix = "aro.monitor.collectors."
+)
+
+// CollectorConfig configures when a monitoring collector runs
+type CollectorConfig struct {
+	Enabled  bool
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type collector struct {
+	name string
+	f    func(context.Context) error
+
+	// interval and timeout override the defaults, if set
+	interval time.Duration
+	timeout  time.Duration
+}
+
+// collectors returns the registry of collectors, in the order in which they
+// are started.  Collectors run concurrently after the API server health check,
+// and only if it succeeds.
+func (mon *Monitor) collectors() []collector {
+	return []collector{
+		{name: "aroOperatorHeartbeat", f: mon.emitAroOperatorHeartbeat},
+		{name: "aroOperatorConditions", f: mon.emitAroOperatorConditions},
+		{name: "nsgReconciliation", f: mon.emitNSGReconciliation},
+		{name: "clusterOperatorConditions", f: mon.emitClusterOperatorConditions},
+		{name: "clusterOperatorVersions", f: mon.emitClusterOperatorVersions},
+		{name: "clusterVersionConditions", f: mon.emitClusterVersionConditions},
+		{name: "clusterVersions", f: mon.emitClusterVersions},
+		{name: "daemonsetStatuses", f: mon.emitDaemonsetStatuses},
+		{name: "deploymentStatuses", f: mon.emitDeploymentStatuses},
+		{name: "machineConfigPoolConditions", f: mon.emitMachineConfigPoolConditions},
+		{name: "machineConfigPoolUnmanagedNodeCounts", f: mon.emitMachineConfigPoolUnmanagedNodeCounts},
+		{name: "nodeConditions", f: mon.emitNodeConditions},
+		{name: "podConditions", f: mon.emitPodConditions},
+		{name: "debugPodsCount", f: mon.emitDebugPodsCount},
+		{name: "quotaFailure", f: mon.detectQuotaFailure},
+		{name: "replicasetStatuses", f: mon.emitReplicasetStatuses},
+		{name: "statefulsetStatuses", f: mon.emitStatefulsetStatuses},
+		{name: "jobConditions", f: mon.emitJobConditions},
+		{name: "summary", f: mon.emitSummary},
+		{name: "hiveRegistrationStatus", f: mon.emitHiveRegistrationStatus},
+		{name: "operatorFlagsAndSupportBanner", f: mon.emitOperatorFlagsAndSupportBanner},
+		{name: "pucmState", f: mon.emitPucmState},
+		{name: "certificateExpirationStatuses", f: mon.emitCertificateExpirationStatuses},
+		{name: "etcdCertificateExpiry", f: mon.emitEtcdCertificateExpiry},
+		// started last because it's the slowest/least reliable
+		{name: "prometheusAlerts", f: mon.emitPrometheusAlerts},
+	}
+}
+
+// CollectorConfigs returns the configuration of every collector: the defaults,
+// with the given overrides applied.  overrides is a comma separated list of
+// <collector>.<setting>=<value>, where setting is one of enabled, interval or
+// timeout, e.g. "prometheusAlerts.interval=5m,nsgReconciliation.enabled=false".
+func CollectorConfigs(overrides string) (map[string]CollectorConfig, error) {
+	configs := map[string]CollectorConfig{}
+	for _, c := range (&Monitor{}).collectors() {
+		config := CollectorConfig{
+			Enabled:  true,
+			Interval: c.interval,
+			Timeout:  c.timeout,
+		}
+		if config.Interval == 0 {
+			config.Interval = defaultCollectorInterval
+		}
+		if config.Timeout == 0 {
+			config.Timeout = defaultCollectorTimeout
+		}
+		configs[c.name] = config
+	}
+
+	m := map[string]string{}
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+
+		k, v, found := strings.Cut(override, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid collector override %q", override)
+		}
+		m[k] = v
+	}
+
+	err := applyCollectorOverrides(configs, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// applyCollectorOverrides applies overrides of the form
+// "<collector>.<setting>": "<value>" to configs.
+func applyCollectorOverrides(configs map[string]CollectorConfig, overrides map[string]string) error {
+	// apply in a stable order, so that errors are deterministic
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := overrides[k]
+
+		name, setting, found := strings.Cut(k, ".")
+		config, ok := configs[name]
+		if !found || !ok {
+			return fmt.Errorf("invalid collector override %q: unknown collector", k)
+		}
+
+		var err error
+		switch setting {
+		case "enabled":
+			config.Enabled, err = strconv.ParseBool(v)
+		case "interval":
+			config.Interval, err = time.ParseDuration(v)
+			if err == nil && config.Interval < defaultCollectorInterval {
+				err = fmt.Errorf("must be at least %s", defaultCollectorInterval)
+			}
+		case "timeout":
+			config.Timeout, err = time.ParseDuration(v)
+			if err == nil && config.Timeout <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid collector override %q: %w", k, err)
+		}
+
+		configs[name] = config
+	}
+
+	return nil
+}
+
+// Schedule records when each collector last ran against a cluster, so that
+// collectors run at their configured intervals across the successive Monitors
+// of the cluster, and the health reasons each collector last reported.  A
+// Schedule is not safe for concurrent use.
+type Schedule struct {
+	configs       map[string]CollectorConfig
+	lastRun       map[string]time.Time
+	healthReasons map[string][]api.HealthReason
+	now           func() time.Time
+}
+
+// NewSchedule returns a Schedule for the given collector configuration, as
+// returned by CollectorConfigs.
+func NewSchedule(configs map[string]CollectorConfig) *Schedule {
+	return &Schedule{
+		configs:       configs,
+		lastRun:       map[string]time.Time{},
+		healthReasons: map[string][]api.HealthReason{},
+		now:           time.Now,
+	}
+}
+
+// due returns whether the collector with the given configuration should run,
+// and if so records that it has.
+func (s *Schedule) due(name string, config CollectorConfig) bool {
+	if !config.Enabled {
+		return false
+	}
+
+	now := s.now()
+	if last, ok := s.lastRun[name]; ok && now.Before(last.Add(config.Interval-collectorIntervalSlack)) {
+		return false
+	}
+
+	s.lastRun[name] = now
+	return true
+}
+
+// clusterConfigs returns the collector configuration for the cluster being
+// monitored: the schedule's configuration with any overrides from the
+// cluster's OperatorFlags applied.
+func (mon *Monitor) clusterConfigs() map[string]CollectorConfig {
+	configs := make(map[string]CollectorConfig, len(mon.schedule.configs))
+	for k, v := range mon.schedule.configs {
+		configs[k] = v
+	}
+
+	overrides := map[string]string{}
+	for k, v := range mon.oc.Properties.OperatorFlags {
+		if strings.HasPrefix(k, CollectorOperatorFlagPrefix) {
+			overrides[strings.TrimPrefix(k, CollectorOperatorFlagPrefix)] = v
+		}
+	}
+
+	if len(overrides) > 0 {
+		err := applyCollectorOverrides(configs, overrides)
+		if err != nil {
+			// ignore the overrides rather than stop monitoring the cluster
+			mon.log.Warnf("ignoring collector overrides in operator flags: %s", err)
+			return mon.schedule.configs
+		}
+	}
+
+	return configs
+}
+
+// runCollectors runs those of the given collectors which are due, at most
+// maxConcurrentCollectors at a time, each with its own timeout, so that a slow
+// collector can't use up the time of the others.
+func (mon *Monitor) runCollectors(ctx context.Context, collectors []collector) (errs []error) {
+	configs := mon.clusterConfigs()
+
+	var due []collector
+	for _, c := range collectors {
+		if mon.schedule.due(c.name, configs[c.name]) {
+			due = append(due, c)
+		}
+	}
+
+	results := make([]error, len(due))
+	sem := make(chan struct{}, maxConcurrentCollectors)
+	var wg sync.WaitGroup
+
+	for i, c := range due {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int, c collector) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i] = mon.runCollector(ctx, c, configs[c.name].Timeout)
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	mon.healthMu.Lock()
+	defer mon.healthMu.Unlock()
+
+	for i, c := range due {
+		if results[i] != nil {
+			errs = append(errs, results[i])
+			mon.emitFailureToGatherMetric(steps.FriendlyName(c.f), results[i])
+			// keep going, retaining the health reasons of the last success
+			continue
+		}
+
+		mon.schedule.healthReasons[c.name] = mon.healthReasons[c.name]
+	}
+
+	return errs
+}
+
+// runCollector runs a collector, returning an error if it panics or if it has
+// not returned by the time its timeout expires.  A collector which ignores the
+// cancellation of its context is left running in the background; it is marked
+// as abandoned so that anything it reports afterwards is dropped.
+func (mon *Monitor) runCollector(ctx context.Context, c collector, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				mon.log.Info(string(debug.Stack()))
+				done <- fmt.Errorf("panic: %v", e)
+			}
+		}()
+
+		done <- c.f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		mon.abandon(c.name)
+		return fmt.Errorf("collector %s did not finish: %w", c.name, ctx.Err())
+	}
+}
+
+// abandon drops the health reasons reported so far by the collector with the
+// given name, and any it reports later
+func (mon *Monitor) abandon(name string) {
+	mon.healthMu.Lock()
+	defer mon.healthMu.Unlock()
+
+	if mon.abandoned == nil {
+		mon.abandoned = map[string]bool{}
+	}
+
+	mon.abandoned[name] = true
+	delete(mon.healthReasons, name)
+}
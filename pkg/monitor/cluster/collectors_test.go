@@ -0,0 +1,294 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+func TestCollectorConfigs(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		overrides string
+		want      map[string]CollectorConfig
+		wantErr   string
+	}{
+		{
+			name: "defaults",
+			want: map[string]CollectorConfig{
+				"prometheusAlerts":  {Enabled: true, Interval: time.Minute, Timeout: 30 * time.Second},
+				"nsgReconciliation": {Enabled: true, Interval: time.Minute, Timeout: 30 * time.Second},
+			},
+		},
+		{
+			name:      "overrides",
+			overrides: "prometheusAlerts.interval=5m, prometheusAlerts.timeout=45s,nsgReconciliation.enabled=false",
+			want: map[string]CollectorConfig{
+				"prometheusAlerts":  {Enabled: true, Interval: 5 * time.Minute, Timeout: 45 * time.Second},
+				"nsgReconciliation": {Enabled: false, Interval: time.Minute, Timeout: 30 * time.Second},
+			},
+		},
+		{
+			name:      "unknown collector",
+			overrides: "doesNotExist.enabled=false",
+			wantErr:   `invalid collector override "doesNotExist.enabled": unknown collector`,
+		},
+		{
+			name:      "unknown setting",
+			overrides: "prometheusAlerts.colour=blue",
+			wantErr:   `invalid collector override "prometheusAlerts.colour": unknown setting`,
+		},
+		{
+			name:      "interval too short",
+			overrides: "prometheusAlerts.interval=10s",
+			wantErr:   `invalid collector override "prometheusAlerts.interval": must be at least 1m0s`,
+		},
+		{
+			name:      "missing value",
+			overrides: "prometheusAlerts.enabled",
+			wantErr:   `invalid collector override "prometheusAlerts.enabled"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := CollectorConfigs(tt.overrides)
+			if err != nil && err.Error() != tt.wantErr ||
+				err == nil && tt.wantErr != "" {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(configs) != len((&Monitor{}).collectors()) {
+				t.Errorf("got %d configs", len(configs))
+			}
+			for name, want := range tt.want {
+				for _, d := range deep.Equal(configs[name], want) {
+					t.Error(name, d)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectorFriendlyNames(t *testing.T) {
+	// the monitor dimension of monitor.clustererrors must not change when
+	// collectors are registered
+	mon := &Monitor{}
+	for _, c := range mon.collectors() {
+		if c.name == "quotaFailure" {
+			if got := steps.FriendlyName(c.f); got != "github.com/Azure/ARO-RP/pkg/monitor/cluster.(*Monitor).detectQuotaFailure-fm" {
+				t.Errorf("got %q", got)
+			}
+		}
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	now := time.Now()
+
+	s := NewSchedule(nil)
+	s.now = func() time.Time { return now }
+
+	config := CollectorConfig{Enabled: true, Interval: 5 * time.Minute, Timeout: time.Second}
+
+	if !s.due("c", config) {
+		t.Error("expected collector to be due on first run")
+	}
+
+	// the worker ticks slightly early or late
+	for i := 1; i < 5; i++ {
+		now = now.Add(time.Minute)
+		if s.due("c", config) {
+			t.Errorf("expected collector not to be due after %d minutes", i)
+		}
+	}
+
+	now = now.Add(time.Minute - time.Second)
+	if !s.due("c", config) {
+		t.Error("expected collector to be due after its interval")
+	}
+
+	if s.due("d", CollectorConfig{Enabled: false, Interval: time.Minute}) {
+		t.Error("expected disabled collector not to be due")
+	}
+}
+
+func TestClusterConfigs(t *testing.T) {
+	defaults, err := CollectorConfigs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name          string
+		operatorFlags api.OperatorFlags
+		want          CollectorConfig
+	}{
+		{
+			name: "no overrides",
+			operatorFlags: api.OperatorFlags{
+				"aro.alertwebhook.enabled": "true",
+			},
+			want: defaults["prometheusAlerts"],
+		},
+		{
+			name: "cluster override",
+			operatorFlags: api.OperatorFlags{
+				CollectorOperatorFlagPrefix + "prometheusAlerts.enabled":  "false",
+				CollectorOperatorFlagPrefix + "prometheusAlerts.interval": "10m",
+			},
+			want: CollectorConfig{Enabled: false, Interval: 10 * time.Minute, Timeout: 30 * time.Second},
+		},
+		{
+			name: "invalid cluster overrides are ignored",
+			operatorFlags: api.OperatorFlags{
+				CollectorOperatorFlagPrefix + "prometheusAlerts.enabled": "false",
+				CollectorOperatorFlagPrefix + "prometheusAlerts.timeout": "forever",
+			},
+			want: defaults["prometheusAlerts"],
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := &Monitor{
+				log: logrus.NewEntry(logrus.StandardLogger()),
+				oc: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						OperatorFlags: tt.operatorFlags,
+					},
+				},
+				schedule: NewSchedule(defaults),
+			}
+
+			configs := mon.clusterConfigs()
+
+			for _, d := range deep.Equal(configs["prometheusAlerts"], tt.want) {
+				t.Error(d)
+			}
+			if !defaults["prometheusAlerts"].Enabled {
+				t.Error("defaults were modified")
+			}
+		})
+	}
+}
+
+func TestRunCollectors(t *testing.T) {
+	ctx := context.Background()
+
+	stuck := make(chan struct{})
+	stuckDone := make(chan struct{})
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	slow := func(ctx context.Context) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	var mon *Monitor
+	collectors := []collector{
+		{name: "stuck", f: func(ctx context.Context) error {
+			defer close(stuckDone)
+
+			// ignores the cancellation of its context, and reports after
+			// it has been abandoned
+			mon.reportHealth("stuck", api.HealthStateUnhealthy, "before timeout")
+			<-stuck
+			mon.reportHealth("stuck", api.HealthStateUnhealthy, "after timeout")
+			return nil
+		}},
+		{name: "panics", f: func(ctx context.Context) error {
+			panic("oh no")
+		}},
+		{name: "fails", f: func(ctx context.Context) error {
+			return errors.New("sad collector")
+		}},
+		{name: "degraded", f: func(ctx context.Context) error {
+			mon.reportHealth("degraded", api.HealthStateDegraded, "something is wrong")
+			return nil
+		}},
+	}
+	for i := 0; i < 2*maxConcurrentCollectors; i++ {
+		collectors = append(collectors, collector{name: "slow" + string(rune('a'+i)), f: slow})
+	}
+
+	configs := map[string]CollectorConfig{}
+	for _, c := range collectors {
+		configs[c.name] = CollectorConfig{Enabled: true, Interval: time.Minute, Timeout: 100 * time.Millisecond}
+	}
+
+	mon = &Monitor{
+		log:      logrus.NewEntry(logrus.StandardLogger()),
+		oc:       &api.OpenShiftCluster{},
+		dims:     map[string]string{},
+		m:        &noop.Noop{},
+		schedule: NewSchedule(configs),
+	}
+
+	start := time.Now()
+	errs := mon.runCollectors(ctx, collectors)
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("collectors took %s", d)
+	}
+
+	var gotErrs []string
+	for _, err := range errs {
+		gotErrs = append(gotErrs, err.Error())
+	}
+	sort.Strings(gotErrs)
+
+	for _, d := range deep.Equal(gotErrs, []string{
+		"collector stuck did not finish: context deadline exceeded",
+		"panic: oh no",
+		"sad collector",
+	}) {
+		t.Error(d)
+	}
+
+	if maxRunning < 2 || maxRunning > maxConcurrentCollectors {
+		t.Errorf("got %d collectors running at once, want between 2 and %d", maxRunning, maxConcurrentCollectors)
+	}
+
+	for _, d := range deep.Equal(mon.schedule.healthReasons["degraded"], []api.HealthReason{
+		{Signal: "degraded", State: api.HealthStateDegraded, Message: "something is wrong"},
+	}) {
+		t.Error(d)
+	}
+
+	close(stuck)
+	<-stuckDone
+
+	mon.healthMu.Lock()
+	defer mon.healthMu.Unlock()
+
+	if reasons, found := mon.healthReasons["stuck"]; found {
+		t.Errorf("expected the health reasons of the abandoned collector to be dropped, got %v", reasons)
+	}
+	if _, found := mon.schedule.healthReasons["stuck"]; found {
+		t.Error("expected the abandoned collector to keep no health reasons")
+	}
+}
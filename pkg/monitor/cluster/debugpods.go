@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	kuval "k8s.io/apimachinery/pkg/util/validation"
 
+	"github.com/Azure/ARO-RP/pkg/operator"
 	"github.com/Azure/ARO-RP/pkg/util/stringutils"
 )
 
@@ -53,9 +54,15 @@ func (mon *Monitor) emitDebugPodsCount(ctx context.Context) error {
 	return nil
 }
 
+// countDebugPods counts the debug pods started recently.  The node diagnostics
+// admin action starts its debug pods in the operator namespace; these are not
+// counted.
 func countDebugPods(debugPods []string, events []eventsv1.Event) int {
 	count := 0
 	for _, e := range events {
+		if e.Regarding.Namespace == operator.Namespace {
+			continue
+		}
 		if eventIsNew(e) && stringutils.Contains(debugPods, e.Regarding.Name) {
 			count++
 		}
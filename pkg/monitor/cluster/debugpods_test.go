@@ -91,6 +91,21 @@ func reactorFn(_ ktesting.Action) (handled bool, ret kruntime.Object, err error)
 				LastObservedTime: metav1.NewMicroTime(now.Time),
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "master-1-started-diagnostics",
+				Namespace: "openshift-azure-operator",
+			},
+			Reason: "Started",
+			Regarding: corev1.ObjectReference{
+				Kind:      "Pod",
+				Name:      "master-1-debug",
+				Namespace: "openshift-azure-operator",
+			},
+			Series: &eventsv1.EventSeries{
+				LastObservedTime: metav1.NewMicroTime(now.Time),
+			},
+		},
 		{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "master-3-started",
@@ -0,0 +1,89 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// healthSignalAPIServer is the health signal of the API server health check,
+// which runs ahead of the collectors.  The other health signals are named
+// after the collector which reports them.
+const healthSignalAPIServer = "apiServer"
+
+// certificateExpiryDegradedDays is how close to expiry a certificate must be
+// for the cluster to be reported as degraded.
+const certificateExpiryDegradedDays = 14
+
+// reportHealth records a reason for the cluster not being healthy.  signal is
+// the name of the reporting collector.  It is safe for concurrent use.
+func (mon *Monitor) reportHealth(signal string, state api.HealthState, format string, args ...interface{}) {
+	mon.healthMu.Lock()
+	defer mon.healthMu.Unlock()
+
+	if mon.abandoned[signal] {
+		return
+	}
+
+	if mon.healthReasons == nil {
+		mon.healthReasons = map[string][]api.HealthReason{}
+	}
+
+	mon.healthReasons[signal] = append(mon.healthReasons[signal], api.HealthReason{
+		Signal:  signal,
+		State:   state,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Health returns the health verdict of the cluster: the most severe state
+// reported by the API server health check and by the enabled collectors.
+// Collectors which did not run in this Monitor contribute the reasons they
+// reported when they last ran successfully.  Call Health after Monitor.
+func (mon *Monitor) Health() *api.ClusterHealth {
+	configs := mon.clusterConfigs()
+
+	mon.healthMu.Lock()
+	defer mon.healthMu.Unlock()
+
+	var reasons []api.HealthReason
+	reasons = append(reasons, mon.healthReasons[healthSignalAPIServer]...)
+	for signal, r := range mon.schedule.healthReasons {
+		if configs[signal].Enabled {
+			reasons = append(reasons, r...)
+		}
+	}
+
+	sort.SliceStable(reasons, func(i, j int) bool {
+		if reasons[i].State != reasons[j].State {
+			return reasons[i].State.Severity() > reasons[j].State.Severity()
+		}
+		if reasons[i].Signal != reasons[j].Signal {
+			return reasons[i].Signal < reasons[j].Signal
+		}
+		return reasons[i].Message < reasons[j].Message
+	})
+
+	state := api.HealthStateHealthy
+	for _, r := range reasons {
+		if r.State.Severity() > state.Severity() {
+			state = r.State
+		}
+	}
+
+	return &api.ClusterHealth{
+		State:         state,
+		Reasons:       reasons,
+		EvaluatedTime: mon.schedule.now().UTC(),
+	}
+}
+
+func (mon *Monitor) emitHealth(health *api.ClusterHealth) {
+	mon.emitGauge("cluster.health", 1, map[string]string{
+		"state": string(health.State),
+	})
+}
@@ -0,0 +1,113 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+func TestHealth(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	defaults, err := CollectorConfigs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name          string
+		operatorFlags api.OperatorFlags
+		apiServer     []api.HealthReason
+		collectors    map[string][]api.HealthReason
+		want          *api.ClusterHealth
+	}{
+		{
+			name: "healthy",
+			want: &api.ClusterHealth{
+				State:         api.HealthStateHealthy,
+				EvaluatedTime: now,
+			},
+		},
+		{
+			name: "most severe state wins",
+			collectors: map[string][]api.HealthReason{
+				"nodeConditions": {
+					{Signal: "nodeConditions", State: api.HealthStateDegraded, Message: "node worker is not ready"},
+				},
+				"clusterOperatorConditions": {
+					{Signal: "clusterOperatorConditions", State: api.HealthStateDegraded, Message: "clusteroperator dns is degraded"},
+					{Signal: "clusterOperatorConditions", State: api.HealthStateUnhealthy, Message: "clusteroperator etcd is not available"},
+				},
+			},
+			want: &api.ClusterHealth{
+				State: api.HealthStateUnhealthy,
+				Reasons: []api.HealthReason{
+					{Signal: "clusterOperatorConditions", State: api.HealthStateUnhealthy, Message: "clusteroperator etcd is not available"},
+					{Signal: "clusterOperatorConditions", State: api.HealthStateDegraded, Message: "clusteroperator dns is degraded"},
+					{Signal: "nodeConditions", State: api.HealthStateDegraded, Message: "node worker is not ready"},
+				},
+				EvaluatedTime: now,
+			},
+		},
+		{
+			name: "api server",
+			apiServer: []api.HealthReason{
+				{Signal: healthSignalAPIServer, State: api.HealthStateUnhealthy, Message: "API server did not respond to the health check"},
+			},
+			want: &api.ClusterHealth{
+				State: api.HealthStateUnhealthy,
+				Reasons: []api.HealthReason{
+					{Signal: healthSignalAPIServer, State: api.HealthStateUnhealthy, Message: "API server did not respond to the health check"},
+				},
+				EvaluatedTime: now,
+			},
+		},
+		{
+			name: "disabled collectors are ignored",
+			operatorFlags: api.OperatorFlags{
+				CollectorOperatorFlagPrefix + "nodeConditions.enabled": "false",
+			},
+			collectors: map[string][]api.HealthReason{
+				"nodeConditions": {
+					{Signal: "nodeConditions", State: api.HealthStateDegraded, Message: "node worker is not ready"},
+				},
+			},
+			want: &api.ClusterHealth{
+				State:         api.HealthStateHealthy,
+				EvaluatedTime: now,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := NewSchedule(defaults)
+			schedule.now = func() time.Time { return now }
+			for signal, reasons := range tt.collectors {
+				schedule.healthReasons[signal] = reasons
+			}
+
+			mon := &Monitor{
+				log: logrus.NewEntry(logrus.StandardLogger()),
+				oc: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						OperatorFlags: tt.operatorFlags,
+					},
+				},
+				schedule: schedule,
+			}
+			for _, r := range tt.apiServer {
+				mon.reportHealth(r.Signal, r.State, "%s", r.Message)
+			}
+
+			for _, d := range deep.Equal(mon.Health(), tt.want) {
+				t.Error(d)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/ARO-RP/pkg/api"
 )
 
 var machineConfigPoolConditionsExpected = map[mcv1.MachineConfigPoolConditionType]corev1.ConditionStatus{
@@ -43,6 +45,10 @@ func (mon *Monitor) emitMachineConfigPoolConditions(ctx context.Context) error {
 					"type":   string(c.Type),
 				})
 
+				if c.Type == mcv1.MachineConfigPoolDegraded && c.Status == corev1.ConditionTrue {
+					mon.reportHealth("machineConfigPoolConditions", api.HealthStateDegraded, "machineconfigpool %s is degraded", mcp.Name)
+				}
+
 				if mon.hourlyRun {
 					mon.log.WithFields(logrus.Fields{
 						"metric":  "machineconfigpool.conditions",
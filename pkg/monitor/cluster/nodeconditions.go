@@ -12,6 +12,8 @@ import (
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/ARO-RP/pkg/api"
 )
 
 var nodeConditionsExpected = map[corev1.NodeConditionType]corev1.ConditionStatus{
@@ -46,6 +48,8 @@ func (mon *Monitor) emitNodeConditions(ctx context.Context) error {
 				"spotInstance": strconv.FormatBool(isSpotInstance),
 			})
 
+			mon.reportNodeConditionHealth(&n, &c, isSpotInstance)
+
 			if mon.hourlyRun {
 				mon.log.WithFields(logrus.Fields{
 					"metric":       "node.conditions",
@@ -67,6 +71,27 @@ func (mon *Monitor) emitNodeConditions(ctx context.Context) error {
 	return nil
 }
 
+// reportNodeConditionHealth reports an unexpected node condition as a health
+// reason.  A master node which is not ready makes the cluster unhealthy; other
+// unexpected conditions make it degraded.  Spot instances are expected to come
+// and go, so are ignored.
+func (mon *Monitor) reportNodeConditionHealth(n *corev1.Node, c *corev1.NodeCondition, isSpotInstance bool) {
+	if isSpotInstance {
+		return
+	}
+
+	if c.Type == corev1.NodeReady {
+		if _, ok := n.Labels[masterRoleLabel]; ok {
+			mon.reportHealth("nodeConditions", api.HealthStateUnhealthy, "master node %s is not ready", n.Name)
+		} else {
+			mon.reportHealth("nodeConditions", api.HealthStateDegraded, "node %s is not ready", n.Name)
+		}
+		return
+	}
+
+	mon.reportHealth("nodeConditions", api.HealthStateDegraded, "node %s has condition %s=%s", n.Name, c.Type, c.Status)
+}
+
 // getSpotInstances returns a map where the keys are the machine name and only exist if the machine is a spot instance
 func (mon *Monitor) getSpotInstances(ctx context.Context) map[string]struct{} {
 	spotInstances := make(map[string]struct{})
@@ -0,0 +1,105 @@
+package monitor
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+const (
+	// clusterHealthRefreshInterval is how often an unchanged health verdict is
+	// stored, so that its evaluated time stays current.
+	clusterHealthRefreshInterval = time.Hour
+
+	// clusterHealthTTL is the time to live, in seconds, of a stored health
+	// verdict.  It is refreshed every time the verdict is stored, so only the
+	// verdicts of clusters which are no longer monitored expire.
+	clusterHealthTTL = 7 * 24 * 60 * 60
+
+	// maxClusterHealthHistory is the number of health state transitions kept
+	// per cluster.
+	maxClusterHealthHistory = 100
+)
+
+// storedHealth is the health verdict of a cluster which a worker last stored
+type storedHealth struct {
+	health *api.ClusterHealth
+	time   time.Time
+}
+
+// storeHealth stores the health verdict of a cluster in the database, if it
+// has changed since it was last stored or was last stored more than
+// clusterHealthRefreshInterval ago.
+func (mon *monitor) storeHealth(ctx context.Context, doc *api.OpenShiftClusterDocument, health *api.ClusterHealth, last *storedHealth) error {
+	if last.health != nil &&
+		last.health.State == health.State &&
+		reflect.DeepEqual(last.health.Reasons, health.Reasons) &&
+		time.Since(last.time) < clusterHealthRefreshInterval {
+		return nil
+	}
+
+	update := func(healthDoc *api.ClusterHealthDocument) error {
+		var history []api.HealthTransition
+		if healthDoc.ClusterHealth != nil {
+			history = healthDoc.ClusterHealth.History
+		}
+
+		healthDoc.Key = doc.Key
+		healthDoc.TTL = clusterHealthTTL
+		healthDoc.ClusterHealth = &api.ClusterHealth{
+			State:         health.State,
+			Reasons:       health.Reasons,
+			EvaluatedTime: health.EvaluatedTime,
+			History:       healthTransitions(history, health),
+		}
+		return nil
+	}
+
+	_, err := mon.dbClusterHealth.Patch(ctx, doc.ID, update)
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		healthDoc := &api.ClusterHealthDocument{
+			ID: doc.ID,
+		}
+		_ = update(healthDoc)
+
+		_, err = mon.dbClusterHealth.Create(ctx, healthDoc)
+	}
+	if err != nil {
+		return err
+	}
+
+	*last = storedHealth{
+		health: health,
+		time:   time.Now(),
+	}
+
+	return nil
+}
+
+// healthTransitions returns history with a transition to health appended, if
+// the state of health differs from the latest state in history.  At most
+// maxClusterHealthHistory transitions are kept.
+func healthTransitions(history []api.HealthTransition, health *api.ClusterHealth) []api.HealthTransition {
+	if len(history) > 0 && history[len(history)-1].State == health.State {
+		return history
+	}
+
+	history = append(history, api.HealthTransition{
+		State:   health.State,
+		Reasons: health.Reasons,
+		Time:    health.EvaluatedTime,
+	})
+
+	if len(history) > maxClusterHealthHistory {
+		history = history[len(history)-maxClusterHealthHistory:]
+	}
+
+	return history
+}
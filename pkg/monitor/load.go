@@ -0,0 +1,105 @@
+package monitor
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// workTimeWeight is the weight of the latest measurement in the moving average
+// of the work time of a cluster
+const workTimeWeight = 0.2
+
+// recordWorkTime adds a measurement of the time spent monitoring a cluster to
+// its moving average
+func (mon *monitor) recordWorkTime(id string, d time.Duration) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	v := mon.docs[id]
+	if v == nil {
+		return
+	}
+
+	if !v.measured {
+		v.workTime = d
+		v.measured = true
+		return
+	}
+
+	v.workTime = time.Duration(workTimeWeight*float64(d) + (1-workTimeWeight)*float64(v.workTime))
+}
+
+// workTimes returns the work times of the buckets we own.  Buckets containing
+// clusters which have not been measured yet are left out, so that a bucket
+// which has just moved to us is not reported as cheaper than it is.  Every
+// bucket reported has been measured, even at zero seconds, e.g. because it
+// holds no clusters.
+func (mon *monitor) workTimes() []api.BucketWorkTime {
+	mon.mu.RLock()
+	defer mon.mu.RUnlock()
+
+	workTimes := make(map[int]time.Duration, len(mon.buckets))
+	unmeasured := map[int]struct{}{}
+	for _, v := range mon.docs {
+		if _, found := mon.buckets[v.doc.Bucket]; !found {
+			continue
+		}
+
+		if !v.measured {
+			unmeasured[v.doc.Bucket] = struct{}{}
+		}
+		workTimes[v.doc.Bucket] += v.workTime
+	}
+
+	// an empty bucket is measured at zero seconds: there is nothing to wait
+	// for
+	for i := range mon.buckets {
+		if _, found := workTimes[i]; !found {
+			workTimes[i] = 0
+		}
+	}
+
+	for i := range unmeasured {
+		delete(workTimes, i)
+	}
+
+	buckets := make([]api.BucketWorkTime, 0, len(workTimes))
+	for i, d := range workTimes {
+		buckets = append(buckets, api.BucketWorkTime{
+			Bucket:  i,
+			Seconds: d.Seconds(),
+		})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Bucket < buckets[j].Bucket })
+
+	return buckets
+}
+
+// emitLoad emits the load of this monitor: the number of buckets and clusters
+// it owns and the total time it spends monitoring them per interval
+func (mon *monitor) emitLoad(workTimes []api.BucketWorkTime) {
+	var seconds float64
+	for _, wt := range workTimes {
+		seconds += wt.Seconds
+	}
+
+	mon.mu.RLock()
+	buckets := len(mon.buckets)
+	var clusters int
+	for _, v := range mon.docs {
+		if v.stop != nil {
+			clusters++
+		}
+	}
+	mon.mu.RUnlock()
+
+	mon.m.EmitGauge("monitor.load.buckets", int64(buckets), nil)
+	mon.m.EmitGauge("monitor.load.clusters", int64(clusters), nil)
+	mon.m.EmitFloat("monitor.load.worktime", seconds, nil)
+}
@@ -0,0 +1,62 @@
+package monitor
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+func TestWorkTimes(t *testing.T) {
+	mon := &monitor{
+		buckets: map[int]struct{}{0: {}, 1: {}, 2: {}, 4: {}},
+		docs: map[string]*cacheDoc{
+			"a": {
+				doc:      &api.OpenShiftClusterDocument{Bucket: 0},
+				workTime: 2 * time.Second,
+				measured: true,
+			},
+			"b": {
+				doc:      &api.OpenShiftClusterDocument{Bucket: 0},
+				workTime: time.Second,
+				measured: true,
+			},
+			"c": {
+				doc:      &api.OpenShiftClusterDocument{Bucket: 1},
+				workTime: time.Second,
+				measured: true,
+			},
+			"d": {
+				// bucket 1 has just moved to us
+				doc: &api.OpenShiftClusterDocument{Bucket: 1},
+			},
+			"e": {
+				// not our bucket
+				doc:      &api.OpenShiftClusterDocument{Bucket: 3},
+				workTime: time.Second,
+				measured: true,
+			},
+			"f": {
+				// measured, but too quick to take any time
+				doc:      &api.OpenShiftClusterDocument{Bucket: 4},
+				measured: true,
+			},
+		},
+	}
+
+	mon.recordWorkTime("a", 7*time.Second)
+	mon.recordWorkTime("doesnotexist", time.Second)
+
+	for _, d := range deep.Equal(mon.workTimes(), []api.BucketWorkTime{
+		{Bucket: 0, Seconds: 4},
+		{Bucket: 2, Seconds: 0},
+		{Bucket: 4, Seconds: 0},
+	}) {
+		t.Error(d)
+	}
+}
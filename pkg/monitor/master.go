@@ -5,10 +5,15 @@ package monitor
 
 import (
 	"context"
+	"sort"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 )
 
+// balanceTolerance is how far, as a fraction of the mean, the load of a
+// monitor may be from the mean load before buckets are moved between monitors
+const balanceTolerance = 0.1
+
 // master updates the monitor document with the list of buckets balanced between
 // registered monitors
 func (mon *monitor) master(ctx context.Context) error {
@@ -31,7 +36,7 @@ func (mon *monitor) master(ctx context.Context) error {
 	// including ourself, balance buckets between them and write the bucket
 	// allocations to the database.  If it turns out that we're not the master,
 	// the patch will fail
-	_, err := mon.dbMonitors.PatchWithLease(ctx, "master", func(doc *api.MonitorDocument) error {
+	doc, err := mon.dbMonitors.PatchWithLease(ctx, "master", func(doc *api.MonitorDocument) error {
 		docs, err := mon.dbMonitors.ListMonitors(ctx)
 		if err != nil {
 			return err
@@ -45,6 +50,7 @@ func (mon *monitor) master(ctx context.Context) error {
 			}
 		}
 
+		mon.updateBucketWorkTimes(docs, doc)
 		mon.balance(monitors, doc)
 
 		return nil
@@ -52,64 +58,167 @@ func (mon *monitor) master(ctx context.Context) error {
 	if err != nil && err.Error() == "lost lease" {
 		mon.isMaster = false
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	mon.emitMasterLoad(doc)
+
+	return nil
 }
 
-// balance shares out buckets over a slice of registered monitors
-func (mon *monitor) balance(monitors []string, doc *api.MonitorDocument) {
-	// initialise doc.Monitor
+// initMaster ensures that doc.Monitor has an owner entry per bucket: this
+// should only do anything on the very first run.  BucketWorkTimes is extended
+// only once a bucket work time is reported.
+func (mon *monitor) initMaster(doc *api.MonitorDocument) {
 	if doc.Monitor == nil {
 		doc.Monitor = &api.Monitor{}
 	}
 
-	// ensure len(doc.Monitor.Buckets) == mon.bucketCount: this should only do
-	// anything on the very first run
 	if len(doc.Monitor.Buckets) < mon.bucketCount {
 		doc.Monitor.Buckets = append(doc.Monitor.Buckets, make([]string, mon.bucketCount-len(doc.Monitor.Buckets))...)
 	}
 	if len(doc.Monitor.Buckets) > mon.bucketCount { // should never happen
 		doc.Monitor.Buckets = doc.Monitor.Buckets[:mon.bucketCount]
 	}
+	if len(doc.Monitor.BucketWorkTimes) > mon.bucketCount { // should never happen
+		doc.Monitor.BucketWorkTimes = doc.Monitor.BucketWorkTimes[:mon.bucketCount]
+	}
+}
 
-	var unallocated []int
-	m := make(map[string][]int, len(monitors)) // map of monitor to list of buckets it owns
-	for _, monitor := range monitors {
-		m[monitor] = nil
+// updateBucketWorkTimes records in the master document the bucket work times
+// reported by the registered monitors.  Only the work time reported by the
+// current owner of a bucket is taken; buckets which no monitor reports keep
+// their last measured work time.
+func (mon *monitor) updateBucketWorkTimes(docs *api.MonitorDocuments, doc *api.MonitorDocument) {
+	mon.initMaster(doc)
+
+	if docs == nil {
+		return
 	}
 
-	var target int // target number of buckets per monitor
-	if len(monitors) > 0 {
-		target = mon.bucketCount / len(monitors)
-		if mon.bucketCount%len(monitors) != 0 {
-			target++
+	for _, monitorDoc := range docs.MonitorDocuments {
+		if monitorDoc.Monitor == nil {
+			continue
+		}
+
+		for _, wt := range monitorDoc.Monitor.WorkTimes {
+			if wt.Bucket < 0 || wt.Bucket >= mon.bucketCount ||
+				doc.Monitor.Buckets[wt.Bucket] != monitorDoc.ID {
+				continue
+			}
+
+			if len(doc.Monitor.BucketWorkTimes) < mon.bucketCount {
+				doc.Monitor.BucketWorkTimes = append(doc.Monitor.BucketWorkTimes, make([]*float64, mon.bucketCount-len(doc.Monitor.BucketWorkTimes))...)
+			}
+			seconds := wt.Seconds
+			doc.Monitor.BucketWorkTimes[wt.Bucket] = &seconds
 		}
 	}
+}
+
+// bucketWeights returns the weight of each of bucketCount buckets: its
+// measured work time, which may be zero, or the mean measured work time if it
+// has not been measured.  If no bucket has been measured, or all measured
+// buckets are measured at zero, all buckets weigh the same.
+func bucketWeights(workTimes []*float64, bucketCount int) []float64 {
+	var total float64
+	var measured int
+	for _, wt := range workTimes {
+		if wt != nil {
+			total += *wt
+			measured++
+		}
+	}
+
+	mean := 1.
+	if total > 0 {
+		mean = total / float64(measured)
+	}
+
+	weights := make([]float64, bucketCount)
+	for i := range weights {
+		if i < len(workTimes) && workTimes[i] != nil && total > 0 {
+			weights[i] = *workTimes[i]
+		} else {
+			weights[i] = mean
+		}
+	}
+
+	return weights
+}
+
+// balance shares out buckets over a slice of registered monitors, so that
+// each monitor has about the same total bucket weight.  To stop buckets moving
+// back and forth as measured work times fluctuate, buckets only move between
+// registered monitors if the load of some monitor is more than
+// balanceTolerance away from the mean, and then only if the move brings the
+// load of the monitor giving up the bucket closer to the mean.
+func (mon *monitor) balance(monitors []string, doc *api.MonitorDocument) {
+	mon.initMaster(doc)
+
+	weights := bucketWeights(doc.Monitor.BucketWorkTimes, len(doc.Monitor.Buckets))
+
+	var unallocated []int
+	m := make(map[string][]int, len(monitors))      // map of monitor to list of buckets it owns
+	load := make(map[string]float64, len(monitors)) // map of monitor to total weight of buckets it owns
+	for _, monitor := range monitors {
+		m[monitor] = nil
+	}
 
 	// load the current bucket allocations into the map
 	for i, monitor := range doc.Monitor.Buckets {
-		if buckets, found := m[monitor]; found && len(buckets) < target {
-			// if the current bucket is allocated to a known monitor and doesn't
-			// take its number of buckets above the target, keep it there...
+		if _, found := m[monitor]; found {
 			m[monitor] = append(m[monitor], i)
+			load[monitor] += weights[i]
 		} else {
-			// ...otherwise we'll reallocate it below
 			unallocated = append(unallocated, i)
 		}
 	}
 
-	// reallocate all unallocated buckets, appending to the least loaded monitor
 	if len(monitors) > 0 {
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+		target := total / float64(len(monitors)) // target load per monitor
+
+		if imbalanced(monitors, load, target) {
+			for _, monitor := range monitors {
+				for {
+					j := shedBucket(m[monitor], weights, load[monitor]-target)
+					if j == -1 {
+						break
+					}
+
+					i := m[monitor][j]
+					m[monitor] = append(m[monitor][:j], m[monitor][j+1:]...)
+					load[monitor] -= weights[i]
+					unallocated = append(unallocated, i)
+				}
+			}
+		}
+
+		// reallocate all unallocated buckets, heaviest first, appending to
+		// the least loaded monitor
+		sort.SliceStable(unallocated, func(i, j int) bool {
+			return weights[unallocated[i]] > weights[unallocated[j]]
+		})
+
 		for _, i := range unallocated {
 			var leastMonitor string
-			for monitor := range m {
+			for _, monitor := range monitors {
 				if leastMonitor == "" ||
-					len(m[monitor]) < len(m[leastMonitor]) {
+					load[monitor] < load[leastMonitor] {
 					leastMonitor = monitor
 				}
 			}
 
 			m[leastMonitor] = append(m[leastMonitor], i)
+			load[leastMonitor] += weights[i]
 		}
+
+		unallocated = nil
 	}
 
 	// write the updated bucket allocations back to the document
@@ -122,3 +231,58 @@ func (mon *monitor) balance(monitors []string, doc *api.MonitorDocument) {
 		}
 	}
 }
+
+// imbalanced returns true if the load of any monitor is more than
+// balanceTolerance away from the target load
+func imbalanced(monitors []string, load map[string]float64, target float64) bool {
+	for _, monitor := range monitors {
+		if load[monitor] > target*(1+balanceTolerance) ||
+			load[monitor] < target*(1-balanceTolerance) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shedBucket returns the index in buckets of the heaviest bucket whose removal
+// brings a monitor which is excess above its target load closer to it, or -1
+func shedBucket(buckets []int, weights []float64, excess float64) int {
+	j := -1
+	for k, i := range buckets {
+		if weights[i] < 2*excess &&
+			(j == -1 || weights[i] > weights[buckets[j]]) {
+			j = k
+		}
+	}
+
+	return j
+}
+
+// emitMasterLoad emits the number of buckets and the total bucket weight
+// allocated to each monitor
+func (mon *monitor) emitMasterLoad(doc *api.MonitorDocument) {
+	if doc == nil || doc.Monitor == nil {
+		return
+	}
+
+	weights := bucketWeights(doc.Monitor.BucketWorkTimes, len(doc.Monitor.Buckets))
+
+	buckets := map[string]int{}
+	load := map[string]float64{}
+	for i, monitor := range doc.Monitor.Buckets {
+		if monitor == "" {
+			continue
+		}
+		buckets[monitor]++
+		load[monitor] += weights[i]
+	}
+
+	for monitor := range buckets {
+		dims := map[string]string{
+			"monitor": monitor,
+		}
+		mon.m.EmitGauge("monitor.master.buckets", int64(buckets[monitor]), dims)
+		mon.m.EmitFloat("monitor.master.load", load[monitor], dims)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/go-test/deep"
+
 	"github.com/Azure/ARO-RP/pkg/api"
 )
 
@@ -162,6 +164,72 @@ func TestBalance(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "weighted",
+			doc: func() *api.MonitorDocument {
+				return &api.MonitorDocument{
+					Monitor: &api.Monitor{
+						Buckets:         []string{"one", "one", "one", "one", "two", "two", "two", "two"},
+						BucketWorkTimes: measured(3, 3, 3, 3, 1, 1, 1, 1),
+					},
+				}
+			},
+			monitors: []string{"one", "two"},
+			validate: func(t *testing.T, tt *test, doc *api.MonitorDocument) {
+				old := tt.doc()
+
+				load := map[string]float64{}
+				for i, bucket := range doc.Monitor.Buckets {
+					load[bucket] += *doc.Monitor.BucketWorkTimes[i]
+					if bucket == "one" && old.Monitor.Buckets[i] != bucket {
+						t.Error(i)
+					}
+				}
+				if load["one"] != 9 || load["two"] != 7 {
+					t.Error(load)
+				}
+			},
+		},
+		{
+			name: "unmeasured buckets weigh the mean",
+			doc: func() *api.MonitorDocument {
+				return &api.MonitorDocument{
+					Monitor: &api.Monitor{
+						Buckets:         []string{"one", "one", "one", "one", "one", "one", "", ""},
+						BucketWorkTimes: measured(4, 4, 4, 4, 4, 4),
+					},
+				}
+			},
+			monitors: []string{"one", "two"},
+			validate: func(t *testing.T, tt *test, doc *api.MonitorDocument) {
+				m := map[string]int{}
+				for _, bucket := range doc.Monitor.Buckets {
+					m[bucket]++
+				}
+				if m["one"] != 4 || m["two"] != 4 {
+					t.Error(m)
+				}
+			},
+		},
+		{
+			name: "within tolerance",
+			doc: func() *api.MonitorDocument {
+				return &api.MonitorDocument{
+					Monitor: &api.Monitor{
+						Buckets:         []string{"one", "one", "one", "one", "two", "two", "two", "two"},
+						BucketWorkTimes: measured(1, 1, 1.7, 0.65, 1, 1, 1, 0.65),
+					},
+				}
+			},
+			monitors: []string{"one", "two"},
+			validate: func(t *testing.T, tt *test, doc *api.MonitorDocument) {
+				old := tt.doc()
+
+				if !reflect.DeepEqual(old, doc) {
+					t.Error(doc.Monitor.Buckets)
+				}
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			mon := &monitor{
@@ -184,3 +252,122 @@ func TestBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateBucketWorkTimes(t *testing.T) {
+	mon := &monitor{
+		bucketCount: 4,
+	}
+
+	doc := &api.MonitorDocument{
+		Monitor: &api.Monitor{
+			Buckets:         []string{"one", "two", "two", "one"},
+			BucketWorkTimes: measured(1, 1, 1, 1),
+		},
+	}
+
+	docs := &api.MonitorDocuments{
+		MonitorDocuments: []*api.MonitorDocument{
+			{
+				ID: "one",
+				Monitor: &api.Monitor{
+					WorkTimes: []api.BucketWorkTime{
+						{Bucket: 0, Seconds: 5},
+						{Bucket: 1, Seconds: 6}, // no longer ours
+						{Bucket: 9, Seconds: 7}, // out of range
+					},
+				},
+			},
+			{
+				ID: "two",
+				Monitor: &api.Monitor{
+					WorkTimes: []api.BucketWorkTime{
+						{Bucket: 1, Seconds: 2},
+					},
+				},
+			},
+			{
+				ID: "three",
+			},
+		},
+	}
+
+	mon.updateBucketWorkTimes(docs, doc)
+
+	for _, d := range deep.Equal(doc.Monitor.BucketWorkTimes, measured(5, 2, 1, 1)) {
+		t.Error(d)
+	}
+}
+
+func TestUpdateBucketWorkTimesUnmeasured(t *testing.T) {
+	mon := &monitor{
+		bucketCount: 4,
+	}
+
+	doc := &api.MonitorDocument{
+		Monitor: &api.Monitor{
+			Buckets: []string{"one", "", "", ""},
+		},
+	}
+
+	docs := &api.MonitorDocuments{
+		MonitorDocuments: []*api.MonitorDocument{
+			{
+				ID: "one",
+				Monitor: &api.Monitor{
+					WorkTimes: []api.BucketWorkTime{
+						{Bucket: 0, Seconds: 0}, // empty bucket
+					},
+				},
+			},
+		},
+	}
+
+	mon.updateBucketWorkTimes(docs, doc)
+
+	for _, d := range deep.Equal(doc.Monitor.BucketWorkTimes, []*float64{measured(0)[0], nil, nil, nil}) {
+		t.Error(d)
+	}
+}
+
+func TestBucketWeights(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		workTimes []*float64
+		want      []float64
+	}{
+		{
+			name: "none measured",
+			want: []float64{1, 1, 1, 1},
+		},
+		{
+			name:      "unmeasured buckets weigh the mean",
+			workTimes: append(measured(2), nil, measured(4)[0]),
+			want:      []float64{2, 3, 4, 3},
+		},
+		{
+			name:      "measured at zero",
+			workTimes: measured(0, 3, 3),
+			want:      []float64{0, 3, 3, 2},
+		},
+		{
+			name:      "all measured at zero",
+			workTimes: measured(0, 0),
+			want:      []float64{1, 1, 1, 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, d := range deep.Equal(bucketWeights(tt.workTimes, 4), tt.want) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+// measured returns the given bucket work times, all measured
+func measured(workTimes ...float64) []*float64 {
+	m := make([]*float64, 0, len(workTimes))
+	for i := range workTimes {
+		m = append(m, &workTimes[i])
+	}
+	return m
+}
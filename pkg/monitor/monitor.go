@@ -17,6 +17,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/monitor/cluster"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/bucket"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
@@ -27,6 +28,7 @@ type monitor struct {
 	baseLog *logrus.Entry
 	dialer  proxy.Dialer
 
+	dbClusterHealth     database.ClusterHealth
 	dbMonitors          database.Monitors
 	dbOpenShiftClusters database.OpenShiftClusters
 	dbSubscriptions     database.Subscriptions
@@ -45,6 +47,8 @@ type monitor struct {
 	lastChangefeed atomic.Value //time.Time
 	startTime      time.Time
 
+	collectorConfigs map[string]cluster.CollectorConfig
+
 	liveConfig       liveconfig.Manager
 	hiveShardConfigs map[int]*rest.Config
 	shardMutex       sync.RWMutex
@@ -54,11 +58,12 @@ type Runnable interface {
 	Run(context.Context) error
 }
 
-func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager) Runnable {
+func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbClusterHealth database.ClusterHealth, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager, collectorConfigs map[string]cluster.CollectorConfig) Runnable {
 	return &monitor{
 		baseLog: log,
 		dialer:  dialer,
 
+		dbClusterHealth:     dbClusterHealth,
 		dbMonitors:          dbMonitors,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbSubscriptions:     dbSubscriptions,
@@ -73,6 +78,8 @@ func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Moni
 
 		startTime: time.Now(),
 
+		collectorConfigs: collectorConfigs,
+
 		liveConfig: liveConfig,
 
 		hiveShardConfigs: map[int]*rest.Config{},
@@ -96,8 +103,11 @@ func (mon *monitor) Run(ctx context.Context) error {
 	go heartbeat.EmitHeartbeat(mon.baseLog, mon.m, "monitor.heartbeat", nil, mon.checkReady)
 
 	for {
-		// register ourself as a monitor
-		err = mon.dbMonitors.MonitorHeartbeat(ctx)
+		// register ourself as a monitor, reporting our load to the master
+		workTimes := mon.workTimes()
+		mon.emitLoad(workTimes)
+
+		err = mon.dbMonitors.MonitorHeartbeat(ctx, workTimes)
 		if err != nil {
 			mon.baseLog.Error(err)
 		}
@@ -195,6 +195,9 @@ func (mon *monitor) worker(stop <-chan struct{}, delay time.Duration, id string)
 
 	h := time.Now().Hour()
 
+	schedule := cluster.NewSchedule(mon.collectorConfigs)
+	var health storedHealth
+
 out:
 	for {
 		mon.mu.RLock()
@@ -211,9 +214,11 @@ out:
 		// TODO: later can modify here to poll once per N minutes and re-issue
 		// cached metrics in the remaining minutes
 
+		start := time.Now()
 		if sub != nil && sub.Subscription != nil && sub.Subscription.State != api.SubscriptionStateSuspended && sub.Subscription.State != api.SubscriptionStateWarned {
-			mon.workOne(context.Background(), log, v.doc, newh != h)
+			mon.workOne(context.Background(), log, v.doc, newh != h, schedule, &health)
 		}
+		mon.recordWorkTime(id, time.Since(start))
 
 		select {
 		case <-t.C:
@@ -228,8 +233,8 @@ out:
 }
 
 // workOne checks the API server health of a cluster
-func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, hourlyRun bool) {
-	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
+func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, hourlyRun bool, schedule *cluster.Schedule, health *storedHealth) {
+	monitorCtx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
 	restConfig, err := restconfig.RestConfig(mon.dialer, doc.OpenShiftCluster)
@@ -246,11 +251,20 @@ func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.Ope
 		log.Warnf("no hiveShardConfigs set for shard %d", shard)
 	}
 
-	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, hourlyRun)
+	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, hourlyRun, schedule)
 	if err != nil {
 		log.Error(err)
 		return
 	}
 
-	c.Monitor(ctx)
+	c.Monitor(monitorCtx)
+
+	// store the health verdict even if monitoring used up its timeout
+	storeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err = mon.storeHealth(storeCtx, doc, c.Health(), health)
+	if err != nil {
+		log.Error(err)
+	}
 }
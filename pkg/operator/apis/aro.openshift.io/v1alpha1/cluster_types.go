@@ -29,6 +29,7 @@ const (
 	DefaultIngressCertificate = "DefaultIngressCertificate"
 	DefaultClusterDNS         = "DefaultClusterDNS"
 	GuardRailsStatus          = "GuardRailsStatus"
+	GuardRailsCompliant       = "GuardRailsCompliant"
 )
 
 // AllConditionTypes is a operator conditions currently in use, any condition not in this list is not
@@ -43,6 +44,7 @@ func AllConditionTypes() []string {
 		DefaultIngressCertificate,
 		DefaultClusterDNS,
 		GuardRailsStatus,
+		GuardRailsCompliant,
 	}
 }
 
@@ -0,0 +1,100 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"strings"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+// policyMode is how a policy from the catalogue is applied to a cluster.  All
+// modes other than policyModeOff map directly onto the enforcementAction of the
+// Gatekeeper Constraint.
+type policyMode string
+
+const (
+	policyModeOff    policyMode = "off"
+	policyModeDryRun policyMode = "dryrun"
+	policyModeWarn   policyMode = "warn"
+	policyModeDeny   policyMode = "deny"
+)
+
+// policyCatalogueVersion must be bumped whenever a policy is added to or
+// removed from the catalogue, or the version or default mode of a policy
+// changes.  It is reported alongside the violations so that SREs know which
+// set of policies a cluster was audited against.
+const policyCatalogueVersion = "1"
+
+type policy struct {
+	// name is the name of the Constraint, which is also the name of its file
+	// under policies/gkconstraints and the key of its operator flags
+	name string
+	// version is the version of the policy rules
+	version string
+	// defaultMode applies when no operator flag sets the mode of the policy.
+	// New policies should default to policyModeDryRun so that their
+	// violations can be reviewed before they are enforced.
+	defaultMode policyMode
+}
+
+var policyCatalogue = []policy{
+	{name: "aro-machine-config-deny", version: "1.0.0", defaultMode: policyModeOff},
+	{name: "aro-machines-deny", version: "1.0.0", defaultMode: policyModeOff},
+	{name: "aro-master-toleration-pod-deny", version: "1.0.0", defaultMode: policyModeOff},
+	{name: "aro-privileged-namespace-deny", version: "1.0.0", defaultMode: policyModeOff},
+	{name: "aro-rw-host-mount-deny", version: "1.0.0", defaultMode: policyModeOff},
+}
+
+func getCataloguePolicy(name string) (*policy, error) {
+	for i := range policyCatalogue {
+		if policyCatalogue[i].name == name {
+			return &policyCatalogue[i], nil
+		}
+	}
+	return nil, fmt.Errorf("policy %s is not in the catalogue", name)
+}
+
+func parsePolicyMode(s string) (policyMode, error) {
+	switch mode := policyMode(strings.ToLower(s)); mode {
+	case policyModeOff, policyModeDryRun, policyModeWarn, policyModeDeny:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid policy mode %q", s)
+}
+
+// getPolicyMode returns the mode of the named policy on the cluster.  The
+// mode flag takes precedence; clusters which still set the older managed and
+// enforcement flags keep their behaviour, and otherwise the default mode from
+// the catalogue applies.
+func getPolicyMode(flags arov1alpha1.OperatorFlags, name string) (policyMode, error) {
+	p, err := getCataloguePolicy(name)
+	if err != nil {
+		return "", err
+	}
+
+	if mode := flags.GetWithDefault(fmt.Sprintf(controllerPolicyModeTemplate, name), ""); mode != "" {
+		m, err := parsePolicyMode(mode)
+		if err != nil {
+			return "", fmt.Errorf("policy %s: %w", name, err)
+		}
+		return m, nil
+	}
+
+	managed := flags.GetWithDefault(fmt.Sprintf(controllerPolicyManagedTemplate, name), "")
+	switch {
+	case managed == "":
+		return p.defaultMode, nil
+	case !strings.EqualFold(managed, "true"):
+		return policyModeOff, nil
+	}
+
+	enforcement := flags.GetWithDefault(fmt.Sprintf(controllerPolicyEnforcementTemplate, name), string(policyModeDryRun))
+	m, err := parsePolicyMode(enforcement)
+	if err != nil || m == policyModeOff {
+		return "", fmt.Errorf("policy %s: invalid enforcement %q", name, enforcement)
+	}
+	return m, nil
+}
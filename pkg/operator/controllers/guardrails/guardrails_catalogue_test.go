@@ -0,0 +1,136 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestPolicyCatalogue(t *testing.T) {
+	files, err := fs.ReadDir(gkPolicyConstraints, gkConstraintsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constraints := map[string]bool{}
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		constraints[name] = true
+
+		if _, err := getCataloguePolicy(name); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, p := range policyCatalogue {
+		if !constraints[p.name] {
+			t.Errorf("policy %s has no constraint", p.name)
+		}
+		if p.version == "" {
+			t.Errorf("policy %s has no version", p.name)
+		}
+		if _, err := parsePolicyMode(string(p.defaultMode)); err != nil {
+			t.Errorf("policy %s: %v", p.name, err)
+		}
+	}
+}
+
+func TestGetPolicyMode(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		policy   string
+		flags    arov1alpha1.OperatorFlags
+		wantMode policyMode
+		wantErr  string
+	}{
+		{
+			name:     "default mode from the catalogue",
+			policy:   "aro-machines-deny",
+			flags:    arov1alpha1.OperatorFlags{},
+			wantMode: policyModeOff,
+		},
+		{
+			name:   "mode flag",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode": "Warn",
+			},
+			wantMode: policyModeWarn,
+		},
+		{
+			name:   "mode flag takes precedence over managed and enforcement",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode":        "off",
+				"aro.guardrails.policies.aro-machines-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement": "deny",
+			},
+			wantMode: policyModeOff,
+		},
+		{
+			name:   "invalid mode flag",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode": "enforce",
+			},
+			wantErr: `policy aro-machines-deny: invalid policy mode "enforce"`,
+		},
+		{
+			name:   "managed with enforcement",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement": "deny",
+			},
+			wantMode: policyModeDeny,
+		},
+		{
+			name:   "managed without enforcement",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed": "true",
+			},
+			wantMode: policyModeDryRun,
+		},
+		{
+			name:   "managed with invalid enforcement",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement": "off",
+			},
+			wantErr: `policy aro-machines-deny: invalid enforcement "off"`,
+		},
+		{
+			name:   "not managed",
+			policy: "aro-machines-deny",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":     "false",
+				"aro.guardrails.policies.aro-machines-deny.enforcement": "deny",
+			},
+			wantMode: policyModeOff,
+		},
+		{
+			name:    "policy not in the catalogue",
+			policy:  "aro-unknown-deny",
+			flags:   arov1alpha1.OperatorFlags{},
+			wantErr: "policy aro-unknown-deny is not in the catalogue",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := getPolicyMode(tt.flags, tt.policy)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if mode != tt.wantMode {
+				t.Errorf("got mode %q, wanted %q", mode, tt.wantMode)
+			}
+		})
+	}
+}
@@ -39,6 +39,7 @@ const (
 	controllerReconciliationMinutes     = "aro.guardrails.reconciliationMinutes"
 	controllerPolicyManagedTemplate     = "aro.guardrails.policies.%s.managed"
 	controllerPolicyEnforcementTemplate = "aro.guardrails.policies.%s.enforcement"
+	controllerPolicyModeTemplate        = "aro.guardrails.policies.%s.mode"
 
 	RoleSCCResourceName = "aro.guardrails.role.scc.resourcename"
 
@@ -117,6 +117,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 			if err != nil {
 				return reconcile.Result{}, err
 			}
+
+			// Report the violations found by the latest GateKeeper audit
+			err = r.reportViolations(ctx, gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
 		}
 
 		// start a ticker to re-enforce gatekeeper policies periodically
@@ -7,15 +7,15 @@ import (
 	"bytes"
 	"context"
 	"embed"
-	"errors"
-	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -24,64 +24,90 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/dynamichelper"
 )
 
-func (r *Reconciler) getPolicyConfig(ctx context.Context, instance *arov1alpha1.Cluster, na string) (string, string, error) {
-	parts := strings.Split(na, ".")
-	if len(parts) < 1 {
-		return "", "", errors.New("unrecognised name: " + na)
-	}
-	name := parts[0]
-
-	managedPath := fmt.Sprintf(controllerPolicyManagedTemplate, name)
-	managed := instance.Spec.OperatorFlags.GetWithDefault(managedPath, "false")
-
-	enforcementPath := fmt.Sprintf(controllerPolicyEnforcementTemplate, name)
-	enforcement := instance.Spec.OperatorFlags.GetWithDefault(enforcementPath, "dryrun")
-
-	return managed, enforcement, nil
+// constraint is a Constraint rendered from the catalogue for a cluster
+type constraint struct {
+	policy *policy
+	mode   policyMode
+	uns    *unstructured.Unstructured
 }
 
-func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string) error {
+func (r *Reconciler) renderConstraints(fs embed.FS, path string, instance *arov1alpha1.Cluster) ([]constraint, error) {
 	template, err := template.ParseFS(fs, filepath.Join(path, "*"))
 	if err != nil {
-		return err
-	}
-
-	instance := &arov1alpha1.Cluster{}
-	err = r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	creates := make([]kruntime.Object, 0)
-	buffer := new(bytes.Buffer)
+	constraints := make([]constraint, 0)
 	for _, templ := range template.Templates() {
-		managed, enforcement, err := r.getPolicyConfig(ctx, instance, templ.Name())
+		name := strings.TrimSuffix(templ.Name(), filepath.Ext(templ.Name()))
+
+		p, err := getCataloguePolicy(name)
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		mode, err := getPolicyMode(instance.Spec.OperatorFlags, name)
+		if err != nil {
+			return nil, err
 		}
+
+		// a Constraint which is off is rendered anyway, so that it can be
+		// found and deleted
 		policyConfig := &config.GuardRailsPolicyConfig{
-			Enforcement: enforcement,
+			Enforcement: string(mode),
 		}
+		if mode == policyModeOff {
+			policyConfig.Enforcement = string(policyModeDryRun)
+		}
+
+		buffer := new(bytes.Buffer)
 		err = templ.Execute(buffer, policyConfig)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		data := buffer.Bytes()
 
-		uns, err := dynamichelper.DecodeUnstructured(data)
+		uns, err := dynamichelper.DecodeUnstructured(buffer.Bytes())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if managed != "true" {
-			err := r.dh.EnsureDeletedGVR(ctx, uns.GroupVersionKind().GroupKind().String(), uns.GetNamespace(), uns.GetName(), uns.GroupVersionKind().Version)
+		constraints = append(constraints, constraint{
+			policy: p,
+			mode:   mode,
+			uns:    uns,
+		})
+	}
+
+	// template.Templates() has no defined order, keep the report stable
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].policy.name < constraints[j].policy.name
+	})
+	return constraints, nil
+}
+
+func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string) error {
+	instance := &arov1alpha1.Cluster{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := r.renderConstraints(fs, path, instance)
+	if err != nil {
+		return err
+	}
+
+	creates := make([]kruntime.Object, 0)
+	for _, c := range constraints {
+		if c.mode == policyModeOff {
+			err := r.dh.EnsureDeletedGVR(ctx, c.uns.GroupVersionKind().GroupKind().String(), c.uns.GetNamespace(), c.uns.GetName(), c.uns.GroupVersionKind().Version)
 			if err != nil && !kerrors.IsNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "notfound") {
 				return err
 			}
 			continue
 		}
 
-		creates = append(creates, uns)
+		creates = append(creates, c.uns)
 	}
 	err = r.dh.Ensure(ctx, creates...)
 	if err != nil {
@@ -141,6 +167,10 @@ func (r *Reconciler) policyTicker(ctx context.Context, instance *arov1alpha1.Clu
 			if err != nil {
 				r.log.Errorf("policyTicker ensurePolicy error %s", err.Error())
 			}
+			err = r.reportViolations(ctx, gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				r.log.Errorf("policyTicker reportViolations error %s", err.Error())
+			}
 		}
 	}
 }
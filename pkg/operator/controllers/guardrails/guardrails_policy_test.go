@@ -0,0 +1,72 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/dynamichelper"
+	mock_dynamichelper "github.com/Azure/ARO-RP/pkg/util/mocks/dynamichelper"
+)
+
+func TestEnsurePolicy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cluster := &arov1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: arov1alpha1.SingletonClusterName,
+		},
+		Spec: arov1alpha1.ClusterSpec{
+			OperatorFlags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode":                 "deny",
+				"aro.guardrails.policies.aro-privileged-namespace-deny.mode":     "warn",
+				"aro.guardrails.policies.aro-rw-host-mount-deny.managed":         "true",
+				"aro.guardrails.policies.aro-master-toleration-pod-deny.mode":    "off",
+				"aro.guardrails.policies.aro-master-toleration-pod-deny.managed": "true",
+			},
+		},
+	}
+
+	dh := mock_dynamichelper.NewMockInterface(controller)
+	dh.EXPECT().EnsureDeletedGVR(gomock.Any(), "ARODenyMachineConfig.constraints.gatekeeper.sh", "", "aro-machine-config-deny", "v1beta1").Return(nil)
+	dh.EXPECT().EnsureDeletedGVR(gomock.Any(), "ARODenyMasterTolerationTaints.constraints.gatekeeper.sh", "", "aro-master-toleration-pod-deny", "v1beta1").Return(nil)
+
+	enforcement := map[string]string{}
+	dh.EXPECT().Ensure(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, objs ...kruntime.Object) {
+		for _, o := range objs {
+			uns := o.(*unstructured.Unstructured)
+			enforcement[uns.GetName()], _ = dynamichelper.GetEnforcementAction(uns)
+		}
+	}).Return(nil)
+
+	r := &Reconciler{
+		log:    logrus.NewEntry(logrus.StandardLogger()),
+		client: ctrlfake.NewClientBuilder().WithObjects(cluster).Build(),
+		dh:     dh,
+	}
+
+	err := r.ensurePolicy(context.Background(), gkPolicyConstraints, gkConstraintsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, diff := range deep.Equal(enforcement, map[string]string{
+		"aro-machines-deny":             "deny",
+		"aro-privileged-namespace-deny": "warn",
+		"aro-rw-host-mount-deny":        "dryrun",
+	}) {
+		t.Error(diff)
+	}
+}
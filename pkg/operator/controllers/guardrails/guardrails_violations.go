@@ -0,0 +1,129 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/conditions"
+)
+
+// maxReportedViolations is the number of violations of each policy which are
+// listed in the condition.  Gatekeeper itself keeps only the first 20
+// violations of each Constraint in its status.
+const maxReportedViolations = 3
+
+// reportViolations aggregates the results of the latest Gatekeeper audit of
+// each enabled policy into the GuardRailsCompliant condition
+func (r *Reconciler) reportViolations(ctx context.Context, fs embed.FS, path string) error {
+	instance := &arov1alpha1.Cluster{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := r.renderConstraints(fs, path, instance)
+	if err != nil {
+		return err
+	}
+
+	cond, err := r.violationsCondition(ctx, constraints)
+	if err != nil {
+		return err
+	}
+
+	return conditions.SetCondition(ctx, r.client, cond, operator.RoleMaster)
+}
+
+func (r *Reconciler) violationsCondition(ctx context.Context, constraints []constraint) (*operatorv1.OperatorCondition, error) {
+	var enabled, audited, violating int
+	var total int64
+	var sb strings.Builder
+
+	for _, c := range constraints {
+		if c.mode == policyModeOff {
+			continue
+		}
+		enabled++
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(c.uns.GroupVersionKind())
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: c.uns.GetNamespace(), Name: c.uns.GetName()}, obj)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		auditTimestamp, _, _ := unstructured.NestedString(obj.Object, "status", "auditTimestamp")
+		if auditTimestamp == "" {
+			fmt.Fprintf(&sb, "\n%s v%s (%s): not audited yet", c.policy.name, c.policy.version, c.mode)
+			continue
+		}
+		audited++
+
+		totalViolations, _, _ := unstructured.NestedInt64(obj.Object, "status", "totalViolations")
+		if totalViolations == 0 {
+			continue
+		}
+		violating++
+		total += totalViolations
+
+		fmt.Fprintf(&sb, "\n%s v%s (%s): %d violations", c.policy.name, c.policy.version, c.mode, totalViolations)
+
+		violations, _, _ := unstructured.NestedSlice(obj.Object, "status", "violations")
+		for i, v := range violations {
+			if i == maxReportedViolations {
+				break
+			}
+			violation, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&sb, "\n  %s %s: %s", violationField(violation, "kind"), violationObject(violation), violationField(violation, "message"))
+		}
+	}
+
+	cond := &operatorv1.OperatorCondition{
+		Type: arov1alpha1.GuardRailsCompliant,
+	}
+
+	switch {
+	case total > 0:
+		cond.Status = operatorv1.ConditionFalse
+		cond.Reason = "ViolationsFound"
+		cond.Message = fmt.Sprintf("Policy catalogue v%s: %d violations of %d of %d enabled policies", policyCatalogueVersion, total, violating, enabled)
+	case audited < enabled:
+		cond.Status = operatorv1.ConditionUnknown
+		cond.Reason = "AuditPending"
+		cond.Message = fmt.Sprintf("Policy catalogue v%s: %d of %d enabled policies audited", policyCatalogueVersion, audited, enabled)
+	default:
+		cond.Status = operatorv1.ConditionTrue
+		cond.Reason = "CheckDone"
+		cond.Message = fmt.Sprintf("Policy catalogue v%s: no violations of %d enabled policies", policyCatalogueVersion, enabled)
+	}
+	cond.Message += sb.String()
+
+	return cond, nil
+}
+
+func violationField(violation map[string]interface{}, field string) string {
+	s, _ := violation[field].(string)
+	return s
+}
+
+func violationObject(violation map[string]interface{}) string {
+	if namespace := violationField(violation, "namespace"); namespace != "" {
+		return namespace + "/" + violationField(violation, "name")
+	}
+	return violationField(violation, "name")
+}
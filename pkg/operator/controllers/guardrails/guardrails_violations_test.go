@@ -0,0 +1,173 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+func auditedConstraint(kind, name string, totalViolations int64, violations ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"status": map[string]interface{}{
+				"auditTimestamp":  "2023-01-01T00:00:00Z",
+				"totalViolations": totalViolations,
+				"violations":      violations,
+			},
+		},
+	}
+}
+
+func violation(kind, namespace, name, message string) interface{} {
+	return map[string]interface{}{
+		"enforcementAction": "dryrun",
+		"kind":              kind,
+		"namespace":         namespace,
+		"name":              name,
+		"message":           message,
+	}
+}
+
+func TestReportViolations(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		flags   arov1alpha1.OperatorFlags
+		objects []client.Object
+		want    operatorv1.OperatorCondition
+	}{
+		{
+			name:  "no policies enabled",
+			flags: arov1alpha1.OperatorFlags{},
+			want: operatorv1.OperatorCondition{
+				Type:    arov1alpha1.GuardRailsCompliant,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "CheckDone",
+				Message: "Policy catalogue v1: no violations of 0 enabled policies",
+			},
+		},
+		{
+			name: "audit pending",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode":       "dryrun",
+				"aro.guardrails.policies.aro-machine-config-deny.mode": "deny",
+			},
+			objects: []client.Object{
+				auditedConstraint("ARODenyLabels", "aro-machines-deny", 0),
+			},
+			want: operatorv1.OperatorCondition{
+				Type:    arov1alpha1.GuardRailsCompliant,
+				Status:  operatorv1.ConditionUnknown,
+				Reason:  "AuditPending",
+				Message: "Policy catalogue v1: 1 of 2 enabled policies audited\naro-machine-config-deny v1.0.0 (deny): not audited yet",
+			},
+		},
+		{
+			name: "no violations",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode":       "dryrun",
+				"aro.guardrails.policies.aro-machine-config-deny.mode": "deny",
+			},
+			objects: []client.Object{
+				auditedConstraint("ARODenyLabels", "aro-machines-deny", 0),
+				auditedConstraint("ARODenyMachineConfig", "aro-machine-config-deny", 0),
+			},
+			want: operatorv1.OperatorCondition{
+				Type:    arov1alpha1.GuardRailsCompliant,
+				Status:  operatorv1.ConditionTrue,
+				Reason:  "CheckDone",
+				Message: "Policy catalogue v1: no violations of 2 enabled policies",
+			},
+		},
+		{
+			name: "violations found",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.mode":             "dryrun",
+				"aro.guardrails.policies.aro-machine-config-deny.mode":       "deny",
+				"aro.guardrails.policies.aro-privileged-namespace-deny.mode": "warn",
+			},
+			objects: []client.Object{
+				auditedConstraint("ARODenyLabels", "aro-machines-deny", 5,
+					violation("Machine", "openshift-machine-api", "master-0", "denied label"),
+					violation("Machine", "openshift-machine-api", "master-1", "denied label"),
+					violation("Machine", "openshift-machine-api", "master-2", "denied label"),
+					violation("Machine", "openshift-machine-api", "master-3", "denied label"),
+				),
+				auditedConstraint("ARODenyMachineConfig", "aro-machine-config-deny", 0),
+				auditedConstraint("ARODenyPrivilegedNamespace", "aro-privileged-namespace-deny", 1,
+					violation("Namespace", "", "openshift-config", "privileged namespace"),
+				),
+			},
+			want: operatorv1.OperatorCondition{
+				Type:   arov1alpha1.GuardRailsCompliant,
+				Status: operatorv1.ConditionFalse,
+				Reason: "ViolationsFound",
+				Message: "Policy catalogue v1: 6 violations of 2 of 3 enabled policies" +
+					"\naro-machines-deny v1.0.0 (dryrun): 5 violations" +
+					"\n  Machine openshift-machine-api/master-0: denied label" +
+					"\n  Machine openshift-machine-api/master-1: denied label" +
+					"\n  Machine openshift-machine-api/master-2: denied label" +
+					"\naro-privileged-namespace-deny v1.0.0 (warn): 1 violations" +
+					"\n  Namespace openshift-config: privileged namespace",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			cluster := &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Spec: arov1alpha1.ClusterSpec{
+					OperatorFlags: tt.flags,
+				},
+			}
+
+			r := &Reconciler{
+				log:    logrus.NewEntry(logrus.StandardLogger()),
+				client: ctrlfake.NewClientBuilder().WithObjects(cluster).WithObjects(tt.objects...).Build(),
+			}
+
+			err := r.reportViolations(ctx, gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got *operatorv1.OperatorCondition
+			for i := range cluster.Status.Conditions {
+				if cluster.Status.Conditions[i].Type == arov1alpha1.GuardRailsCompliant {
+					got = &cluster.Status.Conditions[i]
+				}
+			}
+			if got == nil {
+				t.Fatal("condition not set")
+			}
+
+			if got.Status != tt.want.Status || got.Reason != tt.want.Reason || got.Message != tt.want.Message {
+				t.Errorf("got %s %s %q, wanted %s %s %q", got.Status, got.Reason, got.Message, tt.want.Status, tt.want.Reason, tt.want.Message)
+			}
+		})
+	}
+}
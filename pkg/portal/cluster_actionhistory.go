@@ -0,0 +1,54 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// clusterActionHistory returns the admin actions taken on the cluster, newest
+// first
+func (p *portal) clusterActionHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceId := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+
+	doc, err := p.dbOpenShiftClusters.Get(ctx, resourceId)
+	if err != nil {
+		http.Error(w, "Cluster not found", http.StatusNotFound)
+		return
+	}
+
+	docs, err := p.dbAdminActions.ListByKey(ctx, strings.ToLower(doc.OpenShiftCluster.ID))
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	actions := make([]*api.AdminAction, 0, len(docs.AdminActionDocuments))
+	for _, doc := range docs.AdminActionDocuments {
+		actions = append(actions, doc.AdminAction)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].StartTime.After(actions[j].StartTime)
+	})
+
+	b, err := json.MarshalIndent(actions, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,48 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+// clusterHealth returns the health of the cluster as last evaluated by the
+// monitor, with its history
+func (p *portal) clusterHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceId := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+
+	doc, err := p.dbOpenShiftClusters.Get(ctx, resourceId)
+	if err != nil {
+		http.Error(w, "Cluster not found", http.StatusNotFound)
+		return
+	}
+
+	healthDoc, err := p.dbClusterHealth.Get(ctx, doc.ID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) ||
+		err == nil && healthDoc.ClusterHealth == nil:
+		http.Error(w, "Cluster health not evaluated yet", http.StatusNotFound)
+		return
+	case err != nil:
+		p.internalServerError(w, err)
+		return
+	}
+
+	b, err := json.MarshalIndent(healthDoc.ClusterHealth, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
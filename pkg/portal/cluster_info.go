@@ -4,6 +4,7 @@ package portal
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -29,6 +30,7 @@ type AdminOpenShiftClusterDetail struct {
 	InfraId                 string `json:"infraId"`
 	ApiServerVisibility     string `json:"apiServerVisibility"`
 	InstallPhase            string `json:"installStatus"`
+	HealthState             string `json:"healthState"`
 }
 
 func (p *portal) clusterInfo(w http.ResponseWriter, r *http.Request) {
@@ -74,6 +76,7 @@ func (p *portal) clusterInfo(w http.ResponseWriter, r *http.Request) {
 		InfraId:               doc.OpenShiftCluster.Properties.InfraID,
 		ApiServerVisibility:   string(doc.OpenShiftCluster.Properties.APIServerProfile.Visibility),
 		InstallPhase:          installPhase,
+		HealthState:           p.healthState(ctx, doc.ID),
 	}
 
 	b, err := json.MarshalIndent(clusterInfo, "", "    ")
@@ -85,3 +88,14 @@ func (p *portal) clusterInfo(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_, _ = w.Write(b)
 }
+
+// healthState returns the health state of the cluster as last evaluated by
+// the monitor, or "Unknown"
+func (p *portal) healthState(ctx context.Context, id string) string {
+	healthDoc, err := p.dbClusterHealth.Get(ctx, id)
+	if err != nil || healthDoc.ClusterHealth == nil {
+		return "Unknown"
+	}
+
+	return string(healthDoc.ClusterHealth.State)
+}
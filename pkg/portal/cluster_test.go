@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -132,9 +133,11 @@ func TestClusterList(t *testing.T) {
 
 func TestClusterDetail(t *testing.T) {
 	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+	dbClusterHealth, _ := testdatabase.NewFakeClusterHealth()
 
 	fixture := testdatabase.NewFixture().
-		WithOpenShiftClusters(dbOpenShiftClusters)
+		WithOpenShiftClusters(dbOpenShiftClusters).
+		WithClusterHealth(dbClusterHealth)
 
 	parsedTime, err := time.Parse(time.RFC3339, "2011-01-02T01:03:00Z")
 	if err != nil {
@@ -181,6 +184,12 @@ func TestClusterDetail(t *testing.T) {
 				},
 			},
 		})
+	fixture.AddClusterHealthDocuments(&api.ClusterHealthDocument{
+		ID: "00000000-0000-0000-0000-000000000000",
+		ClusterHealth: &api.ClusterHealth{
+			State: api.HealthStateDegraded,
+		},
+	})
 
 	err = fixture.Create()
 	if err != nil {
@@ -188,6 +197,7 @@ func TestClusterDetail(t *testing.T) {
 	}
 
 	p := &portal{
+		dbClusterHealth:     dbClusterHealth,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 	}
 
@@ -229,9 +239,216 @@ func TestClusterDetail(t *testing.T) {
 		"lastProvisioningState":   api.ProvisioningStateCreating.String(),
 		"provisioningState":       api.ProvisioningStateSucceeded.String(),
 		"installStatus":           "Installed",
+		"healthState":             "Degraded",
 	}
 
 	for _, l := range deep.Equal(expected, r) {
 		t.Error(l)
 	}
 }
+
+func TestClusterHealth(t *testing.T) {
+	evaluatedTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	health := &api.ClusterHealth{
+		State: api.HealthStateUnhealthy,
+		Reasons: []api.HealthReason{
+			{
+				Signal:  "apiServer",
+				State:   api.HealthStateUnhealthy,
+				Message: "API server did not respond to the health check",
+			},
+		},
+		EvaluatedTime: evaluatedTime,
+		History: []api.HealthTransition{
+			{
+				State: api.HealthStateUnhealthy,
+				Time:  evaluatedTime,
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name           string
+		healthDocs     []*api.ClusterHealthDocument
+		wantStatusCode int
+		wantHealth     *api.ClusterHealth
+	}{
+		{
+			name: "health evaluated",
+			healthDocs: []*api.ClusterHealthDocument{
+				{
+					ID:            "00000000-0000-0000-0000-000000000000",
+					ClusterHealth: health,
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHealth:     health,
+		},
+		{
+			name:           "health not evaluated",
+			wantStatusCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+			dbClusterHealth, _ := testdatabase.NewFakeClusterHealth()
+
+			fixture := testdatabase.NewFixture().
+				WithOpenShiftClusters(dbOpenShiftClusters).
+				WithClusterHealth(dbClusterHealth)
+
+			fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				ID:  "00000000-0000-0000-0000-000000000000",
+				Key: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroupName/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+				},
+			})
+			fixture.AddClusterHealthDocuments(tt.healthDocs...)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := &portal{
+				dbClusterHealth:     dbClusterHealth,
+				dbOpenShiftClusters: dbOpenShiftClusters,
+			}
+
+			req, err := http.NewRequest("GET", "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/cluster/health", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aadAuthenticatedRouter := mux.NewRouter()
+			p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+			w := httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("got status code %d, want %d", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantHealth == nil {
+				return
+			}
+
+			var r *api.ClusterHealth
+			err = json.NewDecoder(w.Body).Decode(&r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, l := range deep.Equal(r, tt.wantHealth) {
+				t.Error(l)
+			}
+		})
+	}
+}
+
+func TestClusterActionHistory(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroupName/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	adminAction := func(id, action string, startTime time.Time) *api.AdminAction {
+		return &api.AdminAction{
+			ID:                  id,
+			ResourceID:          strings.ToLower(resourceID),
+			Action:              action,
+			Method:              http.MethodPost,
+			ClientPrincipalName: "someone@example.com",
+			StartTime:           startTime,
+			EndTime:             startTime.Add(time.Minute),
+			Result: api.AdminActionResult{
+				StatusCode: http.StatusOK,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		clusterName    string
+		wantStatusCode int
+		wantActions    []*api.AdminAction
+	}{
+		{
+			name:           "history is returned newest first",
+			clusterName:    "cluster",
+			wantStatusCode: http.StatusOK,
+			wantActions: []*api.AdminAction{
+				adminAction("08080808-0808-0808-0808-080808080002", "stopvm", newer),
+				adminAction("08080808-0808-0808-0808-080808080001", "redeployvm", older),
+			},
+		},
+		{
+			name:           "cluster not found",
+			clusterName:    "missing",
+			wantStatusCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+			dbAdminActions, _ := testdatabase.NewFakeAdminActions()
+
+			fixture := testdatabase.NewFixture().
+				WithOpenShiftClusters(dbOpenShiftClusters).
+				WithAdminActions(dbAdminActions)
+
+			fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				ID:  "00000000-0000-0000-0000-000000000000",
+				Key: strings.ToLower(resourceID),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: resourceID,
+				},
+			})
+			for _, aa := range []*api.AdminAction{
+				adminAction("08080808-0808-0808-0808-080808080001", "redeployvm", older),
+				adminAction("08080808-0808-0808-0808-080808080002", "stopvm", newer),
+			} {
+				fixture.AddAdminActionDocuments(&api.AdminActionDocument{
+					ID:          aa.ID,
+					Key:         strings.ToLower(resourceID),
+					AdminAction: aa,
+				})
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := &portal{
+				dbAdminActions:      dbAdminActions,
+				dbOpenShiftClusters: dbOpenShiftClusters,
+			}
+
+			req, err := http.NewRequest("GET", "/api/00000000-0000-0000-0000-000000000000/resourcegroupname/"+tt.clusterName+"/actionhistory", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aadAuthenticatedRouter := mux.NewRouter()
+			p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+			w := httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("got status code %d, want %d", w.Code, tt.wantStatusCode)
+			}
+			if tt.wantActions == nil {
+				return
+			}
+
+			var r []*api.AdminAction
+			err = json.NewDecoder(w.Body).Decode(&r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, l := range deep.Equal(r, tt.wantActions) {
+				t.Error(l)
+			}
+		})
+	}
+}
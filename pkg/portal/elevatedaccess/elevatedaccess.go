@@ -0,0 +1,379 @@
+package elevatedaccess
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+)
+
+// This file implements just-in-time elevated access.  Members of the elevated
+// groups have standing elevated access to every cluster.  Other portal users
+// can request time-boxed elevated access to a single cluster with a
+// justification; once a member of the elevated groups approves the request,
+// the requester is treated as elevated on that cluster until the grant
+// expires.  Requests and grants are stored as PortalDocuments and are removed
+// by their TTL.
+
+const (
+	// pendingTimeout is how long a request waits for review, and how long a
+	// denied request is kept for reference
+	pendingTimeout = 24 * time.Hour
+
+	minDuration = 15 * time.Minute
+	maxDuration = 8 * time.Hour
+
+	maxJustificationLength = 1024
+)
+
+type ElevatedAccess struct {
+	log           *logrus.Entry
+	baseAccessLog *logrus.Entry
+
+	elevatedGroupIDs []string
+
+	dbPortal database.Portal
+
+	now func() time.Time
+}
+
+func New(log *logrus.Entry,
+	baseAccessLog *logrus.Entry,
+	elevatedGroupIDs []string,
+	dbPortal database.Portal,
+) *ElevatedAccess {
+	return &ElevatedAccess{
+		log:           log,
+		baseAccessLog: baseAccessLog,
+
+		elevatedGroupIDs: elevatedGroupIDs,
+
+		dbPortal: dbPortal,
+
+		now: time.Now,
+	}
+}
+
+// Check returns whether the user in ctx has elevated access to the cluster
+// with the given resource ID.  If the access comes from a just-in-time grant,
+// Check also returns when the grant expires; standing access returns a zero
+// time.
+func (e *ElevatedAccess) Check(ctx context.Context, resourceID string) (bool, time.Time, error) {
+	groups, _ := ctx.Value(middleware.ContextKeyGroups).([]string)
+	if len(middleware.GroupsIntersect(e.elevatedGroupIDs, groups)) > 0 {
+		return true, time.Time{}, nil
+	}
+
+	username, _ := ctx.Value(middleware.ContextKeyUsername).(string)
+	if username == "" {
+		return false, time.Time{}, nil
+	}
+
+	docs, err := e.dbPortal.ListElevatedAccess(ctx, strings.ToLower(resourceID))
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	var expiresAt time.Time
+	for _, doc := range docs.PortalDocuments {
+		if e.active(doc.Portal, username) && doc.Portal.ElevatedAccess.ExpiresAt.After(expiresAt) {
+			expiresAt = *doc.Portal.ElevatedAccess.ExpiresAt
+		}
+	}
+
+	return !expiresAt.IsZero(), expiresAt, nil
+}
+
+// active returns true if portal is an unexpired grant for username
+func (e *ElevatedAccess) active(portal *api.Portal, username string) bool {
+	return portal.Username == username &&
+		portal.ElevatedAccess.State == api.ElevatedAccessStateApproved &&
+		portal.ElevatedAccess.ExpiresAt != nil &&
+		portal.ElevatedAccess.ExpiresAt.After(e.now())
+}
+
+// Request describes a just-in-time elevated access request
+type Request struct {
+	ID            string                  `json:"id"`
+	Username      string                  `json:"username"`
+	Justification string                  `json:"justification"`
+	Duration      string                  `json:"duration"`
+	State         api.ElevatedAccessState `json:"state"`
+	RequestedAt   time.Time               `json:"requestedAt"`
+	ReviewedBy    string                  `json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time              `json:"reviewedAt,omitempty"`
+	ExpiresAt     *time.Time              `json:"expiresAt,omitempty"`
+}
+
+func newRequest(doc *api.PortalDocument) *Request {
+	return &Request{
+		ID:            doc.ID,
+		Username:      doc.Portal.Username,
+		Justification: doc.Portal.ElevatedAccess.Justification,
+		Duration:      doc.Portal.ElevatedAccess.Duration,
+		State:         doc.Portal.ElevatedAccess.State,
+		RequestedAt:   doc.Portal.ElevatedAccess.RequestedAt,
+		ReviewedBy:    doc.Portal.ElevatedAccess.ReviewedBy,
+		ReviewedAt:    doc.Portal.ElevatedAccess.ReviewedAt,
+		ExpiresAt:     doc.Portal.ElevatedAccess.ExpiresAt,
+	}
+}
+
+// List returns the elevated access requests and grants of a cluster, newest
+// first
+func (e *ElevatedAccess) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, ok := resourceIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	docs, err := e.dbPortal.ListElevatedAccess(ctx, resourceID)
+	if err != nil {
+		e.internalServerError(w, err)
+		return
+	}
+
+	requests := make([]*Request, 0, len(docs.PortalDocuments))
+	for _, doc := range docs.PortalDocuments {
+		// grants are deleted by their TTL; skip those which have expired but
+		// are not yet deleted
+		if doc.Portal.ElevatedAccess.State == api.ElevatedAccessStateApproved &&
+			!e.active(doc.Portal, doc.Portal.Username) {
+			continue
+		}
+
+		requests = append(requests, newRequest(doc))
+	}
+
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].RequestedAt.After(requests[j].RequestedAt) })
+
+	e.reply(w, http.StatusOK, requests)
+}
+
+type newRequestBody struct {
+	Justification string `json:"justification"`
+	Duration      string `json:"duration"`
+}
+
+// New creates a pending elevated access request for the user in ctx
+func (e *ElevatedAccess) New(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, ok := resourceIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	mediatype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediatype != "application/json" {
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var body *newRequestBody
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil || body == nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	body.Justification = strings.TrimSpace(body.Justification)
+	if body.Justification == "" || len(body.Justification) > maxJustificationLength {
+		http.Error(w, fmt.Sprintf("The justification must be between 1 and %d characters.", maxJustificationLength), http.StatusBadRequest)
+		return
+	}
+
+	duration, err := time.ParseDuration(body.Duration)
+	if err != nil || duration < minDuration || duration > maxDuration {
+		http.Error(w, fmt.Sprintf("The duration must be between %s and %s.", minDuration, maxDuration), http.StatusBadRequest)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	doc, err := e.dbPortal.Create(ctx, &api.PortalDocument{
+		ID:  e.dbPortal.NewUUID(),
+		TTL: int(pendingTimeout / time.Second),
+		Portal: &api.Portal{
+			Username: username,
+			ID:       resourceID,
+			ElevatedAccess: &api.ElevatedAccess{
+				Justification: body.Justification,
+				Duration:      duration.String(),
+				State:         api.ElevatedAccessStatePending,
+				RequestedAt:   e.now().UTC(),
+			},
+		},
+	})
+	if err != nil {
+		e.internalServerError(w, err)
+		return
+	}
+
+	e.accessLog(ctx, doc).WithFields(logrus.Fields{
+		"justification": body.Justification,
+		"duration":      duration.String(),
+	}).Print("elevated access requested")
+
+	e.reply(w, http.StatusCreated, newRequest(doc))
+}
+
+// Approve grants a pending elevated access request.  The grant starts now and
+// lasts for the requested duration.
+func (e *ElevatedAccess) Approve(w http.ResponseWriter, r *http.Request) {
+	e.review(w, r, api.ElevatedAccessStateApproved)
+}
+
+// Deny rejects a pending elevated access request
+func (e *ElevatedAccess) Deny(w http.ResponseWriter, r *http.Request) {
+	e.review(w, r, api.ElevatedAccessStateDenied)
+}
+
+type httpError struct {
+	statusCode int
+	message    string
+}
+
+func (err *httpError) Error() string {
+	return err.message
+}
+
+func (e *ElevatedAccess) review(w http.ResponseWriter, r *http.Request, state api.ElevatedAccessState) {
+	ctx := r.Context()
+
+	resourceID, ok := resourceIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	// .../openshiftclusters/{resourceName}/elevatedaccess/{id}/{action}
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 12 {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	id := parts[10]
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	groups, _ := ctx.Value(middleware.ContextKeyGroups).([]string)
+	if len(middleware.GroupsIntersect(e.elevatedGroupIDs, groups)) == 0 {
+		http.Error(w, "Requests must be reviewed by a member of the elevated groups.", http.StatusForbidden)
+		return
+	}
+
+	doc, err := e.dbPortal.Patch(ctx, id, func(doc *api.PortalDocument) error {
+		if doc.Portal.ElevatedAccess == nil || doc.Portal.ID != resourceID {
+			return &httpError{http.StatusNotFound, "Request not found"}
+		}
+
+		if doc.Portal.ElevatedAccess.State != api.ElevatedAccessStatePending {
+			return &httpError{http.StatusConflict, fmt.Sprintf("The request is already %s.", strings.ToLower(string(doc.Portal.ElevatedAccess.State)))}
+		}
+
+		if strings.EqualFold(doc.Portal.Username, username) {
+			return &httpError{http.StatusForbidden, "Requests must be reviewed by a different user."}
+		}
+
+		now := e.now().UTC()
+
+		doc.Portal.ElevatedAccess.State = state
+		doc.Portal.ElevatedAccess.ReviewedBy = username
+		doc.Portal.ElevatedAccess.ReviewedAt = &now
+
+		// the TTL restarts on every write, so it is reset to remove the
+		// document when the grant expires
+		doc.TTL = int(pendingTimeout / time.Second)
+		if state == api.ElevatedAccessStateApproved {
+			duration, err := time.ParseDuration(doc.Portal.ElevatedAccess.Duration)
+			if err != nil {
+				return err
+			}
+
+			expiresAt := now.Add(duration)
+			doc.Portal.ElevatedAccess.ExpiresAt = &expiresAt
+			doc.TTL = int(duration / time.Second)
+		}
+
+		return nil
+	})
+	if err, ok := err.(*httpError); ok {
+		http.Error(w, err.message, err.statusCode)
+		return
+	}
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		http.Error(w, "Request not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		e.internalServerError(w, err)
+		return
+	}
+
+	e.accessLog(ctx, doc).WithFields(logrus.Fields{
+		"requester": doc.Portal.Username,
+		"state":     state,
+	}).Print("elevated access reviewed")
+
+	e.reply(w, http.StatusOK, newRequest(doc))
+}
+
+func (e *ElevatedAccess) accessLog(ctx context.Context, doc *api.PortalDocument) *logrus.Entry {
+	return e.baseAccessLog.WithFields(logrus.Fields{
+		"resource_id": doc.Portal.ID,
+		"request_id":  doc.ID,
+		"username":    ctx.Value(middleware.ContextKeyUsername),
+	})
+}
+
+func (e *ElevatedAccess) reply(w http.ResponseWriter, statusCode int, v interface{}) {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		e.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(b)
+}
+
+func (e *ElevatedAccess) internalServerError(w http.ResponseWriter, err error) {
+	e.log.Warn(err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// resourceIDFromPath returns the cluster resource ID at the start of the
+// request path.  Resource IDs are stored lower case, as Check looks them up.
+func resourceIDFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 9 {
+		http.Error(w, "invalid resourceId", http.StatusBadRequest)
+		return "", false
+	}
+
+	resourceID := strings.Join(parts[:9], "/")
+	if !validate.RxClusterID.MatchString(resourceID) {
+		http.Error(w, fmt.Sprintf("invalid resourceId %q", resourceID), http.StatusBadRequest)
+		return "", false
+	}
+
+	return strings.ToLower(resourceID), true
+}
@@ -0,0 +1,554 @@
+package elevatedaccess
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+const (
+	resourceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+	requestID  = "00000000-0000-0000-0000-000000000001"
+)
+
+var elevatedGroupIDs = []string{"10000000-0000-0000-0000-000000000000"}
+
+func userContext(username string, groups ...string) context.Context {
+	ctx := context.WithValue(context.Background(), middleware.ContextKeyUsername, username)
+	return context.WithValue(ctx, middleware.ContextKeyGroups, groups)
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	expired := now.Add(-time.Minute)
+
+	grant := func(id, username string, state api.ElevatedAccessState, expiresAt *time.Time) *api.PortalDocument {
+		return &api.PortalDocument{
+			ID: id,
+			Portal: &api.Portal{
+				Username: username,
+				ID:       resourceID,
+				ElevatedAccess: &api.ElevatedAccess{
+					State:     state,
+					ExpiresAt: expiresAt,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		ctx           context.Context
+		fixture       func(*testdatabase.Fixture)
+		wantElevated  bool
+		wantExpiresAt time.Time
+	}{
+		{
+			name:         "elevated group",
+			ctx:          userContext("username", elevatedGroupIDs...),
+			wantElevated: true,
+		},
+		{
+			name: "approved grant",
+			ctx:  userContext("username"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(grant("00000000-0000-0000-0000-000000000001", "username", api.ElevatedAccessStateApproved, &expiresAt))
+			},
+			wantElevated:  true,
+			wantExpiresAt: expiresAt,
+		},
+		{
+			name: "expired grant",
+			ctx:  userContext("username"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(grant("00000000-0000-0000-0000-000000000001", "username", api.ElevatedAccessStateApproved, &expired))
+			},
+		},
+		{
+			name: "pending request",
+			ctx:  userContext("username"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(grant("00000000-0000-0000-0000-000000000001", "username", api.ElevatedAccessStatePending, nil))
+			},
+		},
+		{
+			name: "grant for another user",
+			ctx:  userContext("username"),
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddPortalDocuments(grant("00000000-0000-0000-0000-000000000001", "other", api.ElevatedAccessStateApproved, &expiresAt))
+			},
+		},
+		{
+			name: "grant for another cluster",
+			ctx:  userContext("username"),
+			fixture: func(f *testdatabase.Fixture) {
+				doc := grant("00000000-0000-0000-0000-000000000001", "username", api.ElevatedAccessStateApproved, &expiresAt)
+				doc.Portal.ID = strings.Replace(resourceID, "/cluster", "/other", 1)
+				f.AddPortalDocuments(doc)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPortal, _ := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			if tt.fixture != nil {
+				tt.fixture(fixture)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, log := testlog.New()
+			e := New(log, log, elevatedGroupIDs, dbPortal)
+			e.now = func() time.Time { return now }
+
+			elevated, expiresAt, err := e.Check(tt.ctx, resourceID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if elevated != tt.wantElevated {
+				t.Error(elevated)
+			}
+
+			if !expiresAt.Equal(tt.wantExpiresAt) {
+				t.Error(expiresAt)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name           string
+		path           string
+		body           string
+		contentType    string
+		wantStatusCode int
+		wantBody       string
+		wantDocs       []*api.PortalDocument
+	}{
+		{
+			name:           "success",
+			body:           `{"justification": "ICM 123456", "duration": "4h"}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusCreated,
+			wantBody: `{
+    "id": "03030303-0303-0303-0303-030303030001",
+    "username": "username",
+    "justification": "ICM 123456",
+    "duration": "4h0m0s",
+    "state": "Pending",
+    "requestedAt": "2024-01-01T12:00:00Z"
+}`,
+			wantDocs: []*api.PortalDocument{
+				{
+					ID:  "03030303-0303-0303-0303-030303030001",
+					TTL: 86400,
+					Portal: &api.Portal{
+						Username: "username",
+						ID:       resourceID,
+						ElevatedAccess: &api.ElevatedAccess{
+							Justification: "ICM 123456",
+							Duration:      "4h0m0s",
+							State:         api.ElevatedAccessStatePending,
+							RequestedAt:   now,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "mixed case resource ID",
+			path:           strings.Replace(resourceID, "/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/", "/resourceGroups/RG/providers/Microsoft.RedHatOpenShift/openShiftClusters/", 1),
+			body:           `{"justification": "ICM 123456", "duration": "4h"}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusCreated,
+			wantBody: `{
+    "id": "03030303-0303-0303-0303-030303030001",
+    "username": "username",
+    "justification": "ICM 123456",
+    "duration": "4h0m0s",
+    "state": "Pending",
+    "requestedAt": "2024-01-01T12:00:00Z"
+}`,
+			wantDocs: []*api.PortalDocument{
+				{
+					ID:  "03030303-0303-0303-0303-030303030001",
+					TTL: 86400,
+					Portal: &api.Portal{
+						Username: "username",
+						ID:       resourceID,
+						ElevatedAccess: &api.ElevatedAccess{
+							Justification: "ICM 123456",
+							Duration:      "4h0m0s",
+							State:         api.ElevatedAccessStatePending,
+							RequestedAt:   now,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "missing justification",
+			body:           `{"justification": " ", "duration": "4h"}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "The justification must be between 1 and 1024 characters.\n",
+		},
+		{
+			name:           "duration too long",
+			body:           `{"justification": "ICM 123456", "duration": "24h"}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "The duration must be between 15m0s and 8h0m0s.\n",
+		},
+		{
+			name:           "invalid duration",
+			body:           `{"justification": "ICM 123456", "duration": "forever"}`,
+			contentType:    "application/json",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "The duration must be between 15m0s and 8h0m0s.\n",
+		},
+		{
+			name:           "wrong content type",
+			body:           `{"justification": "ICM 123456", "duration": "4h"}`,
+			contentType:    "text/plain",
+			wantStatusCode: http.StatusUnsupportedMediaType,
+			wantBody:       "Unsupported Media Type\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPortal, portalClient := testdatabase.NewFakePortal()
+
+			_, log := testlog.New()
+			e := New(log, log, elevatedGroupIDs, dbPortal)
+			e.now = func() time.Time { return now }
+
+			path := tt.path
+			if path == "" {
+				path = resourceID
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "https://localhost:8444"+path+"/elevatedaccess/new", strings.NewReader(tt.body)).WithContext(userContext("username"))
+			r.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			e.New(w, r)
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code)
+			}
+
+			if w.Body.String() != tt.wantBody {
+				t.Error(w.Body.String())
+			}
+
+			checker := testdatabase.NewChecker()
+			checker.AddPortalDocuments(tt.wantDocs...)
+			for _, err := range checker.CheckPortals(portalClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	requestedAt := now.Add(-time.Hour)
+	expiresAt := now.Add(4 * time.Hour)
+
+	pending := func() *api.PortalDocument {
+		return &api.PortalDocument{
+			ID:  requestID,
+			TTL: 86400,
+			Portal: &api.Portal{
+				Username: "requester",
+				ID:       resourceID,
+				ElevatedAccess: &api.ElevatedAccess{
+					Justification: "ICM 123456",
+					Duration:      "4h0m0s",
+					State:         api.ElevatedAccessStatePending,
+					RequestedAt:   requestedAt,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		username       string
+		groups         []string
+		path           string
+		fixture        func() *api.PortalDocument
+		wantStatusCode int
+		wantBody       string
+		wantDoc        func() *api.PortalDocument
+	}{
+		{
+			name:           "approve",
+			username:       "reviewer",
+			groups:         elevatedGroupIDs,
+			path:           resourceID + "/elevatedaccess/" + requestID + "/approve",
+			fixture:        pending,
+			wantStatusCode: http.StatusOK,
+			wantDoc: func() *api.PortalDocument {
+				doc := pending()
+				doc.TTL = 14400
+				doc.Portal.ElevatedAccess.State = api.ElevatedAccessStateApproved
+				doc.Portal.ElevatedAccess.ReviewedBy = "reviewer"
+				doc.Portal.ElevatedAccess.ReviewedAt = &now
+				doc.Portal.ElevatedAccess.ExpiresAt = &expiresAt
+				return doc
+			},
+		},
+		{
+			name:           "deny",
+			username:       "reviewer",
+			groups:         elevatedGroupIDs,
+			path:           resourceID + "/elevatedaccess/" + requestID + "/deny",
+			fixture:        pending,
+			wantStatusCode: http.StatusOK,
+			wantDoc: func() *api.PortalDocument {
+				doc := pending()
+				doc.Portal.ElevatedAccess.State = api.ElevatedAccessStateDenied
+				doc.Portal.ElevatedAccess.ReviewedBy = "reviewer"
+				doc.Portal.ElevatedAccess.ReviewedAt = &now
+				return doc
+			},
+		},
+		{
+			name:           "approve with a mixed case resource ID",
+			username:       "reviewer",
+			groups:         elevatedGroupIDs,
+			path:           strings.Replace(resourceID, "/openshiftclusters/", "/openShiftClusters/", 1) + "/elevatedaccess/" + requestID + "/approve",
+			fixture:        pending,
+			wantStatusCode: http.StatusOK,
+			wantDoc: func() *api.PortalDocument {
+				doc := pending()
+				doc.TTL = 14400
+				doc.Portal.ElevatedAccess.State = api.ElevatedAccessStateApproved
+				doc.Portal.ElevatedAccess.ReviewedBy = "reviewer"
+				doc.Portal.ElevatedAccess.ReviewedAt = &now
+				doc.Portal.ElevatedAccess.ExpiresAt = &expiresAt
+				return doc
+			},
+		},
+		{
+			name:           "approval by a reviewer outside the elevated groups",
+			username:       "reviewer",
+			path:           resourceID + "/elevatedaccess/" + requestID + "/approve",
+			fixture:        pending,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Requests must be reviewed by a member of the elevated groups.\n",
+			wantDoc:        pending,
+		},
+		{
+			name:           "self approval",
+			username:       "requester",
+			groups:         elevatedGroupIDs,
+			path:           resourceID + "/elevatedaccess/" + requestID + "/approve",
+			fixture:        pending,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Requests must be reviewed by a different user.\n",
+			wantDoc:        pending,
+		},
+		{
+			name:     "already reviewed",
+			username: "reviewer",
+			groups:   elevatedGroupIDs,
+			path:     resourceID + "/elevatedaccess/" + requestID + "/approve",
+			fixture: func() *api.PortalDocument {
+				doc := pending()
+				doc.Portal.ElevatedAccess.State = api.ElevatedAccessStateDenied
+				return doc
+			},
+			wantStatusCode: http.StatusConflict,
+			wantBody:       "The request is already denied.\n",
+			wantDoc: func() *api.PortalDocument {
+				doc := pending()
+				doc.Portal.ElevatedAccess.State = api.ElevatedAccessStateDenied
+				return doc
+			},
+		},
+		{
+			name:           "request for another cluster",
+			username:       "reviewer",
+			groups:         elevatedGroupIDs,
+			path:           strings.Replace(resourceID, "/cluster", "/other", 1) + "/elevatedaccess/" + requestID + "/approve",
+			fixture:        pending,
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Request not found\n",
+			wantDoc:        pending,
+		},
+		{
+			name:           "request not found",
+			username:       "reviewer",
+			groups:         elevatedGroupIDs,
+			path:           resourceID + "/elevatedaccess/" + requestID + "/approve",
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Request not found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPortal, portalClient := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+			if tt.fixture != nil {
+				fixture.AddPortalDocuments(tt.fixture())
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, log := testlog.New()
+			e := New(log, log, elevatedGroupIDs, dbPortal)
+			e.now = func() time.Time { return now }
+
+			r := httptest.NewRequest(http.MethodPost, "https://localhost:8444"+tt.path, nil).WithContext(userContext(tt.username, tt.groups...))
+			w := httptest.NewRecorder()
+
+			if strings.HasSuffix(tt.path, "/approve") {
+				e.Approve(w, r)
+			} else {
+				e.Deny(w, r)
+			}
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code, w.Body.String())
+			}
+
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Error(w.Body.String())
+			}
+
+			checker := testdatabase.NewChecker()
+			if tt.wantDoc != nil {
+				checker.AddPortalDocuments(tt.wantDoc())
+			}
+			for _, err := range checker.CheckPortals(portalClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour)
+	expired := now.Add(-time.Minute)
+
+	dbPortal, portalClient := testdatabase.NewFakePortal()
+
+	fixture := testdatabase.NewFixture().WithPortal(dbPortal)
+	fixture.AddPortalDocuments(
+		&api.PortalDocument{
+			ID: "00000000-0000-0000-0000-000000000001",
+			Portal: &api.Portal{
+				Username: "username",
+				ID:       resourceID,
+				ElevatedAccess: &api.ElevatedAccess{
+					State:       api.ElevatedAccessStateApproved,
+					RequestedAt: now.Add(-2 * time.Hour),
+					ExpiresAt:   &expiresAt,
+				},
+			},
+		},
+		&api.PortalDocument{
+			ID: "00000000-0000-0000-0000-000000000002",
+			Portal: &api.Portal{
+				Username: "username",
+				ID:       resourceID,
+				ElevatedAccess: &api.ElevatedAccess{
+					State:       api.ElevatedAccessStatePending,
+					RequestedAt: now.Add(-time.Hour),
+				},
+			},
+		},
+		&api.PortalDocument{
+			ID: "00000000-0000-0000-0000-000000000003",
+			Portal: &api.Portal{
+				Username: "username",
+				ID:       resourceID,
+				ElevatedAccess: &api.ElevatedAccess{
+					State:       api.ElevatedAccessStateApproved,
+					RequestedAt: now.Add(-3 * time.Hour),
+					ExpiresAt:   &expired,
+				},
+			},
+		},
+		&api.PortalDocument{
+			ID: "00000000-0000-0000-0000-000000000004",
+			Portal: &api.Portal{
+				Username:   "username",
+				ID:         resourceID,
+				Kubeconfig: &api.Kubeconfig{},
+			},
+		},
+	)
+
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, log := testlog.New()
+	e := New(log, log, elevatedGroupIDs, dbPortal)
+	e.now = func() time.Time { return now }
+
+	r := httptest.NewRequest(http.MethodGet, "https://localhost:8444"+resourceID+"/elevatedaccess", nil).WithContext(userContext("username"))
+	w := httptest.NewRecorder()
+
+	e.List(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatal(w.Code)
+	}
+
+	var requests []*Request
+	err = json.Unmarshal(w.Body.Bytes(), &requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+
+	for _, err := range deep.Equal(ids, []string{"00000000-0000-0000-0000-000000000002", "00000000-0000-0000-0000-000000000001"}) {
+		t.Error(err)
+	}
+
+	portalClient.SetError(fmt.Errorf("sad"))
+
+	w = httptest.NewRecorder()
+	e.List(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Error(w.Code)
+	}
+}
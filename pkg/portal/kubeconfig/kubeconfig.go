@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
@@ -21,6 +22,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/util/clientcache"
 	"github.com/Azure/ARO-RP/pkg/proxy"
@@ -36,8 +38,8 @@ type Kubeconfig struct {
 	BaseAccessLog *logrus.Entry
 	Audit         *logrus.Entry
 
-	servingCert      *x509.Certificate
-	elevatedGroupIDs []string
+	servingCert    *x509.Certificate
+	elevatedAccess *elevatedaccess.ElevatedAccess
 
 	dbOpenShiftClusters database.OpenShiftClusters
 	DbPortal            database.Portal
@@ -47,6 +49,8 @@ type Kubeconfig struct {
 	Env         env.Core
 
 	ReverseProxy *httputil.ReverseProxy
+
+	now func() time.Time
 }
 
 func New(baseLog *logrus.Entry,
@@ -54,7 +58,7 @@ func New(baseLog *logrus.Entry,
 	env env.Core,
 	baseAccessLog *logrus.Entry,
 	servingCert *x509.Certificate,
-	elevatedGroupIDs []string,
+	elevatedAccess *elevatedaccess.ElevatedAccess,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
 	dialer proxy.Dialer,
@@ -64,8 +68,8 @@ func New(baseLog *logrus.Entry,
 		BaseAccessLog: baseAccessLog,
 		Audit:         audit,
 
-		servingCert:      servingCert,
-		elevatedGroupIDs: elevatedGroupIDs,
+		servingCert:    servingCert,
+		elevatedAccess: elevatedAccess,
 
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		DbPortal:            dbPortal,
@@ -73,6 +77,8 @@ func New(baseLog *logrus.Entry,
 		dialer:      dialer,
 		clientCache: clientcache.New(time.Hour),
 		Env:         env,
+
+		now: time.Now,
 	}
 
 	k.ReverseProxy = &httputil.ReverseProxy{
@@ -84,8 +90,15 @@ func New(baseLog *logrus.Entry,
 	return k
 }
 
+type newRequest struct {
+	Scope     api.KubeconfigScope `json:"scope"`
+	Namespace string              `json:"namespace"`
+}
+
 // New creates a New PortalDocument allowing kubeconfig access to a cluster for
-// 6 hours and returns a kubeconfig with the temporary credentials
+// 6 hours, or until the user's just-in-time elevated access grant expires if
+// sooner, and returns a kubeconfig with the temporary credentials.  The
+// optional request body sets the scope of the token; the default is admin.
 func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -95,28 +108,77 @@ func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	elevated := len(middleware.GroupsIntersect(k.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+	req := &newRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil && err != io.EOF {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case req.Scope == "" || strings.EqualFold(string(req.Scope), string(api.KubeconfigScopeAdmin)):
+		req.Scope = api.KubeconfigScopeAdmin
+	case strings.EqualFold(string(req.Scope), string(api.KubeconfigScopeReadOnly)):
+		req.Scope = api.KubeconfigScopeReadOnly
+	case strings.EqualFold(string(req.Scope), string(api.KubeconfigScopeNamespace)):
+		req.Scope = api.KubeconfigScopeNamespace
+		if !rxNamespace.MatchString(req.Namespace) {
+			http.Error(w, fmt.Sprintf("invalid namespace %q", req.Namespace), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("invalid scope %q", req.Scope), http.StatusBadRequest)
+		return
+	}
+	if req.Scope != api.KubeconfigScopeNamespace {
+		req.Namespace = ""
+	}
+
+	elevated, grantExpiresAt, err := k.elevatedAccess.Check(ctx, resourceID)
+	if err != nil {
+		k.internalServerError(w, err)
+		return
+	}
+
+	// the namespace scope is advisory: it limits which paths are proxied,
+	// but an elevated identity can still escape its namespace, for example
+	// with a privileged pod
+	if elevated && req.Scope == api.KubeconfigScopeNamespace {
+		http.Error(w, "The Namespace scope is not available for elevated kubeconfigs.", http.StatusBadRequest)
+		return
+	}
+
+	now := k.now().UTC()
+	expiresAt := now.Add(kubeconfigNewTimeout)
+	if !grantExpiresAt.IsZero() && grantExpiresAt.Before(expiresAt) {
+		expiresAt = grantExpiresAt
+	}
 
 	token := k.DbPortal.NewUUID()
 	portalDoc := &api.PortalDocument{
 		ID:  token,
-		TTL: int(kubeconfigNewTimeout / time.Second),
+		TTL: int(expiresAt.Sub(now) / time.Second),
 		Portal: &api.Portal{
 			Username: ctx.Value(middleware.ContextKeyUsername).(string),
 			ID:       resourceID,
 			Kubeconfig: &api.Kubeconfig{
-				Elevated: elevated,
+				Elevated:  elevated,
+				TokenID:   k.DbPortal.NewUUID(),
+				Scope:     req.Scope,
+				Namespace: req.Namespace,
+				CreatedAt: &now,
+				ExpiresAt: &expiresAt,
 			},
 		},
 	}
 
-	_, err := k.DbPortal.Create(ctx, portalDoc)
+	_, err = k.DbPortal.Create(ctx, portalDoc)
 	if err != nil {
 		k.internalServerError(w, err)
 		return
 	}
 
-	b, err := k.makeKubeconfig("https://"+r.Host+resourceID+"/kubeconfig/proxy", token)
+	b, err := k.makeKubeconfig("https://"+r.Host+resourceID+"/kubeconfig/proxy", token, req.Namespace)
 	if err != nil {
 		k.internalServerError(w, err)
 		return
@@ -126,6 +188,12 @@ func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 	if elevated {
 		filename += "-elevated"
 	}
+	switch req.Scope {
+	case api.KubeconfigScopeReadOnly:
+		filename += "-readonly"
+	case api.KubeconfigScopeNamespace:
+		filename += "-" + req.Namespace
+	}
 
 	w.Header().Add("Content-Type", "application/json")
 	w.Header().Add("Content-Disposition", `attachment; filename="`+filename+`.kubeconfig"`)
@@ -137,7 +205,11 @@ func (k *Kubeconfig) internalServerError(w http.ResponseWriter, err error) {
 	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 }
 
-func (k *Kubeconfig) makeKubeconfig(server, token string) ([]byte, error) {
+func (k *Kubeconfig) makeKubeconfig(server, token, namespace string) ([]byte, error) {
+	if namespace == "" {
+		namespace = "default"
+	}
+
 	return json.MarshalIndent(&clientcmdv1.Config{
 		APIVersion: "v1",
 		Kind:       "Config",
@@ -166,7 +238,7 @@ func (k *Kubeconfig) makeKubeconfig(server, token string) ([]byte, error) {
 				Name: "context",
 				Context: clientcmdv1.Context{
 					Cluster:   "cluster",
-					Namespace: "default",
+					Namespace: namespace,
 					AuthInfo:  "user",
 				},
 			},
@@ -10,13 +10,16 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/util/responsewriter"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient"
@@ -33,8 +36,22 @@ func TestNew(t *testing.T) {
 
 	servingCert := &x509.Certificate{}
 
+	now := time.Now().UTC().Truncate(time.Second)
+	kubeconfigExpiresAt := now.Add(6 * time.Hour)
+
+	// a grant which outlasts the kubeconfig does not shorten it
+	expiresAt := now.Add(7 * time.Hour)
+	shortExpiresAt := now.Add(2 * time.Hour)
+
+	kubeconfig := func(namespace string) string {
+		return fmt.Sprintf("{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"%s\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}", namespace)
+	}
+
+	tokenID := "03030303-0303-0303-0303-030303030002"
+
 	for _, tt := range []struct {
 		name           string
+		body           string
 		r              func(*http.Request)
 		elevated       bool
 		fixtureChecker func(*testdatabase.Fixture, *testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
@@ -49,9 +66,14 @@ func TestNew(t *testing.T) {
 					ID:  password,
 					TTL: 21600,
 					Portal: &api.Portal{
-						Username:   username,
-						ID:         resourceID,
-						Kubeconfig: &api.Kubeconfig{},
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeAdmin,
+							CreatedAt: &now,
+							ExpiresAt: &kubeconfigExpiresAt,
+						},
 					},
 				}
 				checker.AddPortalDocuments(portalDocument)
@@ -60,7 +82,7 @@ func TestNew(t *testing.T) {
 			wantHeaders: http.Header{
 				"Content-Disposition": []string{`attachment; filename="cluster.kubeconfig"`},
 			},
-			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+			wantBody: kubeconfig("default"),
 		},
 		{
 			name:     "success - elevated",
@@ -73,7 +95,91 @@ func TestNew(t *testing.T) {
 						Username: username,
 						ID:       resourceID,
 						Kubeconfig: &api.Kubeconfig{
-							Elevated: true,
+							Elevated:  true,
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeAdmin,
+							CreatedAt: &now,
+							ExpiresAt: &kubeconfigExpiresAt,
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
+			},
+			wantBody: kubeconfig("default"),
+		},
+		{
+			name: "success - just-in-time elevated",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				grant := &api.PortalDocument{
+					ID: "00000000-0000-0000-0000-000000000001",
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						ElevatedAccess: &api.ElevatedAccess{
+							State:     api.ElevatedAccessStateApproved,
+							ExpiresAt: &expiresAt,
+						},
+					},
+				}
+				fixture.AddPortalDocuments(grant)
+				checker.AddPortalDocuments(grant)
+
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Elevated:  true,
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeAdmin,
+							CreatedAt: &now,
+							ExpiresAt: &kubeconfigExpiresAt,
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
+			},
+			wantBody: kubeconfig("default"),
+		},
+		{
+			name: "success - just-in-time elevated, grant expires first",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				grant := &api.PortalDocument{
+					ID: "00000000-0000-0000-0000-000000000001",
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						ElevatedAccess: &api.ElevatedAccess{
+							State:     api.ElevatedAccessStateApproved,
+							ExpiresAt: &shortExpiresAt,
+						},
+					},
+				}
+				fixture.AddPortalDocuments(grant)
+				checker.AddPortalDocuments(grant)
+
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 7200,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Elevated:  true,
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeAdmin,
+							CreatedAt: &now,
+							ExpiresAt: &shortExpiresAt,
 						},
 					},
 				}
@@ -83,7 +189,85 @@ func TestNew(t *testing.T) {
 			wantHeaders: http.Header{
 				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
 			},
-			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+			wantBody: kubeconfig("default"),
+		},
+		{
+			name: "success - read-only",
+			body: `{"scope": "readonly"}`,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeReadOnly,
+							CreatedAt: &now,
+							ExpiresAt: &kubeconfigExpiresAt,
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-readonly.kubeconfig"`},
+			},
+			wantBody: kubeconfig("default"),
+		},
+		{
+			name: "success - namespace",
+			body: `{"scope": "Namespace", "namespace": "my-app"}`,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							TokenID:   tokenID,
+							Scope:     api.KubeconfigScopeNamespace,
+							Namespace: "my-app",
+							CreatedAt: &now,
+							ExpiresAt: &kubeconfigExpiresAt,
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-my-app.kubeconfig"`},
+			},
+			wantBody: kubeconfig("my-app"),
+		},
+		{
+			name:           "namespace - elevated",
+			body:           `{"scope": "Namespace", "namespace": "my-app"}`,
+			elevated:       true,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "The Namespace scope is not available for elevated kubeconfigs.\n",
+		},
+		{
+			name:           "invalid namespace",
+			body:           `{"scope": "Namespace", "namespace": "My_App"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "invalid namespace \"My_App\"\n",
+		},
+		{
+			name:           "invalid scope",
+			body:           `{"scope": "root"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "invalid scope \"root\"\n",
+		},
+		{
+			name:           "invalid body",
+			body:           `{`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "Bad Request\n",
 		},
 		{
 			name: "bad path",
@@ -128,7 +312,7 @@ func TestNew(t *testing.T) {
 				ctx = context.WithValue(ctx, middleware.ContextKeyGroups, []string(nil))
 			}
 			r, err := http.NewRequestWithContext(ctx, http.MethodPost,
-				"https://localhost:8444"+resourceID+"/kubeconfig/new", nil)
+				"https://localhost:8444"+resourceID+"/kubeconfig/new", strings.NewReader(tt.body))
 			if err != nil {
 				panic(err)
 			}
@@ -148,7 +332,8 @@ func TestNew(t *testing.T) {
 			_, audit := testlog.NewAudit()
 			_, baseLog := testlog.New()
 			_, baseAccessLog := testlog.New()
-			k := New(baseLog, audit, _env, baseAccessLog, servingCert, elevatedGroupIDs, nil, dbPortal, nil)
+			k := New(baseLog, audit, _env, baseAccessLog, servingCert, elevatedaccess.New(baseLog, baseAccessLog, elevatedGroupIDs, dbPortal), nil, dbPortal, nil)
+			k.now = func() time.Time { return now }
 
 			if tt.r != nil {
 				tt.r(r)
@@ -58,6 +58,12 @@ func (k *Kubeconfig) director(r *http.Request) {
 		return
 	}
 
+	path := "/" + strings.Join(strings.Split(r.URL.Path, "/")[11:], "/")
+	if !allowed(portalDoc.Portal.Kubeconfig, r.Method, path) {
+		k.error(r, http.StatusForbidden, nil)
+		return
+	}
+
 	key := struct {
 		resourceID string
 		elevated   bool
@@ -81,7 +87,7 @@ func (k *Kubeconfig) director(r *http.Request) {
 	r.RequestURI = ""
 	r.URL.Scheme = "https"
 	r.URL.Host = "kubernetes:6443"
-	r.URL.Path = "/" + strings.Join(strings.Split(r.URL.Path, "/")[11:], "/")
+	r.URL.Path = path
 	r.Header.Del("Authorization")
 	r.Host = r.URL.Host
 
@@ -294,6 +294,29 @@ func TestProxy(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			wantBody:       "Bad Request\n",
 		},
+		{
+			name: "forbidden by scope",
+			r: func(r *http.Request) {
+				r.Method = http.MethodPost
+			},
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  token,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Scope: api.KubeconfigScopeReadOnly,
+						},
+					},
+				}
+				fixture.AddPortalDocuments(portalDocument)
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       "Forbidden\n",
+		},
 		{
 			name: "sad portal database",
 			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, openShiftClustersClient *cosmosdb.FakeOpenShiftClusterDocumentClient, portalClient *cosmosdb.FakePortalDocumentClient) {
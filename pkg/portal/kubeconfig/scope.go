@@ -0,0 +1,129 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+var rxNamespace = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// requestInfo is the part of a Kubernetes API request which is needed to
+// enforce kubeconfig scopes
+type requestInfo struct {
+	isResourceRequest bool
+	verb              string
+	namespace         string
+	resource          string
+	subresource       string
+}
+
+// parseRequestInfo parses an API server path of the form
+// /api/{version}/[{verb}/][namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+// or /apis/{group}/{version}/..., in the same way as the API server does.
+// {verb} is one of the legacy watch and proxy prefixes.  Other paths,
+// including /api and /apis discovery, are non-resource requests.
+func parseRequestInfo(path string) *requestInfo {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	var prefix int
+	switch parts[0] {
+	case "api":
+		prefix = 2
+	case "apis":
+		prefix = 3
+	default:
+		return &requestInfo{}
+	}
+
+	if len(parts) <= prefix {
+		return &requestInfo{}
+	}
+
+	info := &requestInfo{isResourceRequest: true}
+	parts = parts[prefix:]
+
+	switch parts[0] {
+	case "watch", "proxy":
+		info.verb = parts[0]
+		parts = parts[1:]
+		if len(parts) == 0 {
+			return info
+		}
+	}
+
+	if parts[0] == "namespaces" && len(parts) > 1 {
+		info.namespace = parts[1]
+
+		// /namespaces/{namespace} itself is a request for the namespace
+		if len(parts) == 2 {
+			info.resource = "namespaces"
+			return info
+		}
+
+		parts = parts[2:]
+	}
+
+	info.resource = parts[0]
+	if len(parts) > 2 {
+		info.subresource = parts[2]
+	}
+
+	return info
+}
+
+// allowed returns true if the scope of kubeconfig permits a request with the
+// given method to the given API server path
+func allowed(kubeconfig *api.Kubeconfig, method, path string) bool {
+	info := parseRequestInfo(path)
+
+	switch kubeconfig.Scope {
+	case "", api.KubeconfigScopeAdmin:
+		return true
+
+	case api.KubeconfigScopeReadOnly:
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			return false
+		}
+
+		if info.verb == "proxy" {
+			return false
+		}
+
+		switch info.subresource {
+		case "exec", "attach", "portforward", "proxy":
+			return false
+		}
+
+		return info.resource != "secrets"
+
+	case api.KubeconfigScopeNamespace:
+		// the namespace scope only limits which paths are proxied; it is not
+		// a boundary for an identity which may, for example, create
+		// privileged pods, so it is refused for elevated kubeconfigs
+		if kubeconfig.Elevated {
+			return false
+		}
+
+		if !info.isResourceRequest {
+			// discovery, /version, /openapi etc.
+			return method == http.MethodGet || method == http.MethodHead
+		}
+
+		if kubeconfig.Namespace == "" || info.namespace != kubeconfig.Namespace {
+			return false
+		}
+
+		// the namespace itself may be read but not changed
+		return info.resource != "namespaces" || method == http.MethodGet || method == http.MethodHead
+	}
+
+	return false
+}
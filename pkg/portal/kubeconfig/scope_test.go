@@ -0,0 +1,201 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+func TestAllowed(t *testing.T) {
+	admin := &api.Kubeconfig{Scope: api.KubeconfigScopeAdmin}
+	legacy := &api.Kubeconfig{}
+	readOnly := &api.Kubeconfig{Scope: api.KubeconfigScopeReadOnly}
+	namespace := &api.Kubeconfig{Scope: api.KubeconfigScopeNamespace, Namespace: "my-app"}
+
+	for _, tt := range []struct {
+		name       string
+		kubeconfig *api.Kubeconfig
+		method     string
+		path       string
+		want       bool
+	}{
+		{
+			name:       "admin may delete nodes",
+			kubeconfig: admin,
+			method:     http.MethodDelete,
+			path:       "/api/v1/nodes/node-0",
+			want:       true,
+		},
+		{
+			name:       "token without scope is admin",
+			kubeconfig: legacy,
+			method:     http.MethodPost,
+			path:       "/api/v1/namespaces/default/pods/pod/exec",
+			want:       true,
+		},
+		{
+			name:       "read-only may list pods",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/default/pods",
+			want:       true,
+		},
+		{
+			name:       "read-only may get cluster operators",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/apis/config.openshift.io/v1/clusteroperators/dns",
+			want:       true,
+		},
+		{
+			name:       "read-only may use discovery",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/apis",
+			want:       true,
+		},
+		{
+			name:       "read-only may not patch",
+			kubeconfig: readOnly,
+			method:     http.MethodPatch,
+			path:       "/apis/apps/v1/namespaces/default/deployments/app",
+		},
+		{
+			name:       "read-only may not read secrets",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/default/secrets/secret",
+		},
+		{
+			name:       "read-only may not list secrets across namespaces",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/secrets",
+		},
+		{
+			name:       "read-only may watch pods with the legacy watch path",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/watch/namespaces/default/pods",
+			want:       true,
+		},
+		{
+			name:       "read-only may not watch secrets with the legacy watch path",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/watch/secrets",
+		},
+		{
+			name:       "read-only may not watch namespaced secrets with the legacy watch path",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/watch/namespaces/x/secrets",
+		},
+		{
+			name:       "read-only may not use the legacy proxy path",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/proxy/namespaces/default/services/svc",
+		},
+		{
+			name:       "read-only may not exec",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/default/pods/pod/exec",
+		},
+		{
+			name:       "read-only may not port-forward",
+			kubeconfig: readOnly,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/default/pods/pod/portforward",
+		},
+		{
+			name:       "namespace may create in its namespace",
+			kubeconfig: namespace,
+			method:     http.MethodPost,
+			path:       "/apis/apps/v1/namespaces/my-app/deployments",
+			want:       true,
+		},
+		{
+			name:       "namespace may exec in its namespace",
+			kubeconfig: namespace,
+			method:     http.MethodPost,
+			path:       "/api/v1/namespaces/my-app/pods/pod/exec",
+			want:       true,
+		},
+		{
+			name:       "namespace may get its namespace",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/my-app",
+			want:       true,
+		},
+		{
+			name:       "namespace may use discovery",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/version",
+			want:       true,
+		},
+		{
+			name:       "namespace may watch in its namespace with the legacy watch path",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/watch/namespaces/my-app/pods",
+			want:       true,
+		},
+		{
+			name:       "namespace may not watch across namespaces with the legacy watch path",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/watch/pods",
+		},
+		{
+			name:       "elevated namespace kubeconfigs are refused",
+			kubeconfig: &api.Kubeconfig{Scope: api.KubeconfigScopeNamespace, Namespace: "my-app", Elevated: true},
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/my-app/pods",
+		},
+		{
+			name:       "namespace may not delete its namespace",
+			kubeconfig: namespace,
+			method:     http.MethodDelete,
+			path:       "/api/v1/namespaces/my-app",
+		},
+		{
+			name:       "namespace may not access other namespaces",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/namespaces/openshift-etcd/pods",
+		},
+		{
+			name:       "namespace may not list across namespaces",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/pods",
+		},
+		{
+			name:       "namespace may not access cluster-scoped resources",
+			kubeconfig: namespace,
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes",
+		},
+		{
+			name:       "unknown scope",
+			kubeconfig: &api.Kubeconfig{Scope: "Unknown"},
+			method:     http.MethodGet,
+			path:       "/api/v1/nodes",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allowed(tt.kubeconfig, tt.method, tt.path)
+			if got != tt.want {
+				t.Error(got)
+			}
+		})
+	}
+}
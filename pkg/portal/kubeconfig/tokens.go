@@ -0,0 +1,165 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+)
+
+// Token describes an outstanding kubeconfig token.  It does not contain the
+// token itself.
+type Token struct {
+	ID        string              `json:"id"`
+	Username  string              `json:"username"`
+	Elevated  bool                `json:"elevated"`
+	Scope     api.KubeconfigScope `json:"scope"`
+	Namespace string              `json:"namespace,omitempty"`
+	CreatedAt *time.Time          `json:"createdAt,omitempty"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+}
+
+// Tokens lists the outstanding kubeconfig tokens of a cluster, newest first.
+// Users with elevated access to the cluster see every token; other users see
+// their own.
+func (k *Kubeconfig) Tokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, all, ok := k.tokensAccess(w, r)
+	if !ok {
+		return
+	}
+
+	docs, err := k.DbPortal.ListKubeconfigs(ctx, resourceID)
+	if err != nil {
+		k.internalServerError(w, err)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	tokens := make([]*Token, 0, len(docs.PortalDocuments))
+	for _, doc := range docs.PortalDocuments {
+		if !all && doc.Portal.Username != username {
+			continue
+		}
+
+		scope := doc.Portal.Kubeconfig.Scope
+		if scope == "" {
+			scope = api.KubeconfigScopeAdmin
+		}
+
+		tokens = append(tokens, &Token{
+			ID:        doc.Portal.Kubeconfig.TokenID,
+			Username:  doc.Portal.Username,
+			Elevated:  doc.Portal.Kubeconfig.Elevated,
+			Scope:     scope,
+			Namespace: doc.Portal.Kubeconfig.Namespace,
+			CreatedAt: doc.Portal.Kubeconfig.CreatedAt,
+			ExpiresAt: doc.Portal.Kubeconfig.ExpiresAt,
+		})
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].CreatedAt == nil || tokens[j].CreatedAt == nil {
+			return tokens[j].CreatedAt == nil && tokens[i].CreatedAt != nil
+		}
+		return tokens[i].CreatedAt.After(*tokens[j].CreatedAt)
+	})
+
+	b, err := json.MarshalIndent(tokens, "", "    ")
+	if err != nil {
+		k.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// Revoke deletes an outstanding kubeconfig token, after which the proxy
+// rejects it.  Users with elevated access to the cluster may revoke any token;
+// other users may revoke their own.
+func (k *Kubeconfig) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, all, ok := k.tokensAccess(w, r)
+	if !ok {
+		return
+	}
+
+	// .../openshiftclusters/{resourceName}/kubeconfig/tokens/{tokenId}
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 12 || parts[11] == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	tokenID := parts[11]
+
+	docs, err := k.DbPortal.ListKubeconfigs(ctx, resourceID)
+	if err != nil {
+		k.internalServerError(w, err)
+		return
+	}
+
+	username := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	var doc *api.PortalDocument
+	for _, d := range docs.PortalDocuments {
+		if d.Portal.Kubeconfig.TokenID == tokenID && (all || d.Portal.Username == username) {
+			doc = d
+			break
+		}
+	}
+	if doc == nil {
+		http.Error(w, "Token not found", http.StatusNotFound)
+		return
+	}
+
+	err = k.DbPortal.Delete(ctx, doc)
+	if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		k.internalServerError(w, err)
+		return
+	}
+
+	k.BaseAccessLog.WithFields(logrus.Fields{
+		"resource_id": resourceID,
+		"token_id":    tokenID,
+		"owner":       doc.Portal.Username,
+		"username":    username,
+	}).Print("kubeconfig token revoked")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tokensAccess validates the resource ID in the request path and returns
+// whether the user may see and revoke the tokens of all users
+func (k *Kubeconfig) tokensAccess(w http.ResponseWriter, r *http.Request) (string, bool, bool) {
+	ctx := r.Context()
+
+	resourceID := strings.Join(strings.Split(r.URL.Path, "/")[:9], "/")
+	if !validate.RxClusterID.MatchString(resourceID) {
+		http.Error(w, fmt.Sprintf("invalid resourceId %q", resourceID), http.StatusBadRequest)
+		return "", false, false
+	}
+
+	elevated, _, err := k.elevatedAccess.Check(ctx, resourceID)
+	if err != nil {
+		k.internalServerError(w, err)
+		return "", false, false
+	}
+
+	return resourceID, elevated, true
+}
@@ -0,0 +1,251 @@
+package kubeconfig
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/util/responsewriter"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestTokens(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+	elevatedGroupIDs := []string{"10000000-0000-0000-0000-000000000000"}
+	username := "username"
+
+	createdAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	laterCreatedAt := createdAt.Add(time.Hour)
+	expiresAt := createdAt.Add(6 * time.Hour)
+
+	ownToken := &api.PortalDocument{
+		ID: "00000000-0000-0000-0000-000000000001",
+		Portal: &api.Portal{
+			Username: username,
+			ID:       resourceID,
+			Kubeconfig: &api.Kubeconfig{
+				TokenID:   "20000000-0000-0000-0000-000000000001",
+				Scope:     api.KubeconfigScopeReadOnly,
+				CreatedAt: &createdAt,
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+	otherToken := &api.PortalDocument{
+		ID: "00000000-0000-0000-0000-000000000002",
+		Portal: &api.Portal{
+			Username: "other",
+			ID:       resourceID,
+			Kubeconfig: &api.Kubeconfig{
+				TokenID:   "20000000-0000-0000-0000-000000000002",
+				Elevated:  true,
+				CreatedAt: &laterCreatedAt,
+				ExpiresAt: &expiresAt,
+			},
+		},
+	}
+
+	ownTokenJSON := `    {
+        "id": "20000000-0000-0000-0000-000000000001",
+        "username": "username",
+        "elevated": false,
+        "scope": "ReadOnly",
+        "createdAt": "2022-01-01T00:00:00Z",
+        "expiresAt": "2022-01-01T06:00:00Z"
+    }`
+	otherTokenJSON := `    {
+        "id": "20000000-0000-0000-0000-000000000002",
+        "username": "other",
+        "elevated": true,
+        "scope": "Admin",
+        "createdAt": "2022-01-01T01:00:00Z",
+        "expiresAt": "2022-01-01T06:00:00Z"
+    }`
+
+	for _, tt := range []struct {
+		name           string
+		method         string
+		path           string
+		elevated       bool
+		fixtureChecker func(*testdatabase.Fixture, *testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name:   "list - not elevated sees own tokens",
+			method: http.MethodGet,
+			path:   resourceID + "/kubeconfig/tokens",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken, otherToken)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n" + ownTokenJSON + "\n]",
+		},
+		{
+			name:     "list - elevated sees all tokens, newest first",
+			method:   http.MethodGet,
+			path:     resourceID + "/kubeconfig/tokens",
+			elevated: true,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken, otherToken)
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[\n" + otherTokenJSON + ",\n" + ownTokenJSON + "\n]",
+		},
+		{
+			name:           "list - empty",
+			method:         http.MethodGet,
+			path:           resourceID + "/kubeconfig/tokens",
+			wantStatusCode: http.StatusOK,
+			wantBody:       "[]",
+		},
+		{
+			name:   "list - sad database",
+			method: http.MethodGet,
+			path:   resourceID + "/kubeconfig/tokens",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalClient.SetError(fmt.Errorf("sad"))
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "Internal Server Error\n",
+		},
+		{
+			name:   "revoke - own token",
+			method: http.MethodDelete,
+			path:   resourceID + "/kubeconfig/tokens/20000000-0000-0000-0000-000000000001",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken, otherToken)
+				checker.AddPortalDocuments(otherToken)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "revoke - not elevated may not revoke other users' tokens",
+			method: http.MethodDelete,
+			path:   resourceID + "/kubeconfig/tokens/20000000-0000-0000-0000-000000000002",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken, otherToken)
+				checker.AddPortalDocuments(ownToken, otherToken)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Token not found\n",
+		},
+		{
+			name:     "revoke - elevated may revoke other users' tokens",
+			method:   http.MethodDelete,
+			path:     resourceID + "/kubeconfig/tokens/20000000-0000-0000-0000-000000000002",
+			elevated: true,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken, otherToken)
+				checker.AddPortalDocuments(ownToken)
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "revoke - not found",
+			method: http.MethodDelete,
+			path:   resourceID + "/kubeconfig/tokens/20000000-0000-0000-0000-000000000003",
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				fixture.AddPortalDocuments(ownToken)
+				checker.AddPortalDocuments(ownToken)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "Token not found\n",
+		},
+		{
+			name:           "bad path",
+			method:         http.MethodGet,
+			path:           "/subscriptions/BAD/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/tokens",
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "invalid resourceId \"/subscriptions/BAD/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster\"\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			dbPortal, portalClient := testdatabase.NewFakePortal()
+
+			fixture := testdatabase.NewFixture().
+				WithPortal(dbPortal)
+
+			checker := testdatabase.NewChecker()
+
+			if tt.fixtureChecker != nil {
+				tt.fixtureChecker(fixture, checker, portalClient)
+			}
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx = context.WithValue(ctx, middleware.ContextKeyUsername, username)
+			if tt.elevated {
+				ctx = context.WithValue(ctx, middleware.ContextKeyGroups, elevatedGroupIDs)
+			} else {
+				ctx = context.WithValue(ctx, middleware.ContextKeyGroups, []string(nil))
+			}
+			r, err := http.NewRequestWithContext(ctx, tt.method, "https://localhost:8444"+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			_env := mock_env.NewMockInterface(ctrl)
+
+			_, audit := testlog.NewAudit()
+			_, baseLog := testlog.New()
+			_, baseAccessLog := testlog.New()
+			k := New(baseLog, audit, _env, baseAccessLog, &x509.Certificate{}, elevatedaccess.New(baseLog, baseAccessLog, elevatedGroupIDs, dbPortal), nil, dbPortal, nil)
+
+			router := &mux.Router{}
+			router.NewRoute().Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/kubeconfig/tokens").HandlerFunc(k.Tokens)
+			router.NewRoute().Methods(http.MethodDelete).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/kubeconfig/tokens/{tokenId}").HandlerFunc(k.Revoke)
+
+			w := responsewriter.New(r)
+
+			router.ServeHTTP(w, r)
+
+			portalClient.SetError(nil)
+
+			if tt.method == http.MethodDelete {
+				for _, err = range checker.CheckPortals(portalClient) {
+					t.Error(err)
+				}
+			}
+
+			resp := w.Response()
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Error(resp.StatusCode)
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Errorf("%q", string(b))
+			}
+		})
+	}
+}
@@ -30,11 +30,14 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics"
 	"github.com/Azure/ARO-RP/pkg/portal/assets"
 	"github.com/Azure/ARO-RP/pkg/portal/cluster"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
 	"github.com/Azure/ARO-RP/pkg/portal/kubeconfig"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/prometheus"
 	"github.com/Azure/ARO-RP/pkg/portal/ssh"
 	"github.com/Azure/ARO-RP/pkg/proxy"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 )
@@ -63,12 +66,18 @@ type portal struct {
 
 	groupIDs         []string
 	elevatedGroupIDs []string
+	elevatedAccess   *elevatedaccess.ElevatedAccess
 
+	dbAdminActions      database.AdminActions
+	dbClusterHealth     database.ClusterHealth
 	dbPortal            database.Portal
 	dbOpenShiftClusters database.OpenShiftClusters
 
 	dialer proxy.Dialer
 
+	aead       encryption.AEAD
+	recordings blobstore.Store
+
 	templateV1 *template.Template
 	templateV2 *template.Template
 
@@ -94,9 +103,13 @@ func NewPortal(env env.Core,
 	sshKey *rsa.PrivateKey,
 	groupIDs []string,
 	elevatedGroupIDs []string,
+	dbAdminActions database.AdminActions,
+	dbClusterHealth database.ClusterHealth,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
 	dialer proxy.Dialer,
+	aead encryption.AEAD,
+	recordings blobstore.Store,
 	m metrics.Emitter,
 ) Runnable {
 	return &portal{
@@ -119,12 +132,18 @@ func NewPortal(env env.Core,
 
 		groupIDs:         groupIDs,
 		elevatedGroupIDs: elevatedGroupIDs,
+		elevatedAccess:   elevatedaccess.New(log, baseAccessLog, elevatedGroupIDs, dbPortal),
 
+		dbAdminActions:      dbAdminActions,
+		dbClusterHealth:     dbClusterHealth,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbPortal:            dbPortal,
 
 		dialer: dialer,
 
+		aead:       aead,
+		recordings: recordings,
+
 		m: m,
 	}
 }
@@ -177,7 +196,7 @@ func (p *portal) setupRouter(kconfig *kubeconfig.Kubeconfig, prom *prometheus.Pr
 }
 
 func (p *portal) setupServices() (*kubeconfig.Kubeconfig, *prometheus.Prometheus, *ssh.SSH, error) {
-	ssh, err := ssh.New(p.env, p.log, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
+	ssh, err := ssh.New(p.env, p.log, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedAccess, p.dbOpenShiftClusters, p.dbPortal, p.dialer, p.aead, p.recordings)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -187,7 +206,7 @@ func (p *portal) setupServices() (*kubeconfig.Kubeconfig, *prometheus.Prometheus
 		return nil, nil, nil, err
 	}
 
-	k := kubeconfig.New(p.log, p.audit, p.env, p.baseAccessLog, p.servingCerts[0], p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
+	k := kubeconfig.New(p.log, p.audit, p.env, p.baseAccessLog, p.servingCerts[0], p.elevatedAccess, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
 
 	prom := prometheus.New(p.log, p.dbOpenShiftClusters, p.dialer)
 
@@ -302,6 +321,8 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	// Cluster-specific routes
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/clusteroperators").HandlerFunc(p.clusterOperators)
 	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}").HandlerFunc(p.clusterInfo)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/health").HandlerFunc(p.clusterHealth)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/actionhistory").HandlerFunc(p.clusterActionHistory)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/nodes").HandlerFunc(p.nodes)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machines").HandlerFunc(p.machines)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machine-sets").HandlerFunc(p.machineSets)
@@ -321,10 +342,20 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	//kubeconfig
 	if kconfig != nil {
 		r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/kubeconfig/new").HandlerFunc(kconfig.New)
+		r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/kubeconfig/tokens").HandlerFunc(kconfig.Tokens)
+		r.Methods(http.MethodDelete).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/kubeconfig/tokens/{tokenId}").HandlerFunc(kconfig.Revoke)
 	}
 
+	// just-in-time elevated access
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/elevatedaccess").HandlerFunc(p.elevatedAccess.List)
+	r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/elevatedaccess/new").HandlerFunc(p.elevatedAccess.New)
+	r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/elevatedaccess/{requestId}/approve").HandlerFunc(p.elevatedAccess.Approve)
+	r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/elevatedaccess/{requestId}/deny").HandlerFunc(p.elevatedAccess.Deny)
+
 	// ssh
 	r.Methods(http.MethodPost).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/new").HandlerFunc(sshStruct.New)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/recordings").HandlerFunc(sshStruct.Recordings)
+	r.Methods(http.MethodGet).Path("/subscriptions/{subscriptionId}/resourcegroups/{resourceGroupName}/providers/microsoft.redhatopenshift/openshiftclusters/{resourceName}/ssh/recordings/{recording}").HandlerFunc(sshStruct.Recording)
 }
 
 func (p *portal) index(w http.ResponseWriter, r *http.Request) {
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/noop"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
 	"github.com/Azure/ARO-RP/pkg/util/log/audit"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
 	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
@@ -90,7 +91,7 @@ func TestSecurity(t *testing.T) {
 		},
 	}
 
-	p := NewPortal(_env, portalAuditLog, portalLog, portalAccessLog, l, sshl, nil, "", serverkey, servercerts, "", nil, nil, make([]byte, 32), sshkey, nil, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, nil, &noop.Noop{})
+	p := NewPortal(_env, portalAuditLog, portalLog, portalAccessLog, l, sshl, nil, "", serverkey, servercerts, "", nil, nil, make([]byte, 32), sshkey, nil, elevatedGroupIDs, nil, nil, dbOpenShiftClusters, dbPortal, nil, nil, blobstore.NewMemory(), &noop.Noop{})
 	go func() {
 		err := p.Run(ctx)
 		if err != nil {
@@ -104,6 +105,7 @@ func TestSecurity(t *testing.T) {
 		checkResponse                 func(*testing.T, bool, bool, *http.Response)
 		unauthenticatedWantStatusCode int
 		authenticatedWantStatusCode   int
+		elevatedWantStatusCode        int
 		wantAuditOperation            string
 		wantAuditTargetResources      []audit.TargetResource
 	}{
@@ -252,6 +254,47 @@ func TestSecurity(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "/ssh/recordings",
+			request: func() (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, "https://server/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroupName/providers/microsoft.redhatopenshift/openshiftclusters/resourceName/ssh/recordings", nil)
+			},
+			authenticatedWantStatusCode: http.StatusForbidden,
+			elevatedWantStatusCode:      http.StatusOK,
+			wantAuditOperation:          "GET /subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/ssh/recordings",
+			wantAuditTargetResources: []audit.TargetResource{
+				{
+					TargetResourceType: "ssh",
+					TargetResourceName: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/ssh/recordings",
+				},
+			},
+		},
+		{
+			name: "/elevatedaccess",
+			request: func() (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, "https://server/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroupName/providers/microsoft.redhatopenshift/openshiftclusters/resourceName/elevatedaccess", nil)
+			},
+			wantAuditOperation: "GET /subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/elevatedaccess",
+			wantAuditTargetResources: []audit.TargetResource{
+				{
+					TargetResourceType: "elevatedaccess",
+					TargetResourceName: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/elevatedaccess",
+				},
+			},
+		},
+		{
+			name: "/kubeconfig/tokens",
+			request: func() (*http.Request, error) {
+				return http.NewRequest(http.MethodGet, "https://server/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroupName/providers/microsoft.redhatopenshift/openshiftclusters/resourceName/kubeconfig/tokens", nil)
+			},
+			wantAuditOperation: "GET /subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/kubeconfig/tokens",
+			wantAuditTargetResources: []audit.TargetResource{
+				{
+					TargetResourceType: "kubeconfig",
+					TargetResourceName: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroupname/providers/microsoft.redhatopenshift/openshiftclusters/resourcename/kubeconfig/tokens",
+				},
+			},
+		},
 		{
 			name: "/doesnotexist",
 			request: func() (*http.Request, error) {
@@ -283,6 +326,10 @@ func TestSecurity(t *testing.T) {
 				wantStatusCode: tt.authenticatedWantStatusCode,
 			},
 		} {
+			if tt2.elevated && tt.elevatedWantStatusCode != 0 {
+				tt2.wantStatusCode = tt.elevatedWantStatusCode
+			}
+
 			t.Run(tt2.name+tt.name, func(t *testing.T) {
 				defer auditHook.Reset()
 
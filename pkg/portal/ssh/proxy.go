@@ -190,13 +190,18 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 	}
 
 	// Proxy channels and requests between the two connections.
-	return s.proxyConn(ctx, accessLog, keyring, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
+	return s.proxyConn(ctx, accessLog, keyring, portalDoc.Portal, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
 }
 
 // proxyConn handles incoming new channel and administrative requests.  It calls
 // newChannel to handle new channels, each on a new goroutine.
-func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
-	timer := time.NewTimer(sshTimeout)
+func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, portal *api.Portal, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
+	timeout := sshTimeout
+	if portal.SSH.ExpiresAt != nil && time.Until(*portal.SSH.ExpiresAt) < timeout {
+		timeout = time.Until(*portal.SSH.ExpiresAt)
+	}
+
+	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
 	var sessionOpened bool
@@ -219,8 +224,14 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 				sessionOpened = true
 			}
 
+			// record the terminal stream of SRE->cluster sessions
+			var rec *recorder
+			if s.recordings != nil && nc.ChannelType() == "session" {
+				rec = newRecorder(time.Now, portal, fmt.Sprintf("master-%d", portal.SSH.Master))
+			}
+
 			go func() {
-				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession)
+				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession, rec)
 			}()
 
 		case nc := <-downstreamNewChannels:
@@ -235,7 +246,7 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 				}()
 			} else {
 				go func() {
-					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false)
+					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false, nil)
 				}()
 			}
 
@@ -276,8 +287,9 @@ func (s *SSH) handleAgent(accessLog *logrus.Entry, nc cryptossh.NewChannel, keyr
 
 // newChannel handles an incoming request to create a new channel.  If the
 // channel creation is successful, it calls proxyChannel to proxy the channel
-// between SRE and cluster.
-func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool) error {
+// between SRE and cluster.  If rec is not nil, the channel is recorded and the
+// recording is streamed to the recordings store until the channel closes.
+func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool, rec *recorder) error {
 	defer recover.Panic(s.log)
 
 	ch2, rs2, err := downstreamConn.OpenChannel(nc.ChannelType(), nc.ExtraData())
@@ -307,7 +319,33 @@ func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc crypto
 		go s.keepAliveConn(ctx, ch1)
 	}
 
-	return s.proxyChannel(ch1, ch2, rs1, rs2)
+	if rec != nil {
+		streamCtx, stopStreaming := context.WithCancel(context.Background())
+		streamDone := make(chan struct{})
+		go func() {
+			defer recover.Panic(s.log)
+			defer close(streamDone)
+			s.streamRecording(streamCtx, channelLog, rec)
+		}()
+
+		defer func() {
+			stopStreaming()
+			<-streamDone
+
+			saveCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			err := s.saveRecording(saveCtx, rec)
+			if err != nil {
+				channelLog.Error(err)
+				return
+			}
+
+			channelLog.WithField("recording", rec.id).Print("recorded")
+		}()
+	}
+
+	return s.proxyChannel(ch1, ch2, rs1, rs2, rec)
 }
 
 func (s *SSH) proxyGlobalRequest(r *cryptossh.Request, c cryptossh.Conn) error {
@@ -328,15 +366,24 @@ func (s *SSH) proxyRequest(r *cryptossh.Request, ch cryptossh.Channel) error {
 	return r.Reply(ok, nil)
 }
 
-func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request) error {
+// proxyChannel proxies a channel and its requests between ch1 and ch2.  If rec
+// is not nil, it records the ch1->ch2 stream as input and the ch2->ch1 stream
+// as output.
+func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request, rec *recorder) error {
 	g := errgroup.Group{}
 
+	var r1, r2 io.Reader = ch1, ch2
+	if rec != nil {
+		r1 = io.TeeReader(ch1, rec.writer("i"))
+		r2 = io.TeeReader(ch2, rec.writer("o"))
+	}
+
 	g.Go(func() error {
 		defer recover.Panic(s.log)
 		defer func() {
 			_ = ch1.CloseWrite()
 		}()
-		_, err := io.Copy(ch1, ch2)
+		_, err := io.Copy(ch1, r2)
 		if err != nil {
 			return err
 		}
@@ -348,7 +395,7 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer func() {
 			_ = ch2.CloseWrite()
 		}()
-		_, err := io.Copy(ch2, ch1)
+		_, err := io.Copy(ch2, r1)
 		if err != nil {
 			return err
 		}
@@ -359,6 +406,10 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer recover.Panic(s.log)
 
 		for r := range rs1 {
+			if rec != nil {
+				rec.request(r)
+			}
+
 			err := s.proxyRequest(r, ch2)
 			if err != nil {
 				break
@@ -0,0 +1,491 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+// This file records the terminal stream of SSH sessions in asciicast v2
+// format (https://docs.asciinema.org/manual/asciicast/v2/).  Every session
+// channel which an SRE opens is recorded separately and stored in the
+// recordings blob store under the resource ID of the cluster, so that it can
+// be replayed from the portal.  The recording is streamed to the store while
+// the session runs: it is saved as a series of separately encrypted chunks, so
+// that at most the last few seconds of a session are lost if the portal stops
+// before the session ends.
+
+const (
+	// maxRecordingSize caps the recorded terminal stream of a session.  Once
+	// it is reached, the session continues but is no longer recorded.
+	maxRecordingSize = 64 << 20
+
+	// recordingFlushInterval is how often the events recorded since the
+	// last chunk are saved, and recordingChunkSize is how much of them may
+	// be held before they are saved early
+	recordingFlushInterval = 10 * time.Second
+	recordingChunkSize     = 1 << 20
+
+	recordingTimeFormat = "20060102-150405"
+)
+
+var rxRecordingID = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// asciicastHeader is the header line of an asciicast v2 recording.  Username,
+// ResourceID and Hostname are not part of the format; players ignore them.
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+
+	Username   string `json:"username"`
+	ResourceID string `json:"resourceId"`
+	Hostname   string `json:"hostname"`
+}
+
+// recorder records the terminal stream of a session channel
+type recorder struct {
+	mu sync.Mutex
+
+	now   func() time.Time
+	id    string
+	start time.Time
+
+	header    asciicastHeader
+	events    bytes.Buffer      // events not saved yet
+	size      int               // size of all events recorded
+	pending   map[string][]byte // incomplete UTF-8 sequences, by event code
+	truncated bool
+
+	// flushMu serialises flushes.  chunk is the number of chunks saved, and
+	// truncatedSaved whether the last of them was marked truncated.
+	flushMu        sync.Mutex
+	chunk          int
+	truncatedSaved bool
+
+	// full is signalled when events reaches recordingChunkSize
+	full chan struct{}
+}
+
+func newRecorder(now func() time.Time, portal *api.Portal, hostname string) *recorder {
+	start := now()
+
+	return &recorder{
+		now:   now,
+		id:    start.UTC().Format(recordingTimeFormat) + "-" + uuid.DefaultGenerator.Generate(),
+		start: start,
+		header: asciicastHeader{
+			Version:    2,
+			Width:      80,
+			Height:     24,
+			Timestamp:  start.Unix(),
+			Title:      fmt.Sprintf("%s@%s %s", portal.Username, hostname, portal.ID),
+			Username:   portal.Username,
+			ResourceID: portal.ID,
+			Hostname:   hostname,
+		},
+		pending: map[string][]byte{},
+		full:    make(chan struct{}, 1),
+	}
+}
+
+// event records data on the stream identified by code: "o" for output, "i"
+// for input and "r" for terminal resizes.  Any incomplete UTF-8 sequence at
+// the end of data is held back until the next event on the same stream.
+func (r *recorder) event(code string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.truncated {
+		return
+	}
+
+	data = append(r.pending[code], data...)
+
+	n := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				n = i
+			}
+			break
+		}
+	}
+	r.pending[code] = append([]byte(nil), data[n:]...)
+
+	if n == 0 {
+		return
+	}
+
+	b, err := json.Marshal([]interface{}{r.now().Sub(r.start).Seconds(), code, string(data[:n])})
+	if err != nil {
+		return
+	}
+
+	if r.size+len(b)+1 > maxRecordingSize {
+		r.truncated = true
+		return
+	}
+
+	r.events.Write(b)
+	r.events.WriteByte('\n')
+	r.size += len(b) + 1
+
+	if r.events.Len() >= recordingChunkSize {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+type recorderWriter struct {
+	r    *recorder
+	code string
+}
+
+func (w *recorderWriter) Write(b []byte) (int, error) {
+	w.r.event(w.code, b)
+	return len(b), nil
+}
+
+// writer returns an io.Writer which records what is written to it as events
+// with the given code
+func (r *recorder) writer(code string) io.Writer {
+	return &recorderWriter{r: r, code: code}
+}
+
+// request records the terminal size and command from SRE->cluster channel
+// requests
+func (r *recorder) request(req *cryptossh.Request) {
+	switch req.Type {
+	case "pty-req":
+		var ptyReq struct {
+			Term    string
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+			Modes   string
+		}
+		if cryptossh.Unmarshal(req.Payload, &ptyReq) != nil {
+			return
+		}
+
+		r.mu.Lock()
+		r.header.Width = int(ptyReq.Columns)
+		r.header.Height = int(ptyReq.Rows)
+		r.header.Env = map[string]string{"TERM": ptyReq.Term}
+		r.mu.Unlock()
+
+	case "window-change":
+		var windowChange struct {
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+		}
+		if cryptossh.Unmarshal(req.Payload, &windowChange) != nil {
+			return
+		}
+
+		r.event("r", []byte(fmt.Sprintf("%dx%d", windowChange.Columns, windowChange.Rows)))
+
+	case "exec":
+		var exec struct {
+			Command string
+		}
+		if cryptossh.Unmarshal(req.Payload, &exec) != nil {
+			return
+		}
+
+		r.mu.Lock()
+		r.header.Command = exec.Command
+		r.mu.Unlock()
+	}
+}
+
+// flush passes the events recorded since the last flush to save as the next
+// chunk of the recording.  The first chunk starts with the asciicast v2
+// header, so the chunks of a recording concatenate to a valid asciicast.  The
+// events are only discarded once save succeeds, so that a failed flush is
+// retried by the next one.
+func (r *recorder) flush(save func(chunk int, b []byte, truncated bool) error) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	chunk, n, truncated := r.chunk, r.events.Len(), r.truncated
+
+	var b []byte
+	if chunk == 0 {
+		h, err := json.Marshal(r.header)
+		if err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		b = append(h, '\n')
+	}
+	b = append(b, r.events.Bytes()...)
+	r.mu.Unlock()
+
+	if chunk > 0 && n == 0 && truncated == r.truncatedSaved {
+		return nil
+	}
+
+	err := save(chunk, b, truncated)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.events.Next(n)
+	r.mu.Unlock()
+
+	r.chunk++
+	r.truncatedSaved = truncated
+
+	return nil
+}
+
+// recordingPrefix returns the blob name prefix of the recordings of a cluster
+func recordingPrefix(resourceID string) string {
+	return strings.TrimPrefix(strings.ToLower(resourceID), "/") + "/"
+}
+
+// recordingChunkName returns the blob name of a chunk of a recording.  The
+// chunks of a recording share the prefix <recordingPrefix><id>/ and sort in
+// order.
+func recordingChunkName(resourceID, id string, chunk int) string {
+	return fmt.Sprintf("%s%s/%08d.cast", recordingPrefix(resourceID), id, chunk)
+}
+
+// saveRecording encrypts the events recorded since it was last called and
+// writes them to the recordings store as the next chunk of the recording
+func (s *SSH) saveRecording(ctx context.Context, r *recorder) error {
+	return r.flush(func(chunk int, b []byte, truncated bool) error {
+		b, err := s.aead.Seal(b)
+		if err != nil {
+			return err
+		}
+
+		return s.recordings.Put(ctx, recordingChunkName(r.header.ResourceID, r.id, chunk), b, map[string]string{
+			"username":  r.header.Username,
+			"hostname":  r.header.Hostname,
+			"starttime": r.start.UTC().Format(time.RFC3339),
+			"truncated": fmt.Sprint(truncated),
+		})
+	})
+}
+
+// streamRecording saves the recording every recordingFlushInterval, or as
+// soon as recordingChunkSize of events are held, until ctx is done
+func (s *SSH) streamRecording(ctx context.Context, log *logrus.Entry, r *recorder) {
+	t := time.NewTicker(recordingFlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-r.full:
+		}
+
+		saveCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := s.saveRecording(saveCtx, r)
+		cancel()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// Recording describes a recorded SSH session
+type Recording struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Hostname  string    `json:"hostname"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// Recordings lists the recorded SSH sessions of a cluster, newest first
+func (s *SSH) Recordings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, ok := s.recordingsAccess(w, r)
+	if !ok {
+		return
+	}
+
+	blobs, err := s.recordings.List(ctx, recordingPrefix(resourceID))
+	if err != nil {
+		s.internalServerError(w, err)
+		return
+	}
+
+	// group the chunks of each recording.  A recording whose session is
+	// still open, or whose portal stopped before the session ended, ends at
+	// its last saved chunk.
+	recordings := make([]*Recording, 0, len(blobs))
+	byID := map[string]*Recording{}
+	for _, blob := range blobs {
+		id := strings.TrimPrefix(blob.Name, recordingPrefix(resourceID))
+		if i := strings.IndexByte(id, '/'); i != -1 {
+			id = id[:i]
+		}
+
+		recording := byID[id]
+		if recording == nil {
+			startTime, _ := time.Parse(time.RFC3339, blob.Metadata["starttime"])
+
+			recording = &Recording{
+				ID:        id,
+				Username:  blob.Metadata["username"],
+				Hostname:  blob.Metadata["hostname"],
+				StartTime: startTime,
+			}
+			byID[id] = recording
+			recordings = append(recordings, recording)
+		}
+
+		savedTime := blob.LastModified
+		if t, err := time.Parse(time.RFC3339, blob.Metadata[recordingSavedTimeMetadata]); err == nil {
+			savedTime = t
+		}
+		if savedTime.After(recording.EndTime) {
+			recording.EndTime = savedTime
+		}
+		if blob.Metadata["truncated"] == "true" {
+			recording.Truncated = true
+		}
+	}
+
+	sort.SliceStable(recordings, func(i, j int) bool { return recordings[i].ID > recordings[j].ID })
+
+	b, err := json.MarshalIndent(recordings, "", "    ")
+	if err != nil {
+		s.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// Recording returns a recorded SSH session in asciicast v2 format
+func (s *SSH) Recording(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resourceID, ok := s.recordingsAccess(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+	if !rxRecordingID.MatchString(id) {
+		http.Error(w, fmt.Sprintf("invalid recording %q", id), http.StatusBadRequest)
+		return
+	}
+
+	blobs, err := s.recordings.List(ctx, recordingPrefix(resourceID)+id+"/")
+	if err != nil {
+		s.internalServerError(w, err)
+		return
+	}
+	if len(blobs) == 0 {
+		http.Error(w, "Recording not found", http.StatusNotFound)
+		return
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Name < blobs[j].Name })
+
+	var buf bytes.Buffer
+	for _, blob := range blobs {
+		b, err := s.recordings.Get(ctx, blob.Name)
+		if err != nil {
+			s.internalServerError(w, err)
+			return
+		}
+
+		b, err = s.aead.Open(b)
+		if err != nil {
+			s.internalServerError(w, err)
+			return
+		}
+
+		buf.Write(b)
+	}
+
+	s.baseAccessLog.WithFields(logrus.Fields{
+		"resource_id": resourceID,
+		"recording":   id,
+		"username":    ctx.Value(middleware.ContextKeyUsername),
+	}).Print("recording replayed")
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// recordingsAccess validates the resource ID in the request path and checks
+// that recordings are enabled and that the user has elevated access.
+// Recordings contain everything shown in a break-glass session, so they are
+// only available to elevated users.
+func (s *SSH) recordingsAccess(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ctx := r.Context()
+
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 9 {
+		http.Error(w, "invalid resourceId", http.StatusBadRequest)
+		return "", false
+	}
+
+	resourceID := strings.Join(parts[:9], "/")
+	if !validate.RxClusterID.MatchString(resourceID) {
+		http.Error(w, fmt.Sprintf("invalid resourceId %q", resourceID), http.StatusBadRequest)
+		return "", false
+	}
+
+	if s.recordings == nil {
+		http.Error(w, "SSH session recording is not enabled", http.StatusNotFound)
+		return "", false
+	}
+
+	elevated, _, err := s.elevatedAccess.Check(ctx, resourceID)
+	if err != nil {
+		s.internalServerError(w, err)
+		return "", false
+	}
+	if !elevated {
+		http.Error(w, "Elevated access is required.", http.StatusForbidden)
+		return "", false
+	}
+
+	return resourceID, true
+}
@@ -0,0 +1,353 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	r := newRecorder(func() time.Time { return now }, &api.Portal{
+		Username: "username",
+		ID:       "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster",
+	}, "master-0")
+
+	if !rxRecordingID.MatchString(r.id) {
+		t.Errorf("invalid recording id %q", r.id)
+	}
+	if !strings.HasPrefix(r.id, "20240101-000000-") {
+		t.Error(r.id)
+	}
+
+	r.request(&cryptossh.Request{
+		Type: "pty-req",
+		Payload: cryptossh.Marshal(&struct {
+			Term    string
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+			Modes   string
+		}{
+			Term:    "xterm",
+			Columns: 120,
+			Rows:    40,
+		}),
+	})
+
+	now = start.Add(time.Second)
+	_, _ = r.writer("i").Write([]byte("ls\r"))
+
+	// "é" is split across two writes: its first byte is held back
+	now = start.Add(2 * time.Second)
+	_, _ = r.writer("o").Write([]byte("caf\xc3"))
+	now = start.Add(3 * time.Second)
+	_, _ = r.writer("o").Write([]byte("\xa9\r\n"))
+
+	now = start.Add(4 * time.Second)
+	r.request(&cryptossh.Request{
+		Type: "window-change",
+		Payload: cryptossh.Marshal(&struct {
+			Columns uint32
+			Rows    uint32
+			Width   uint32
+			Height  uint32
+		}{
+			Columns: 100,
+			Rows:    30,
+		}),
+	})
+
+	var b []byte
+	err := r.flush(func(chunk int, chunkBytes []byte, truncated bool) error {
+		b = chunkBytes
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"version":2,"width":120,"height":40,"timestamp":1704067200,"title":"username@master-0 /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster","env":{"TERM":"xterm"},"username":"username","resourceId":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster","hostname":"master-0"}
+[1,"i","ls\r"]
+[2,"o","caf"]
+[3,"o","é\r\n"]
+[4,"r","100x30"]
+`
+	if string(b) != want {
+		t.Error(string(b))
+	}
+}
+
+func TestRecorderTruncated(t *testing.T) {
+	r := newRecorder(time.Now, &api.Portal{}, "master-0")
+
+	chunk := bytes.Repeat([]byte("a"), 1<<20)
+	for i := 0; i < 65; i++ {
+		r.event("o", chunk)
+	}
+
+	if !r.truncated {
+		t.Error("expected truncated recording")
+	}
+	if r.size > maxRecordingSize {
+		t.Error(r.size)
+	}
+}
+
+func TestRecorderFlush(t *testing.T) {
+	r := newRecorder(time.Now, &api.Portal{Username: "username"}, "master-0")
+
+	type saved struct {
+		chunk     int
+		b         string
+		truncated bool
+	}
+	var got []saved
+	var saveErr error
+	save := func(chunk int, b []byte, truncated bool) error {
+		if saveErr != nil {
+			return saveErr
+		}
+		got = append(got, saved{chunk: chunk, b: string(b), truncated: truncated})
+		return nil
+	}
+
+	// the first chunk carries the header even without events
+	err := r.flush(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].chunk != 0 || !strings.HasPrefix(got[0].b, `{"version":2,`) || !strings.HasSuffix(got[0].b, "}\n") {
+		t.Fatal(got)
+	}
+
+	// nothing is saved without new events
+	err = r.flush(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatal(got)
+	}
+
+	// events are kept when saving fails, and saved by the next flush
+	r.event("o", []byte("one"))
+	saveErr = errors.New("failed")
+	err = r.flush(save)
+	if err != saveErr {
+		t.Fatal(err)
+	}
+
+	r.event("o", []byte("two"))
+	saveErr = nil
+	err = r.flush(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].chunk != 1 || strings.Count(got[1].b, "\n") != 2 || !strings.Contains(got[1].b, `"one"`) || !strings.Contains(got[1].b, `"two"`) {
+		t.Fatal(got)
+	}
+
+	// a full chunk signals that it should be saved early
+	select {
+	case <-r.full:
+		t.Fatal("unexpected full signal")
+	default:
+	}
+	r.event("o", bytes.Repeat([]byte("a"), recordingChunkSize))
+	select {
+	case <-r.full:
+	default:
+		t.Fatal("expected full signal")
+	}
+
+	// truncation is saved even when no events remain to be saved
+	err = r.flush(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.size = maxRecordingSize
+	r.event("o", []byte("dropped"))
+	err = r.flush(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[3].chunk != 3 || got[3].b != "" || !got[3].truncated || got[2].truncated {
+		t.Fatal(len(got))
+	}
+}
+
+func TestRecordings(t *testing.T) {
+	ctx := context.Background()
+
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+	elevatedGroupIDs := []string{"10000000-0000-0000-0000-000000000000"}
+
+	aead, err := encryption.NewXChaCha20Poly1305(ctx, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recordings := blobstore.NewMemory()
+
+	dbPortal, _ := testdatabase.NewFakePortal()
+
+	s := &SSH{
+		log:            logrus.NewEntry(logrus.StandardLogger()),
+		baseAccessLog:  logrus.NewEntry(logrus.StandardLogger()),
+		elevatedAccess: elevatedaccess.New(logrus.NewEntry(logrus.StandardLogger()), nil, elevatedGroupIDs, dbPortal),
+		aead:           aead,
+		recordings:     recordings,
+	}
+
+	// the recording is saved in two chunks, as while a session is open
+	rec := newRecorder(time.Now, &api.Portal{Username: "username", ID: resourceID}, "master-0")
+	rec.event("o", []byte("hello\r\n"))
+
+	err = s.saveRecording(ctx, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec.event("o", []byte("world\r\n"))
+
+	err = s.saveRecording(ctx, rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the chunks are stored encrypted
+	for chunk, plaintext := range []string{"hello", "world"} {
+		b, err := recordings.Get(ctx, recordingChunkName(resourceID, rec.id, chunk))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte(plaintext)) {
+			t.Error("recording is not encrypted")
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		path           string
+		groups         []string
+		recordings     blobstore.Store
+		wantStatusCode int
+		wantBody       func(*testing.T, []byte)
+	}{
+		{
+			name:           "list",
+			path:           resourceID + "/ssh/recordings",
+			groups:         elevatedGroupIDs,
+			recordings:     recordings,
+			wantStatusCode: http.StatusOK,
+			wantBody: func(t *testing.T, b []byte) {
+				var r []*Recording
+				err := json.Unmarshal(b, &r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(r) != 1 || r[0].ID != rec.id || r[0].Username != "username" || r[0].Hostname != "master-0" {
+					t.Error(string(b))
+				}
+			},
+		},
+		{
+			name:           "get",
+			path:           resourceID + "/ssh/recordings/" + rec.id,
+			groups:         elevatedGroupIDs,
+			recordings:     recordings,
+			wantStatusCode: http.StatusOK,
+			wantBody: func(t *testing.T, b []byte) {
+				lines := strings.Split(string(b), "\n")
+				if len(lines) != 4 ||
+					!strings.HasPrefix(lines[0], `{"version":2,`) ||
+					!strings.HasSuffix(lines[1], `"o","hello\r\n"]`) ||
+					!strings.HasSuffix(lines[2], `"o","world\r\n"]`) ||
+					lines[3] != "" {
+					t.Error(string(b))
+				}
+			},
+		},
+		{
+			name:           "get not found",
+			path:           resourceID + "/ssh/recordings/20240101-000000-00000000-0000-0000-0000-000000000000",
+			groups:         elevatedGroupIDs,
+			recordings:     recordings,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "get invalid id",
+			path:           resourceID + "/ssh/recordings/..",
+			groups:         elevatedGroupIDs,
+			recordings:     recordings,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "other cluster",
+			path:           "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/other/ssh/recordings/" + rec.id,
+			groups:         elevatedGroupIDs,
+			recordings:     recordings,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "not elevated",
+			path:           resourceID + "/ssh/recordings",
+			recordings:     recordings,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "not enabled",
+			path:           resourceID + "/ssh/recordings",
+			groups:         elevatedGroupIDs,
+			wantStatusCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s.recordings = tt.recordings
+
+			r, err := http.NewRequestWithContext(context.WithValue(ctx, middleware.ContextKeyGroups, tt.groups), http.MethodGet, "https://localhost:8444"+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+
+			if strings.HasSuffix(tt.path, "/ssh/recordings") {
+				s.Recordings(w, r)
+			} else {
+				s.Recording(w, r)
+			}
+
+			if w.Code != tt.wantStatusCode {
+				t.Error(w.Code, w.Body.String())
+			}
+
+			if tt.wantBody != nil {
+				tt.wantBody(t, w.Body.Bytes())
+			}
+		})
+	}
+}
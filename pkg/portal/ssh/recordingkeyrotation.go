@@ -0,0 +1,168 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// recordingSavedTimeMetadata records when a chunk was first saved, so that
+// re-sealing a chunk does not move the end time of its recording
+const recordingSavedTimeMetadata = "savedtime"
+
+// recordingsKeyRotation re-seals the chunks of SSH session recordings with the
+// current encryption key, counting each chunk as a document.  Once the portal
+// seals with the current key no session writes chunks with an older one, so
+// chunks which need re-sealing are never written concurrently.
+type recordingsKeyRotation struct {
+	log        *logrus.Entry
+	aead       encryption.AEAD
+	versioner  encryption.KeyVersioner
+	recordings blobstore.Store
+}
+
+// NewRecordingsKeyRotation returns a KeyRotation for the SSH session
+// recordings in recordings.  aead must hold all the keys in use.
+func NewRecordingsKeyRotation(log *logrus.Entry, recordings blobstore.Store, aead encryption.AEAD) (database.KeyRotation, error) {
+	versioner, ok := aead.(encryption.KeyVersioner)
+	if !ok {
+		return nil, fmt.Errorf("the AEAD does not report key versions")
+	}
+
+	return &recordingsKeyRotation{
+		log:        log,
+		aead:       aead,
+		versioner:  versioner,
+		recordings: recordings,
+	}, nil
+}
+
+func (r *recordingsKeyRotation) Rotate(ctx context.Context) (*database.KeyRotationProgress, error) {
+	sealKeyVersion := r.versioner.SealKeyVersion()
+	r.log.Printf("re-sealing SSH recordings with key version %s", sealKeyVersion)
+
+	p, err := r.walk(ctx, func(blob *blobstore.Blob, sealed []byte, version string) (bool, error) {
+		if version == sealKeyVersion {
+			return false, nil
+		}
+
+		b, err := r.aead.Open(sealed)
+		if err != nil {
+			return false, err
+		}
+
+		sealed, err = r.aead.Seal(b)
+		if err != nil {
+			return false, err
+		}
+
+		metadata := make(map[string]string, len(blob.Metadata)+1)
+		for k, v := range blob.Metadata {
+			metadata[k] = v
+		}
+		if metadata[recordingSavedTimeMetadata] == "" {
+			metadata[recordingSavedTimeMetadata] = blob.LastModified.UTC().Format(time.RFC3339)
+		}
+
+		err = r.recordings.Put(ctx, blob.Name, sealed, metadata)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return p, err
+	}
+
+	r.log.Printf("SSH recordings: %d chunks scanned, %d re-sealed, %d failed", p.Documents, p.Rotated, p.Failed)
+
+	if p.Failed > 0 {
+		return p, fmt.Errorf("%d SSH recording chunks could not be re-sealed", p.Failed)
+	}
+
+	return p, nil
+}
+
+func (r *recordingsKeyRotation) Verify(ctx context.Context, keyVersion string) (*database.KeyRotationProgress, error) {
+	var dependent int
+	p, err := r.walk(ctx, func(blob *blobstore.Blob, sealed []byte, version string) (bool, error) {
+		if version == keyVersion {
+			dependent++
+			r.log.Warnf("SSH recording chunk %s depends on key version %s", blob.Name, keyVersion)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return p, err
+	}
+
+	r.log.Printf("SSH recordings: %d chunks scanned", p.Documents)
+
+	switch {
+	case dependent > 0:
+		return p, fmt.Errorf("%d SSH recording chunks depend on key version %s", dependent, keyVersion)
+	case p.Failed > 0:
+		return p, fmt.Errorf("%d SSH recording chunks could not be checked", p.Failed)
+	}
+
+	return p, nil
+}
+
+// walk reads every recording chunk and calls f with it and the version of the
+// key which sealed it.  f returns true if it re-sealed the chunk.  Chunks which
+// cannot be read or for which f fails are logged and counted as failed.
+func (r *recordingsKeyRotation) walk(ctx context.Context, f func(blob *blobstore.Blob, sealed []byte, version string) (bool, error)) (*database.KeyRotationProgress, error) {
+	p := &database.KeyRotationProgress{
+		KeyVersions: map[string]int{},
+	}
+
+	blobs, err := r.recordings.List(ctx, "")
+	if err != nil {
+		return p, err
+	}
+
+	for _, blob := range blobs {
+		p.Documents++
+
+		err := func() error {
+			sealed, err := r.recordings.Get(ctx, blob.Name)
+			if err != nil {
+				return err
+			}
+
+			version, err := r.versioner.OpenKeyVersion(sealed)
+			if err != nil {
+				return err
+			}
+
+			p.KeyVersions[version]++
+
+			rotated, err := f(blob, sealed, version)
+			if err != nil {
+				return err
+			}
+
+			if rotated {
+				p.Rotated++
+			}
+
+			return nil
+		}()
+		if err != nil {
+			p.Failed++
+			r.log.Errorf("SSH recording chunk %s: %v", blob.Name, err)
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,142 @@
+package ssh
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+// fakeVersionedAEAD seals values by prefixing them with the current key
+// version, and opens values prefixed with any known key version
+type fakeVersionedAEAD struct {
+	current string
+	known   []string
+}
+
+func (a *fakeVersionedAEAD) Seal(b []byte) ([]byte, error) {
+	return append([]byte(a.current+":"), b...), nil
+}
+
+func (a *fakeVersionedAEAD) Open(b []byte) ([]byte, error) {
+	version, err := a.OpenKeyVersion(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b[len(version)+1:], nil
+}
+
+func (a *fakeVersionedAEAD) SealKeyVersion() string {
+	return a.current
+}
+
+func (a *fakeVersionedAEAD) OpenKeyVersion(b []byte) (string, error) {
+	for _, version := range a.known {
+		if bytes.HasPrefix(b, []byte(version+":")) {
+			return version, nil
+		}
+	}
+
+	return "", errors.New("message authentication failed")
+}
+
+func TestRecordingsKeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	aead := &fakeVersionedAEAD{current: "new", known: []string{"old", "new"}}
+
+	recordings := blobstore.NewMemory()
+	for name, b := range map[string]string{
+		"cluster/recording/00000000.cast": "old:header",
+		"cluster/recording/00000001.cast": "new:events",
+	} {
+		err := recordings.Put(ctx, name, []byte(b), map[string]string{"username": "user"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, err := recordings.List(ctx, "cluster/recording/00000000.cast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRecordingsKeyRotation(logrus.NewEntry(logrus.StandardLogger()), recordings, aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Verify(ctx, "old")
+	utilerror.AssertErrorMessage(t, err, "1 SSH recording chunks depend on key version old")
+
+	p, err := r.Rotate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Documents != 2 || p.Rotated != 1 || p.Failed != 0 {
+		t.Errorf("got %d scanned, %d re-sealed, %d failed", p.Documents, p.Rotated, p.Failed)
+	}
+	if p.KeyVersions["old"] != 1 || p.KeyVersions["new"] != 1 {
+		t.Error(p.KeyVersions)
+	}
+
+	b, err := recordings.Get(ctx, "cluster/recording/00000000.cast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "new:header" {
+		t.Error(string(b))
+	}
+
+	after, err := recordings.List(ctx, "cluster/recording/00000000.cast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after[0].Metadata["username"] != "user" {
+		t.Error(after[0].Metadata)
+	}
+	if after[0].Metadata[recordingSavedTimeMetadata] != before[0].LastModified.UTC().Format(time.RFC3339) {
+		t.Error(after[0].Metadata)
+	}
+
+	_, err = r.Verify(ctx, "old")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecordingsKeyRotationFailed(t *testing.T) {
+	ctx := context.Background()
+
+	aead := &fakeVersionedAEAD{current: "new", known: []string{"new"}}
+
+	recordings := blobstore.NewMemory()
+	err := recordings.Put(ctx, "cluster/recording/00000000.cast", []byte("unknown:header"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRecordingsKeyRotation(logrus.NewEntry(logrus.StandardLogger()), recordings, aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := r.Rotate(ctx)
+	utilerror.AssertErrorMessage(t, err, "1 SSH recording chunks could not be re-sealed")
+	if p.Failed != 1 {
+		t.Error(p.Failed)
+	}
+
+	_, err = r.Verify(ctx, "old")
+	utilerror.AssertErrorMessage(t, err, "1 SSH recording chunks could not be checked")
+}
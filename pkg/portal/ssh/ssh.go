@@ -23,8 +23,11 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/elevatedaccess"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/proxy"
+	"github.com/Azure/ARO-RP/pkg/util/blobstore"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
 )
 
 const (
@@ -37,13 +40,18 @@ type SSH struct {
 	baseAccessLog *logrus.Entry
 	l             net.Listener
 
-	elevatedGroupIDs []string
+	elevatedAccess *elevatedaccess.ElevatedAccess
 
 	dbOpenShiftClusters database.OpenShiftClusters
 	dbPortal            database.Portal
 
 	dialer proxy.Dialer
 
+	// aead encrypts session recordings; recordings stores them.  If
+	// recordings is nil, sessions are not recorded.
+	aead       encryption.AEAD
+	recordings blobstore.Store
+
 	baseServerConfig *cryptossh.ServerConfig
 
 	hostPubKey cryptossh.PublicKey
@@ -54,10 +62,12 @@ func New(env env.Core,
 	baseAccessLog *logrus.Entry,
 	l net.Listener,
 	hostKey *rsa.PrivateKey,
-	elevatedGroupIDs []string,
+	elevatedAccess *elevatedaccess.ElevatedAccess,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
 	dialer proxy.Dialer,
+	aead encryption.AEAD,
+	recordings blobstore.Store,
 ) (*SSH, error) {
 	hostPubKey, err := cryptossh.NewPublicKey(&hostKey.PublicKey)
 	if err != nil {
@@ -70,13 +80,16 @@ func New(env env.Core,
 		baseAccessLog: baseAccessLog,
 		l:             l,
 
-		elevatedGroupIDs: elevatedGroupIDs,
+		elevatedAccess: elevatedAccess,
 
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbPortal:            dbPortal,
 
 		dialer: dialer,
 
+		aead:       aead,
+		recordings: recordings,
+
 		baseServerConfig: &cryptossh.ServerConfig{},
 
 		hostPubKey: hostPubKey,
@@ -132,7 +145,11 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	elevated := len(middleware.GroupsIntersect(s.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+	elevated, expiresAt, err := s.elevatedAccess.Check(ctx, resourceID)
+	if err != nil {
+		s.internalServerError(w, err)
+		return
+	}
 	if !elevated {
 		s.sendResponse(w, "", "", "", "Elevated access is required.", s.env.IsLocalDevelopmentMode())
 		return
@@ -154,6 +171,10 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	if !expiresAt.IsZero() {
+		portalDoc.Portal.SSH.ExpiresAt = &expiresAt
+	}
+
 	_, err = s.dbPortal.Create(ctx, portalDoc)
 	if err != nil {
 		s.internalServerError(w, err)
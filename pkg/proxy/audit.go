@@ -0,0 +1,91 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fingerprint identifies a client certificate
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// clientName returns the name of the client which made r
+func (s *Server) clientName(r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", false
+	}
+
+	name, ok := s.clients[fingerprint(r.TLS.PeerCertificates[0])]
+	return name, ok
+}
+
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijacking not supported")
+	}
+
+	return hijacker.Hijack()
+}
+
+// audit rejects requests from unknown clients and writes a record of every
+// request to the audit log once it completes.  For CONNECT requests and
+// protocol upgrades, this is when the tunnel closes.
+func (s *Server) audit(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := time.Now()
+
+		name, ok := s.clientName(r)
+		aw := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		defer func() {
+			s.AuditLog.WithFields(logrus.Fields{
+				"client":               name,
+				"request_method":       r.Method,
+				"request_host":         r.Host,
+				"request_path":         r.URL.Path,
+				"request_proto":        r.Proto,
+				"request_remote_addr":  r.RemoteAddr,
+				"request_upgrade":      r.Header.Get("Upgrade"),
+				"response_status_code": aw.statusCode,
+				"duration":             time.Since(t).Seconds(),
+			}).Print("proxied request")
+		}()
+
+		if !ok {
+			http.Error(aw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(aw, r)
+	})
+}
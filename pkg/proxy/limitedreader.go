@@ -0,0 +1,57 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *rate.Limiter
+}
+
+// NewLimitedReader returns a Reader which reads from r no faster than l
+// allows.  l may be shared between readers, in which case they share its
+// bandwidth.  If l is nil, r is returned.
+func NewLimitedReader(ctx context.Context, r io.Reader, l *rate.Limiter) io.Reader {
+	if l == nil {
+		return r
+	}
+
+	return &limitedReader{
+		ctx: ctx,
+		r:   r,
+		l:   l,
+	}
+}
+
+func (r *limitedReader) Read(b []byte) (int, error) {
+	if burst := r.l.Burst(); burst > 0 && len(b) > burst {
+		b = b[:burst]
+	}
+
+	n, err := r.r.Read(b)
+
+	// the limiter's burst may change while we wait, so wait for tokens in
+	// chunks no larger than the current burst
+	for remaining := n; remaining > 0; {
+		chunk := remaining
+		if burst := r.l.Burst(); burst > 0 && chunk > burst {
+			chunk = burst
+		}
+
+		if werr := r.l.WaitN(r.ctx, chunk); werr != nil {
+			return n, werr
+		}
+
+		remaining -= chunk
+	}
+
+	return n, err
+}
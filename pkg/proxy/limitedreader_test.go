@@ -0,0 +1,65 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestLimitedReader(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 10)
+
+	t.Run("nil limiter", func(t *testing.T) {
+		r := bytes.NewReader(data)
+
+		if NewLimitedReader(ctx, r, nil) != r {
+			t.Error("expected reader to be returned unchanged")
+		}
+	})
+
+	t.Run("reads are no larger than the burst", func(t *testing.T) {
+		l := rate.NewLimiter(rate.Limit(1e9), 16)
+		r := NewLimitedReader(ctx, bytes.NewReader(data), l)
+
+		var got []byte
+		b := make([]byte, 64)
+		for {
+			n, err := r.Read(b)
+			if n > 16 {
+				t.Errorf("read %d bytes", n)
+			}
+			got = append(got, b[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if !bytes.Equal(got, data) {
+			t.Error(string(got))
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		l := rate.NewLimiter(rate.Limit(1), 16)
+		l.AllowN(time.Now(), 16) // drain the bucket
+
+		_, err := NewLimitedReader(ctx, bytes.NewReader(data), l).Read(make([]byte, 64))
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
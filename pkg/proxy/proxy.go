@@ -4,29 +4,51 @@ package proxy
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"io"
+	"log"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
 
 	utilnet "github.com/Azure/ARO-RP/pkg/util/net"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 )
 
+// Server is the development proxy.  It authenticates clients by their
+// certificates and either tunnels CONNECT requests into Subnet or, for any
+// other request, acts as a reverse proxy to the HTTPS server named by the
+// request's Host header, which must resolve into Subnet.  It serves HTTP/1.1
+// and HTTP/2, passes WebSocket and other protocol upgrades through, and writes
+// a record of every request to AuditLog, naming the client which made it.
 type Server struct {
-	Log *logrus.Entry
+	Log      *logrus.Entry
+	AuditLog *logrus.Entry
 
-	CertFile       string
-	KeyFile        string
+	CertFile string
+	KeyFile  string
+	// ClientCertFile contains the certificate of a single client, which is
+	// named after the file.  It is ignored if ClientCertFiles is set.
 	ClientCertFile string
-	Subnet         string
-	subnet         *net.IPNet
+	// ClientCertFiles maps client names to files containing their
+	// certificates
+	ClientCertFiles map[string]string
+	Subnet          string
+	subnet          *net.IPNet
+
+	clients      map[string]string
+	lookupIPAddr func(context.Context, string) ([]net.IPAddr, error)
+	reverseProxy http.Handler
 }
 
 func (s *Server) Run() error {
@@ -36,25 +58,37 @@ func (s *Server) Run() error {
 	}
 	s.subnet = subnet
 
-	b, err := os.ReadFile(s.ClientCertFile)
-	if err != nil {
-		return err
-	}
-
-	clientCert, err := x509.ParseCertificate(b)
-	if err != nil {
-		return err
+	clientCertFiles := s.ClientCertFiles
+	if len(clientCertFiles) == 0 {
+		clientCertFiles = map[string]string{
+			strings.TrimSuffix(filepath.Base(s.ClientCertFile), filepath.Ext(s.ClientCertFile)): s.ClientCertFile,
+		}
 	}
 
 	pool := x509.NewCertPool()
-	pool.AddCert(clientCert)
+	s.clients = map[string]string{}
+
+	for name, file := range clientCertFiles {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		clientCert, err := x509.ParseCertificate(b)
+		if err != nil {
+			return err
+		}
+
+		pool.AddCert(clientCert)
+		s.clients[fingerprint(clientCert)] = name
+	}
 
 	cert, err := os.ReadFile(s.CertFile)
 	if err != nil {
 		return err
 	}
 
-	b, err = os.ReadFile(s.KeyFile)
+	b, err := os.ReadFile(s.KeyFile)
 	if err != nil {
 		return err
 	}
@@ -64,45 +98,60 @@ func (s *Server) Run() error {
 		return err
 	}
 
-	l, err := tls.Listen("tcp", ":8443", &tls.Config{
-		Certificates: []tls.Certificate{
-			{
-				Certificate: [][]byte{
-					cert,
+	l, err := net.Listen("tcp", ":8443")
+	if err != nil {
+		return err
+	}
+
+	s.lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	s.reverseProxy = s.newReverseProxy()
+
+	server := &http.Server{
+		Handler:  s.audit(http.HandlerFunc(s.proxyHandler)),
+		ErrorLog: log.New(s.Log.Writer(), "", 0),
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{
+				{
+					Certificate: [][]byte{
+						cert,
+					},
+					PrivateKey: key,
 				},
-				PrivateKey: key,
+			},
+			ClientCAs:  pool,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			CipherSuites: []uint16{
+				tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+				tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+				tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			},
+			SessionTicketsDisabled: true,
+			MinVersion:             tls.VersionTLS12,
+			CurvePreferences: []tls.CurveID{
+				tls.CurveP256,
+				tls.X25519,
 			},
 		},
-		ClientCAs:  pool,
-		ClientAuth: tls.RequireAndVerifyClientCert,
-		CipherSuites: []uint16{
-			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
-			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-		},
-		SessionTicketsDisabled: true,
-		MinVersion:             tls.VersionTLS12,
-		CurvePreferences: []tls.CurveID{
-			tls.CurveP256,
-			tls.X25519,
-		},
-	})
-	if err != nil {
-		return err
 	}
 
-	return http.Serve(l, http.HandlerFunc(s.proxyHandler))
+	// ServeTLS enables HTTP/2 as well as HTTP/1.1
+	return server.ServeTLS(l, "", "")
 }
 
-func (s Server) proxyHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) proxyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		s.reverseProxy.ServeHTTP(w, r)
+		return
+	}
+
 	err := s.validateProxyRequest(w, r)
 	if err != nil {
 		return
 	}
-	Proxy(s.Log, w, r, 0)
+	Proxy(s.Log, w, r, 0, nil)
 }
 
 // validateProxyRequest checks that the request is valid. If not, it writes the
@@ -130,20 +179,38 @@ func (s Server) validateProxyRequest(w http.ResponseWriter, r *http.Request) err
 // Proxy takes an HTTP/1.x CONNECT Request and ResponseWriter from the Golang
 // HTTP stack and uses Hijack() to get the underlying Connection (c1).  It dials
 // a second Connection (c2) to the requested end Host and then copies data in
-// both directions (c1->c2 and c2->c1).
-func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
-	c2, err := utilnet.Dial("tcp", r.Host, sz)
+// both directions (c1->c2 and c2->c1), sharing the bandwidth allowed by l
+// between them if l is not nil.  It returns what happened on the connection
+// once both directions are closed or, for HTTP/2, once the server closes.
+func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int, l *rate.Limiter) *Stats {
+	return ProxyTo(log, w, r, r.Host, sz, l)
+}
+
+// ProxyTo is like Proxy, but dials addr instead of the requested Host.  It is
+// used by callers which have resolved and checked the requested Host
+// themselves.
+func ProxyTo(log *logrus.Entry, w http.ResponseWriter, r *http.Request, addr string, sz int, l *rate.Limiter) *Stats {
+	stats := &Stats{}
+
+	c2, err := utilnet.Dial("tcp", addr, sz)
 	if err != nil {
+		stats.CloseReason = CloseReasonDialFailed
 		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return stats
 	}
 
 	defer c2.Close()
 
+	if r.ProtoMajor == 2 {
+		proxyHTTP2(log, w, r, c2, l, stats)
+		return stats
+	}
+
 	hijacker, ok := w.(http.Hijacker)
 	if !ok {
+		stats.CloseReason = CloseReasonHijackFailed
 		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
-		return
+		return stats
 	}
 
 	// Do as much setup as possible before calling Hijack(), because after
@@ -154,8 +221,9 @@ func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
 
 	c1, buf, err := hijacker.Hijack()
 	if err != nil {
+		stats.CloseReason = CloseReasonHijackFailed
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return stats
 	}
 
 	defer c1.Close()
@@ -176,7 +244,7 @@ func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
 				conn2.CloseWrite()
 			}
 		}()
-		_, _ = io.Copy(c2, buf)
+		stats.ClientDone(io.Copy(c2, NewLimitedReader(r.Context(), buf, l)))
 	}()
 
 	// copy from c2->c1.  Call c1.CloseWrite() when done.
@@ -186,5 +254,89 @@ func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
 			closeWriter.CloseWrite()
 		}
 	}()
-	_, _ = io.Copy(c1, c2)
+	stats.ServerDone(io.Copy(c1, NewLimitedReader(r.Context(), c2, l)))
+
+	return stats
+}
+
+// proxyHTTP2 handles an HTTP/2 CONNECT request.  HTTP/2 connections cannot be
+// hijacked: instead the request body carries data from the client and the
+// response body carries data to it.
+func proxyHTTP2(log *logrus.Entry, w http.ResponseWriter, r *http.Request, c2 net.Conn, l *rate.Limiter, stats *Stats) {
+	w.WriteHeader(http.StatusOK)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		stats.CloseReason = CloseReasonHijackFailed
+		return
+	}
+	flusher.Flush()
+
+	// mu guards returned, which stops the c1->c2 goroutine from recording
+	// its stats once proxyHTTP2 has returned them
+	var mu sync.Mutex
+	var returned, clientDone bool
+	cw := &countingWriter{w: c2}
+
+	go func() {
+		defer recover.Panic(log)
+		defer func() {
+			conn2, ok := c2.(*net.TCPConn)
+			if ok {
+				conn2.CloseWrite()
+			}
+		}()
+		_, err := io.Copy(cw, NewLimitedReader(r.Context(), r.Body, l))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !returned {
+			stats.ClientDone(cw.count(), err)
+			clientDone = true
+		}
+	}()
+
+	stats.ServerDone(io.Copy(&flushWriter{w: w, f: flusher}, NewLimitedReader(r.Context(), c2, l)))
+
+	// Once the server has closed, close c2 and return without waiting for
+	// the c1->c2 goroutine: reading the request body only ends when the
+	// client closes its side, or when returning ends the request.
+	c2.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	returned = true
+	if !clientDone {
+		stats.ClientDone(cw.count(), nil)
+	}
+}
+
+// countingWriter counts the bytes written to w.  The count may be read while
+// writes are in progress.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	atomic.AddInt64(&cw.n, int64(n))
+	return n, err
+}
+
+func (cw *countingWriter) count() int64 {
+	return atomic.LoadInt64(&cw.n)
+}
+
+// flushWriter flushes after every write so that data reaches the client
+// without delay
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw *flushWriter) Write(b []byte) (int, error) {
+	n, err := fw.w.Write(b)
+	fw.f.Flush()
+	return n, err
 }
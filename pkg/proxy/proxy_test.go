@@ -4,11 +4,16 @@ package proxy
 // Licensed under the Apache License 2.0.
 
 import (
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 func TestRequestValidation(t *testing.T) {
@@ -80,3 +85,76 @@ func TestRequestValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestProxyHTTP2UpstreamCloses(t *testing.T) {
+	// the upstream sends a greeting and closes straight away
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		_, _ = c.Write([]byte("hello"))
+	}()
+
+	statsCh := make(chan *Stats, 1)
+	p := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statsCh <- Proxy(logrus.NewEntry(logrus.StandardLogger()), w, r, 0, nil)
+	}))
+	p.EnableHTTP2 = true
+	p.StartTLS()
+	defer p.Close()
+
+	// the client never closes its side of the connection
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	req, err := http.NewRequest(http.MethodConnect, p.URL, pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = l.Addr().String()
+
+	cli := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+			ForceAttemptHTTP2: true,
+		},
+	}
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ProtoMajor != 2 || resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.Proto, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Error(string(b))
+	}
+
+	select {
+	case stats := <-statsCh:
+		if stats.CloseReason != CloseReasonServerClosed || stats.BytesOut != 5 || stats.BytesIn != 0 {
+			t.Error(stats.CloseReason, stats.BytesOut, stats.BytesIn)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the proxy to return")
+	}
+}
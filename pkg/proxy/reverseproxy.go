@@ -0,0 +1,78 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+var errDestinationNotAllowed = errors.New("destination is not part of the allowed subnet")
+
+// newReverseProxy returns a reverse proxy to the HTTPS server named by each
+// request's Host header.  Requests are forwarded over HTTP/1.1 whichever
+// protocol the client used, so that protocol upgrades (WebSocket, and the SPDY
+// streams used by oc exec) can be passed through.
+func (s *Server) newReverseProxy() *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = "https"
+			r.URL.Host = r.Host
+		},
+		Transport: &http.Transport{
+			DialContext: s.dialContext,
+			TLSClientConfig: &tls.Config{
+				// the destinations are development clusters whose serving
+				// certificates are not trusted by the proxy; clients
+				// authenticate the proxy, not the destination
+				InsecureSkipVerify: true,
+			},
+			// an empty, non-nil map disables HTTP/2 to destinations
+			TLSNextProto:        map[string]func(string, *tls.Conn) http.RoundTripper{},
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// stream responses such as logs and watches as they arrive
+		FlushInterval: -1,
+		ErrorLog:      log.New(s.Log.Writer(), "", 0),
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, errDestinationNotAllowed) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			s.Log.Warn(err)
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		},
+	}
+}
+
+// dialContext connects to address if it resolves into the allowed subnet.  The
+// address is resolved here, rather than when the request is validated, so
+// that the address which is checked is the one which is dialled.
+func (s *Server) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := s.lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		if s.subnet.Contains(ip.IP) {
+			return (&net.Dialer{}).DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+		}
+	}
+
+	return nil, errDestinationNotAllowed
+}
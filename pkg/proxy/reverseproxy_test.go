@@ -0,0 +1,282 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bufio"
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+
+	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+// newTestBackend returns a server which replies with the protocol of each
+// request, and echoes data back after accepting an "echo" protocol upgrade
+func newTestBackend() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "echo" {
+			fmt.Fprintf(w, "%s %s", r.Host, r.URL.Path)
+			return
+		}
+
+		c, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		_ = buf.Flush()
+
+		_, _ = io.Copy(c, buf)
+	}))
+}
+
+type testClient struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestClient(t *testing.T, name string) *testClient {
+	key, certs, err := utiltls.GenerateKeyAndCertificate(name, nil, nil, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testClient{key: key, cert: certs[0]}
+}
+
+func (c *testClient) tlsConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{c.cert.Raw},
+				PrivateKey:  c.key,
+			},
+		},
+	}
+}
+
+// newTestProxy starts s behind a TLS listener which, like Run, requires client
+// certificates.  Unlike Run, any certificate is accepted by the listener, so
+// that the test can check that s rejects unknown clients itself.
+func newTestProxy(t *testing.T, s *Server, clients ...*testClient) *httptest.Server {
+	_, subnet, err := net.ParseCIDR("127.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.clients = map[string]string{}
+	for _, c := range clients {
+		s.clients[fingerprint(c.cert)] = c.cert.Subject.CommonName
+	}
+
+	s.subnet = subnet
+	s.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "console.example.com":
+			return []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}, nil
+		case "public.example.com":
+			return []net.IPAddr{{IP: net.IPv4(8, 8, 8, 8)}}, nil
+		}
+		return net.DefaultResolver.LookupIPAddr(ctx, host)
+	}
+	s.reverseProxy = s.newReverseProxy()
+
+	p := httptest.NewUnstartedServer(s.audit(http.HandlerFunc(s.proxyHandler)))
+	p.EnableHTTP2 = true
+	p.TLS = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+	p.StartTLS()
+
+	return p
+}
+
+func TestReverseProxy(t *testing.T) {
+	backend := newTestBackend()
+	defer backend.Close()
+
+	backendURL, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := newTestClient(t, "alice")
+	mallory := newTestClient(t, "mallory")
+
+	for _, tt := range []struct {
+		name           string
+		client         *testClient
+		host           string
+		http2          bool
+		wantStatusCode int
+		wantProtoMajor int
+		wantBody       string
+		wantClient     string
+	}{
+		{
+			name:           "HTTP/1.1",
+			client:         alice,
+			host:           "console.example.com:" + backendURL.Port(),
+			wantStatusCode: http.StatusOK,
+			wantProtoMajor: 1,
+			wantBody:       "console.example.com:" + backendURL.Port() + " /path",
+			wantClient:     "alice",
+		},
+		{
+			name:           "HTTP/2",
+			client:         alice,
+			host:           "console.example.com:" + backendURL.Port(),
+			http2:          true,
+			wantStatusCode: http.StatusOK,
+			wantProtoMajor: 2,
+			wantBody:       "console.example.com:" + backendURL.Port() + " /path",
+			wantClient:     "alice",
+		},
+		{
+			name:           "destination outside the subnet",
+			client:         alice,
+			host:           "public.example.com:" + backendURL.Port(),
+			wantStatusCode: http.StatusForbidden,
+			wantProtoMajor: 1,
+			wantBody:       "Forbidden\n",
+			wantClient:     "alice",
+		},
+		{
+			name:           "unknown client",
+			client:         mallory,
+			host:           "console.example.com:" + backendURL.Port(),
+			wantStatusCode: http.StatusForbidden,
+			wantProtoMajor: 1,
+			wantBody:       "Forbidden\n",
+			wantClient:     "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h, log := testlog.New()
+
+			p := newTestProxy(t, &Server{Log: log, AuditLog: log}, alice)
+			defer p.Close()
+
+			cli := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig:   tt.client.tlsConfig(),
+					ForceAttemptHTTP2: tt.http2,
+				},
+			}
+
+			req, err := http.NewRequest(http.MethodGet, p.URL+"/path", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Host = tt.host
+
+			resp, err := cli.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.wantStatusCode {
+				t.Error(resp.StatusCode)
+			}
+
+			if resp.ProtoMajor != tt.wantProtoMajor {
+				t.Error(resp.Proto)
+			}
+
+			if string(b) != tt.wantBody {
+				t.Error(string(b))
+			}
+
+			p.Close()
+
+			err = testlog.AssertLoggingOutput(h, []map[string]types.GomegaMatcher{
+				{
+					"msg":                  gomega.Equal("proxied request"),
+					"client":               gomega.Equal(tt.wantClient),
+					"request_host":         gomega.Equal(tt.host),
+					"response_status_code": gomega.Equal(tt.wantStatusCode),
+				},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReverseProxyUpgrade(t *testing.T) {
+	backend := newTestBackend()
+	defer backend.Close()
+
+	backendURL, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := newTestClient(t, "alice")
+
+	_, log := testlog.New()
+
+	p := newTestProxy(t, &Server{Log: log, AuditLog: log}, alice)
+	defer p.Close()
+
+	c, err := tls.Dial("tcp", p.Listener.Addr().String(), alice.tlsConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	_, err = fmt.Fprintf(c, "GET / HTTP/1.1\r\nHost: console.example.com:%s\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n", backendURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(c)
+
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatal(resp.StatusCode)
+	}
+
+	_, err = c.Write([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, 5)
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != "hello" {
+		t.Error(string(b))
+	}
+}
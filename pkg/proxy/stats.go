@@ -0,0 +1,58 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sync"
+)
+
+// Reasons for which a proxied connection closed.  A connection closes when
+// either direction finishes; the reason records which direction finished
+// first and whether it finished cleanly.
+const (
+	CloseReasonDialFailed   = "dial failed"
+	CloseReasonHijackFailed = "hijack failed"
+	CloseReasonClientClosed = "client closed"
+	CloseReasonClientError  = "client error"
+	CloseReasonServerClosed = "server closed"
+	CloseReasonServerError  = "server error"
+)
+
+// Stats records what happened on a proxied connection.  BytesIn counts the
+// bytes copied from the client to the server and BytesOut the bytes copied
+// from the server to the client.
+type Stats struct {
+	mu sync.Mutex
+
+	BytesIn     int64
+	BytesOut    int64
+	CloseReason string
+}
+
+// ClientDone records that copying from the client to the server has finished
+func (s *Stats) ClientDone(n int64, err error) {
+	s.done(&s.BytesIn, n, err, CloseReasonClientClosed, CloseReasonClientError)
+}
+
+// ServerDone records that copying from the server to the client has finished
+func (s *Stats) ServerDone(n int64, err error) {
+	s.done(&s.BytesOut, n, err, CloseReasonServerClosed, CloseReasonServerError)
+}
+
+func (s *Stats) done(bytes *int64, n int64, err error, closed, failed string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*bytes = n
+
+	if s.CloseReason != "" {
+		return
+	}
+
+	if err != nil {
+		s.CloseReason = failed
+	} else {
+		s.CloseReason = closed
+	}
+}
@@ -0,0 +1,64 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		done            func(*Stats)
+		wantBytesIn     int64
+		wantBytesOut    int64
+		wantCloseReason string
+	}{
+		{
+			name: "client closes first",
+			done: func(s *Stats) {
+				s.ClientDone(10, nil)
+				s.ServerDone(20, nil)
+			},
+			wantBytesIn:     10,
+			wantBytesOut:    20,
+			wantCloseReason: CloseReasonClientClosed,
+		},
+		{
+			name: "server fails first",
+			done: func(s *Stats) {
+				s.ServerDone(20, errors.New("connection reset by peer"))
+				s.ClientDone(10, nil)
+			},
+			wantBytesIn:     10,
+			wantBytesOut:    20,
+			wantCloseReason: CloseReasonServerError,
+		},
+		{
+			name: "client fails first",
+			done: func(s *Stats) {
+				s.ClientDone(0, errors.New("connection reset by peer"))
+				s.ServerDone(5, nil)
+			},
+			wantBytesOut:    5,
+			wantCloseReason: CloseReasonClientError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Stats{}
+			tt.done(s)
+
+			if s.BytesIn != tt.wantBytesIn {
+				t.Error(s.BytesIn)
+			}
+			if s.BytesOut != tt.wantBytesOut {
+				t.Error(s.BytesOut)
+			}
+			if s.CloseReason != tt.wantCloseReason {
+				t.Error(s.CloseReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package blobstore
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	azstorage "github.com/Azure/azure-sdk-for-go/storage"
+
+	"github.com/Azure/ARO-RP/pkg/util/storage"
+)
+
+type azureStore struct {
+	storage       storage.Manager
+	resourceGroup string
+	account       string
+	container     string
+}
+
+// NewAzure returns a Store backed by a container in an Azure storage account.
+// The container is created on first use.  A fresh account SAS is requested
+// for every operation, so the Store can be long-lived.
+func NewAzure(storage storage.Manager, resourceGroup, account, container string) Store {
+	return &azureStore{
+		storage:       storage,
+		resourceGroup: resourceGroup,
+		account:       account,
+		container:     container,
+	}
+}
+
+func (s *azureStore) containerRef(ctx context.Context) (*azstorage.Container, error) {
+	blobService, err := s.storage.BlobService(ctx, s.resourceGroup, s.account, mgmtstorage.Permissions("rwlc"), mgmtstorage.SignedResourceTypesC+mgmtstorage.SignedResourceTypesO)
+	if err != nil {
+		return nil, err
+	}
+
+	return blobService.GetContainerReference(s.container), nil
+}
+
+func (s *azureStore) Put(ctx context.Context, name string, b []byte, metadata map[string]string) error {
+	c, err := s.containerRef(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CreateIfNotExists(nil)
+	if err != nil {
+		return err
+	}
+
+	blobRef := c.GetBlobReference(name)
+	blobRef.Metadata = metadata
+
+	return blobRef.CreateBlockBlobFromReader(bytes.NewReader(b), nil)
+}
+
+func (s *azureStore) Get(ctx context.Context, name string) ([]byte, error) {
+	rc, err := s.GetStream(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// blockSize is the size of the blocks PutStream uploads
+const blockSize = 4 << 20
+
+// PutStream uploads r as a series of blocks and commits them once r is fully
+// read.  Blocks which are never committed are discarded by Azure storage.
+func (s *azureStore) PutStream(ctx context.Context, name string, r io.Reader, metadata map[string]string) error {
+	c, err := s.containerRef(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CreateIfNotExists(nil)
+	if err != nil {
+		return err
+	}
+
+	blobRef := c.GetBlobReference(name)
+	blobRef.Metadata = metadata
+
+	var blocks []azstorage.Block
+	b := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, b)
+		if n > 0 {
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", len(blocks))))
+
+			err := blobRef.PutBlock(id, b[:n], nil)
+			if err != nil {
+				return err
+			}
+
+			blocks = append(blocks, azstorage.Block{ID: id, Status: azstorage.BlockStatusUncommitted})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return blobRef.PutBlockList(blocks, nil)
+}
+
+func (s *azureStore) GetStream(ctx context.Context, name string) (io.ReadCloser, error) {
+	c, err := s.containerRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := c.GetBlobReference(name).Get(nil)
+	if err, ok := err.(azstorage.AzureStorageServiceError); ok && err.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+func (s *azureStore) List(ctx context.Context, prefix string) ([]*Blob, error) {
+	c, err := s.containerRef(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobs []*Blob
+	params := azstorage.ListBlobsParameters{
+		Prefix: prefix,
+		Include: &azstorage.IncludeBlobDataset{
+			Metadata: true,
+		},
+	}
+
+	for {
+		resp, err := c.ListBlobs(params)
+		if err, ok := err.(azstorage.AzureStorageServiceError); ok && err.StatusCode == http.StatusNotFound {
+			return nil, nil // container not created yet
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, b := range resp.Blobs {
+			blobs = append(blobs, &Blob{
+				Name:         b.Name,
+				Metadata:     b.Metadata,
+				LastModified: time.Time(b.Properties.LastModified),
+			})
+		}
+
+		if resp.NextMarker == "" {
+			return blobs, nil
+		}
+		params.Marker = resp.NextMarker
+	}
+}
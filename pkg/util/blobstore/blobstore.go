@@ -0,0 +1,36 @@
+package blobstore
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// Blob describes a stored blob
+type Blob struct {
+	Name         string
+	Metadata     map[string]string
+	LastModified time.Time
+}
+
+// Store is a store of named blobs with metadata.  Blob names may contain "/"
+// to group blobs under a prefix.
+type Store interface {
+	Put(ctx context.Context, name string, b []byte, metadata map[string]string) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]*Blob, error)
+
+	// PutStream stores the content read from r without holding it all in
+	// memory.  Nothing is stored if reading r fails.
+	PutStream(ctx context.Context, name string, r io.Reader, metadata map[string]string) error
+	// GetStream returns the content of a blob as a stream, which the caller
+	// must close
+	GetStream(ctx context.Context, name string) (io.ReadCloser, error)
+}
@@ -0,0 +1,108 @@
+package blobstore
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryBlob struct {
+	b            []byte
+	metadata     map[string]string
+	lastModified time.Time
+}
+
+type memoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string]*memoryBlob
+}
+
+// NewMemory returns a Store which keeps blobs in memory, for local
+// development and testing
+func NewMemory() Store {
+	return &memoryStore{
+		blobs: map[string]*memoryBlob{},
+	}
+}
+
+func (s *memoryStore) Put(ctx context.Context, name string, b []byte, metadata map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		m[k] = v
+	}
+
+	s.blobs[name] = &memoryBlob{
+		b:            append([]byte(nil), b...),
+		metadata:     m,
+		lastModified: time.Now().UTC(),
+	}
+
+	return nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blob, found := s.blobs[name]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte(nil), blob.b...), nil
+}
+
+func (s *memoryStore) List(ctx context.Context, prefix string) ([]*Blob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var blobs []*Blob
+	for name, blob := range s.blobs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		m := make(map[string]string, len(blob.metadata))
+		for k, v := range blob.metadata {
+			m[k] = v
+		}
+
+		blobs = append(blobs, &Blob{
+			Name:         name,
+			Metadata:     m,
+			LastModified: blob.lastModified,
+		})
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Name < blobs[j].Name })
+
+	return blobs, nil
+}
+
+func (s *memoryStore) PutStream(ctx context.Context, name string, r io.Reader, metadata map[string]string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(ctx, name, b, metadata)
+}
+
+func (s *memoryStore) GetStream(ctx context.Context, name string) (io.ReadCloser, error) {
+	b, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
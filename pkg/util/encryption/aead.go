@@ -7,3 +7,13 @@ type AEAD interface {
 	Open([]byte) ([]byte, error)
 	Seal([]byte) ([]byte, error)
 }
+
+// KeyVersioner is implemented by AEADs which hold more than one key, so that
+// callers can tell which key protects a value.  Key versions have the form
+// <secret name>/<secret version>.
+type KeyVersioner interface {
+	// SealKeyVersion returns the version of the key which Seal uses
+	SealKeyVersion() string
+	// OpenKeyVersion returns the version of the key which opens input
+	OpenKeyVersion(input []byte) (string, error)
+}
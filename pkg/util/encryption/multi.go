@@ -4,31 +4,61 @@ package encryption
 // Licensed under the Apache License 2.0.
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/Azure/ARO-RP/pkg/metrics"
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
 )
 
+// keyVersionHeader starts the values sealed by multi.  It is followed by the
+// length of the key version which sealed the value, the key version and the
+// sealed value itself.  Values sealed before key versions were recorded have
+// no header; multi tries each of its keys to open them.
+var keyVersionHeader = []byte("\x00aro")
+
+// SealKeyVersionEnvVar enables writing the key version header in Seal.  Every
+// binary opens values with and without the header, so the header must only be
+// written once all RP, monitor and portal instances run a binary which can
+// read it.
+const SealKeyVersionEnvVar = "ARO_ENCRYPTION_SEAL_KEY_VERSION"
+
+// versionedAEAD is an AEAD together with the version of its key
+type versionedAEAD struct {
+	AEAD
+	version string
+}
+
 type multi struct {
-	sealer  AEAD
-	openers []AEAD
+	m metrics.Emitter
+
+	sealer  *versionedAEAD
+	openers []*versionedAEAD
+
+	sealKeyVersion bool
 }
 
 var _ AEAD = (*multi)(nil)
+var _ KeyVersioner = (*multi)(nil)
 
-func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName, legacySecretName string) (AEAD, error) {
-	key, err := serviceKeyvault.GetBase64Secret(ctx, secretName, "")
+func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName, legacySecretName string, m metrics.Emitter) (AEAD, error) {
+	bundle, err := serviceKeyvault.GetSecret(ctx, secretName)
 	if err != nil {
 		return nil, err
 	}
 
-	aead, err := NewAES256SHA512(ctx, key)
-	if err != nil {
-		return nil, err
-	}
+	sealerVersion := secretName + "/" + filepath.Base(*bundle.ID)
+
+	c := &multi{
+		m: m,
 
-	m := &multi{
-		sealer: aead,
+		sealKeyVersion: strings.EqualFold(os.Getenv(SealKeyVersionEnvVar), "true"),
 	}
 
 	for _, x := range []struct {
@@ -43,30 +73,139 @@ func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName,
 			return nil, err
 		}
 
-		for _, key := range keys {
-			aead, err = x.aeadFactory(ctx, key)
+		versions := make([]string, 0, len(keys))
+		for version := range keys {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		for _, version := range versions {
+			aead, err := x.aeadFactory(ctx, keys[version])
 			if err != nil {
 				return nil, err
 			}
 
-			m.openers = append(m.openers, aead)
+			opener := &versionedAEAD{
+				AEAD:    aead,
+				version: x.secretName + "/" + version,
+			}
+
+			if opener.version == sealerVersion {
+				c.sealer = opener
+			}
+
+			c.openers = append(c.openers, opener)
 		}
 	}
 
-	return m, nil
+	if c.sealer == nil {
+		return nil, fmt.Errorf("key version %s is not enabled", sealerVersion)
+	}
+
+	if len(c.sealer.version) > 255 {
+		return nil, fmt.Errorf("key version %s is too long", c.sealer.version)
+	}
+
+	return c, nil
+}
+
+func (c *multi) Open(input []byte) ([]byte, error) {
+	b, version, tagged, err := c.open(input)
+	if err != nil {
+		return nil, err
+	}
+
+	c.m.EmitCounter("encryption.opens", 1, map[string]string{
+		"keyVersion": version,
+		"tagged":     strconv.FormatBool(tagged),
+	})
+
+	return b, nil
 }
 
-func (c *multi) Open(input []byte) (b []byte, err error) {
+// open opens input, using the key named by its key version header if it has
+// one and trying each key otherwise.  It returns the opened value, the
+// version of the key which opened it and whether input had a key version
+// header.
+func (c *multi) open(input []byte) (b []byte, version string, tagged bool, err error) {
+	version, sealed, tagged := splitKeyVersion(input)
+	if tagged {
+		b, err = c.openTagged(version, sealed)
+		if err == nil {
+			return b, version, true, nil
+		}
+
+		// a value sealed without a header may by chance start with one, so
+		// try each key on the whole input before giving up
+		for _, opener := range c.openers {
+			if b, openErr := opener.Open(input); openErr == nil {
+				return b, opener.version, false, nil
+			}
+		}
+
+		return nil, "", true, err
+	}
+
 	for _, opener := range c.openers {
 		b, err = opener.Open(input)
 		if err == nil {
-			return
+			return b, opener.version, false, nil
 		}
 	}
 
-	return nil, err
+	return nil, "", false, err
+}
+
+func (c *multi) openTagged(version string, sealed []byte) ([]byte, error) {
+	for _, opener := range c.openers {
+		if opener.version == version {
+			return opener.Open(sealed)
+		}
+	}
+
+	return nil, fmt.Errorf("key version %s is not enabled", version)
 }
 
 func (c *multi) Seal(input []byte) ([]byte, error) {
-	return c.sealer.Seal(input)
+	b, err := c.sealer.Seal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.sealKeyVersion {
+		return b, nil
+	}
+
+	sealed := make([]byte, 0, len(keyVersionHeader)+1+len(c.sealer.version)+len(b))
+	sealed = append(sealed, keyVersionHeader...)
+	sealed = append(sealed, byte(len(c.sealer.version)))
+	sealed = append(sealed, c.sealer.version...)
+
+	return append(sealed, b...), nil
+}
+
+func (c *multi) SealKeyVersion() string {
+	return c.sealer.version
+}
+
+func (c *multi) OpenKeyVersion(input []byte) (string, error) {
+	_, version, _, err := c.open(input)
+	return version, err
+}
+
+// splitKeyVersion splits a value sealed by multi into the version of the key
+// which sealed it and the sealed value.  It returns false if the value has no
+// key version header.
+func splitKeyVersion(input []byte) (string, []byte, bool) {
+	if !bytes.HasPrefix(input, keyVersionHeader) || len(input) == len(keyVersionHeader) {
+		return "", input, false
+	}
+
+	rest := input[len(keyVersionHeader):]
+	n := int(rest[0])
+	if len(rest) < 1+n {
+		return "", input, false
+	}
+
+	return string(rest[1 : 1+n]), rest[1+n:], true
 }
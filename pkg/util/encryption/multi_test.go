@@ -4,50 +4,197 @@ package encryption
 // Licensed under the Apache License 2.0.
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"reflect"
 	"testing"
 
+	azkeyvault "github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
 	mock_encryption "github.com/Azure/ARO-RP/pkg/util/mocks/encryption"
+	mock_keyvault "github.com/Azure/ARO-RP/pkg/util/mocks/keyvault"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
+func TestNewMulti(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name              string
+		currentVersion    string
+		wantSealerVersion string
+		wantErr           string
+	}{
+		{
+			name:              "current version seals",
+			currentVersion:    "v2",
+			wantSealerVersion: "secret/v2",
+		},
+		{
+			name:           "current version is not enabled",
+			currentVersion: "v3",
+			wantErr:        "key version secret/v3 is not enabled",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			serviceKeyvault := mock_keyvault.NewMockManager(controller)
+			serviceKeyvault.EXPECT().GetSecret(gomock.Any(), "secret").Return(azkeyvault.SecretBundle{
+				ID: to.StringPtr("https://kv.vault.azure.net/secrets/secret/" + tt.currentVersion),
+			}, nil)
+			serviceKeyvault.EXPECT().GetBase64Secrets(gomock.Any(), "secret").Return(map[string][]byte{
+				"v1": bytes.Repeat([]byte{1}, 64),
+				"v2": bytes.Repeat([]byte{2}, 64),
+			}, nil)
+			serviceKeyvault.EXPECT().GetBase64Secrets(gomock.Any(), "legacysecret").Return(map[string][]byte{
+				"v1": bytes.Repeat([]byte{3}, 32),
+			}, nil)
+
+			aead, err := NewMulti(ctx, serviceKeyvault, "secret", "legacysecret", &noop.Noop{})
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+
+			m := aead.(*multi)
+			if m.SealKeyVersion() != tt.wantSealerVersion {
+				t.Error(m.SealKeyVersion())
+			}
+
+			var versions []string
+			for _, opener := range m.openers {
+				versions = append(versions, opener.version)
+			}
+			if !reflect.DeepEqual(versions, []string{"secret/v1", "secret/v2", "legacysecret/v1"}) {
+				t.Error(versions)
+			}
+
+			sealed, err := m.Seal([]byte("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, err := m.OpenKeyVersion(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tt.wantSealerVersion {
+				t.Error(version)
+			}
+
+			// values sealed with an older key still open, and report its version
+			sealed, err = m.openers[2].Seal([]byte("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, err = m.OpenKeyVersion(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != "legacysecret/v1" {
+				t.Error(version)
+			}
+		})
+	}
+}
+
 func TestOpen(t *testing.T) {
 	mockInput := []byte("fakeInput")
+	// mockTaggedInput is "sealed" sealed with key version secret/2
+	mockTaggedInput := []byte("\x00aro\x08secret/2sealed")
 
 	type test struct {
 		name       string
-		mocks      func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD)
+		input      []byte
+		mocks      func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter)
 		wantResult []byte
 		wantErr    string
 	}
 
 	for _, tt := range []*test{
 		{
-			name: "first opener succeeds, do not try second",
-			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD) {
+			name:  "first opener succeeds, do not try second",
+			input: mockInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
 				firstOpener.EXPECT().Open(mockInput).Return([]byte("result from the first opener"), nil)
+				m.EXPECT().EmitCounter("encryption.opens", int64(1), map[string]string{"keyVersion": "secret/1", "tagged": "false"})
 			},
 			wantResult: []byte("result from the first opener"),
 		},
 		{
-			name: "first opener errors, but second succeeds",
-			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD) {
+			name:  "first opener errors, but second succeeds",
+			input: mockInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
 				firstOpener.EXPECT().Open(mockInput).Return(nil, errors.New("fake error from the first opener"))
 				secondOpener.EXPECT().Open(mockInput).Return([]byte("result from the second opener"), nil)
+				m.EXPECT().EmitCounter("encryption.opens", int64(1), map[string]string{"keyVersion": "secret/2", "tagged": "false"})
 			},
 			wantResult: []byte("result from the second opener"),
 		},
 		{
-			name: "all openers error",
-			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD) {
+			name:  "all openers error",
+			input: mockInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
 				firstOpener.EXPECT().Open(mockInput).Return(nil, errors.New("fake error from the first opener"))
 				secondOpener.EXPECT().Open(mockInput).Return(nil, errors.New("fake error from the second opener"))
 			},
 			wantErr: "fake error from the second opener",
 		},
+		{
+			name:  "key version header picks the opener",
+			input: mockTaggedInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
+				secondOpener.EXPECT().Open([]byte("sealed")).Return([]byte("result from the second opener"), nil)
+				m.EXPECT().EmitCounter("encryption.opens", int64(1), map[string]string{"keyVersion": "secret/2", "tagged": "true"})
+			},
+			wantResult: []byte("result from the second opener"),
+		},
+		{
+			name:  "opener picked by key version header errors",
+			input: mockTaggedInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
+				secondOpener.EXPECT().Open([]byte("sealed")).Return(nil, errors.New("fake error from the second opener"))
+				firstOpener.EXPECT().Open(mockTaggedInput).Return(nil, errors.New("fake error from the first opener"))
+				secondOpener.EXPECT().Open(mockTaggedInput).Return(nil, errors.New("fake error from the second opener"))
+			},
+			wantErr: "fake error from the second opener",
+		},
+		{
+			name:  "key version in header is not enabled",
+			input: []byte("\x00aro\x08secret/3sealed"),
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
+				firstOpener.EXPECT().Open([]byte("\x00aro\x08secret/3sealed")).Return(nil, errors.New("fake error from the first opener"))
+				secondOpener.EXPECT().Open([]byte("\x00aro\x08secret/3sealed")).Return(nil, errors.New("fake error from the second opener"))
+			},
+			wantErr: "key version secret/3 is not enabled",
+		},
+		{
+			name:  "untagged value which looks tagged falls back to each key",
+			input: mockTaggedInput,
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
+				secondOpener.EXPECT().Open([]byte("sealed")).Return(nil, errors.New("fake error from the second opener"))
+				firstOpener.EXPECT().Open(mockTaggedInput).Return([]byte("result from the first opener"), nil)
+				m.EXPECT().EmitCounter("encryption.opens", int64(1), map[string]string{"keyVersion": "secret/1", "tagged": "false"})
+			},
+			wantResult: []byte("result from the first opener"),
+		},
+		{
+			name:  "truncated header is treated as untagged",
+			input: []byte("\x00aro\x08secret"),
+			mocks: func(firstOpener *mock_encryption.MockAEAD, secondOpener *mock_encryption.MockAEAD, m *mock_metrics.MockEmitter) {
+				firstOpener.EXPECT().Open([]byte("\x00aro\x08secret")).Return([]byte("result from the first opener"), nil)
+				m.EXPECT().EmitCounter("encryption.opens", int64(1), map[string]string{"keyVersion": "secret/1", "tagged": "false"})
+			},
+			wantResult: []byte("result from the first opener"),
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			controller := gomock.NewController(t)
@@ -55,17 +202,19 @@ func TestOpen(t *testing.T) {
 
 			firstOpener := mock_encryption.NewMockAEAD(controller)
 			secondOpener := mock_encryption.NewMockAEAD(controller)
+			m := mock_metrics.NewMockEmitter(controller)
 
 			multi := multi{
-				openers: []AEAD{
-					firstOpener,
-					secondOpener,
+				m: m,
+				openers: []*versionedAEAD{
+					{AEAD: firstOpener, version: "secret/1"},
+					{AEAD: secondOpener, version: "secret/2"},
 				},
 			}
 
-			tt.mocks(firstOpener, secondOpener)
+			tt.mocks(firstOpener, secondOpener, m)
 
-			b, err := multi.Open(mockInput)
+			b, err := multi.Open(tt.input)
 			utilerror.AssertErrorMessage(t, err, tt.wantErr)
 			if b != nil && !reflect.DeepEqual(tt.wantResult, b) ||
 				b == nil && tt.wantResult != nil {
@@ -74,3 +223,43 @@ func TestOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestSeal(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		sealKeyVersion bool
+		want           []byte
+	}{
+		{
+			name: "key version header is not written by default",
+			want: []byte("sealed"),
+		},
+		{
+			name:           "key version header is written when enabled",
+			sealKeyVersion: true,
+			want:           []byte("\x00aro\x08secret/2sealed"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			sealer := mock_encryption.NewMockAEAD(controller)
+			sealer.EXPECT().Seal([]byte("input")).Return([]byte("sealed"), nil)
+
+			multi := multi{
+				sealer:         &versionedAEAD{AEAD: sealer, version: "secret/2"},
+				sealKeyVersion: tt.sealKeyVersion,
+			}
+
+			b, err := multi.Seal([]byte("input"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(b, tt.want) {
+				t.Errorf("%q", b)
+			}
+		})
+	}
+}
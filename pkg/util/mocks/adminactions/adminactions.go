@@ -18,6 +18,8 @@ import (
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	watch "k8s.io/apimachinery/pkg/watch"
+
+	blobstore "github.com/Azure/ARO-RP/pkg/util/blobstore"
 )
 
 // MockKubeActions is a mock of KubeActions interface.
@@ -202,6 +204,34 @@ func (mr *MockKubeActionsMockRecorder) ResolveGVR(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveGVR", reflect.TypeOf((*MockKubeActions)(nil).ResolveGVR), arg0, arg1)
 }
 
+// RunMustGather mocks base method.
+func (m *MockKubeActions) RunMustGather(arg0 context.Context, arg1 io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunMustGather", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunMustGather indicates an expected call of RunMustGather.
+func (mr *MockKubeActionsMockRecorder) RunMustGather(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMustGather", reflect.TypeOf((*MockKubeActions)(nil).RunMustGather), arg0, arg1)
+}
+
+// RunNodeDiagnostic mocks base method.
+func (m *MockKubeActions) RunNodeDiagnostic(arg0 context.Context, arg1 http.ResponseWriter, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunNodeDiagnostic", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunNodeDiagnostic indicates an expected call of RunNodeDiagnostic.
+func (mr *MockKubeActionsMockRecorder) RunNodeDiagnostic(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNodeDiagnostic", reflect.TypeOf((*MockKubeActions)(nil).RunNodeDiagnostic), arg0, arg1, arg2, arg3)
+}
+
 // MockAzureActions is a mock of AzureActions interface.
 type MockAzureActions struct {
 	ctrl     *gomock.Controller
@@ -270,6 +300,64 @@ func (mr *MockAzureActionsMockRecorder) GroupResourceList(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupResourceList", reflect.TypeOf((*MockAzureActions)(nil).GroupResourceList), arg0)
 }
 
+// MustGatherGet mocks base method.
+func (m *MockAzureActions) MustGatherGet(arg0 context.Context, arg1 string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MustGatherGet", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MustGatherGet indicates an expected call of MustGatherGet.
+func (mr *MockAzureActionsMockRecorder) MustGatherGet(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MustGatherGet", reflect.TypeOf((*MockAzureActions)(nil).MustGatherGet), arg0, arg1)
+}
+
+// MustGatherList mocks base method.
+func (m *MockAzureActions) MustGatherList(arg0 context.Context) ([]*blobstore.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MustGatherList", arg0)
+	ret0, _ := ret[0].([]*blobstore.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MustGatherList indicates an expected call of MustGatherList.
+func (mr *MockAzureActionsMockRecorder) MustGatherList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MustGatherList", reflect.TypeOf((*MockAzureActions)(nil).MustGatherList), arg0)
+}
+
+// MustGatherPutStatus mocks base method.
+func (m *MockAzureActions) MustGatherPutStatus(arg0 context.Context, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MustGatherPutStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MustGatherPutStatus indicates an expected call of MustGatherPutStatus.
+func (mr *MockAzureActionsMockRecorder) MustGatherPutStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MustGatherPutStatus", reflect.TypeOf((*MockAzureActions)(nil).MustGatherPutStatus), arg0, arg1, arg2)
+}
+
+// MustGatherUpload mocks base method.
+func (m *MockAzureActions) MustGatherUpload(arg0 context.Context, arg1 string, arg2 io.Reader, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MustGatherUpload", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MustGatherUpload indicates an expected call of MustGatherUpload.
+func (mr *MockAzureActionsMockRecorder) MustGatherUpload(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MustGatherUpload", reflect.TypeOf((*MockAzureActions)(nil).MustGatherUpload), arg0, arg1, arg2, arg3)
+}
+
 // NICReconcileFailedState mocks base method.
 func (m *MockAzureActions) NICReconcileFailedState(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
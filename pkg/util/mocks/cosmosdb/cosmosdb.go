@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/Azure/ARO-RP/pkg/database/cosmosdb (interfaces: PermissionClient)
+// Source: github.com/Azure/ARO-RP/pkg/database/cosmosdb (interfaces: PermissionClient,TokenPermissionClient,UserClient)
 
 // Package mock_cosmosdb is a generated GoMock package.
 package mock_cosmosdb
@@ -7,6 +7,7 @@ package mock_cosmosdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 
@@ -123,3 +124,240 @@ func (mr *MockPermissionClientMockRecorder) Replace(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockPermissionClient)(nil).Replace), arg0, arg1)
 }
+
+// MockTokenPermissionClient is a mock of TokenPermissionClient interface.
+type MockTokenPermissionClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenPermissionClientMockRecorder
+}
+
+// MockTokenPermissionClientMockRecorder is the mock recorder for MockTokenPermissionClient.
+type MockTokenPermissionClientMockRecorder struct {
+	mock *MockTokenPermissionClient
+}
+
+// NewMockTokenPermissionClient creates a new mock instance.
+func NewMockTokenPermissionClient(ctrl *gomock.Controller) *MockTokenPermissionClient {
+	mock := &MockTokenPermissionClient{ctrl: ctrl}
+	mock.recorder = &MockTokenPermissionClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenPermissionClient) EXPECT() *MockTokenPermissionClientMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTokenPermissionClient) Create(arg0 context.Context, arg1 *cosmosdb.Permission) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTokenPermissionClientMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTokenPermissionClient)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockTokenPermissionClient) Delete(arg0 context.Context, arg1 *cosmosdb.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTokenPermissionClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTokenPermissionClient)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockTokenPermissionClient) Get(arg0 context.Context, arg1 string) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTokenPermissionClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTokenPermissionClient)(nil).Get), arg0, arg1)
+}
+
+// GetWithExpiry mocks base method.
+func (m *MockTokenPermissionClient) GetWithExpiry(arg0 context.Context, arg1 string, arg2 time.Duration) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithExpiry", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithExpiry indicates an expected call of GetWithExpiry.
+func (mr *MockTokenPermissionClientMockRecorder) GetWithExpiry(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithExpiry", reflect.TypeOf((*MockTokenPermissionClient)(nil).GetWithExpiry), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockTokenPermissionClient) List() cosmosdb.PermissionIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].(cosmosdb.PermissionIterator)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockTokenPermissionClientMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTokenPermissionClient)(nil).List))
+}
+
+// ListAll mocks base method.
+func (m *MockTokenPermissionClient) ListAll(arg0 context.Context) (*cosmosdb.Permissions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", arg0)
+	ret0, _ := ret[0].(*cosmosdb.Permissions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockTokenPermissionClientMockRecorder) ListAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockTokenPermissionClient)(nil).ListAll), arg0)
+}
+
+// Replace mocks base method.
+func (m *MockTokenPermissionClient) Replace(arg0 context.Context, arg1 *cosmosdb.Permission) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockTokenPermissionClientMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockTokenPermissionClient)(nil).Replace), arg0, arg1)
+}
+
+// MockUserClient is a mock of UserClient interface.
+type MockUserClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserClientMockRecorder
+}
+
+// MockUserClientMockRecorder is the mock recorder for MockUserClient.
+type MockUserClientMockRecorder struct {
+	mock *MockUserClient
+}
+
+// NewMockUserClient creates a new mock instance.
+func NewMockUserClient(ctrl *gomock.Controller) *MockUserClient {
+	mock := &MockUserClient{ctrl: ctrl}
+	mock.recorder = &MockUserClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserClient) EXPECT() *MockUserClientMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserClient) Create(arg0 context.Context, arg1 *cosmosdb.User) (*cosmosdb.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserClientMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserClient)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockUserClient) Delete(arg0 context.Context, arg1 *cosmosdb.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUserClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserClient)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockUserClient) Get(arg0 context.Context, arg1 string) (*cosmosdb.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserClient)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockUserClient) List() cosmosdb.UserIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].(cosmosdb.UserIterator)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockUserClientMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserClient)(nil).List))
+}
+
+// ListAll mocks base method.
+func (m *MockUserClient) ListAll(arg0 context.Context) (*cosmosdb.Users, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", arg0)
+	ret0, _ := ret[0].(*cosmosdb.Users)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockUserClientMockRecorder) ListAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockUserClient)(nil).ListAll), arg0)
+}
+
+// Replace mocks base method.
+func (m *MockUserClient) Replace(arg0 context.Context, arg1 *cosmosdb.User) (*cosmosdb.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockUserClientMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockUserClient)(nil).Replace), arg0, arg1)
+}
@@ -33,6 +33,18 @@ func (m *MockEmitter) EXPECT() *MockEmitterMockRecorder {
 	return m.recorder
 }
 
+// EmitCounter mocks base method.
+func (m *MockEmitter) EmitCounter(arg0 string, arg1 int64, arg2 map[string]string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmitCounter", arg0, arg1, arg2)
+}
+
+// EmitCounter indicates an expected call of EmitCounter.
+func (mr *MockEmitterMockRecorder) EmitCounter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitCounter", reflect.TypeOf((*MockEmitter)(nil).EmitCounter), arg0, arg1, arg2)
+}
+
 // EmitFloat mocks base method.
 func (m *MockEmitter) EmitFloat(arg0 string, arg1 float64, arg2 map[string]string) {
 	m.ctrl.T.Helper()
@@ -56,3 +68,15 @@ func (mr *MockEmitterMockRecorder) EmitGauge(arg0, arg1, arg2 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitGauge", reflect.TypeOf((*MockEmitter)(nil).EmitGauge), arg0, arg1, arg2)
 }
+
+// EmitHistogram mocks base method.
+func (m *MockEmitter) EmitHistogram(arg0 string, arg1 float64, arg2 map[string]string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmitHistogram", arg0, arg1, arg2)
+}
+
+// EmitHistogram indicates an expected call of EmitHistogram.
+func (mr *MockEmitterMockRecorder) EmitHistogram(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitHistogram", reflect.TypeOf((*MockEmitter)(nil).EmitHistogram), arg0, arg1, arg2)
+}
@@ -0,0 +1,43 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AlwaysRun returns a wrapper Step which opts `step` out of being skipped
+// when RunResumable resumes from a checkpoint, including when it is part of a
+// completed Parallel group.  It is intended for steps which are not safe to
+// skip, such as those which initialise in-memory state (e.g. clients) that
+// later steps depend on: checkpoints record only which steps completed, so
+// such state is re-derived by running the step again.
+func AlwaysRun(step Step) Step {
+	return alwaysRunStep{
+		step: step,
+	}
+}
+
+type alwaysRunStep struct {
+	step Step
+}
+
+func (s alwaysRunStep) run(ctx context.Context, log *logrus.Entry) error {
+	return s.step.run(ctx, log)
+}
+
+func (s alwaysRunStep) String() string {
+	return s.step.String()
+}
+
+func (s alwaysRunStep) metricsName() string {
+	return s.step.metricsName()
+}
+
+func isAlwaysRun(step Step) bool {
+	_, ok := step.(alwaysRunStep)
+	return ok
+}
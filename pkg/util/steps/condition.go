@@ -81,7 +81,13 @@ func (c conditionStep) run(ctx context.Context, log *logrus.Entry) error {
 	// is ErrWaitTimeout. Internal ErrWaitTimeout errors are wrapped to avoid
 	// confusion with wait.PollImmediateUntil's own behavior of returning
 	// ErrWaitTimeout when the condition is not met.
+	var attempted bool
 	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		if attempted {
+			countRetry(ctx)
+		}
+		attempted = true
+
 		// We use the outer context, not the timeout context, as we do not want
 		// to time out the condition function itself, only stop retrying once
 		// timeoutCtx's timeout has fired.
@@ -0,0 +1,80 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of Step, as reported by Describe
+const (
+	KindAction                      = "Action"
+	KindCondition                   = "Condition"
+	KindAuthorizationRetryingAction = "AuthorizationRetryingAction"
+	KindParallel                    = "Parallel"
+)
+
+// Description describes a Step without running it
+type Description struct {
+	// Kind is one of KindAction, KindCondition,
+	// KindAuthorizationRetryingAction or KindParallel
+	Kind string
+
+	// Func is the short name of the function the Step wraps, e.g.
+	// "ensureResourceGroup"
+	Func string
+
+	// Timeout and FailOnTimeout are only set for conditions
+	Timeout       time.Duration
+	FailOnTimeout bool
+
+	// Steps is only set for parallel groups
+	Steps []Description
+}
+
+// Describe returns a Description of the given Step.
+func Describe(step Step) Description {
+	switch s := step.(type) {
+	case alwaysRunStep:
+		return Describe(s.step)
+	case actionStep:
+		return Description{
+			Kind: KindAction,
+			Func: funcName(s.f),
+		}
+	case conditionStep:
+		return Description{
+			Kind:          KindCondition,
+			Func:          funcName(s.f),
+			Timeout:       s.timeout,
+			FailOnTimeout: s.fail,
+		}
+	case *conditionStep:
+		return Describe(*s)
+	case parallelStep:
+		d := Description{
+			Kind: KindParallel,
+		}
+		for _, step := range s.steps {
+			d.Steps = append(d.Steps, Describe(step))
+		}
+		return d
+	case *authorizationRefreshingActionStep:
+		return Description{
+			Kind: KindAuthorizationRetryingAction,
+			Func: funcName(s.f),
+		}
+	}
+
+	return Description{
+		Func: step.String(),
+	}
+}
+
+// funcName returns the short name of f, without the "-fm" suffix which the Go
+// runtime adds to method values.
+func funcName(f interface{}) string {
+	return strings.TrimSuffix(shortName(FriendlyName(f)), "-fm")
+}
@@ -0,0 +1,46 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestDescribe(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		step Step
+		want Description
+	}{
+		{
+			name: "action",
+			step: Action(successfulFunc),
+			want: Description{Kind: KindAction, Func: "successfulFunc"},
+		},
+		{
+			name: "condition",
+			step: Condition(alwaysTrueCondition, 5*time.Minute, false),
+			want: Description{Kind: KindCondition, Func: "alwaysTrueCondition", Timeout: 5 * time.Minute},
+		},
+		{
+			name: "authorization retrying action",
+			step: AuthorizationRetryingAction(nil, successfulFunc),
+			want: Description{Kind: KindAuthorizationRetryingAction, Func: "successfulFunc"},
+		},
+		{
+			name: "always run wrapper is transparent",
+			step: AlwaysRun(Condition(alwaysTrueCondition, time.Minute, true)),
+			want: Description{Kind: KindCondition, Func: "alwaysTrueCondition", Timeout: time.Minute, FailOnTimeout: true},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, d := range deep.Equal(Describe(tt.step), tt.want) {
+				t.Error(d)
+			}
+		})
+	}
+}
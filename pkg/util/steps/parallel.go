@@ -0,0 +1,149 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Parallel returns a Step which runs the given steps concurrently and
+// completes once they have all completed.  When a step fails, the context
+// passed to the remaining steps is cancelled.  If a single step fails its
+// error is returned directly, otherwise the errors are aggregated.
+//
+// Steps run in parallel must be independent of each other and must not race
+// on shared state: in particular, steps which update the cluster document
+// must not be run in parallel.
+func Parallel(steps ...Step) Step {
+	return parallelStep{
+		steps: steps,
+	}
+}
+
+type parallelStep struct {
+	steps []Step
+}
+
+func (s parallelStep) run(ctx context.Context, log *logrus.Entry) error {
+	_, err := s.runParallel(ctx, log, nil, nil)
+	return err
+}
+
+// runParallel runs the steps concurrently, returning the time cost of each
+// step for metrics usage if now is not nil and passing a Record of each step
+// to rec if it is not nil.
+func (s parallelStep) runParallel(ctx context.Context, log *logrus.Entry, now func() time.Time, rec Recorder) (map[string]int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	stepTimeRun := make(map[string]int64)
+
+	for _, step := range s.steps {
+		wg.Add(1)
+
+		go func(step Step) {
+			defer wg.Done()
+
+			log.Infof("running step %s", step)
+
+			startTime := time.Now()
+			err := runRecorded(ctx, log, step, rec, func(ctx context.Context, log *logrus.Entry) error {
+				return runRecovering(ctx, log, step)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				// Don't report errors caused by a sibling having failed and
+				// cancelled the context.
+				if len(errs) > 0 && ctx.Err() != nil &&
+					(errors.Is(err, context.Canceled) || errors.Is(err, wait.ErrWaitTimeout)) {
+					log.Warnf("step %s cancelled: %s", step, err.Error())
+					return
+				}
+
+				log.Errorf("step %s encountered error: %s", step, err.Error())
+				errs = append(errs, err)
+				cancel()
+				return
+			}
+
+			if now != nil {
+				stepTimeRun[step.metricsName()] = int64(now().Sub(startTime).Seconds())
+			}
+		}(step)
+	}
+
+	wg.Wait()
+
+	switch len(errs) {
+	case 0:
+		return stepTimeRun, nil
+	case 1:
+		return nil, errs[0]
+	default:
+		return nil, utilerrors.NewAggregate(errs)
+	}
+}
+
+// runAlwaysRun runs those of the steps which are marked with AlwaysRun
+func (s parallelStep) runAlwaysRun(ctx context.Context, log *logrus.Entry) error {
+	var steps []Step
+	for _, step := range s.steps {
+		if isAlwaysRun(step) {
+			steps = append(steps, step)
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil
+	}
+
+	return parallelStep{steps: steps}.run(ctx, log)
+}
+
+// runRecovering runs step, converting a panic into an error so that a panic in
+// one step of a parallel group fails the group rather than the process.
+func runRecovering(ctx context.Context, log *logrus.Entry, step Step) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Info(string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+
+	return step.run(ctx, log)
+}
+
+func (s parallelStep) String() string {
+	names := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		names = append(names, step.String())
+	}
+
+	return fmt.Sprintf("[Parallel %s]", strings.Join(names, ", "))
+}
+
+func (s parallelStep) metricsName() string {
+	names := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		names = append(names, step.metricsName())
+	}
+
+	return fmt.Sprintf("parallel.%s", strings.Join(names, "."))
+}
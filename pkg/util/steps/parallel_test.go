@@ -0,0 +1,126 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func waitForCancelFunc(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(10 * time.Second):
+		return errors.New("not cancelled")
+	}
+}
+
+func otherFailingFunc(context.Context) error { return errors.New("oh no again!") }
+
+func panickingFunc(context.Context) error { panic("oh dear") }
+
+func TestParallel(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		steps       []Step
+		wantMetrics map[string]int64
+		wantErr     []string
+	}{
+		{
+			name: "all successful steps record their own timings",
+			steps: []Step{
+				Action(successfulFunc),
+				Parallel(
+					Action(successfulFunc),
+					Condition(alwaysTrueCondition, 50*time.Millisecond, true),
+				),
+			},
+			wantMetrics: map[string]int64{
+				"action.successfulFunc":         1,
+				"condition.alwaysTrueCondition": 1,
+			},
+		},
+		{
+			name: "a failing step cancels its siblings and its error is returned directly",
+			steps: []Step{
+				Parallel(
+					Action(waitForCancelFunc),
+					Action(failingFunc),
+				),
+				Action(successfulFunc),
+			},
+			wantErr: []string{"oh no!"},
+		},
+		{
+			name: "errors from steps which fail independently are aggregated",
+			steps: []Step{
+				Parallel(
+					Action(failingFunc),
+					Action(otherFailingFunc),
+				),
+			},
+			// the order of aggregated errors depends on scheduling
+			wantErr: []string{"[oh no!, oh no again!]", "[oh no again!, oh no!]"},
+		},
+		{
+			name: "a panicking step fails the group",
+			steps: []Step{
+				Parallel(
+					Action(successfulFunc),
+					Action(panickingFunc),
+				),
+			},
+			wantErr: []string{"panic: oh dear"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			_, log := testlog.New()
+
+			stepTimeRun, err := Run(ctx, log, 25*time.Millisecond, tt.steps, func() time.Time { return time.Now().Add(time.Second) })
+			switch {
+			case err == nil && tt.wantErr != nil:
+				t.Errorf("got nil error, want one of %q", tt.wantErr)
+			case err != nil && !containsString(tt.wantErr, err.Error()):
+				t.Errorf("got error %q, want one of %q", err, tt.wantErr)
+			}
+
+			if tt.wantMetrics != nil {
+				for _, d := range deep.Equal(stepTimeRun, tt.wantMetrics) {
+					t.Error(d)
+				}
+			}
+		})
+	}
+}
+
+func TestParallelStepNaming(t *testing.T) {
+	step := Parallel(Action(successfulFunc), Condition(alwaysTrueCondition, time.Minute, true))
+
+	wantString := "[Parallel [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 1m0s]]"
+	if got := step.String(); got != wantString {
+		t.Errorf("got %q, want %q", got, wantString)
+	}
+
+	wantMetricsName := "parallel.action.successfulFunc.condition.alwaysTrueCondition"
+	if got := step.metricsName(); got != wantMetricsName {
+		t.Errorf("got %q, want %q", got, wantMetricsName)
+	}
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
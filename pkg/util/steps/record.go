@@ -0,0 +1,100 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// Outcomes of a step, as reported in a Record
+const (
+	OutcomeSucceeded = "Succeeded"
+	OutcomeFailed    = "Failed"
+	OutcomeSkipped   = "Skipped"
+)
+
+// Record is structured telemetry about a single step run by RunResumable
+type Record struct {
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Outcome    string
+	ErrorClass string
+	Retries    int
+}
+
+// Recorder receives a Record for each step run or skipped by RunResumable.
+// Recording is best effort: it must not fail the run.  Record may be called
+// concurrently for steps run in parallel.
+type Recorder interface {
+	Record(ctx context.Context, r Record)
+}
+
+// recordTimeout bounds a call to Recorder.Record
+const recordTimeout = 30 * time.Second
+
+// record passes r to rec with a context of its own, so that the outcome of a
+// step which failed because the run's context was cancelled or timed out is
+// still recorded.
+func record(rec Recorder, r Record) {
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	rec.Record(ctx, r)
+}
+
+type retryCounterContextKey struct{}
+
+// withRetryCounter returns a context which steps can use to count how many
+// times they retried, and a func returning the count so far.
+func withRetryCounter(ctx context.Context) (context.Context, func() int) {
+	var retries int32
+	return context.WithValue(ctx, retryCounterContextKey{}, &retries), func() int {
+		return int(atomic.LoadInt32(&retries))
+	}
+}
+
+// countRetry counts a retry against the counter in ctx, if there is one.
+func countRetry(ctx context.Context) {
+	if retries, ok := ctx.Value(retryCounterContextKey{}).(*int32); ok {
+		atomic.AddInt32(retries, 1)
+	}
+}
+
+// ErrorClass returns a short, low-cardinality classification of err which is
+// suitable for telemetry.
+func ErrorClass(err error) string {
+	var cloudErr *api.CloudError
+	var detailedErr autorest.DetailedError
+	var statusErr kerrors.APIStatus
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &cloudErr):
+		return fmt.Sprintf("CloudError/%s", cloudErr.Code)
+	case errors.Is(err, wait.ErrWaitTimeout):
+		return "Timeout"
+	case errors.Is(err, context.Canceled):
+		return "Canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DeadlineExceeded"
+	case errors.As(err, &detailedErr):
+		return fmt.Sprintf("Azure/%v", detailedErr.StatusCode)
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("Kubernetes/%s", kerrors.ReasonForError(err))
+	}
+
+	return "Internal"
+}
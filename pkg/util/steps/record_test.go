@@ -0,0 +1,198 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/go-test/deep"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+type fakeRecorder struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (r *fakeRecorder) Record(ctx context.Context, rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// records are written even if the run's context is done, as
+	// stepRecorder does with the database
+	if ctx.Err() != nil {
+		return
+	}
+
+	if rec.StartTime.IsZero() || rec.EndTime.Before(rec.StartTime) {
+		panic(fmt.Sprintf("invalid record times %s, %s", rec.StartTime, rec.EndTime))
+	}
+
+	// times are not deterministic
+	rec.StartTime = time.Time{}
+	rec.EndTime = time.Time{}
+	r.records = append(r.records, rec)
+}
+
+type eventualCondition struct {
+	calls int
+}
+
+func (c *eventualCondition) trueOnThirdCall(context.Context) (bool, error) {
+	c.calls++
+	return c.calls == 3, nil
+}
+
+func TestRunResumableRecords(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		steps     []Step
+		completed []string
+		want      []Record
+		wantErr   string
+	}{
+		{
+			name: "run, skipped and failed steps are recorded",
+			steps: []Step{
+				Action(successfulFunc),
+				Action(successfulFunc),
+				Action(failingFunc),
+			},
+			completed: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+			want: []Record{
+				{Name: "action.successfulFunc", Outcome: OutcomeSkipped},
+				{Name: "action.successfulFunc", Outcome: OutcomeSucceeded},
+				{Name: "action.failingFunc", Outcome: OutcomeFailed, ErrorClass: "Internal"},
+			},
+			wantErr: "oh no!",
+		},
+		{
+			name: "condition retries are counted",
+			steps: []Step{
+				&conditionStep{
+					f:            (&eventualCondition{}).trueOnThirdCall,
+					timeout:      time.Second,
+					fail:         true,
+					pollInterval: 5 * time.Millisecond,
+				},
+			},
+			want: []Record{
+				{Name: "condition.trueOnThirdCall-fm", Outcome: OutcomeSucceeded, Retries: 2},
+			},
+		},
+		{
+			name: "steps in a parallel group are recorded individually",
+			steps: []Step{
+				Parallel(
+					Action(successfulFunc),
+				),
+			},
+			want: []Record{
+				{Name: "action.successfulFunc", Outcome: OutcomeSucceeded},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			_, log := testlog.New()
+			cp := &fakeCheckpointer{completed: tt.completed}
+			rec := &fakeRecorder{}
+
+			_, err := RunResumable(ctx, log, 25*time.Millisecond, tt.steps, currentTimeFunc, cp, rec)
+			if err == nil && tt.wantErr != "" || err != nil && err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+
+			for _, diff := range deep.Equal(rec.records, tt.want) {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRunResumableRecordsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, log := testlog.New()
+	rec := &fakeRecorder{}
+
+	steps := []Step{
+		Action(func(context.Context) error {
+			cancel()
+			return context.Canceled
+		}),
+	}
+
+	_, err := RunResumable(ctx, log, 25*time.Millisecond, steps, currentTimeFunc, nil, rec)
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+
+	for _, diff := range deep.Equal(rec.records, []Record{
+		{Name: "action.func1", Outcome: OutcomeFailed, ErrorClass: ErrorClass(context.Canceled)},
+	}) {
+		t.Error(diff)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "cloud error",
+			err:  api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", ""),
+			want: "CloudError/InvalidParameter",
+		},
+		{
+			name: "wrapped timeout",
+			err:  fmt.Errorf("waiting: %w", wait.ErrWaitTimeout),
+			want: "Timeout",
+		},
+		{
+			name: "canceled",
+			err:  context.Canceled,
+			want: "Canceled",
+		},
+		{
+			name: "azure error",
+			err:  autorest.DetailedError{StatusCode: http.StatusConflict},
+			want: "Azure/409",
+		},
+		{
+			name: "kubernetes error",
+			err:  kerrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "name"),
+			want: "Kubernetes/NotFound",
+		},
+		{
+			name: "other error",
+			err:  errors.New("oh no!"),
+			want: "Internal",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorClass(tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -77,6 +77,7 @@ func (s *authorizationRefreshingActionStep) run(ctx context.Context, log *logrus
 				azureerrors.IsInvalidSecretError(err) ||
 				err == ErrWantRefresh) {
 			log.Printf("auth error, refreshing and retrying: %v", err)
+			countRetry(ctx)
 			// Try refreshing auth.
 			err = s.auth.Rebuild()
 			return false, err // retry step
@@ -41,16 +41,76 @@ type Step interface {
 	metricsName() string
 }
 
+// Checkpointer persists the progress of a Run so that a later Run of the same
+// steps can resume from the first unfinished step.
+type Checkpointer interface {
+	// Completed returns the names of the steps which have already completed,
+	// in run order.
+	Completed() []string
+
+	// Checkpoint records that the step at index i has completed, discarding
+	// any records for steps at or after index i.
+	Checkpoint(ctx context.Context, i int, name string, duration time.Duration) error
+}
+
 // Run executes the provided steps in order until one fails or all steps
 // are completed. Errors from failed steps are returned directly.
 // time cost for each step run will be recorded for metrics usage
 func Run(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, steps []Step, now func() time.Time) (map[string]int64, error) {
+	return RunResumable(ctx, log, pollInterval, steps, now, nil, nil)
+}
+
+// RunResumable behaves like Run, but records each completed step using cp.
+// Leading steps which cp reports as already completed are skipped, unless
+// they are marked with AlwaysRun.  If rec is not nil, a Record of each step
+// run or skipped is passed to it.  If cp and rec are nil, RunResumable behaves
+// exactly like Run.
+func RunResumable(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, steps []Step, now func() time.Time, cp Checkpointer, rec Recorder) (map[string]int64, error) {
+	var resumeFrom int
+	if cp != nil {
+		resumeFrom = completedPrefix(steps, cp.Completed())
+		if resumeFrom > 0 {
+			log.Infof("resuming after %d completed steps", resumeFrom)
+		}
+	}
+
 	stepTimeRun := make(map[string]int64)
-	for _, step := range steps {
+	for i, step := range steps {
+		if i < resumeFrom && !isAlwaysRun(step) {
+			log.Infof("skipping completed step %s", step)
+			if rec != nil {
+				t := time.Now()
+				record(rec, Record{Name: step.metricsName(), StartTime: t, EndTime: t, Outcome: OutcomeSkipped})
+			}
+
+			// the AlwaysRun steps of a completed parallel group still run, so
+			// that the in-memory state they produce is re-derived
+			if p, ok := step.(parallelStep); ok {
+				err := p.runAlwaysRun(ctx, log)
+				if err != nil {
+					log.Errorf("step %s encountered error: %s", step, err.Error())
+					return nil, err
+				}
+			}
+			continue
+		}
+
 		log.Infof("running step %s", step)
 
 		startTime := time.Now()
-		err := step.run(ctx, log)
+
+		var err error
+		if p, ok := step.(parallelStep); ok {
+			// record the time cost of each step in the group, rather than of
+			// the group as a whole
+			var parallelTimeRun map[string]int64
+			parallelTimeRun, err = p.runParallel(ctx, log, now, rec)
+			for name, duration := range parallelTimeRun {
+				stepTimeRun[name] = duration
+			}
+		} else {
+			err = runRecorded(ctx, log, step, rec, step.run)
+		}
 
 		if err != nil {
 			log.Errorf("step %s encountered error: %s", step, err.Error())
@@ -60,10 +120,61 @@ func Run(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, ste
 			return nil, err
 		}
 
+		currentTime := time.Now()
 		if now != nil {
-			currentTime := now()
-			stepTimeRun[step.metricsName()] = int64(currentTime.Sub(startTime).Seconds())
+			currentTime = now()
+			if _, ok := step.(parallelStep); !ok {
+				stepTimeRun[step.metricsName()] = int64(currentTime.Sub(startTime).Seconds())
+			}
+		}
+
+		if cp != nil && i >= resumeFrom {
+			err = cp.Checkpoint(ctx, i, step.String(), currentTime.Sub(startTime))
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return stepTimeRun, nil
 }
+
+// runRecorded calls run for step and, if rec is not nil, passes it a Record
+// of the outcome.
+func runRecorded(ctx context.Context, log *logrus.Entry, step Step, rec Recorder, run func(context.Context, *logrus.Entry) error) error {
+	if rec == nil {
+		return run(ctx, log)
+	}
+
+	ctx, retries := withRetryCounter(ctx)
+
+	r := Record{
+		Name:      step.metricsName(),
+		StartTime: time.Now(),
+		Outcome:   OutcomeSucceeded,
+	}
+
+	err := run(ctx, log)
+
+	r.EndTime = time.Now()
+	r.Retries = retries()
+	if err != nil {
+		r.Outcome = OutcomeFailed
+		r.ErrorClass = ErrorClass(err)
+	}
+
+	record(rec, r)
+
+	return err
+}
+
+// completedPrefix returns the number of leading steps which match the
+// completed step names.  Matching stops at the first difference, so if the
+// list of steps has changed since the checkpoint was recorded (e.g. the RP was
+// upgraded mid-operation) only the unchanged prefix is skipped.
+func completedPrefix(steps []Step, completed []string) int {
+	var i int
+	for i < len(steps) && i < len(completed) && steps[i].String() == completed[i] {
+		i++
+	}
+	return i
+}
@@ -6,6 +6,8 @@ package steps
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -277,3 +279,145 @@ func TestStepMetricsNameFormatting(t *testing.T) {
 		})
 	}
 }
+
+type fakeCheckpointer struct {
+	completed []string
+}
+
+func (c *fakeCheckpointer) Completed() []string {
+	return c.completed
+}
+
+func (c *fakeCheckpointer) Checkpoint(ctx context.Context, i int, name string, duration time.Duration) error {
+	c.completed = append(c.completed[:i], name)
+	return nil
+}
+
+func TestRunResumable(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		steps         []Step
+		completed     []string
+		wantRun       []string
+		wantCompleted []string
+		wantErr       string
+	}{
+		{
+			name: "no checkpoint runs and records all steps",
+			steps: []Step{
+				Action(successfulFunc),
+				Condition(alwaysTrueCondition, 50*time.Millisecond, true),
+			},
+			wantRun: []string{
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"running step [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+			},
+			wantCompleted: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"[Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+			},
+		},
+		{
+			name: "completed steps are skipped unless marked AlwaysRun",
+			steps: []Step{
+				AlwaysRun(Action(successfulFunc)),
+				Condition(alwaysTrueCondition, 50*time.Millisecond, true),
+				Action(successfulFunc),
+			},
+			completed: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"[Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+			},
+			wantRun: []string{
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"skipping completed step [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+			wantCompleted: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"[Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+		},
+		{
+			name: "AlwaysRun steps of a completed parallel group are run",
+			steps: []Step{
+				Parallel(
+					AlwaysRun(Action(successfulFunc)),
+					Condition(alwaysTrueCondition, 50*time.Millisecond, true),
+				),
+				Action(successfulFunc),
+			},
+			completed: []string{
+				"[Parallel [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]]",
+			},
+			wantRun: []string{
+				"skipping completed step [Parallel [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]]",
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+			wantCompleted: []string{
+				"[Parallel [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]]",
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+		},
+		{
+			name: "only the matching prefix of a stale checkpoint is skipped",
+			steps: []Step{
+				Action(successfulFunc),
+				Condition(alwaysTrueCondition, 50*time.Millisecond, true),
+			},
+			completed: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.removedFunc]",
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.otherRemovedFunc]",
+			},
+			wantRun: []string{
+				"skipping completed step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"running step [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+			},
+			wantCompleted: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"[Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 50ms]",
+			},
+		},
+		{
+			name: "a failing step is not recorded",
+			steps: []Step{
+				Action(successfulFunc),
+				Action(failingFunc),
+			},
+			wantRun: []string{
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+				"running step [Action github.com/Azure/ARO-RP/pkg/util/steps.failingFunc]",
+			},
+			wantCompleted: []string{
+				"[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			},
+			wantErr: "oh no!",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			h, log := testlog.New()
+			cp := &fakeCheckpointer{completed: tt.completed}
+
+			_, err := RunResumable(ctx, log, 25*time.Millisecond, tt.steps, currentTimeFunc, cp, nil)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			var gotRun []string
+			for _, e := range h.AllEntries() {
+				if strings.HasPrefix(e.Message, "running step") || strings.HasPrefix(e.Message, "skipping completed step") {
+					gotRun = append(gotRun, e.Message)
+				}
+			}
+
+			if !reflect.DeepEqual(gotRun, tt.wantRun) {
+				t.Errorf("got steps %#v, want %#v", gotRun, tt.wantRun)
+			}
+			if !reflect.DeepEqual(cp.completed, tt.wantCompleted) {
+				t.Errorf("got completed %#v, want %#v", cp.completed, tt.wantCompleted)
+			}
+		})
+	}
+}
@@ -74,6 +74,10 @@ export const kcmStatisticsKey = "kcmstatistics"
 export const dnsStatisticsKey = "dnsstatistics"
 export const ingressStatisticsKey = "ingressstatistics"
 export const clusterOperatorsKey = "clusteroperators"
+export const sshRecordingsKey = "sshrecordings"
+export const actionHistoryKey = "actionhistory"
+export const elevatedAccessKey = "elevatedaccess"
+export const kubeconfigTokensKey = "kubeconfigtokens"
 
 const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
 
@@ -169,6 +173,30 @@ export function ClusterDetailPanel(props: {
           url: '#clusteroperators',
           icon: 'Shapes',
         },
+        {
+          name: "SSHRecordings",
+          key: sshRecordingsKey,
+          url: "#sshrecordings",
+          icon: "Video",
+        },
+        {
+          name: "ActionHistory",
+          key: actionHistoryKey,
+          url: "#actionhistory",
+          icon: "History",
+        },
+        {
+          name: "ElevatedAccess",
+          key: elevatedAccessKey,
+          url: "#elevatedaccess",
+          icon: "Permissions",
+        },
+        {
+          name: "KubeconfigTokens",
+          key: kubeconfigTokensKey,
+          url: "#kubeconfigtokens",
+          icon: "AzureKeyVault",
+        },
       ],
     },
   ]
@@ -317,6 +345,7 @@ export function ClusterDetailPanel(props: {
             <MemoisedClusterDetailListComponent
               item={data}
               cluster={props.currentCluster}
+              csrfToken={props.csrfToken}
               isDataLoaded={dataLoaded}
               detailPanelVisible={detailPanelVisible}
             />
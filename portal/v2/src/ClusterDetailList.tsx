@@ -1,4 +1,4 @@
-import { Component } from "react"
+import { Component, MutableRefObject } from "react"
 import React from "react"
 import { OverviewWrapper } from "./ClusterDetailListComponents/OverviewWrapper"
 import { NodesWrapper } from "./ClusterDetailListComponents/NodesWrapper"
@@ -6,12 +6,17 @@ import { MachinesWrapper } from "./ClusterDetailListComponents/MachinesWrapper"
 import { MachineSetsWrapper } from "./ClusterDetailListComponents/MachineSetsWrapper"
 import { Statistics } from "./ClusterDetailListComponents/Statistics/Statistics"
 import { ClusterOperatorsWrapper } from "./ClusterDetailListComponents/ClusterOperatorsWrapper";
+import { SSHRecordingsWrapper } from "./ClusterDetailListComponents/SSHRecordingsWrapper"
+import { ActionHistoryWrapper } from "./ClusterDetailListComponents/ActionHistoryWrapper"
+import { ElevatedAccessWrapper } from "./ClusterDetailListComponents/ElevatedAccessWrapper"
+import { KubeconfigTokensWrapper } from "./ClusterDetailListComponents/KubeconfigTokensWrapper"
 
 import { ICluster } from "./App"
 
 interface ClusterDetailComponentProps {
   item: IClusterDetails
   cluster: ICluster | null
+  csrfToken: MutableRefObject<string>
   isDataLoaded: boolean
   detailPanelVisible: string
 }
@@ -35,10 +40,12 @@ export interface IClusterDetails {
   provisioningState: string
   version: string
   installStatus: string
+  healthState: string
 }
 
 export interface WrapperProps {
   currentCluster: ICluster | null
+  csrfToken: MutableRefObject<string>
   detailPanelSelected: string
   loaded: boolean
 }
@@ -53,6 +60,10 @@ const detailComponents: Map<string, any> = new Map<string, any>([
     ["machines", MachinesWrapper],
     ["machinesets", MachineSetsWrapper],
     ["clusteroperators", ClusterOperatorsWrapper],
+    ["sshrecordings", SSHRecordingsWrapper],
+    ["actionhistory", ActionHistoryWrapper],
+    ["elevatedaccess", ElevatedAccessWrapper],
+    ["kubeconfigtokens", KubeconfigTokensWrapper],
     ["statistics", Statistics]
 ])
 
@@ -83,7 +94,7 @@ export class ClusterDetailComponent extends Component<ClusterDetailComponentProp
       } else {
         const DetailView = detailComponents.get(panel)
         return (
-          <DetailView currentCluster={this.props.cluster!} detailPanelSelected={panel} loaded={this.props.isDataLoaded}/>
+          <DetailView currentCluster={this.props.cluster!} csrfToken={this.props.csrfToken} detailPanelSelected={panel} loaded={this.props.isDataLoaded}/>
         )
       }
     }
@@ -0,0 +1,164 @@
+import { useState, useEffect } from "react"
+import { AxiosResponse } from "axios"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  DetailsList,
+  IColumn,
+  SelectionMode,
+} from "@fluentui/react"
+import { fetchActionHistory } from "../Request"
+import { actionHistoryKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface IAdminAction {
+  id: string
+  action: string
+  method: string
+  parameters?: { [key: string]: string }
+  clientPrincipalName: string
+  correlationId?: string
+  startTime: string
+  endTime: string
+  result: {
+    statusCode: number
+    error?: string
+  }
+}
+
+const formatParameters = (parameters?: { [key: string]: string }): string =>
+  Object.entries(parameters || {})
+    .map(([k, v]) => k + "=" + v)
+    .join(", ")
+
+export function ActionHistoryWrapper(props: WrapperProps) {
+  const [actions, setActions] = useState<IAdminAction[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}
+      >
+        {error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const columns: IColumn[] = [
+    {
+      key: "startTime",
+      name: "Start Time",
+      fieldName: "startTime",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "action",
+      name: "Action",
+      fieldName: "action",
+      minWidth: 120,
+      maxWidth: 160,
+    },
+    {
+      key: "parameters",
+      name: "Parameters",
+      minWidth: 150,
+      maxWidth: 300,
+      isMultiline: true,
+      onRender: (item: IAdminAction) => formatParameters(item.parameters),
+    },
+    {
+      key: "clientPrincipalName",
+      name: "Who",
+      fieldName: "clientPrincipalName",
+      minWidth: 150,
+      maxWidth: 250,
+    },
+    {
+      key: "result",
+      name: "Result",
+      minWidth: 200,
+      isMultiline: true,
+      onRender: (item: IAdminAction) =>
+        item.result.error
+          ? item.result.statusCode + ": " + item.result.error
+          : String(item.result.statusCode),
+    },
+    {
+      key: "correlationId",
+      name: "Correlation ID",
+      fieldName: "correlationId",
+      minWidth: 150,
+      maxWidth: 300,
+    },
+  ]
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: () => {
+        setActions([])
+        setFetching("")
+      },
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setActions(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (
+      props.detailPanelSelected.toLowerCase() == actionHistoryKey &&
+      fetching === "" &&
+      props.loaded &&
+      props.currentCluster
+    ) {
+      setFetching("FETCHING")
+      fetchActionHistory(props.currentCluster).then(onData)
+    }
+  }, [actions, props.loaded, props.detailPanelSelected])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack>
+        <CommandBar items={_items} ariaLabel="Refresh" styles={controlStyles} />
+        <DetailsList
+          items={actions}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          compact={true}
+        />
+      </Stack>
+    </Stack>
+  )
+}
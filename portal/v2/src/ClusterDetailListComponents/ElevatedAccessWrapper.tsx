@@ -0,0 +1,243 @@
+import { useState, useEffect } from "react"
+import { AxiosResponse } from "axios"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  DetailsList,
+  IColumn,
+  SelectionMode,
+  TextField,
+  Dropdown,
+  IDropdownOption,
+  PrimaryButton,
+  DefaultButton,
+} from "@fluentui/react"
+import { fetchElevatedAccess, RequestElevatedAccess, ReviewElevatedAccess } from "../Request"
+import { elevatedAccessKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface IElevatedAccessRequest {
+  id: string
+  username: string
+  justification: string
+  duration: string
+  state: string
+  requestedAt: string
+  reviewedBy?: string
+  reviewedAt?: string
+  expiresAt?: string
+}
+
+const durationOptions: IDropdownOption[] = [
+  { key: "30m", text: "30 minutes" },
+  { key: "1h", text: "1 hour" },
+  { key: "2h", text: "2 hours" },
+  { key: "4h", text: "4 hours" },
+  { key: "8h", text: "8 hours" },
+]
+
+const formStyles = { root: { maxWidth: 600, marginBottom: 20 } }
+
+export function ElevatedAccessWrapper(props: WrapperProps) {
+  const [requests, setRequests] = useState<IElevatedAccessRequest[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+  const [justification, setJustification] = useState("")
+  const [duration, setDuration] = useState<string>("1h")
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}
+      >
+        {typeof error?.data === "string" && error.data !== "" ? error.data : error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const refresh = () => {
+    setRequests([])
+    setFetching("")
+  }
+
+  const onResult = (result: AxiosResponse | null) => {
+    if (result?.status === 200 || result?.status === 201) {
+      refresh()
+    } else {
+      setError(result)
+    }
+  }
+
+  const onRequest = () => {
+    if (!props.currentCluster) {
+      return
+    }
+    RequestElevatedAccess(props.csrfToken.current, props.currentCluster, justification, duration).then(
+      (result) => {
+        if (result?.status === 201) {
+          setJustification("")
+        }
+        onResult(result)
+      }
+    )
+  }
+
+  const onReview = (request: IElevatedAccessRequest, action: "approve" | "deny") => {
+    if (!props.currentCluster) {
+      return
+    }
+    ReviewElevatedAccess(props.csrfToken.current, props.currentCluster, request.id, action).then(
+      onResult
+    )
+  }
+
+  const columns: IColumn[] = [
+    {
+      key: "username",
+      name: "Requester",
+      fieldName: "username",
+      minWidth: 150,
+      maxWidth: 250,
+    },
+    {
+      key: "justification",
+      name: "Justification",
+      fieldName: "justification",
+      minWidth: 200,
+      isMultiline: true,
+    },
+    {
+      key: "duration",
+      name: "Duration",
+      fieldName: "duration",
+      minWidth: 70,
+      maxWidth: 80,
+    },
+    {
+      key: "state",
+      name: "State",
+      fieldName: "state",
+      minWidth: 70,
+      maxWidth: 80,
+    },
+    {
+      key: "requestedAt",
+      name: "Requested At",
+      fieldName: "requestedAt",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "reviewedBy",
+      name: "Reviewed By",
+      fieldName: "reviewedBy",
+      minWidth: 150,
+      maxWidth: 250,
+    },
+    {
+      key: "expiresAt",
+      name: "Expires At",
+      fieldName: "expiresAt",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "review",
+      name: "",
+      minWidth: 170,
+      onRender: (item: IElevatedAccessRequest) =>
+        item.state === "Pending" && (
+          <Stack horizontal tokens={{ childrenGap: 5 }}>
+            <DefaultButton text="Approve" onClick={() => onReview(item, "approve")} />
+            <DefaultButton text="Deny" onClick={() => onReview(item, "deny")} />
+          </Stack>
+        ),
+    },
+  ]
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: refresh,
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setRequests(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (
+      props.detailPanelSelected.toLowerCase() == elevatedAccessKey &&
+      fetching === "" &&
+      props.loaded &&
+      props.currentCluster
+    ) {
+      setFetching("FETCHING")
+      fetchElevatedAccess(props.currentCluster).then(onData)
+    }
+  }, [requests, props.loaded, props.detailPanelSelected])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack styles={formStyles} tokens={{ childrenGap: 10 }}>
+        <TextField
+          label="Justification"
+          multiline
+          rows={2}
+          value={justification}
+          onChange={(_, value) => setJustification(value || "")}
+        />
+        <Dropdown
+          label="Duration"
+          options={durationOptions}
+          selectedKey={duration}
+          onChange={(_, option) => option && setDuration(option.key as string)}
+        />
+        <Stack.Item>
+          <PrimaryButton
+            text="Request elevated access"
+            disabled={justification.trim() === ""}
+            onClick={onRequest}
+          />
+        </Stack.Item>
+      </Stack>
+      <Stack>
+        <CommandBar items={_items} ariaLabel="Refresh" styles={controlStyles} />
+        <DetailsList
+          items={requests}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          compact={true}
+        />
+      </Stack>
+    </Stack>
+  )
+}
@@ -0,0 +1,170 @@
+import { useState, useEffect } from "react"
+import { AxiosResponse } from "axios"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  DetailsList,
+  IColumn,
+  SelectionMode,
+  DefaultButton,
+} from "@fluentui/react"
+import { fetchKubeconfigTokens, RevokeKubeconfigToken } from "../Request"
+import { kubeconfigTokensKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface IKubeconfigToken {
+  id: string
+  username: string
+  elevated: boolean
+  scope: string
+  namespace?: string
+  createdAt?: string
+  expiresAt?: string
+}
+
+export function KubeconfigTokensWrapper(props: WrapperProps) {
+  const [tokens, setTokens] = useState<IKubeconfigToken[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}
+      >
+        {typeof error?.data === "string" && error.data !== "" ? error.data : error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const refresh = () => {
+    setTokens([])
+    setFetching("")
+  }
+
+  const onRevoke = (token: IKubeconfigToken) => {
+    if (!props.currentCluster) {
+      return
+    }
+    RevokeKubeconfigToken(props.csrfToken.current, props.currentCluster, token.id).then(
+      (result) => {
+        if (result?.status === 204) {
+          refresh()
+        } else {
+          setError(result)
+        }
+      }
+    )
+  }
+
+  const columns: IColumn[] = [
+    {
+      key: "username",
+      name: "Username",
+      fieldName: "username",
+      minWidth: 150,
+      maxWidth: 250,
+    },
+    {
+      key: "scope",
+      name: "Scope",
+      minWidth: 120,
+      maxWidth: 200,
+      onRender: (item: IKubeconfigToken) =>
+        item.namespace ? item.scope + " (" + item.namespace + ")" : item.scope,
+    },
+    {
+      key: "elevated",
+      name: "Elevated",
+      minWidth: 70,
+      maxWidth: 80,
+      onRender: (item: IKubeconfigToken) => (item.elevated ? "Yes" : "No"),
+    },
+    {
+      key: "createdAt",
+      name: "Created At",
+      fieldName: "createdAt",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "expiresAt",
+      name: "Expires At",
+      fieldName: "expiresAt",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "revoke",
+      name: "",
+      minWidth: 80,
+      onRender: (item: IKubeconfigToken) => (
+        <DefaultButton text="Revoke" onClick={() => onRevoke(item)} />
+      ),
+    },
+  ]
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: refresh,
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setTokens(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (
+      props.detailPanelSelected.toLowerCase() == kubeconfigTokensKey &&
+      fetching === "" &&
+      props.loaded &&
+      props.currentCluster
+    ) {
+      setFetching("FETCHING")
+      fetchKubeconfigTokens(props.currentCluster).then(onData)
+    }
+  }, [tokens, props.loaded, props.detailPanelSelected])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack>
+        <CommandBar items={_items} ariaLabel="Refresh" styles={controlStyles} />
+        <DetailsList
+          items={tokens}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          compact={true}
+        />
+      </Stack>
+    </Stack>
+  )
+}
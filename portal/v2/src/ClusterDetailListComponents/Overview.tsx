@@ -80,7 +80,8 @@ const clusterDetailHeadings : IClusterDetails = {
     provisioningState: 'Provisioning State',
     resourceId: 'Resource Id',
     version: 'Version',
-    installStatus: 'Installation Status'
+    installStatus: 'Installation Status',
+    healthState: 'Health State'
 }
 
 function ClusterDetailCell(
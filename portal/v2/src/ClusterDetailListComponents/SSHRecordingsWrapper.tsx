@@ -0,0 +1,237 @@
+import { useState, useEffect, useRef } from "react"
+import { AxiosResponse } from "axios"
+import {
+  IMessageBarStyles,
+  MessageBar,
+  MessageBarType,
+  Stack,
+  CommandBar,
+  ICommandBarItemProps,
+  DetailsList,
+  IColumn,
+  SelectionMode,
+  Link,
+  Text,
+} from "@fluentui/react"
+import { fetchSSHRecording, fetchSSHRecordings } from "../Request"
+import { sshRecordingsKey } from "../ClusterDetail"
+import { WrapperProps } from "../ClusterDetailList"
+
+export interface ISSHRecording {
+  id: string
+  username: string
+  hostname: string
+  startTime: string
+  endTime: string
+  truncated?: boolean
+}
+
+// asciicast v2 events are [time, code, data]
+type AsciicastEvent = [number, string, string]
+
+// stripANSI removes terminal escape sequences, as the recording is replayed
+// into plain text rather than into a terminal emulator
+const stripANSI = (s: string): string =>
+  // eslint-disable-next-line no-control-regex
+  s.replace(/\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[()][0-9A-Za-z]|\x1b[=>]|\r/g, "")
+
+const parseAsciicast = (data: string): AsciicastEvent[] => {
+  const events: AsciicastEvent[] = []
+  data
+    .split("\n")
+    .slice(1) // header
+    .forEach((line) => {
+      if (line) {
+        events.push(JSON.parse(line))
+      }
+    })
+  return events.filter((e) => e[1] === "o")
+}
+
+const outputStyle = {
+  backgroundColor: "#1e1e1e",
+  color: "#d4d4d4",
+  padding: 10,
+  minHeight: 400,
+  maxHeight: 600,
+  overflow: "auto",
+  whiteSpace: "pre-wrap" as const,
+  fontFamily: "monospace",
+}
+
+export function SSHRecordingsWrapper(props: WrapperProps) {
+  const [recordings, setRecordings] = useState<ISSHRecording[]>([])
+  const [error, setError] = useState<AxiosResponse | null>(null)
+  const [fetching, setFetching] = useState("")
+  const [playing, setPlaying] = useState<ISSHRecording | null>(null)
+  const [output, setOutput] = useState("")
+  const timer = useRef<ReturnType<typeof setTimeout> | null>(null)
+
+  const errorBarStyles: Partial<IMessageBarStyles> = { root: { marginBottom: 15 } }
+
+  const errorBar = (): any => {
+    return (
+      <MessageBar
+        messageBarType={MessageBarType.error}
+        isMultiline={false}
+        onDismiss={() => setError(null)}
+        dismissButtonAriaLabel="Close"
+        styles={errorBarStyles}
+      >
+        {error?.status === 403 ? "Elevated access is required." : error?.statusText}
+      </MessageBar>
+    )
+  }
+
+  const stop = () => {
+    if (timer.current) {
+      clearTimeout(timer.current)
+      timer.current = null
+    }
+  }
+
+  // play replays the output events of the recording with their original
+  // timing, compressing idle periods to at most two seconds
+  const play = (recording: ISSHRecording, events: AsciicastEvent[]) => {
+    stop()
+    setPlaying(recording)
+    setOutput("")
+
+    let i = 0
+    let last = 0
+    const next = () => {
+      if (i >= events.length) {
+        timer.current = null
+        return
+      }
+      const [time, , data] = events[i++]
+      const delay = Math.min(time - last, 2) * 1000
+      last = time
+      timer.current = setTimeout(() => {
+        setOutput((output) => output + stripANSI(data))
+        next()
+      }, delay)
+    }
+    next()
+  }
+
+  const onReplay = (recording: ISSHRecording) => {
+    if (!props.currentCluster) {
+      return
+    }
+    fetchSSHRecording(props.currentCluster, recording.id).then((result) => {
+      if (result?.status === 200) {
+        play(recording, parseAsciicast(result.data))
+      } else {
+        setError(result)
+      }
+    })
+  }
+
+  const columns: IColumn[] = [
+    {
+      key: "startTime",
+      name: "Start Time",
+      fieldName: "startTime",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "endTime",
+      name: "End Time",
+      fieldName: "endTime",
+      minWidth: 160,
+      maxWidth: 200,
+    },
+    {
+      key: "username",
+      name: "Username",
+      fieldName: "username",
+      minWidth: 150,
+      maxWidth: 250,
+    },
+    {
+      key: "hostname",
+      name: "Hostname",
+      fieldName: "hostname",
+      minWidth: 100,
+      maxWidth: 150,
+    },
+    {
+      key: "replay",
+      name: "",
+      minWidth: 80,
+      onRender: (item: ISSHRecording) => (
+        <Link onClick={() => onReplay(item)}>{item.truncated ? "Replay (truncated)" : "Replay"}</Link>
+      ),
+    },
+  ]
+
+  const controlStyles = {
+    root: {
+      paddingLeft: 0,
+      float: "right",
+    },
+  }
+
+  const _items: ICommandBarItemProps[] = [
+    {
+      key: "refresh",
+      text: "Refresh",
+      iconProps: { iconName: "Refresh" },
+      onClick: () => {
+        setRecordings([])
+        setFetching("")
+      },
+    },
+  ]
+
+  useEffect(() => {
+    const onData = (result: AxiosResponse | null) => {
+      if (result?.status === 200) {
+        setRecordings(result.data)
+      } else {
+        setError(result)
+      }
+      if (props.currentCluster) {
+        setFetching(props.currentCluster.name)
+      }
+    }
+
+    if (
+      props.detailPanelSelected.toLowerCase() == sshRecordingsKey &&
+      fetching === "" &&
+      props.loaded &&
+      props.currentCluster
+    ) {
+      setFetching("FETCHING")
+      fetchSSHRecordings(props.currentCluster).then(onData)
+    }
+  }, [recordings, props.loaded, props.detailPanelSelected])
+
+  // stop any replay when the component is unmounted
+  useEffect(() => stop, [])
+
+  return (
+    <Stack>
+      <Stack.Item grow>{error && errorBar()}</Stack.Item>
+      <Stack>
+        <CommandBar items={_items} ariaLabel="Refresh" styles={controlStyles} />
+        <DetailsList
+          items={recordings}
+          columns={columns}
+          selectionMode={SelectionMode.none}
+          compact={true}
+        />
+        {playing && (
+          <Stack>
+            <Text variant="medium">
+              {playing.username}@{playing.hostname} {playing.startTime}
+            </Text>
+            <pre style={outputStyle}>{output}</pre>
+          </Stack>
+        )}
+      </Stack>
+    </Stack>
+  )
+}
@@ -11,6 +11,16 @@ const OnError = (err: AxiosResponse): AxiosResponse | null => {
   }
 }
 
+// OnElevatedError is used by endpoints which return 403 when the user lacks
+// elevated access to the cluster, so that the caller can show the reason
+// rather than redirecting to login
+const OnElevatedError = (err: AxiosResponse): AxiosResponse | null => {
+  if (err.status === 403 && typeof err.data === "string" && err.data !== "") {
+    return err
+  }
+  return OnError(err)
+}
+
 export const fetchClusters = async (): Promise<AxiosResponse | null> => {
   try {
     const result = await axios("/api/clusters")
@@ -125,7 +135,9 @@ export const ProcessLogOut = async (): Promise<any> => {
 
 export const RequestKubeconfig = async (
   csrfToken: string,
-  resourceID: string
+  resourceID: string,
+  scope: string,
+  namespace?: string
 ): Promise<AxiosResponse | null> => {
   try {
     const result = await axios({
@@ -133,6 +145,11 @@ export const RequestKubeconfig = async (
       url: resourceID + "/kubeconfig/new",
       headers: {
         "X-CSRF-Token": csrfToken,
+        "Content-Type": "application/json",
+      },
+      data: {
+        scope: scope,
+        namespace: namespace,
       },
     })
     return result
@@ -160,3 +177,129 @@ export const fetchStatistics = async (
     return OnError(err)
   }
 }
+
+export const fetchActionHistory = async (cluster: ICluster): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(
+      "/api/" + cluster.subscription + "/" + cluster.resourceGroup + "/" + cluster.name + "/actionhistory"
+    )
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const fetchSSHRecordings = async (cluster: ICluster): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(cluster.resourceId + "/ssh/recordings")
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
+
+export const fetchSSHRecording = async (
+  cluster: ICluster,
+  recordingID: string
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios({
+      url: cluster.resourceId + "/ssh/recordings/" + recordingID,
+      responseType: "text",
+      transformResponse: (data) => data,
+    })
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
+
+export const fetchElevatedAccess = async (cluster: ICluster): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(cluster.resourceId + "/elevatedaccess")
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
+
+export const RequestElevatedAccess = async (
+  csrfToken: string,
+  cluster: ICluster,
+  justification: string,
+  duration: string
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios({
+      method: "POST",
+      url: cluster.resourceId + "/elevatedaccess/new",
+      headers: {
+        "X-CSRF-Token": csrfToken,
+        "Content-Type": "application/json",
+      },
+      data: {
+        justification: justification,
+        duration: duration,
+      },
+    })
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
+
+export const ReviewElevatedAccess = async (
+  csrfToken: string,
+  cluster: ICluster,
+  requestID: string,
+  action: "approve" | "deny"
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios({
+      method: "POST",
+      url: cluster.resourceId + "/elevatedaccess/" + requestID + "/" + action,
+      headers: {
+        "X-CSRF-Token": csrfToken,
+      },
+    })
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
+
+export const fetchKubeconfigTokens = async (cluster: ICluster): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios(cluster.resourceId + "/kubeconfig/tokens")
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnError(err)
+  }
+}
+
+export const RevokeKubeconfigToken = async (
+  csrfToken: string,
+  cluster: ICluster,
+  tokenID: string
+): Promise<AxiosResponse | null> => {
+  try {
+    const result = await axios({
+      method: "DELETE",
+      url: cluster.resourceId + "/kubeconfig/tokens/" + tokenID,
+      headers: {
+        "X-CSRF-Token": csrfToken,
+      },
+    })
+    return result
+  } catch (e: any) {
+    const err = e.response as AxiosResponse
+    return OnElevatedError(err)
+  }
+}
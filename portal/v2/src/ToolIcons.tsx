@@ -1,4 +1,4 @@
-import { IconButton, TooltipHost } from "@fluentui/react"
+import { IconButton, IContextualMenuProps, TooltipHost } from "@fluentui/react"
 import { AxiosResponse } from "axios"
 import { RequestKubeconfig } from "./Request"
 import { MutableRefObject, useEffect, useLayoutEffect } from "react"
@@ -14,6 +14,11 @@ type ToolIconsProps = {
   sshBox: any
 }
 
+type KubeconfigRequest = {
+  scope: string
+  namespace?: string
+}
+
 type FileDownload = {
   name: string
   content: string
@@ -24,6 +29,9 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
     const [data, setData] = useState<FileDownload>({ name: "", content: "" })
     const [error, setError] = useState<AxiosResponse | null>(null)
     const [fetching, setFetching] = useState("DONE")
+    const [kubeconfigRequest, setKubeconfigRequest] = useState<KubeconfigRequest>({
+      scope: "Admin",
+    })
     const buttonRef = useRef<HTMLAnchorElement | null>(null)
 
     useEffect(() => {
@@ -42,9 +50,47 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
 
       if (fetching === "") {
         setFetching("FETCHING")
-        RequestKubeconfig(csrfToken.current, resourceId).then(onData)
+        RequestKubeconfig(
+          csrfToken.current,
+          resourceId,
+          kubeconfigRequest.scope,
+          kubeconfigRequest.namespace
+        ).then(onData)
+      }
+    }, [fetching, error, data, resourceId, csrfToken, kubeconfigRequest])
+
+    const _onKubeconfigClick = (scope: string) => {
+      let namespace: string | undefined
+      if (scope === "Namespace") {
+        const value = window.prompt("Namespace")
+        if (!value) {
+          return
+        }
+        namespace = value
       }
-    }, [fetching, error, data, resourceId, csrfToken])
+      setKubeconfigRequest({ scope: scope, namespace: namespace })
+      setFetching("")
+    }
+
+    const kubeconfigMenuProps: IContextualMenuProps = {
+      items: [
+        {
+          key: "readonly",
+          text: "Read-only",
+          onClick: () => _onKubeconfigClick("ReadOnly"),
+        },
+        {
+          key: "namespace",
+          text: "Namespace...",
+          onClick: () => _onKubeconfigClick("Namespace"),
+        },
+        {
+          key: "admin",
+          text: "Admin",
+          onClick: () => _onKubeconfigClick("Admin"),
+        },
+      ],
+    }
 
     const _onCopyResourceID = (resourceId: any) => {
       navigator.clipboard.writeText(resourceId)
@@ -97,7 +143,7 @@ export const ToolIcons = forwardRef<any, ToolIconsProps>(
             iconProps={{ iconName: "kubernetes-svg" }}
             disabled={fetching === "FETCHING"}
             aria-label="Download Kubeconfig"
-            onClick={() => setFetching("")}
+            menuProps={kubeconfigMenuProps}
           />
           <a style={{ display: "none" }} ref={buttonRef} href={"#"}>
             dl
@@ -0,0 +1,38 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func injectAdminActions(c *cosmosdb.FakeAdminActionDocumentClient) {
+	c.SetQueryHandler(database.AdminActionsGetQuery, fakeAdminActionsGetQuery)
+
+	c.SetSorter(func(in []*api.AdminActionDocument) {
+		sort.Slice(in, func(i, j int) bool { return strings.Compare(in[i].ID, in[j].ID) < 0 })
+	})
+}
+
+func fakeAdminActionsGetQuery(client cosmosdb.AdminActionDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.AdminActionDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), nil)
+	if err != nil {
+		return cosmosdb.NewFakeAdminActionDocumentErroringRawIterator(err)
+	}
+
+	var docs []*api.AdminActionDocument
+	for _, doc := range input.AdminActionDocuments {
+		if doc.Key == query.Parameters[0].Value {
+			docs = append(docs, doc)
+		}
+	}
+
+	return cosmosdb.NewFakeAdminActionDocumentIterator(docs, 0)
+}
@@ -0,0 +1,18 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func injectAsyncOperations(c *cosmosdb.FakeAsyncOperationDocumentClient) {
+	c.SetSorter(func(in []*api.AsyncOperationDocument) {
+		sort.Slice(in, func(i, j int) bool { return strings.Compare(in[i].ID, in[j].ID) < 0 })
+	})
+}
@@ -26,6 +26,8 @@ type Checker struct {
 	gatewayDocuments          []*api.GatewayDocument
 	openShiftVersionDocuments []*api.OpenShiftVersionDocument
 	validationResult          []*api.ValidationResult
+	fleetOperationDocuments   []*api.FleetOperationDocument
+	adminActionDocuments      []*api.AdminActionDocument
 }
 
 func NewChecker() *Checker {
@@ -120,6 +122,28 @@ func (f *Checker) AddValidationResult(docs ...*api.ValidationResult) {
 	}
 }
 
+func (f *Checker) AddFleetOperationDocuments(docs ...*api.FleetOperationDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.fleetOperationDocuments = append(f.fleetOperationDocuments, docCopy.(*api.FleetOperationDocument))
+	}
+}
+
+func (f *Checker) AddAdminActionDocuments(docs ...*api.AdminActionDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.adminActionDocuments = append(f.adminActionDocuments, docCopy.(*api.AdminActionDocument))
+	}
+}
+
 func (f *Checker) CheckOpenShiftClusters(openShiftClusters *cosmosdb.FakeOpenShiftClusterDocumentClient) (errs []error) {
 	ctx := context.Background()
 
@@ -273,3 +297,43 @@ func (f *Checker) CheckOpenShiftVersions(versions *cosmosdb.FakeOpenShiftVersion
 
 	return errs
 }
+
+func (f *Checker) CheckFleetOperations(fleetOperations *cosmosdb.FakeFleetOperationDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := fleetOperations.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	if len(f.fleetOperationDocuments) != 0 && len(all.FleetOperationDocuments) == len(f.fleetOperationDocuments) {
+		diff := deep.Equal(all.FleetOperationDocuments, f.fleetOperationDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.FleetOperationDocuments) != 0 || len(f.fleetOperationDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("fleetOperations length different, %d vs %d", len(all.FleetOperationDocuments), len(f.fleetOperationDocuments)))
+	}
+
+	return errs
+}
+
+func (f *Checker) CheckAdminActions(adminActions *cosmosdb.FakeAdminActionDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := adminActions.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	if len(f.adminActionDocuments) != 0 && len(all.AdminActionDocuments) == len(f.adminActionDocuments) {
+		diff := deep.Equal(all.AdminActionDocuments, f.adminActionDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.AdminActionDocuments) != 0 || len(f.adminActionDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("adminActions length different, %d vs %d", len(all.AdminActionDocuments), len(f.adminActionDocuments)))
+	}
+
+	return errs
+}
@@ -20,6 +20,9 @@ type Fixture struct {
 	gatewayDocuments                     []*api.GatewayDocument
 	openShiftVersionDocuments            []*api.OpenShiftVersionDocument
 	clusterManagerConfigurationDocuments []*api.ClusterManagerConfigurationDocument
+	clusterHealthDocuments               []*api.ClusterHealthDocument
+	fleetOperationDocuments              []*api.FleetOperationDocument
+	adminActionDocuments                 []*api.AdminActionDocument
 
 	openShiftClustersDatabase            database.OpenShiftClusters
 	billingDatabase                      database.Billing
@@ -29,6 +32,9 @@ type Fixture struct {
 	gatewayDatabase                      database.Gateway
 	openShiftVersionsDatabase            database.OpenShiftVersions
 	clusterManagerConfigurationsDatabase database.ClusterManagerConfigurations
+	clusterHealthDatabase                database.ClusterHealth
+	fleetOperationsDatabase              database.FleetOperations
+	adminActionsDatabase                 database.AdminActions
 
 	openShiftVersionsUUID uuid.Generator
 }
@@ -78,6 +84,21 @@ func (f *Fixture) WithOpenShiftVersions(db database.OpenShiftVersions, uuid uuid
 	return f
 }
 
+func (f *Fixture) WithClusterHealth(db database.ClusterHealth) *Fixture {
+	f.clusterHealthDatabase = db
+	return f
+}
+
+func (f *Fixture) WithFleetOperations(db database.FleetOperations) *Fixture {
+	f.fleetOperationsDatabase = db
+	return f
+}
+
+func (f *Fixture) WithAdminActions(db database.AdminActions) *Fixture {
+	f.adminActionsDatabase = db
+	return f
+}
+
 func (f *Fixture) AddOpenShiftClusterDocuments(docs ...*api.OpenShiftClusterDocument) {
 	for _, doc := range docs {
 		docCopy, err := deepCopy(doc)
@@ -166,6 +187,39 @@ func (f *Fixture) AddClusterManagerConfigurationDocuments(docs ...*api.ClusterMa
 	}
 }
 
+func (f *Fixture) AddClusterHealthDocuments(docs ...*api.ClusterHealthDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.clusterHealthDocuments = append(f.clusterHealthDocuments, docCopy.(*api.ClusterHealthDocument))
+	}
+}
+
+func (f *Fixture) AddFleetOperationDocuments(docs ...*api.FleetOperationDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.fleetOperationDocuments = append(f.fleetOperationDocuments, docCopy.(*api.FleetOperationDocument))
+	}
+}
+
+func (f *Fixture) AddAdminActionDocuments(docs ...*api.AdminActionDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.adminActionDocuments = append(f.adminActionDocuments, docCopy.(*api.AdminActionDocument))
+	}
+}
+
 func (f *Fixture) Create() error {
 	ctx := context.Background()
 
@@ -234,5 +288,32 @@ func (f *Fixture) Create() error {
 		}
 	}
 
+	for _, i := range f.clusterHealthDocuments {
+		_, err := f.clusterHealthDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range f.fleetOperationDocuments {
+		if i.ID == "" {
+			i.ID = f.fleetOperationsDatabase.NewUUID()
+		}
+		_, err := f.fleetOperationsDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range f.adminActionDocuments {
+		if i.ID == "" {
+			i.ID = f.adminActionsDatabase.NewUUID()
+		}
+		_, err := f.adminActionsDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
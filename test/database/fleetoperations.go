@@ -0,0 +1,67 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func fakeFleetOperationsDequeueQuery(client cosmosdb.FleetOperationDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.FleetOperationDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), nil)
+	if err != nil {
+		return cosmosdb.NewFakeFleetOperationDocumentErroringRawIterator(err)
+	}
+
+	var results []*api.FleetOperationDocument
+	for _, r := range input.FleetOperationDocuments {
+		if r.FleetOperation == nil || r.FleetOperation.State.IsTerminal() {
+			continue
+		}
+		if r.FleetOperation.State == api.FleetOperationStatePaused &&
+			!hasRunningFleetOperationTargets(r.FleetOperation) {
+			continue
+		}
+		if int64(r.LeaseExpires) < time.Now().Unix() {
+			results = append(results, r)
+		}
+	}
+
+	return cosmosdb.NewFakeFleetOperationDocumentIterator(results, 0)
+}
+
+func hasRunningFleetOperationTargets(op *api.FleetOperation) bool {
+	for _, target := range op.Targets {
+		if target.State == api.FleetOperationTargetStateRunning {
+			return true
+		}
+	}
+	return false
+}
+
+func fakeFleetOperationsRenewLeaseTrigger(ctx context.Context, doc *api.FleetOperationDocument) error {
+	doc.LeaseExpires = int(time.Now().Unix()) + 60
+	return nil
+}
+
+func fakeFleetOperationsRetryLaterTrigger(ctx context.Context, doc *api.FleetOperationDocument) error {
+	doc.LeaseExpires = int(time.Now().Unix()) + 60
+	return nil
+}
+
+func injectFleetOperations(c *cosmosdb.FakeFleetOperationDocumentClient) {
+	c.SetQueryHandler(database.FleetOperationsDequeueQuery, fakeFleetOperationsDequeueQuery)
+
+	c.SetTriggerHandler("renewLease", fakeFleetOperationsRenewLeaseTrigger)
+	c.SetTriggerHandler("retryLater", fakeFleetOperationsRetryLaterTrigger)
+
+	c.SetSorter(func(in []*api.FleetOperationDocument) {
+		sort.Slice(in, func(i, j int) bool { return in[i].ID < in[j].ID })
+	})
+}
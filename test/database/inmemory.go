@@ -48,6 +48,7 @@ func NewFakeBilling() (db database.Billing, client *cosmosdb.FakeBillingDocument
 func NewFakeAsyncOperations() (db database.AsyncOperations, client *cosmosdb.FakeAsyncOperationDocumentClient) {
 	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.ASYNCOPERATIONS)
 	client = cosmosdb.NewFakeAsyncOperationDocumentClient(jsonHandle)
+	injectAsyncOperations(client)
 	db = database.NewAsyncOperationsWithProvidedClient(client, uuid)
 	return db, client
 }
@@ -55,6 +56,7 @@ func NewFakeAsyncOperations() (db database.AsyncOperations, client *cosmosdb.Fak
 func NewFakePortal() (db database.Portal, client *cosmosdb.FakePortalDocumentClient) {
 	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.PORTAL)
 	client = cosmosdb.NewFakePortalDocumentClient(jsonHandle)
+	injectPortal(client)
 	db = database.NewPortalWithProvidedClient(client, uuid)
 	return db, client
 }
@@ -66,6 +68,28 @@ func NewFakeGateway() (db database.Gateway, client *cosmosdb.FakeGatewayDocument
 	return db, client
 }
 
+func NewFakeClusterHealth() (db database.ClusterHealth, client *cosmosdb.FakeClusterHealthDocumentClient) {
+	client = cosmosdb.NewFakeClusterHealthDocumentClient(jsonHandle)
+	db = database.NewClusterHealthWithProvidedClient(client)
+	return db, client
+}
+
+func NewFakeFleetOperations() (db database.FleetOperations, client *cosmosdb.FakeFleetOperationDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.FLEETOPERATIONS)
+	client = cosmosdb.NewFakeFleetOperationDocumentClient(jsonHandle)
+	injectFleetOperations(client)
+	db = database.NewFleetOperationsWithProvidedClient(client, "", uuid)
+	return db, client
+}
+
+func NewFakeAdminActions() (db database.AdminActions, client *cosmosdb.FakeAdminActionDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.ADMINACTIONS)
+	client = cosmosdb.NewFakeAdminActionDocumentClient(jsonHandle)
+	injectAdminActions(client)
+	db = database.NewAdminActionsWithProvidedClient(client, uuid)
+	return db, client
+}
+
 func NewFakeOpenShiftVersions(uuid uuid.Generator) (db database.OpenShiftVersions, client *cosmosdb.FakeOpenShiftVersionDocumentClient) {
 	client = cosmosdb.NewFakeOpenShiftVersionDocumentClient(jsonHandle)
 	db = database.NewOpenShiftVersionsWithProvidedClient(client, uuid)
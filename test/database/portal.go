@@ -0,0 +1,43 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func injectPortal(c *cosmosdb.FakePortalDocumentClient) {
+	c.SetQueryHandler(database.PortalElevatedAccessQuery, fakePortalQuery(func(portal *api.Portal) bool { return portal.ElevatedAccess != nil }))
+	c.SetQueryHandler(database.PortalKubeconfigsQuery, fakePortalQuery(func(portal *api.Portal) bool { return portal.Kubeconfig != nil }))
+
+	c.SetSorter(func(in []*api.PortalDocument) {
+		sort.Slice(in, func(i, j int) bool { return strings.Compare(in[i].ID, in[j].ID) < 0 })
+	})
+}
+
+// fakePortalQuery returns a query handler which returns the documents of the
+// cluster given in the @id parameter which match f
+func fakePortalQuery(f func(*api.Portal) bool) func(cosmosdb.PortalDocumentClient, *cosmosdb.Query, *cosmosdb.Options) cosmosdb.PortalDocumentRawIterator {
+	return func(client cosmosdb.PortalDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.PortalDocumentRawIterator {
+		input, err := client.ListAll(context.Background(), nil)
+		if err != nil {
+			return cosmosdb.NewFakePortalDocumentErroringRawIterator(err)
+		}
+
+		var docs []*api.PortalDocument
+		for _, doc := range input.PortalDocuments {
+			if f(doc.Portal) && doc.Portal.ID == query.Parameters[0].Value {
+				docs = append(docs, doc)
+			}
+		}
+
+		return cosmosdb.NewFakePortalDocumentIterator(docs, 0)
+	}
+}
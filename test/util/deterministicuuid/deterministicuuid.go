@@ -19,6 +19,8 @@ const (
 	GATEWAY
 	OPENSHIFT_VERSIONS
 	CLUSTERMANAGER
+	FLEETOPERATIONS
+	ADMINACTIONS
 )
 
 type gen struct {